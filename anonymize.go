@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Anonymize renames every node of the graph using the given function, which
+// is given the node's current name, so graphs can be shared, like in bug
+// reports, without leaking the names of their nodes. If the function is nil,
+// nodes are named after their position in the graph: "n0", "n1", and so on.
+//
+//	g.Anonymize(nil)                              // api → db: n0 → n1
+//	g.Anonymize(func(old string) string {         // api → db: 1f2a… → 9c0e…
+//		return fmt.Sprintf("%x", sha256.Sum256([]byte(salt+old)))[:8]
+//	})
+//
+// The graph's structure, including the attributes and labels of its nodes
+// and edges, is kept, so those should be removed separately if they are
+// sensitive. For graphs created using WithUniqueNames, an error wrapping
+// ErrDuplicateName is returned, without renaming any node, if two nodes
+// would have the same name.
+func (inst *Instance) Anonymize(rename func(old string) string) error {
+	if rename == nil {
+		i := -1
+		return inst.RelabelAll(func(*Node) string {
+			i++
+			return fmt.Sprintf("n%d", i)
+		})
+	}
+
+	return inst.RelabelAll(func(node *Node) string {
+		return rename(node.Name)
+	})
+}
+
+// rewirable is an edge considered by RewireRandomly, with the node it
+// belongs to, and the corresponding edge on the other side.
+type rewirable struct {
+	from        *Node
+	edge, other *Edge
+}
+
+// RewireRandomly randomly swaps the ends of pairs of edges of the graph,
+// using the given seed so the result can be reproduced, keeping the degree
+// of every node. A swap replaces two edges a → b and c → d with a → d and
+// c → b, so each node keeps its in-degree and out-degree, and its number
+// of undirected edges. Edges keep their names and attributes, and only
+// edges with the same direction and name are swapped.
+//
+//	a → b    c → d    RewireRandomly(1, seed): a → d    c → b
+//
+// The given fraction of the edges, between 0 and 1, is the number of swaps
+// to make, but swaps that would create self-loops, or duplicate edges, are
+// skipped, so fewer swaps may be made in small or dense graphs. The number
+// of swaps made is returned.
+//
+// Together with Anonymize, this is useful to share a graph with the same
+// degree distribution as a production graph, without its exact structure.
+//
+// https://en.wikipedia.org/wiki/Degree-preserving_randomization
+func (inst *Instance) RewireRandomly(fraction float64, seed int64) int {
+	var (
+		members = inst.members()
+		pairs   = pairEdges(inst.Nodes)
+	)
+
+	// Parallel edges each have their own other side, see pairEdges.
+	var edges []rewirable
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		if !members.Contains(edge.Node) || edge.Node == from {
+			return
+		}
+		other := pairs[edge]
+		if other == nil {
+			return
+		}
+		edges = append(edges, rewirable{from: from, edge: edge, other: other})
+	})
+
+	if len(edges) < 2 || fraction <= 0 {
+		return 0
+	}
+
+	target := int(math.Round(math.Min(fraction, 1) * float64(len(edges))))
+
+	rng := rand.New(rand.NewSource(seed))
+
+	swaps := 0
+	for attempt := 0; swaps < target && attempt < 10*target; attempt++ {
+		i, j := rng.Intn(len(edges)), rng.Intn(len(edges))
+		if i == j {
+			continue
+		}
+
+		e1, e2 := edges[i], edges[j]
+		if e1.edge.Direction != e2.edge.Direction || e1.edge.Name != e2.edge.Name {
+			continue
+		}
+
+		// Undirected and bi-directional edges can be swapped either way
+		// around, so the second edge is randomly flipped.
+		if e2.edge.Direction.AnyOf(None, Unknown, Both) && rng.Intn(2) == 1 {
+			e2 = rewirable{from: e2.edge.Node, edge: e2.other, other: e2.edge}
+		}
+
+		a, b, c, d := e1.from, e1.edge.Node, e2.from, e2.edge.Node
+		if a == c || b == d || a == d || c == b {
+			continue
+		}
+		if hasEdge(a, d, e1.edge) || hasEdge(c, b, e2.edge) {
+			continue
+		}
+
+		// a → b and c → d become a → d and c → b: the edges of a and c
+		// point to their new nodes, and the other sides of the edges
+		// swap places between b and d, taking their partner's attributes.
+		e1.edge.Node, e2.edge.Node = d, b
+		e1.other.Node, e2.other.Node = c, a
+		e1.other.Attributes, e2.other.Attributes = e2.other.Attributes, e1.other.Attributes
+
+		edges[i] = rewirable{from: a, edge: e1.edge, other: e2.other}
+		edges[j] = rewirable{from: c, edge: e2.edge, other: e1.other}
+
+		swaps++
+	}
+
+	if swaps > 0 {
+		inst.Touch()
+	}
+
+	return swaps
+}
+
+// hasEdge reports whether the from node has an edge to the given node with
+// the same direction and name as the given edge.
+func hasEdge(from, to *Node, like *Edge) bool {
+	for _, edge := range from.Edges {
+		if edge.Node == to && edge.Direction == like.Direction && edge.Name == like.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package graph_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Anonymize(t *testing.T) {
+	var (
+		api = graph.NewNode("api", nil)
+		db  = graph.NewNode("db", nil)
+	)
+
+	api.AddEdgeWithDirection(db, graph.Out)
+
+	g := graph.New("prod", graph.WithNodes(graph.Nodes{api, db}))
+
+	if err := g.Anonymize(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fmt.Sprint(g.Edges()); got != "n0 → n1" {
+		t.Fatalf("unexpected edges %s", got)
+	}
+
+	err := g.Anonymize(func(old string) string {
+		return strings.ToUpper(old)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := g.Nodes.String(); got != "N0, N1" {
+		t.Fatalf("unexpected nodes %s", got)
+	}
+}
+
+func TestInstance_Anonymize_uniqueNames(t *testing.T) {
+	g := graph.New("prod", graph.WithUniqueNames())
+	g.AddNodes(graph.NewNode("a", nil), graph.NewNode("b", nil))
+
+	err := g.Anonymize(func(string) string { return "x" })
+	if !errors.Is(err, graph.ErrDuplicateName) {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+
+	if got := g.Nodes.String(); got != "a, b" {
+		t.Fatalf("expected nodes to keep their names, got %s", got)
+	}
+
+	if err := g.Anonymize(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, ok := g.Lookup("n1"); !ok || node != g.Nodes[1] {
+		t.Fatal("expected the index of names to be updated")
+	}
+	if _, ok := g.Lookup("a"); ok {
+		t.Fatal("expected old names to be removed from the index")
+	}
+}
+
+// degrees returns the number of edges of each direction of each node.
+func degrees(g *graph.Instance) map[string]map[graph.EdgeDirection]int {
+	result := map[string]map[graph.EdgeDirection]int{}
+	for _, node := range g.Nodes {
+		result[node.Name] = map[graph.EdgeDirection]int{}
+		for _, edge := range node.Edges {
+			result[node.Name][edge.Direction]++
+		}
+	}
+	return result
+}
+
+func TestInstance_RewireRandomly(t *testing.T) {
+	g := graph.New("test")
+
+	nodes := graph.Nodes{}
+	for i := 0; i < 20; i++ {
+		nodes = append(nodes, graph.NewNode(fmt.Sprint(i), nil))
+	}
+	g.AddNodes(nodes...)
+
+	for i, node := range nodes {
+		node.AddEdgeWithDirection(nodes[(i+1)%len(nodes)], graph.Out)
+		node.AddEdgeWithDirection(nodes[(i+3)%len(nodes)], graph.Out)
+		if i%2 == 0 {
+			node.AddEdgeWithDirection(nodes[(i+5)%len(nodes)], graph.None)
+		}
+	}
+
+	nodes[0].Edges[0].Attributes = graph.Attributes{"weight": 3}
+
+	before := degrees(g)
+	edges := fmt.Sprint(g.Edges())
+
+	swaps := g.RewireRandomly(1, 1)
+	if swaps == 0 {
+		t.Fatal("expected edges to be swapped")
+	}
+
+	if fmt.Sprint(g.Edges()) == edges {
+		t.Fatal("expected the edges to change")
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(degrees(g)) != fmt.Sprint(before) {
+		t.Fatalf("expected degrees to be kept, got:\n%v\nbefore:\n%v", degrees(g), before)
+	}
+
+	weighted := 0
+	for _, edge := range g.Edges() {
+		if edge.Weight() == 3 {
+			weighted++
+		}
+		if edge.From() == edge.Node {
+			t.Fatalf("unexpected self-loop %s", edge)
+		}
+	}
+	if weighted != 1 {
+		t.Fatalf("expected the weighted edge to be kept, got %d", weighted)
+	}
+
+	if g.RewireRandomly(0, 1) != 0 {
+		t.Fatal("expected no swaps for a fraction of 0")
+	}
+}
+
+func TestInstance_RewireRandomly_parallel(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g := graph.New("test")
+
+		nodes := graph.Nodes{}
+		for i := 0; i < 6; i++ {
+			nodes = append(nodes, graph.NewNode(fmt.Sprintf("n%d", i), nil))
+		}
+		g.AddNodes(nodes...)
+
+		// Parallel weighted edges n0 → n2, among other edges.
+		nodes[0].AddWeightedEdge(nodes[2], 1)
+		nodes[0].AddWeightedEdge(nodes[2], 2)
+		nodes[1].AddEdge(nodes[3])
+		nodes[4].AddEdge(nodes[5])
+		nodes[3].AddEdge(nodes[4])
+
+		before := degrees(g)
+
+		g.RewireRandomly(1, seed)
+
+		if err := graph.CheckInvariants(g); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+
+		if fmt.Sprint(degrees(g)) != fmt.Sprint(before) {
+			t.Fatalf("seed %d: expected degrees to be kept, got:\n%v\nbefore:\n%v", seed, degrees(g), before)
+		}
+	}
+}
@@ -6,8 +6,19 @@ import "fmt"
 type Attributes map[string]any
 
 // UseAttribute is a helper function to use a named attribute of a specific type.
-func UseAttribute[T any](attrs Attributes, name string, fn func(T)) error {
+//
+// Optional defaults can be given, which are consulted in order when the named
+// attribute doesn't exist, such as an instance's NodeDefaults or EdgeDefaults.
+// Defaults are only consulted when given, see Node.Attr and Edge.Attr for
+// looking up attributes with the defaults of the graph they belong to.
+func UseAttribute[T any](attrs Attributes, name string, fn func(T), defaults ...Attributes) error {
 	v, ok := attrs[name]
+	for _, d := range defaults {
+		if ok {
+			break
+		}
+		v, ok = d[name]
+	}
 	if !ok {
 		return fmt.Errorf("graph attribute %q doesn't exist", name)
 	}
@@ -19,21 +30,55 @@ func UseAttribute[T any](attrs Attributes, name string, fn func(T)) error {
 	return nil
 }
 
-// GetAttributes is a helper function to return a named attribute of a specific type.
-func GetAttribute[T any](attrs Attributes, name string) (T, error) {
+// GetAttribute is a helper function to return a named attribute of a specific type.
+//
+// Optional defaults can be given, which are consulted in order when the
+// named attribute doesn't exist. Like UseAttribute, defaults are only
+// consulted when given:
+//
+//	color, err := graph.GetAttribute[string](node.Attributes, "color", g.NodeDefaults)
+func GetAttribute[T any](attrs Attributes, name string, defaults ...Attributes) (T, error) {
 	var (
 		v   T
 		err error
 	)
 	err = UseAttribute(attrs, name, func(value T) {
 		v = value
-	})
+	}, defaults...)
 	if err != nil {
 		return v, fmt.Errorf("failed to get attribute: %w", err)
 	}
 	return v, nil
 }
 
+// Attr returns the named attribute of the node, falling back to the
+// NodeDefaults of the graph the node belongs to, if any, when the node
+// doesn't have it.
+func (n *Node) Attr(name string) (any, bool) {
+	if v, ok := n.Attributes[name]; ok {
+		return v, true
+	}
+	if n.graph != nil {
+		v, ok := n.graph.NodeDefaults[name]
+		return v, ok
+	}
+	return nil, false
+}
+
+// Attr returns the named attribute of the edge, falling back to the
+// EdgeDefaults of the graph of the node the edge belongs to, if any, when
+// the edge doesn't have it.
+func (e *Edge) Attr(name string) (any, bool) {
+	if v, ok := e.Attributes[name]; ok {
+		return v, true
+	}
+	if e.from != nil && e.from.graph != nil {
+		v, ok := e.from.graph.EdgeDefaults[name]
+		return v, ok
+	}
+	return nil, false
+}
+
 // SetAttribute is a helper function to set a named attribute.
 func SetAttribute[T any](attrs Attributes, name string, value T) {
 	attrs[name] = value
@@ -43,3 +88,35 @@ func SetAttribute[T any](attrs Attributes, name string, value T) {
 func DeleteAttribute[T any](attrs Attributes, name string) {
 	delete(attrs, name)
 }
+
+// toFloat converts the given numeric attribute value to a float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
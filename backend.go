@@ -0,0 +1,452 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+)
+
+// NodeRef is a reference to a node in a Graph backend.
+//
+// References must be comparable, so they can be used as map keys, and the
+// same node must always be referenced by an equal value.
+type NodeRef interface {
+	// Key returns the name of the node.
+	Key() string
+
+	// Attrs returns the attributes of the node.
+	Attrs() Attributes
+}
+
+// EdgeRef is a reference to a directed edge in a Graph backend.
+type EdgeRef interface {
+	// Source returns the node the edge starts from.
+	Source() NodeRef
+
+	// Target returns the node the edge points to.
+	Target() NodeRef
+
+	// Weight returns the numeric weight of the edge.
+	Weight() float64
+}
+
+// Graph is the interface implemented by graph backends, like Instance,
+// CompactGraph, and the disk package, which allows algorithms to be written
+// once and run against any of them.
+//
+// Undirected (None) and bi-directional (Both) edges are reported as outward
+// edges of both of their nodes.
+type Graph interface {
+	// NodeCount returns the number of nodes in the graph.
+	NodeCount() int
+
+	// EachNode calls the given function for each node in the graph, in a
+	// stable order, until it returns false.
+	EachNode(fn func(NodeRef) bool)
+
+	// FindNode returns a node with the given key.
+	FindNode(key string) (NodeRef, bool)
+
+	// OutEdges returns the edges starting from the given node.
+	OutEdges(n NodeRef) []EdgeRef
+
+	// InEdges returns the edges pointing to the given node.
+	InEdges(n NodeRef) []EdgeRef
+}
+
+var (
+	_ Graph   = (*Instance)(nil)
+	_ Graph   = (*CompactGraph)(nil)
+	_ NodeRef = (*Node)(nil)
+	_ EdgeRef = (*Edge)(nil)
+)
+
+// Key returns the name of the node, implementing the NodeRef interface.
+func (n *Node) Key() string {
+	return n.Name
+}
+
+// Attrs returns the attributes of the node, implementing the NodeRef interface.
+func (n *Node) Attrs() Attributes {
+	return n.Attributes
+}
+
+// Source returns the node the edge starts from, implementing the EdgeRef
+// interface. This is the same as From.
+func (e *Edge) Source() NodeRef {
+	if e.from == nil {
+		return nil
+	}
+	return e.from
+}
+
+// Target returns the node the edge points to, implementing the EdgeRef
+// interface. This is the same as the edge's Node.
+func (e *Edge) Target() NodeRef {
+	return e.Node
+}
+
+// NodeCount returns the number of nodes in the graph instance.
+func (inst *Instance) NodeCount() int {
+	return len(inst.Nodes)
+}
+
+// EachNode calls the given function for each node in the graph instance
+// until it returns false.
+func (inst *Instance) EachNode(fn func(NodeRef) bool) {
+	for _, node := range inst.Nodes {
+		if !fn(node) {
+			return
+		}
+	}
+}
+
+// FindNode returns the first node in the graph instance with the given name,
+// see Lookup.
+func (inst *Instance) FindNode(key string) (NodeRef, bool) {
+	node, ok := inst.Lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return node, true
+}
+
+// OutEdges returns the edges of the given node that aren't inward edges.
+func (inst *Instance) OutEdges(n NodeRef) []EdgeRef {
+	return outEdges(n, nil)
+}
+
+// outEdges returns the edges of the given node that aren't inward edges,
+// and are allowed by the given filter, if not nil.
+func outEdges(n NodeRef, filter EdgeFilter) []EdgeRef {
+	node, ok := n.(*Node)
+	if !ok {
+		return nil
+	}
+
+	var edges []EdgeRef
+	for _, edge := range node.Edges {
+		switch {
+		case edge.Direction == In:
+		case filter != nil && !filter(edge):
+		case edge.from == nil:
+			edges = append(edges, edgeRef{source: node, target: edge.Node, weight: edge.Weight()})
+		default:
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// InEdges returns the edges pointing to the given node, which are the other
+// sides of the node's edges that aren't outward edges.
+func (inst *Instance) InEdges(n NodeRef) []EdgeRef {
+	return inEdges(n, nil)
+}
+
+// inEdges returns the edges pointing to the given node, which are allowed
+// by the given filter, if not nil.
+func inEdges(n NodeRef, filter EdgeFilter) []EdgeRef {
+	node, ok := n.(*Node)
+	if !ok {
+		return nil
+	}
+
+	var edges []EdgeRef
+	for _, edge := range node.Edges {
+		if edge.Direction == Out || (filter != nil && !filter(edge)) {
+			continue
+		}
+		if other := edge.reciprocal(node); other != nil {
+			edges = append(edges, other)
+		} else {
+			edges = append(edges, edgeRef{source: edge.Node, target: node, weight: edge.Weight()})
+		}
+	}
+	return edges
+}
+
+// edgeRef is a simple EdgeRef, used by backends that don't store edge values.
+type edgeRef struct {
+	source, target NodeRef
+	weight         float64
+}
+
+func (e edgeRef) Source() NodeRef { return e.source }
+func (e edgeRef) Target() NodeRef { return e.target }
+func (e edgeRef) Weight() float64 { return e.weight }
+
+// compactNode is a reference to a node in a CompactGraph.
+type compactNode struct {
+	cg *CompactGraph
+	id int32
+}
+
+func (n compactNode) Key() string       { return n.cg.Name(n.id) }
+func (n compactNode) Attrs() Attributes { return n.cg.Attributes(n.id) }
+
+// NodeCount returns the number of nodes in the compact graph.
+func (cg *CompactGraph) NodeCount() int {
+	return cg.Len()
+}
+
+// EachNode calls the given function for each node in the compact graph, in
+// order of their IDs, until it returns false.
+func (cg *CompactGraph) EachNode(fn func(NodeRef) bool) {
+	for i := int32(0); int(i) < cg.Len(); i++ {
+		if !fn(compactNode{cg, i}) {
+			return
+		}
+	}
+}
+
+// FindNode returns the first node in the compact graph with the given name.
+func (cg *CompactGraph) FindNode(key string) (NodeRef, bool) {
+	id, ok := cg.Lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return compactNode{cg, id}, true
+}
+
+// OutEdges returns the outward edges of the given node, which all have a
+// weight of 1.
+func (cg *CompactGraph) OutEdges(n NodeRef) []EdgeRef {
+	node, ok := n.(compactNode)
+	if !ok || node.cg != cg {
+		return nil
+	}
+
+	var edges []EdgeRef
+	for _, id := range cg.Out(node.id) {
+		edges = append(edges, edgeRef{source: node, target: compactNode{cg, id}, weight: 1})
+	}
+	return edges
+}
+
+// InEdges returns the inward edges of the given node, which all have a
+// weight of 1.
+func (cg *CompactGraph) InEdges(n NodeRef) []EdgeRef {
+	node, ok := n.(compactNode)
+	if !ok || node.cg != cg {
+		return nil
+	}
+
+	var edges []EdgeRef
+	for _, id := range cg.In(node.id) {
+		edges = append(edges, edgeRef{source: compactNode{cg, id}, target: node, weight: 1})
+	}
+	return edges
+}
+
+// DepthFirst performs a depth-first-search of the given graph, starting at
+// the given node and following outward edges. The given function can return
+// false to stop traversal.
+func DepthFirst(g Graph, start NodeRef, fn func(NodeRef) bool) {
+	visited := map[NodeRef]bool{}
+
+	stack := []NodeRef{start}
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+
+		if !fn(n) {
+			return
+		}
+
+		out := g.OutEdges(n)
+		for i := len(out) - 1; i >= 0; i-- {
+			if next := out[i].Target(); !visited[next] {
+				stack = append(stack, next)
+			}
+		}
+	}
+}
+
+// BreadthFirst performs a breadth-first-search of the given graph, starting
+// at the given node and following outward edges. The given function can
+// return false to stop traversal.
+func BreadthFirst(g Graph, start NodeRef, fn func(NodeRef) bool) {
+	visited := map[NodeRef]bool{start: true}
+
+	queue := []NodeRef{start}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if !fn(n) {
+			return
+		}
+
+		for _, edge := range g.OutEdges(n) {
+			if next := edge.Target(); !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// ShortestPath returns the lightest path from the start node to the end
+// node of the given graph using Dijkstra's algorithm, along with its total
+// weight. Edge weights must not be negative.
+//
+// If there is no path, a nil path and an infinite weight are returned.
+//
+// https://en.wikipedia.org/wiki/Dijkstra%27s_algorithm
+func ShortestPath(g Graph, start, end NodeRef) ([]NodeRef, float64) {
+	dist := map[NodeRef]float64{start: 0}
+	parents := map[NodeRef]NodeRef{}
+	done := map[NodeRef]bool{}
+
+	queue := &refQueue{{node: start}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(refItem)
+		if done[item.node] {
+			continue
+		}
+		done[item.node] = true
+
+		if item.node == end {
+			path := []NodeRef{end}
+			for at := end; at != start; {
+				at = parents[at]
+				path = append(path, at)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, item.dist
+		}
+
+		for _, edge := range g.OutEdges(item.node) {
+			next := edge.Target()
+			d := item.dist + edge.Weight()
+			if current, ok := dist[next]; !ok || d < current {
+				dist[next] = d
+				parents[next] = item.node
+				heap.Push(queue, refItem{node: next, dist: d})
+			}
+		}
+	}
+
+	return nil, math.Inf(1)
+}
+
+// refItem is a node and its distance in a refQueue.
+type refItem struct {
+	node NodeRef
+	dist float64
+}
+
+// refQueue is a priority queue of nodes ordered by distance, implementing
+// the heap.Interface interface.
+type refQueue []refItem
+
+func (q refQueue) Len() int           { return len(q) }
+func (q refQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q refQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *refQueue) Push(x any)        { *q = append(*q, x.(refItem)) }
+
+func (q *refQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// BridgesOf returns the bridges of the given graph, the edges whose removal
+// would increase its number of (weakly) connected components, using Tarjan's
+// bridge-finding algorithm.
+//
+// Edges are treated as undirected, so parallel edges and edges in both
+// directions between the same pair of nodes count as a single connection,
+// which is represented by the first such outward edge found.
+//
+// https://en.wikipedia.org/wiki/Bridge_(graph_theory)#Tarjan's_bridge-finding_algorithm
+func BridgesOf(g Graph) []EdgeRef {
+	type pair struct{ a, b NodeRef }
+
+	var (
+		order     []NodeRef
+		neighbors = map[NodeRef][]NodeRef{}
+		edges     = map[pair]EdgeRef{}
+	)
+
+	g.EachNode(func(n NodeRef) bool {
+		order = append(order, n)
+		return true
+	})
+
+	for _, n := range order {
+		for _, edge := range g.OutEdges(n) {
+			target := edge.Target()
+			if target == n {
+				continue
+			}
+			if _, ok := edges[pair{n, target}]; ok {
+				continue
+			}
+			if _, ok := edges[pair{target, n}]; ok {
+				continue
+			}
+			neighbors[n] = append(neighbors[n], target)
+			neighbors[target] = append(neighbors[target], n)
+			edges[pair{n, target}] = edge
+		}
+	}
+
+	var (
+		bridges []EdgeRef
+		counter int
+		index   = map[NodeRef]int{}
+		low     = map[NodeRef]int{}
+	)
+
+	var visit func(n, parent NodeRef)
+	visit = func(n, parent NodeRef) {
+		counter++
+		index[n], low[n] = counter, counter
+
+		for _, next := range neighbors[n] {
+			if next == parent {
+				continue
+			}
+			if _, ok := index[next]; ok {
+				if index[next] < low[n] {
+					low[n] = index[next]
+				}
+				continue
+			}
+
+			visit(next, n)
+
+			if low[next] < low[n] {
+				low[n] = low[next]
+			}
+
+			if low[next] > index[n] {
+				if edge, ok := edges[pair{n, next}]; ok {
+					bridges = append(bridges, edge)
+				} else {
+					bridges = append(bridges, edges[pair{next, n}])
+				}
+			}
+		}
+	}
+
+	for _, n := range order {
+		if _, ok := index[n]; !ok {
+			visit(n, nil)
+		}
+	}
+
+	return bridges
+}
@@ -0,0 +1,109 @@
+package graph_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func refKeys(refs []graph.NodeRef) string {
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = ref.Key()
+	}
+	return strings.Join(keys, " → ")
+}
+
+func TestGraphBackends(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c → d ↔ e
+	// ↑       ↓
+	// └───────┘
+
+	graph.ConnectNodes(a, b, c, d)
+	c.AddEdge(a)
+	d.AddLink(e)
+
+	inst := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	backends := map[string]graph.Graph{
+		"instance": inst,
+		"compact":  graph.Compact(inst),
+	}
+
+	for name, g := range backends {
+		t.Run(name, func(t *testing.T) {
+			if g.NodeCount() != 5 {
+				t.Fatalf("expected 5 nodes, got %d", g.NodeCount())
+			}
+
+			start, ok := g.FindNode("a")
+			if !ok {
+				t.Fatalf("expected to find node a")
+			}
+
+			end, _ := g.FindNode("e")
+
+			var visited []graph.NodeRef
+			graph.DepthFirst(g, start, func(n graph.NodeRef) bool {
+				visited = append(visited, n)
+				return true
+			})
+
+			if keys := refKeys(visited); keys != "a → b → c → d → e" {
+				t.Fatalf("unexpected depth-first order: %s", keys)
+			}
+
+			path, weight := graph.ShortestPath(g, start, end)
+			if keys := refKeys(path); keys != "a → b → c → d → e" || weight != 4 {
+				t.Fatalf("unexpected shortest path %s with weight %v", keys, weight)
+			}
+
+			c, _ := g.FindNode("c")
+			if len(g.InEdges(c)) != 1 || g.InEdges(c)[0].Source().Key() != "b" {
+				t.Fatalf("expected c to have one inward edge from b")
+			}
+
+			var bridges []string
+			for _, edge := range graph.BridgesOf(g) {
+				bridges = append(bridges, fmt.Sprintf("%s-%s", edge.Source().Key(), edge.Target().Key()))
+			}
+
+			if strings.Join(bridges, ", ") != "d-e, c-d" {
+				t.Fatalf("unexpected bridges: %v", bridges)
+			}
+		})
+	}
+}
+
+func TestShortestPath_weighted(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddWeightedEdge(c, 5)
+	a.AddWeightedEdge(b, 1)
+	b.AddWeightedEdge(c, 2)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	path, weight := graph.ShortestPath(g, a, c)
+	if keys := refKeys(path); keys != "a → b → c" || weight != 3 {
+		t.Fatalf("unexpected shortest path %s with weight %v", keys, weight)
+	}
+
+	if path, _ := graph.ShortestPath(g, c, a); path != nil {
+		t.Fatalf("did not expect a path from c to a: %s", refKeys(path))
+	}
+}
@@ -1,6 +1,5 @@
 package graph
 
-
 // FindBridges finds all "bridge" paths within a graph. An edge,
 // part of a path, is a bridge if and only if it is not contained
 // in any cycle. Therefore, a bridge cannot be a cycle chord.
@@ -29,11 +28,20 @@ package graph
 // connected components. Equivalently, an edge is a bridge
 // if and only if it is not contained in any cycle.
 //
+// Each bridge is returned as a path starting from the node it was found
+// from. For bridges returned as edges, see Instance.Bridges. Traversal
+// options, like WithEdgeFilter, can be given to only consider some edges.
+//
 // References
 // - https://en.wikipedia.org/wiki/Bridge_(graph_theory)
 // - https://en.wikipedia.org/wiki/Strongly_connected_component
 // - https://mathworld.wolfram.com/GraphBridge.html
-func FindBridges(root *Node) []Path {
+//
+// Deprecated: Use Instance.Bridges, which returns bridges as edges, found
+// in linear time.
+func FindBridges(root *Node, opts ...func(*VisitOrder)) []Path {
+	order := newVisitOrder(opts...)
+
 	bridges := Paths{}
 
 	var addUniqBridge = func(p Path) {
@@ -45,10 +53,12 @@ func FindBridges(root *Node) []Path {
 		}
 	}
 
-	root.VisitAll(func(n *Node) {
-		for _, edge := range n.Edges {
+	visitAll(root, order, func(n *Node) {
+		for _, edge := range order.filter(n.Edges) {
+			edgeNodeEdges := order.filter(edge.Node.Edges)
+
 			// First, skip edge nodes that themselves do not contain edges.
-			if len(edge.Node.Edges) == 0 {
+			if len(edgeNodeEdges) == 0 {
 				continue
 			}
 
@@ -76,8 +86,8 @@ func FindBridges(root *Node) []Path {
 			// 1. a → b → c → a
 			//
 
-			if len(edge.Node.Edges) == 1 {
-				path := edge.Node.PathTo(edge.Node.Edges[0].Node)
+			if len(edgeNodeEdges) == 1 {
+				path := edge.Node.PathTo(edgeNodeEdges[0].Node, opts...)
 				if len(path) > 0 {
 					addUniqBridge(path)
 				}
@@ -108,9 +118,9 @@ func FindBridges(root *Node) []Path {
 			// 1. c → d → e → c
 			//
 
-			for _, edgeNodeEdge := range edge.Node.Edges {
-				if !edgeNodeEdge.Node.HasPath(edge.Node) {
-					path := edge.Node.PathTo(edgeNodeEdge.Node)
+			for _, edgeNodeEdge := range edgeNodeEdges {
+				if !edgeNodeEdge.Node.HasPath(edge.Node, opts...) {
+					path := edge.Node.PathTo(edgeNodeEdge.Node, opts...)
 					if len(path) > 0 {
 						addUniqBridge(path)
 						continue
@@ -172,3 +182,40 @@ func FindBridges(root *Node) []Path {
 
 	return bridges
 }
+
+// Bridges finds all of the bridges within the graph instance, covering every
+// component instead of only the nodes reachable from a root node.
+//
+// Unlike FindBridges, edges are treated as undirected relationships, so a
+// pair of edges created using AddLink counts as a single relationship. This
+// makes the bi-directional barbell's c ↔ d link a bridge, since removing the
+// relationship disconnects the graph:
+//
+//	a           e
+//	↑ ⤡       ⤢ ↑
+//	|   c ↔ d   |   Bridges (1): c → d
+//	↓ ⤢       ⤡ ↓
+//	b           f
+//
+// Each bridge is returned as the first outward edge of the relationship
+// found, so its String is the same as the path of the two nodes it connects.
+// Bridges are found in linear time using BridgesOf.
+//
+// An edge filter can be given, see WithEdgeFilter, to find the bridges of
+// the relationships it allows, without copying the graph.
+func (inst *Instance) Bridges(opts ...func(*VisitOrder)) Edges {
+	defer inst.trace("Bridges")()
+
+	var g Graph = inst
+	if filter := newVisitOrder(opts...).edgeFilter(); filter != nil {
+		g = filteredGraph{Instance: inst, filter: filter}
+	}
+
+	refs := BridgesOf(g)
+
+	bridges := make(Edges, 0, len(refs))
+	for _, ref := range refs {
+		bridges = append(bridges, ref.(*Edge))
+	}
+	return bridges
+}
@@ -0,0 +1,88 @@
+package graph
+
+// Generation returns the generation of the graph, a counter incremented by
+// every change made using the methods of the graph, or of its nodes, which
+// is used to know when results cached by the graph are out of date.
+//
+// Changes made directly to fields, like appending to Nodes or Edges, don't
+// increment the generation, so Touch must be called after them. Results
+// cached by the graph are only checked against the number of nodes they
+// were computed for, to catch nodes appended to Nodes directly, since
+// checking every edge would cost as much as computing most results.
+func (inst *Instance) Generation() uint64 {
+	return inst.generation
+}
+
+// Touch increments the generation of the graph, discarding cached results
+// of queries like Components, StronglyConnectedComponents, and
+// TopologicalSort, after changing fields of the graph or its nodes
+// directly. The next version published by Write then compares every node
+// with the previous version.
+func (inst *Instance) Touch() {
+	inst.generation++
+	inst.published.changedAll()
+}
+
+// touchNode increments the generation of the graph, recording that the
+// given node changed for the next version published by Write.
+func (inst *Instance) touchNode(node *Node) {
+	inst.generation++
+	inst.published.changed(node)
+}
+
+// touch increments the generation of the graph the node belongs to, if any.
+func (n *Node) touch() {
+	if n != nil && n.graph != nil {
+		n.graph.touchNode(n)
+	}
+}
+
+// cacheShape is the state of a graph that the results it caches were
+// computed for: its generation, and its number of nodes.
+type cacheShape struct {
+	generation uint64
+	nodes      int
+}
+
+// newCacheShape returns the current state of the given graph.
+func newCacheShape(inst *Instance) cacheShape {
+	return cacheShape{generation: inst.generation, nodes: len(inst.Nodes)}
+}
+
+// memoize returns the result of the given function for the graph, computing
+// it only if it hasn't been computed for the current generation and
+// number of nodes of the graph yet, see Generation. Results are shared between
+// calls, so they should be copied before being returned to callers that
+// might change them.
+func memoize[T any](inst *Instance, key string, compute func() T) T {
+	inst.cacheMu.Lock()
+	if inst.cache != nil && inst.cacheShape != newCacheShape(inst) {
+		inst.cache = nil
+	}
+	value, ok := inst.cache[key]
+	inst.cacheMu.Unlock()
+
+	if ok {
+		return value.(T)
+	}
+
+	computed := compute()
+
+	inst.cacheMu.Lock()
+	if inst.cache == nil {
+		inst.cache, inst.cacheShape = map[string]any{}, newCacheShape(inst)
+	}
+	inst.cache[key] = computed
+	inst.cacheMu.Unlock()
+
+	return computed
+}
+
+// copyNodeSets returns a copy of the given node sets.
+func copyNodeSets(sets []NodeSet) []NodeSet {
+	copies := make([]NodeSet, len(sets))
+	for i, set := range sets {
+		copies[i] = set.Union(nil)
+	}
+	return copies
+}
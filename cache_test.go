@@ -0,0 +1,123 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Generation(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	mutations := []struct {
+		name string
+		fn   func()
+	}{
+		{"AddNode", func() { g.AddNode(c) }},
+		{"AddEdge", func() { a.AddEdge(b) }},
+		{"SetAttribute", func() { a.SetAttribute("color", "red") }},
+		{"DeleteAttribute", func() { a.DeleteAttribute("color") }},
+		{"AddLabel", func() { a.AddLabel("Service") }},
+		{"RemoveLabel", func() { a.RemoveLabel("Service") }},
+		{"RemoveEdge", func() { a.RemoveEdge(a.Edges[0]) }},
+		{"Reverse", func() { g.Reverse() }},
+		{"RemoveNode", func() { g.RemoveNode(c) }},
+		{"Touch", func() { g.Touch() }},
+	}
+
+	for _, m := range mutations {
+		before := g.Generation()
+		m.fn()
+		if g.Generation() <= before {
+			t.Fatalf("expected %s to increment the generation", m.name)
+		}
+	}
+}
+
+func TestInstance_cachedQueries(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	components := g.Components()
+	if len(components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(components))
+	}
+
+	// Results are copies, so changing them doesn't change the cache.
+	delete(components[0], a)
+
+	if !g.Components()[0].Contains(a) {
+		t.Fatal("expected cached components to be unchanged")
+	}
+
+	sorted, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted[0] = nil
+
+	sorted, err = g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sorted[0] != a {
+		t.Fatalf("expected cached sort to be unchanged, got: %v", sorted)
+	}
+
+	if n := len(g.StronglyConnectedComponents()); n != 3 {
+		t.Fatalf("expected 3 strongly connected components, got %d", n)
+	}
+
+	// Changes through the graph's methods invalidate cached results.
+	b.AddEdge(c)
+	c.AddEdge(a)
+
+	if n := len(g.Components()); n != 1 {
+		t.Fatalf("expected 1 component after adding edges, got %d", n)
+	}
+
+	if n := len(g.StronglyConnectedComponents()); n != 1 {
+		t.Fatalf("expected 1 strongly connected component after adding edges, got %d", n)
+	}
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fatal("expected an error sorting a cycle")
+	}
+
+	// Direct changes to edges need a call to Touch.
+	c.Edges = c.Edges[:1]
+	a.Edges = a.Edges[:1]
+	g.Touch()
+
+	if _, err := g.TopologicalSort(); err != nil {
+		t.Fatalf("expected no error after removing the cycle, got: %v", err)
+	}
+
+	// Nodes appended directly are detected without a call to Touch.
+	d := graph.NewNode("d", nil)
+	g.Nodes = append(g.Nodes, d)
+
+	if n := len(g.Components()); n != 2 {
+		t.Fatalf("expected 2 components after appending a node, got %d", n)
+	}
+
+	d.Edges = append(d.Edges, &graph.Edge{Node: a, Direction: graph.Out})
+	g.Touch()
+
+	if n := len(g.StronglyConnectedComponents()); n != 4 {
+		t.Fatalf("expected 4 strongly connected components after appending an edge, got %d", n)
+	}
+}
@@ -42,25 +42,31 @@ func (cliques Cliques) ContainsNode(n *Node) bool {
 //
 // Cliques: [1] {c, e, d}
 //
+// Traversal options, like WithEdgeFilter, can be given to only consider
+// some edges.
+//
 // References
 // - https://en.wikipedia.org/wiki/Clique_(graph_theory)
 // - https://en.wikipedia.org/wiki/Induced_subgraph
 // - https://en.wikipedia.org/wiki/Complete_graph
 // - https://mathworld.wolfram.com/Clique.html
-func FindCliques(root *Node, minSize int) Cliques {
+func FindCliques(root *Node, minSize int, opts ...func(*VisitOrder)) Cliques {
+	order := newVisitOrder(opts...)
+
 	cliques := Cliques{}
 
-	root.VisitAll(func(n *Node) {
-		if len(n.Edges) == 0 {
+	visitAll(root, order, func(n *Node) {
+		edges := order.filter(n.Edges)
+		if len(edges) == 0 {
 			return
 		}
 
 		clique := Clique{}
 		clique.Add(n)
 
-		for _, edge := range n.Edges {
-			for _, otherEdge := range n.Edges.ButNotWith(edge.Node) {
-				if otherEdge.Node.Edges.AdjacentTo(clique.Nodes()...) {
+		for _, edge := range edges {
+			for _, otherEdge := range edges.ButNotWith(edge.Node) {
+				if order.filter(otherEdge.Node.Edges).AdjacentTo(clique.Nodes()...) {
 					clique.Add(otherEdge.Node)
 				}
 			}
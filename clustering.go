@@ -0,0 +1,111 @@
+package graph
+
+import "sort"
+
+// TriangleCount returns the number of triangles in the graph, sets of three
+// nodes that are all adjacent to each other, ignoring edge directions.
+//
+//	a ── b
+//	│  ╱ │    Triangles: 2 ({a, b, c} and {b, c, d})
+//	c ── d
+//
+// Nodes are ordered by degree, and each triangle is only counted from its
+// lowest ordered node by intersecting the neighbors ordered after it, which
+// keeps the work done for high degree nodes small.
+//
+// https://en.wikipedia.org/wiki/Clustering_coefficient
+func (inst *Instance) TriangleCount() int {
+	defer inst.trace("TriangleCount")()
+
+	neighbors := undirectedNeighbors(inst.Nodes)
+
+	order := make(Nodes, len(inst.Nodes))
+	copy(order, inst.Nodes)
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(neighbors[order[i]]) < len(neighbors[order[j]])
+	})
+
+	rank := make(map[*Node]int, len(order))
+	for i, node := range order {
+		rank[node] = i
+	}
+
+	// The neighbors of each node that are ordered after it.
+	forward := make(map[*Node]NodeSet, len(order))
+	for _, node := range order {
+		forward[node] = NodeSet{}
+		for neighbor := range neighbors[node] {
+			if rank[neighbor] > rank[node] {
+				forward[node].Add(neighbor)
+			}
+		}
+	}
+
+	var count int
+
+	for _, node := range order {
+		for v := range forward[node] {
+			for w := range forward[v] {
+				if forward[node].Contains(w) {
+					count++
+				}
+			}
+		}
+	}
+
+	return count
+}
+
+// ClusteringCoefficient returns the local clustering coefficient of the
+// node, the fraction of pairs of its neighbors that are adjacent to each
+// other, ignoring edge directions and edges to itself. Nodes with fewer
+// than two neighbors have a coefficient of 0.
+//
+//	a ── b
+//	│  ╱ │    b: 2 of 3 neighbor pairs are adjacent, 0.67
+//	c ── d
+//
+// https://en.wikipedia.org/wiki/Clustering_coefficient
+func (n *Node) ClusteringCoefficient() float64 {
+	neighbors := NodeSet{}
+	for _, edge := range n.Edges {
+		if edge.Node != n {
+			neighbors.Add(edge.Node)
+		}
+	}
+
+	k := len(neighbors)
+	if k < 2 {
+		return 0
+	}
+
+	var links int
+	for neighbor := range neighbors {
+		adjacent := NodeSet{}
+		for _, edge := range neighbor.Edges {
+			if edge.Node != neighbor && neighbors.Contains(edge.Node) {
+				adjacent.Add(edge.Node)
+			}
+		}
+		links += len(adjacent)
+	}
+
+	// Each link between neighbors was counted from both of its sides.
+	return float64(links) / float64(k*(k-1))
+}
+
+// AverageClusteringCoefficient returns the mean of the local clustering
+// coefficients of the nodes in the graph, or 0 for an empty graph.
+func (inst *Instance) AverageClusteringCoefficient() float64 {
+	if len(inst.Nodes) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, node := range inst.Nodes {
+		total += node.ClusteringCoefficient()
+	}
+
+	return total / float64(len(inst.Nodes))
+}
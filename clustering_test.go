@@ -0,0 +1,64 @@
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_TriangleCount(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a ── b
+	// │  ╱ │
+	// c ── d ── e
+
+	a.AddLink(b)
+	a.AddLink(c)
+	b.AddLink(c)
+	b.AddLink(d)
+	c.AddLink(d)
+	d.AddLink(e)
+
+	// Parallel edges and edges in both directions don't add triangles.
+	b.AddEdge(a)
+	c.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	if count := g.TriangleCount(); count != 2 {
+		t.Fatalf("expected 2 triangles, got %d", count)
+	}
+
+	tests := []struct {
+		Node     *graph.Node
+		Expected float64
+	}{
+		{a, 1},
+		{b, 2.0 / 3.0},
+		{c, 2.0 / 3.0},
+		{d, 1.0 / 3.0},
+		{e, 0},
+	}
+
+	for _, test := range tests {
+		if coefficient := test.Node.ClusteringCoefficient(); math.Abs(coefficient-test.Expected) > 1e-9 {
+			t.Fatalf("%s: expected clustering coefficient %v, got %v", test.Node.Name, test.Expected, coefficient)
+		}
+	}
+
+	if average := g.AverageClusteringCoefficient(); math.Abs(average-8.0/15.0) > 1e-9 {
+		t.Fatalf("unexpected average clustering coefficient: %v", average)
+	}
+
+	if average := graph.New("empty").AverageClusteringCoefficient(); average != 0 {
+		t.Fatalf("unexpected average clustering coefficient for empty graph: %v", average)
+	}
+}
@@ -0,0 +1,434 @@
+// Command graph reads, converts, and inspects graphs from the shell.
+//
+//	graph convert [-from format] [-to format] [file]
+//	graph stats [-from format] [file]
+//	graph components [-from format] [-strong] [file]
+//	graph bridges [-from format] [file]
+//	graph path [-from format] [-weighted] file start end
+//	graph render [-from format] [-format dot|svg|png] [file]
+//	graph repl [-from format] [file]
+//	graph diff [-from format] [-format text|markdown|json] before after
+//
+// Graphs are read from the given file, or from standard input if no file,
+// or "-", is given. The format of the input is detected from the file's
+// extension, unless it is given using -from. Supported formats are "json",
+// "jgf", the JSON Graph Format, "dot", "yaml", "tgf", "csv", an edge list
+// of "from,to" records, with an optional "from,to" header, describing
+// directed edges, and "txt", an edge list with the names of the nodes of
+// each edge, and an optional weight, separated by spaces, as datasets are
+// often distributed in. Graphs can also be written as "cytoscape", the
+// elements JSON used by Cytoscape.js.
+//
+// Results are written to standard output, one per line, so they can be
+// used in shell pipelines:
+//
+//	$ graph convert -to dot deps.json | graph components -from dot
+//	$ graph render -format svg deps.csv > deps.svg
+//
+// Rendering to SVG or PNG requires the Graphviz "dot" command.
+//
+// The repl command starts an interactive shell, see package shell, to
+// change and inspect the graph in the given file, or an empty graph,
+// reading commands from standard input:
+//
+//	$ graph repl deps.json
+//	graph> path a c
+//	a → b → c
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/shell"
+)
+
+// usage is the usage message of the command.
+const usage = `usage: graph <command> [flags] [file]
+
+commands:
+  convert      convert a graph between formats
+  stats        print statistics about a graph
+  components   print the connected components of a graph
+  bridges      print the bridges of a graph
+  path         print the shortest path between two nodes
+  render       render a graph using Graphviz
+  repl         start an interactive shell
+  diff         report the changes between two graphs
+
+formats: json, jgf, dot, yaml, tgf, csv, txt (input only), cytoscape (output only)
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "graph: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned when the command is used incorrectly.
+var errUsage = errors.New("invalid usage, see graph help")
+
+// run runs the command with the given arguments, reading graphs from the
+// given input when no file is given, and writing results to the given
+// output.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	from := flags.String("from", "", "the format of the input")
+
+	var (
+		to       *string
+		format   *string
+		strong   *bool
+		weighted *bool
+	)
+
+	switch cmd {
+	case "help", "-h", "-help", "--help":
+		_, err := io.WriteString(stdout, usage)
+		return err
+	case "convert":
+		to = flags.String("to", "json", "the format of the output")
+	case "components":
+		strong = flags.Bool("strong", false, "print strongly connected components")
+	case "path":
+		weighted = flags.Bool("weighted", false, "use edge weights")
+	case "render":
+		format = flags.String("format", "svg", "the output format: dot, svg, or png")
+	case "diff":
+		format = flags.String("format", "text", "the report format: text, markdown, or json")
+	case "stats", "bridges", "repl":
+	default:
+		return fmt.Errorf("unknown command %q: %w", cmd, errUsage)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%v: %w", err, errUsage)
+	}
+	args = flags.Args()
+
+	var file string
+	switch {
+	case cmd == "path" && len(args) == 3:
+		file, args = args[0], args[1:]
+	case cmd == "path":
+		return fmt.Errorf("path needs a file, and start and end nodes: %w", errUsage)
+	case cmd == "diff" && len(args) == 2:
+		file, args = args[0], args[1:]
+	case cmd == "diff":
+		return fmt.Errorf("diff needs the before and after files: %w", errUsage)
+	case len(args) == 1:
+		file = args[0]
+	case len(args) > 1:
+		return fmt.Errorf("too many arguments: %w", errUsage)
+	}
+
+	if cmd == "repl" {
+		if file == "-" {
+			return fmt.Errorf("repl reads commands from standard input, not a graph: %w", errUsage)
+		}
+
+		g := graph.New("shell")
+		if file != "" {
+			var err error
+			if g, err = readGraph(file, *from, nil); err != nil {
+				return err
+			}
+		}
+		return shell.New(g, shell.WithPrompt("graph> ")).Run(stdin, stdout)
+	}
+
+	g, err := readGraph(file, *from, stdin)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "convert":
+		return writeGraph(stdout, g, *to)
+	case "stats":
+		return printStats(stdout, g)
+	case "components":
+		components := g.Components()
+		if *strong {
+			components = g.StronglyConnectedComponents()
+		}
+		for _, component := range components {
+			var names []string
+			for _, node := range component.Sorted() {
+				names = append(names, node.Name)
+			}
+			if _, err := fmt.Fprintln(stdout, strings.Join(names, " ")); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "bridges":
+		for _, bridge := range g.Bridges() {
+			if _, err := fmt.Fprintln(stdout, bridge); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "path":
+		return printPath(stdout, g, args[0], args[1], *weighted)
+	case "render":
+		return render(stdout, g, *format)
+	case "diff":
+		after, err := readGraph(args[0], *from, stdin)
+		if err != nil {
+			return err
+		}
+		return graph.Compare(g, after).Render(stdout, graph.DiffFormat(*format))
+	}
+
+	return nil
+}
+
+// readGraph reads a graph from the given file, or the given input if the
+// file is empty or "-", using the given format, or the format detected from
+// the file's extension.
+func readGraph(file, format string, stdin io.Reader) (*graph.Instance, error) {
+	r := stdin
+	name := "stdin"
+
+	if file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		r = f
+		name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+
+	var (
+		nodes graph.Nodes
+		err   error
+	)
+
+	switch strings.ToLower(format) {
+	case "json":
+		nodes, err = graph.DecodeJSON(r)
+	case "dot", "gv":
+		nodes, err = graph.DecodeDOT(r)
+	case "yaml", "yml":
+		g, err := graph.DecodeYAML(r)
+		if err != nil {
+			return nil, err
+		}
+		g.Name = name
+		return g, nil
+	case "jgf":
+		g, err := graph.DecodeJGF(r)
+		if err != nil {
+			return nil, err
+		}
+		if g.Name == "" {
+			g.Name = name
+		}
+		return g, nil
+	case "tgf":
+		nodes, err = graph.DecodeTGF(r)
+	case "txt", "edges":
+		nodes, err = graph.DecodeEdgeList(r)
+	case "csv":
+		g := graph.New(name)
+		return g, decodeCSV(r, g)
+	case "":
+		return nil, fmt.Errorf("unknown input format, use -from: %w", errUsage)
+	default:
+		return nil, fmt.Errorf("unsupported input format %q: %w", format, errUsage)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.New(name, graph.WithNodes(nodes)), nil
+}
+
+// writeGraph writes the graph to the given output using the given format.
+func writeGraph(w io.Writer, g *graph.Instance, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		return graph.EncodeJSON(w, g.Nodes)
+	case "dot", "gv":
+		return g.EncodeDOT(w)
+	case "yaml", "yml":
+		return graph.EncodeYAML(w, g)
+	case "jgf":
+		return graph.EncodeJGF(w, g)
+	case "cytoscape":
+		return graph.EncodeCytoscapeJSON(w, g)
+	case "tgf":
+		return graph.EncodeTGF(w, g.Nodes)
+	case "csv":
+		return encodeCSV(w, g)
+	default:
+		return fmt.Errorf("unsupported output format %q: %w", format, errUsage)
+	}
+}
+
+// csvHeader is the optional header of CSV edge lists.
+var csvHeader = []string{"from", "to"}
+
+// decodeCSV adds the directed edges of the CSV edge list read from the
+// given input to the graph.
+func decodeCSV(r io.Reader, g *graph.Instance) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	pairs := make([][2]string, 0, len(records))
+	for i, record := range records {
+		if i == 0 && len(record) == 2 && record[0] == csvHeader[0] && record[1] == csvHeader[1] {
+			continue
+		}
+		if len(record) != 2 {
+			return fmt.Errorf("CSV record %d has %d fields, expected 2", i+1, len(record))
+		}
+		pairs = append(pairs, [2]string{record[0], record[1]})
+	}
+
+	return g.LoadEdges(pairs)
+}
+
+// encodeCSV writes the graph's edges as a CSV edge list with a header.
+// Undirected and bi-directional edges are written once, like Edges.
+func encodeCSV(w io.Writer, g *graph.Instance) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, edge := range g.Edges() {
+		from, to := edge.From(), edge.Node
+		if edge.Direction == graph.In {
+			from, to = to, from
+		}
+		if err := cw.Write([]string{from.Name, to.Name}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// printStats writes statistics about the graph.
+func printStats(w io.Writer, g *graph.Instance) error {
+	stats := []struct {
+		name  string
+		value any
+	}{
+		{"name", g.Name},
+		{"nodes", g.NodeCount()},
+		{"edges", g.EdgeCount()},
+		{"components", len(g.Components())},
+		{"strongly connected components", len(g.StronglyConnectedComponents())},
+		{"bridges", len(g.Bridges())},
+		{"density", fmt.Sprintf("%.4f", g.Density())},
+		{"acyclic", g.IsAcyclic()},
+	}
+
+	for _, stat := range stats {
+		if _, err := fmt.Fprintf(w, "%s: %v\n", stat.name, stat.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printPath writes the shortest path between the given nodes, by the number
+// of edges, or by their weights.
+func printPath(w io.Writer, g *graph.Instance, start, end string, weighted bool) error {
+	from, ok := g.Lookup(start)
+	if !ok {
+		return fmt.Errorf("node %q not found", start)
+	}
+
+	to, ok := g.Lookup(end)
+	if !ok {
+		return fmt.Errorf("node %q not found", end)
+	}
+
+	var path graph.Path
+	if weighted {
+		var weight float64
+		path, weight = from.UniformCostSearch(func(n *graph.Node) bool { return n == to }, nil)
+		if path != nil {
+			_, err := fmt.Fprintf(w, "%s (%g)\n", path, weight)
+			return err
+		}
+	} else {
+		path = from.PathToAvoiding(to, nil, nil)
+	}
+
+	if path == nil {
+		return fmt.Errorf("no path from %q to %q", start, end)
+	}
+
+	_, err := fmt.Fprintln(w, path)
+	return err
+}
+
+// render writes the graph using the given format, using the Graphviz "dot"
+// command to render formats other than DOT.
+func render(w io.Writer, g *graph.Instance, format string) error {
+	var buf bytes.Buffer
+	if err := g.EncodeDOT(&buf); err != nil {
+		return err
+	}
+
+	if format == "dot" {
+		_, err := buf.WriteTo(w)
+		return err
+	}
+
+	switch format {
+	case "svg", "png":
+	default:
+		return fmt.Errorf("unsupported render format %q: %w", format, errUsage)
+	}
+
+	dot, err := exec.LookPath("dot")
+	if err != nil {
+		return fmt.Errorf("rendering %s requires Graphviz: %w", format, err)
+	}
+
+	cmd := exec.Command(dot, "-T"+format)
+	cmd.Stdin = &buf
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	// a → b → c    d
+
+	csv := filepath.Join(dir, "deps.csv")
+	if err := os.WriteFile(csv, []byte("from,to\na,b\nb,c\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dot := filepath.Join(dir, "deps.dot")
+	if err := os.WriteFile(dot, []byte("digraph { a -> b -> c; d }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{
+			args:     []string{"convert", "-to", "dot", csv},
+			expected: "digraph {\n\t\"a\"\n\t\"b\"\n\t\"c\"\n\t\"a\" -> { \"b\" }\n\t\"b\" -> { \"c\" }\n}\n",
+		},
+		{
+			args:     []string{"convert", "-from", "dot", "-to", "csv"},
+			stdin:    "digraph { a -> b -> c; d }",
+			expected: "from,to\na,b\nb,c\n",
+		},
+		{
+			args:     []string{"convert", "-to", "tgf", csv},
+			expected: "1 a\n2 b\n3 c\n#\n1 2\n2 3\n",
+		},
+		{
+			args:     []string{"convert", "-from", "jgf", "-to", "csv"},
+			stdin:    `{"graph": {"nodes": {"a": {}, "b": {}}, "edges": [{"source": "a", "target": "b"}]}}`,
+			expected: "from,to\na,b\n",
+		},
+		{
+			args:     []string{"convert", "-from", "txt", "-to", "cytoscape"},
+			stdin:    "a b\n",
+			expected: "{\n  \"data\": {\n    \"name\": \"stdin\"\n  },\n  \"elements\": {\n    \"nodes\": [\n      {\n        \"data\": {\n          \"id\": \"a\"\n        }\n      },\n      {\n        \"data\": {\n          \"id\": \"b\"\n        }\n      }\n    ],\n    \"edges\": [\n      {\n        \"data\": {\n          \"id\": \"e0\",\n          \"source\": \"a\",\n          \"target\": \"b\"\n        }\n      }\n    ]\n  }\n}\n",
+		},
+		{
+			args:     []string{"convert", "-from", "txt", "-to", "csv"},
+			stdin:    "# dataset\n1 2\n2 3\n",
+			expected: "from,to\n1,2\n2,3\n",
+		},
+		{
+			args:     []string{"stats", dot},
+			expected: "name: deps\nnodes: 4\nedges: 2\ncomponents: 2\nstrongly connected components: 4\nbridges: 2\ndensity: 0.1667\nacyclic: true\n",
+		},
+		{
+			args:     []string{"components", dot},
+			expected: "a b c\nd\n",
+		},
+		{
+			args:     []string{"bridges", csv},
+			expected: "b → c\na → b\n",
+		},
+		{
+			args:     []string{"path", dot, "a", "c"},
+			expected: "a → b → c\n",
+		},
+		{
+			args:     []string{"path", "-weighted", csv, "a", "c"},
+			expected: "a → b → c (2)\n",
+		},
+		{
+			args:     []string{"diff", csv, dot},
+			expected: "+ node d\n",
+		},
+		{
+			args:     []string{"repl", csv},
+			stdin:    "path a c\n",
+			expected: "graph> a → b → c\ngraph> ",
+		},
+		{
+			args:     []string{"render", "-format", "dot", csv},
+			expected: "digraph {\n\t\"a\"\n\t\"b\"\n\t\"c\"\n\t\"a\" -> { \"b\" }\n\t\"b\" -> { \"c\" }\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.args[:1], " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := run(test.args, strings.NewReader(test.stdin), &stdout)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if stdout.String() != test.expected {
+				t.Fatalf("got:\n%s\nexpected:\n%s", stdout.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"stats"},
+		{"stats", "-from", "xml"},
+		{"path", "-from", "dot", "-", "a"},
+		{"diff", "-"},
+		{"convert", "-from", "csv", "-to", "xml"},
+	} {
+		err := run(args, strings.NewReader("a,b\n"), &bytes.Buffer{})
+		if !errors.Is(err, errUsage) {
+			t.Fatalf("expected a usage error for %q, got: %v", args, err)
+		}
+	}
+
+	err := run([]string{"path", "-from", "csv", "-", "b", "a"}, strings.NewReader("a,b\n"), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "no path") {
+		t.Fatalf("expected no path, got: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// attributeTypeKey and attributeValueKey are the keys of the JSON object
+// used to encode attribute values of registered types.
+const (
+	attributeTypeKey  = "$type"
+	attributeValueKey = "value"
+)
+
+// attributeTypes is the registry of attribute value types that keep their
+// type when encoded and decoded as JSON.
+var attributeTypes = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: map[string]reflect.Type{},
+	byType: map[reflect.Type]string{},
+}
+
+// RegisterAttributeType registers a type of attribute value using the given
+// name, so values of the type keep their type when encoded and decoded as
+// JSON, instead of being decoded as generic maps, slices, or strings.
+//
+//	type Owner struct{ Team string }
+//
+//	graph.RegisterAttributeType[Owner]("owner")
+//	graph.RegisterAttributeType[time.Time]("time")
+//
+// Values of registered types are encoded as an object with the type name
+// and the value's own JSON encoding, which can be customized by implementing
+// the json.Marshaler and json.Unmarshaler interfaces:
+//
+//	{"$type": "owner", "value": {"Team": "platform"}}
+//
+// Only attribute values themselves are handled, not registered values
+// nested in other values. Registering a name or type again replaces it.
+func RegisterAttributeType[T any](name string) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	attributeTypes.Lock()
+	defer attributeTypes.Unlock()
+
+	if old, ok := attributeTypes.byName[name]; ok {
+		delete(attributeTypes.byType, old)
+	}
+	if old, ok := attributeTypes.byType[typ]; ok {
+		delete(attributeTypes.byName, old)
+	}
+
+	attributeTypes.byName[name] = typ
+	attributeTypes.byType[typ] = name
+}
+
+// marshalAttributes returns a copy of the given attributes with the values
+// of registered types wrapped with their type name, ready to encode.
+func marshalAttributes(attrs Attributes) (Attributes, error) {
+	attributeTypes.RLock()
+	defer attributeTypes.RUnlock()
+
+	if len(attrs) == 0 || len(attributeTypes.byType) == 0 {
+		return attrs, nil
+	}
+
+	var wrapped Attributes
+
+	for k, v := range attrs {
+		name, ok := attributeTypes.byType[reflect.TypeOf(v)]
+		if !ok {
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("graph failed to marshal attribute %q: %w", k, err)
+		}
+
+		if wrapped == nil {
+			wrapped = copyAttributes(attrs)
+		}
+		wrapped[k] = map[string]any{
+			attributeTypeKey:  name,
+			attributeValueKey: json.RawMessage(b),
+		}
+	}
+
+	if wrapped == nil {
+		return attrs, nil
+	}
+	return wrapped, nil
+}
+
+// unmarshalAttributes replaces the wrapped values of registered types in the
+// given decoded attributes with values of their type.
+func unmarshalAttributes(attrs Attributes) error {
+	attributeTypes.RLock()
+	defer attributeTypes.RUnlock()
+
+	for k, v := range attrs {
+		m, ok := v.(map[string]any)
+		if !ok || len(m) != 2 {
+			continue
+		}
+
+		name, ok := m[attributeTypeKey].(string)
+		if !ok {
+			continue
+		}
+
+		typ, ok := attributeTypes.byName[name]
+		if !ok {
+			continue
+		}
+
+		b, err := json.Marshal(m[attributeValueKey])
+		if err != nil {
+			return fmt.Errorf("graph failed to unmarshal attribute %q: %w", k, err)
+		}
+
+		value := reflect.New(typ)
+		if err := json.Unmarshal(b, value.Interface()); err != nil {
+			return fmt.Errorf("graph failed to unmarshal attribute %q as %q: %w", k, name, err)
+		}
+
+		attrs[k] = value.Elem().Interface()
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package graph_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/picatz/graph"
+)
+
+type owner struct {
+	Team  string
+	Email string
+}
+
+func TestRegisterAttributeType(t *testing.T) {
+	graph.RegisterAttributeType[owner]("owner")
+	graph.RegisterAttributeType[time.Time]("time")
+
+	created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	a := graph.NewNode("a", graph.Attributes{
+		"owner":   owner{Team: "platform", Email: "platform@example.com"},
+		"created": created,
+		"plain":   map[string]any{"$type": "unknown", "value": 1.0},
+		"count":   3,
+	})
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := graph.EncodeJSON(buf, graph.Nodes{a}); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := graph.DecodeJSON(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attrs := nodes[0].Attributes
+
+	if o, ok := attrs["owner"].(owner); !ok || o.Team != "platform" {
+		t.Fatalf("expected owner to keep its type, got %T: %v", attrs["owner"], attrs["owner"])
+	}
+
+	if c, ok := attrs["created"].(time.Time); !ok || !c.Equal(created) {
+		t.Fatalf("expected created to keep its type, got %T: %v", attrs["created"], attrs["created"])
+	}
+
+	if _, ok := attrs["plain"].(map[string]any); !ok {
+		t.Fatalf("expected unregistered types to be left alone, got %T", attrs["plain"])
+	}
+
+	if attrs["count"] != 3.0 {
+		t.Fatalf("expected count to be decoded as a number, got %T", attrs["count"])
+	}
+}
@@ -0,0 +1,274 @@
+package graph
+
+import "strings"
+
+// CompactGraph is a read-only, memory efficient representation of a graph
+// instance using the compressed sparse row (CSR) format, where nodes are
+// identified by their int32 index.
+//
+// Each node's outward and inward neighbors are stored as contiguous ranges
+// of two shared slices, instead of individually allocated Node and Edge
+// values, which makes it well suited for analyzing large static graphs.
+//
+// https://en.wikipedia.org/wiki/Sparse_matrix#Compressed_sparse_row_(CSR,_CRS_or_Yale_format)
+type CompactGraph struct {
+	names []string
+	attrs []Attributes
+
+	outOffsets []int32
+	outTargets []int32
+
+	inOffsets []int32
+	inTargets []int32
+
+	index map[string]int32
+}
+
+// Compact returns a compact, read-only representation of the given graph.
+//
+// Every edge that isn't an inward edge is treated as a directed edge, so
+// undirected (None) and bi-directional (Both) edges can be followed in both
+// directions. Edges to nodes outside of the graph instance are ignored.
+func Compact(inst *Instance) *CompactGraph {
+	n := len(inst.Nodes)
+
+	cg := &CompactGraph{
+		names:      make([]string, n),
+		attrs:      make([]Attributes, n),
+		outOffsets: make([]int32, n+1),
+		inOffsets:  make([]int32, n+1),
+		index:      make(map[string]int32, n),
+	}
+
+	ids := make(map[*Node]int32, n)
+	for i, node := range inst.Nodes {
+		ids[node] = int32(i)
+		cg.names[i] = node.Name
+		cg.attrs[i] = node.Attributes
+		if _, ok := cg.index[node.Name]; !ok {
+			cg.index[node.Name] = int32(i)
+		}
+	}
+
+	// First pass counts the degrees to compute the offsets.
+	inDegrees := make([]int32, n)
+	for i, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if edge.Direction == In {
+				continue
+			}
+			if j, ok := ids[edge.Node]; ok {
+				cg.outOffsets[i+1]++
+				inDegrees[j]++
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		cg.outOffsets[i+1] += cg.outOffsets[i]
+		cg.inOffsets[i+1] = cg.inOffsets[i] + inDegrees[i]
+	}
+
+	// Second pass fills in the targets of each range.
+	cg.outTargets = make([]int32, cg.outOffsets[n])
+	cg.inTargets = make([]int32, cg.inOffsets[n])
+
+	inFill := make([]int32, n)
+	for i, node := range inst.Nodes {
+		next := cg.outOffsets[i]
+		for _, edge := range node.Edges {
+			if edge.Direction == In {
+				continue
+			}
+			if j, ok := ids[edge.Node]; ok {
+				cg.outTargets[next] = j
+				next++
+				cg.inTargets[cg.inOffsets[j]+inFill[j]] = int32(i)
+				inFill[j]++
+			}
+		}
+	}
+
+	return cg
+}
+
+// Len returns the number of nodes in the graph.
+func (cg *CompactGraph) Len() int {
+	return len(cg.names)
+}
+
+// EdgeCount returns the number of directed edges in the graph.
+func (cg *CompactGraph) EdgeCount() int {
+	return len(cg.outTargets)
+}
+
+// Name returns the name of the node with the given ID.
+func (cg *CompactGraph) Name(id int32) string {
+	return cg.names[id]
+}
+
+// Attributes returns the attributes of the node with the given ID.
+func (cg *CompactGraph) Attributes(id int32) Attributes {
+	return cg.attrs[id]
+}
+
+// Lookup returns the ID of the first node with the given name.
+func (cg *CompactGraph) Lookup(name string) (int32, bool) {
+	id, ok := cg.index[name]
+	return id, ok
+}
+
+// Out returns the IDs of the nodes the given node has edges to.
+//
+// The returned slice is shared with the graph and must not be modified.
+func (cg *CompactGraph) Out(id int32) []int32 {
+	return cg.outTargets[cg.outOffsets[id]:cg.outOffsets[id+1]]
+}
+
+// In returns the IDs of the nodes that have edges to the given node.
+//
+// The returned slice is shared with the graph and must not be modified.
+func (cg *CompactGraph) In(id int32) []int32 {
+	return cg.inTargets[cg.inOffsets[id]:cg.inOffsets[id+1]]
+}
+
+// DFS performs a depth-first-search starting at the given node following
+// outward edges. The given function can return false to stop traversal.
+func (cg *CompactGraph) DFS(start int32, fn func(int32) bool) {
+	visited := make([]bool, cg.Len())
+
+	stack := []int32{start}
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		if !fn(id) {
+			return
+		}
+
+		out := cg.Out(id)
+		for i := len(out) - 1; i >= 0; i-- {
+			if !visited[out[i]] {
+				stack = append(stack, out[i])
+			}
+		}
+	}
+}
+
+// BFS performs a breadth-first-search starting at the given node following
+// outward edges. The given function can return false to stop traversal.
+func (cg *CompactGraph) BFS(start int32, fn func(int32) bool) {
+	visited := make([]bool, cg.Len())
+	visited[start] = true
+
+	queue := []int32{start}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if !fn(id) {
+			return
+		}
+
+		for _, next := range cg.Out(id) {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// PathTo returns the shortest path, by number of edges, from the start
+// node to the end node as a slice of node IDs, nil if no path was found.
+func (cg *CompactGraph) PathTo(start, end int32) []int32 {
+	parents := make([]int32, cg.Len())
+	for i := range parents {
+		parents[i] = -1
+	}
+	parents[start] = start
+
+	queue := []int32{start}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, next := range cg.Out(id) {
+			if next == end {
+				path := []int32{end}
+				for at := id; ; at = parents[at] {
+					path = append(path, at)
+					if at == start {
+						break
+					}
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+
+			if parents[next] == -1 {
+				parents[next] = id
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HasPath checks if there is a path from the start node to the end node.
+func (cg *CompactGraph) HasPath(start, end int32) bool {
+	return cg.PathTo(start, end) != nil
+}
+
+// PathString returns a human-readable string for the given path of node IDs.
+func (cg *CompactGraph) PathString(path []int32) string {
+	names := make([]string, len(path))
+	for i, id := range path {
+		names[i] = cg.names[id]
+	}
+	return strings.Join(names, " "+Stringer.Out+" ")
+}
+
+// Components returns the weakly connected components of the graph as
+// slices of node IDs, in the order their first node appears.
+func (cg *CompactGraph) Components() [][]int32 {
+	visited := make([]bool, cg.Len())
+
+	components := [][]int32{}
+
+	for i := range cg.names {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+
+		component := []int32{int32(i)}
+
+		for j := 0; j < len(component); j++ {
+			id := component[j]
+			for _, neighbors := range [][]int32{cg.Out(id), cg.In(id)} {
+				for _, next := range neighbors {
+					if !visited[next] {
+						visited[next] = true
+						component = append(component, next)
+					}
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components
+}
@@ -0,0 +1,73 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestCompact(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"example": true})
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c    d ↔ e
+	// └───────↑
+
+	graph.ConnectNodes(a, b, c)
+	a.AddEdge(c)
+	d.AddLink(e)
+
+	cg := graph.Compact(graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e))))
+
+	if cg.Len() != 5 {
+		t.Fatalf("expected 5 nodes, got %d", cg.Len())
+	}
+
+	if cg.EdgeCount() != 5 {
+		t.Fatalf("expected 5 edges, got %d", cg.EdgeCount())
+	}
+
+	ia, _ := cg.Lookup("a")
+	ic, _ := cg.Lookup("c")
+	id, _ := cg.Lookup("d")
+
+	if cg.Attributes(ia)["example"] != true {
+		t.Fatalf("expected attributes to be kept")
+	}
+
+	if path := cg.PathString(cg.PathTo(ia, ic)); path != "a → c" {
+		t.Fatalf("expected shortest path a → c, got %q", path)
+	}
+
+	if cg.HasPath(ic, ia) {
+		t.Fatalf("did not expect c to have a path to a")
+	}
+
+	if len(cg.In(ic)) != 2 {
+		t.Fatalf("expected c to have 2 inward edges, got %d", len(cg.In(ic)))
+	}
+
+	var visited []string
+	cg.DFS(ia, func(id int32) bool {
+		visited = append(visited, cg.Name(id))
+		return true
+	})
+
+	if len(visited) != 3 || visited[0] != "a" {
+		t.Fatalf("unexpected DFS visit order: %v", visited)
+	}
+
+	components := cg.Components()
+	if len(components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(components))
+	}
+
+	if len(components[1]) != 2 || components[1][0] != id {
+		t.Fatalf("unexpected second component: %v", components[1])
+	}
+}
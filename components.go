@@ -0,0 +1,170 @@
+package graph
+
+// Components returns the weakly connected components of the graph, where
+// edge direction is ignored when determining if two nodes are connected.
+//
+// Only nodes that belong to the graph instance are considered, edges to
+// nodes outside of the instance are ignored. Components are returned in
+// the order their first node appears in the graph.
+//
+// Results are cached until the graph changes, see Generation, unless an
+// edge filter is given, see WithEdgeFilter, to only consider some edges.
+//
+// https://en.wikipedia.org/wiki/Component_(graph_theory)
+func (inst *Instance) Components(opts ...func(*VisitOrder)) []NodeSet {
+	defer inst.trace("Components")()
+
+	if filter := newVisitOrder(opts...).edgeFilter(); filter != nil {
+		return inst.componentsWhere(filter)
+	}
+
+	return copyNodeSets(memoize(inst, "components", inst.components))
+}
+
+// components returns the weakly connected components of the graph.
+func (inst *Instance) components() []NodeSet {
+	return inst.componentsWhere(nil)
+}
+
+// componentsWhere returns the weakly connected components of the graph,
+// only following the edges allowed by the given filter, if not nil.
+func (inst *Instance) componentsWhere(filter EdgeFilter) []NodeSet {
+	return componentsOf(inst.Nodes, inst, filter)
+}
+
+// componentsOf returns the weakly connected components of the given nodes
+// of the graph, only following the edges allowed by the given filter, if
+// not nil, to other nodes among them.
+func componentsOf(nodes Nodes, inst *Instance, filter EdgeFilter) []NodeSet {
+	members := inst.setOf(nodes)
+
+	visited := NodeSet{}
+
+	components := []NodeSet{}
+
+	for _, node := range nodes {
+		if visited.Contains(node) {
+			continue
+		}
+
+		component := NodeSet{}
+
+		stack := Nodes{node}
+
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if visited.Contains(n) {
+				continue
+			}
+
+			visited.Add(n)
+			component.Add(n)
+
+			for _, edge := range n.Edges {
+				if filter != nil && !filter(edge) {
+					continue
+				}
+				if members.Contains(edge.Node) && !visited.Contains(edge.Node) {
+					stack = append(stack, edge.Node)
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components
+}
+
+// WeaklyConnectedComponents returns the weakly connected components of the
+// graph, the same as Components. Every edge is followed in both directions,
+// whether it is an In, Out, Both, or None edge.
+func (inst *Instance) WeaklyConnectedComponents() []NodeSet {
+	return inst.Components()
+}
+
+// IsWeaklyConnected checks if the graph has at least one node, and every node
+// is connected to every other node when edge directions are ignored.
+//
+//	a → b ← c    weakly connected, but c has no path to a
+func (inst *Instance) IsWeaklyConnected() bool {
+	return len(inst.Nodes) > 0 && len(inst.Components()) == 1
+}
+
+// IsStronglyConnected checks if the graph has at least one node, and every
+// node has a directed path to every other node. Out edges are followed in
+// their direction, while None and Both edges can be followed either way.
+//
+//	a → b → c
+//	↑       ↓    strongly connected
+//	└───────┘
+//
+// See StronglyConnectedComponents to extract the strongly connected parts.
+func (inst *Instance) IsStronglyConnected() bool {
+	return len(inst.Nodes) > 0 && len(inst.StronglyConnectedComponents()) == 1
+}
+
+// componentRoots returns a root node for each part of the graph reachable
+// using VisitAll with the given order, which is what root based algorithms
+// like FindCliques and FindBridges explore, in the order the roots appear in
+// the graph.
+func (inst *Instance) componentRoots(order *VisitOrder) Nodes {
+	covered := NodeSet{}
+
+	roots := Nodes{}
+
+	for _, node := range inst.Nodes {
+		if covered.Contains(node) {
+			continue
+		}
+
+		roots = append(roots, node)
+
+		visitAll(node, order, func(n *Node) {
+			covered.Add(n)
+		})
+	}
+
+	return roots
+}
+
+// FindCliques finds the cliques of at least the given size in every
+// component of the graph, using the package level FindCliques function
+// with a root node from each component. Traversal options, like
+// WithEdgeFilter, are passed to FindCliques.
+func (inst *Instance) FindCliques(minSize int, opts ...func(*VisitOrder)) Cliques {
+	cliques := Cliques{}
+
+	for _, root := range inst.componentRoots(newVisitOrder(opts...)) {
+		for _, clique := range FindCliques(root, minSize, opts...) {
+			if !cliques.ContainsClique(clique) {
+				cliques = append(cliques, clique)
+			}
+		}
+	}
+
+	return cliques
+}
+
+// FindBridges finds the bridge paths in every component of the graph, using
+// the package level FindBridges function with a root node from each
+// component. See Bridges for undirected bridge semantics. Traversal options,
+// like WithEdgeFilter, are passed to FindBridges.
+//
+// Deprecated: Use Bridges, which returns bridges as edges, found in linear
+// time.
+func (inst *Instance) FindBridges(opts ...func(*VisitOrder)) []Path {
+	bridges := Paths{}
+
+	for _, root := range inst.componentRoots(newVisitOrder(opts...)) {
+		for _, bridge := range FindBridges(root, opts...) {
+			if !bridges.ContainsPath(bridge) {
+				bridges = append(bridges, bridge)
+			}
+		}
+	}
+
+	return bridges
+}
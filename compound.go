@@ -0,0 +1,194 @@
+package graph
+
+import "fmt"
+
+// IsCompound checks if the node is a compound node, containing a nested
+// child graph.
+func (n *Node) IsCompound() bool {
+	return n.Child != nil
+}
+
+// Collapse replaces the given nodes of the graph with a new compound node
+// with the given name, whose Child graph contains them and the edges between
+// them. Edges between the given nodes and the rest of the graph are moved to
+// the compound node, remembering the nodes they were attached to, so
+// Expand can restore them.
+//
+//	a → b → c → d    Collapse("bc", b, c)    a → bc → d
+//	                                              ┆
+//	                                            b → c
+//
+// This is useful to model systems of systems, where a node of a graph is
+// itself a graph, which is encoded as a cluster using EncodeDOT.
+func (inst *Instance) Collapse(name string, nodes ...*Node) (*Node, error) {
+	members := inst.members()
+
+	collapsed := NodeSet{}
+	for _, node := range nodes {
+		if !members.Contains(node) {
+			return nil, fmt.Errorf("graph cannot collapse node %q that is not in the graph", nodeName(node))
+		}
+		collapsed.Add(node)
+	}
+
+	// The edges of the nodes' neighbors are changed directly.
+	inst.Touch()
+
+	compound := NewNode(name, nil)
+	compound.Child = New(name)
+
+	for _, node := range nodes {
+		if !compound.Child.Nodes.Contains(node) {
+			inst.detach(node)
+			compound.Child.AddNode(node)
+		}
+	}
+
+	for _, node := range compound.Child.Nodes {
+		edges := Edges{}
+
+		for _, edge := range node.Edges {
+			if collapsed.Contains(edge.Node) {
+				edges = append(edges, edge)
+				continue
+			}
+
+			// Point the other side of the relationship at the compound
+			// node, remembering the node inside of it.
+			if other := edge.reciprocal(node); other != nil {
+				other.Node = compound
+				other.inner = node
+			}
+
+			edge.from = compound
+			edge.origin = node
+			compound.Edges = append(compound.Edges, edge)
+		}
+
+		node.Edges = edges
+	}
+
+	inst.AddNode(compound)
+
+	return compound, nil
+}
+
+// Expand replaces the given compound node of the graph with the nodes of its
+// child graph, reversing Collapse. Edges of the compound node are moved back
+// to the nodes they were attached to before collapsing. Edges added to the
+// compound node since are attached to each root node of the child graph,
+// nodes without inward edges from other nodes of the child graph, or every
+// node of the child graph if there are none. Compound nodes with an empty
+// child graph are removed, along with all of their edges.
+func (inst *Instance) Expand(compound *Node) error {
+	if !compound.IsCompound() {
+		return fmt.Errorf("graph cannot expand node %q that is not a compound node", nodeName(compound))
+	}
+
+	if !inst.Nodes.Contains(compound) {
+		return fmt.Errorf("graph cannot expand node %q that is not in the graph", compound.Name)
+	}
+
+	// The edges of the compound node's neighbors are changed directly.
+	inst.Touch()
+
+	child := compound.Child
+
+	if len(child.Nodes) == 0 {
+		inst.RemoveNode(compound)
+		compound.Child = nil
+		return nil
+	}
+
+	roots := child.Nodes.Filter(func(n *Node) bool {
+		for _, edge := range n.Edges {
+			if edge.Direction == In && edge.Node != n {
+				return false
+			}
+		}
+		return true
+	})
+	if len(roots) == 0 {
+		roots = child.Nodes
+	}
+
+	for _, edge := range compound.Edges {
+		other := edge.reciprocal(compound)
+
+		targets := roots
+		if edge.origin != nil {
+			targets = Nodes{edge.origin}
+
+			// Parallel relationships with the compound node are told
+			// apart by the node inside of it they were attached to.
+			for _, candidate := range edge.Node.Edges {
+				if candidate.Node == compound && candidate.inner == edge.origin && candidate.Direction == edge.Direction.Reverse() && candidate.Name == edge.Name {
+					other = candidate
+					break
+				}
+			}
+		}
+
+		for i, target := range targets {
+			if i > 0 {
+				// Attach a copy of the relationship to the other roots.
+				target.AddEdgeWithDirection(edge.Node, edge.Direction)
+				added := target.Edges[len(target.Edges)-1]
+				added.Name, added.Attributes = edge.Name, edge.Attributes
+				if back := added.reciprocal(target); back != nil {
+					back.Name, back.Attributes = edge.Name, edge.Attributes
+				}
+				continue
+			}
+
+			edge.from = target
+			edge.origin = nil
+			target.Edges = append(target.Edges, edge)
+
+			if other != nil {
+				other.Node = target
+				other.inner = nil
+			}
+		}
+	}
+
+	compound.Edges = nil
+
+	inst.detach(compound)
+
+	for _, node := range child.Nodes {
+		child.unindexNode(node)
+		inst.AddNode(node)
+	}
+
+	compound.Child = nil
+
+	return nil
+}
+
+// VisitNested walks the nodes of the graph, and the nodes of the child
+// graphs of compound nodes, recursively, calling the given function with
+// each node and its nesting depth, starting at 0 for the nodes of the graph.
+// Each node's child graph is visited right after the node. Traversal stops
+// if the function returns false.
+//
+//	a → bc → d    a (0), bc (0), b (1), c (1), d (0)
+//	     ┆
+//	   b → c
+func (inst *Instance) VisitNested(fn func(n *Node, depth int) bool) {
+	visitNested(inst, 0, fn)
+}
+
+// visitNested visits the nodes of the given graph at the given depth, and
+// their child graphs, returning false if traversal was stopped.
+func visitNested(inst *Instance, depth int, fn func(*Node, int) bool) bool {
+	for _, node := range inst.Nodes {
+		if !fn(node, depth) {
+			return false
+		}
+		if node.Child != nil && !visitNested(node.Child, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package graph_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+const compound_golden = `digraph {
+	compound=true
+	"a"
+	"d"
+	subgraph "cluster_bc" {
+		label="bc"
+		"b"
+		"c"
+		"b" -> "c"
+	}
+	"a" -> "b" ["lhead"="cluster_bc"]
+	"a" -> "c" ["lhead"="cluster_bc", "weight"="2"]
+	"c" -> "d" ["ltail"="cluster_bc"]
+}
+`
+
+func TestInstance_Collapse(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a → b → c → d
+	// └───────↗
+
+	graph.ConnectNodes(a, b, c, d)
+	a.AddWeightedEdge(c, 2)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	original := g.Clone()
+
+	bc, err := g.Collapse("bc", b, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bc.IsCompound() || bc.Child.Nodes.String() != "b, c" {
+		t.Fatalf("expected a compound node containing b and c, got %v", bc.Child)
+	}
+
+	if g.Nodes.String() != "a, d, bc" || g.Edges().String() != "a → bc, a → bc, bc → d" {
+		t.Fatalf("unexpected collapsed graph: %v: %v", g.Nodes, g.Edges())
+	}
+
+	if bc.Child.Edges().String() != "b → c" {
+		t.Fatalf("unexpected child graph edges: %v", bc.Child.Edges())
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	g.VisitNested(func(n *graph.Node, depth int) bool {
+		visited = append(visited, fmt.Sprintf("%s:%d", n.Name, depth))
+		return true
+	})
+
+	if strings.Join(visited, " ") != "a:0 d:0 bc:0 b:1 c:1" {
+		t.Fatalf("unexpected nested visit: %v", visited)
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := g.EncodeDOT(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != compound_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), compound_golden)
+	}
+
+	clone, extended := g.Clone(), g.Clone()
+
+	if err := g.Expand(bc); err != nil {
+		t.Fatal(err)
+	}
+
+	if !graph.Equal(g, original) {
+		t.Fatalf("expected expanding to restore the graph: %v", g.Edges())
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Expand(a); err == nil {
+		t.Fatal("expected an error expanding a node that isn't compound")
+	}
+
+	// Clones of compound nodes expand the same way.
+	if err := clone.Expand(clone.Nodes[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	if !graph.Equal(clone, original) {
+		t.Fatalf("expected expanding the clone to restore the graph: %v", clone.Edges())
+	}
+
+	// Edges added to the compound node are attached to its root nodes.
+	e := graph.NewNode("e", nil)
+	extended.AddNode(e)
+	e.AddEdge(extended.Nodes[2])
+
+	if err := extended.Expand(extended.Nodes[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Edges.String() != "e → b" {
+		t.Fatalf("expected the new edge to be attached to b, got %v", e.Edges)
+	}
+
+	if _, err := g.Collapse("x", graph.NewNode("x", nil)); err == nil {
+		t.Fatal("expected an error collapsing a node that isn't in the graph")
+	}
+}
+
+func TestInstance_Expand_empty(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	compound, err := g.Collapse("empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.AddEdge(compound)
+	compound.AddEdge(b)
+
+	if err := g.Expand(compound); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Nodes.Contains(compound) {
+		t.Fatal("expected the empty compound node to be removed")
+	}
+
+	if len(a.Edges) != 0 || len(b.Edges) != 0 {
+		t.Fatalf("expected the edges with the compound node to be removed, got %v and %v", a.Edges, b.Edges)
+	}
+}
@@ -0,0 +1,344 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrConstraintViolated is wrapped by the violations of the constraints of a
+// graph, see Constraint.
+var ErrConstraintViolated = errors.New("graph constraint violated")
+
+// Constraint is a rule the graph must follow, like ForbidEdges, MaxOutDegree,
+// and Acyclic, which are attached to a graph using WithConstraints or
+// AddConstraint, to enforce architecture rules like "databases may not
+// depend on frontends".
+//
+// Check returns the violations of the constraint by the graph, or none if
+// the graph follows it. Constraints that also implement EdgeConstraint are
+// enforced when edges are added using the error-returning mutation methods,
+// like TryAddEdge.
+type Constraint interface {
+	Check(inst *Instance) []Violation
+}
+
+// EdgeConstraint is a Constraint that can check if adding an edge with the
+// given direction between two nodes of the graph would violate it, without
+// adding it, so the edge can be refused.
+type EdgeConstraint interface {
+	Constraint
+	CheckEdge(inst *Instance, from, to *Node, direction EdgeDirection) []Violation
+}
+
+// ConstraintFunc is a function that implements the Constraint interface.
+type ConstraintFunc func(inst *Instance) []Violation
+
+// Check implements the Constraint interface.
+func (f ConstraintFunc) Check(inst *Instance) []Violation {
+	return f(inst)
+}
+
+// Violation describes how a graph violates one of its constraints.
+type Violation struct {
+	// Constraint is the constraint that is violated.
+	Constraint Constraint
+
+	// Node is the node that violates the constraint, like the node with a
+	// forbidden edge, or too many edges.
+	Node *Node
+
+	// Reason describes the violation.
+	Reason string
+}
+
+// Error returns the reason of the violation, implementing the error
+// interface.
+func (v Violation) Error() string {
+	return fmt.Sprintf("%v: %s", ErrConstraintViolated, v.Reason)
+}
+
+// Unwrap returns ErrConstraintViolated, so errors.Is can check for
+// violations.
+func (v Violation) Unwrap() error {
+	return ErrConstraintViolated
+}
+
+// WithConstraints is a functional option that attaches the given
+// constraints to the graph, see AddConstraint.
+//
+//	g := graph.New("services", graph.WithConstraints(
+//		graph.ForbidEdges("db", "frontend"),
+//		graph.MaxOutDegree(10),
+//		graph.Acyclic(),
+//	))
+func WithConstraints(constraints ...Constraint) func(*Instance) {
+	return func(inst *Instance) {
+		inst.constraints = append(inst.constraints, constraints...)
+	}
+}
+
+// AddConstraint attaches the given constraint to the graph. It is enforced
+// by the error-returning mutation methods, like TryAddEdge, if it is an
+// EdgeConstraint, and checked by CheckConstraints.
+//
+// Other mutation methods, like AddEdge, can't refuse changes, so graphs
+// changed using them should be checked using CheckConstraints.
+func (inst *Instance) AddConstraint(c Constraint) {
+	inst.constraints = append(inst.constraints, c)
+}
+
+// CheckConstraints returns the violations of the graph's constraints, in
+// the order the constraints were attached, or nil if the graph follows all
+// of them.
+func (inst *Instance) CheckConstraints() []Violation {
+	var violations []Violation
+	for _, c := range inst.constraints {
+		violations = append(violations, c.Check(inst)...)
+	}
+	return violations
+}
+
+// checkEdgeConstraints returns the first violation of the graph's edge
+// constraints that adding the given edge would cause, or nil.
+func (inst *Instance) checkEdgeConstraints(from, to *Node, direction EdgeDirection) error {
+	for _, c := range inst.constraints {
+		ec, ok := c.(EdgeConstraint)
+		if !ok {
+			continue
+		}
+		if violations := ec.CheckEdge(inst, from, to, direction); len(violations) > 0 {
+			return violations[0]
+		}
+	}
+	return nil
+}
+
+// arc is a pair of nodes an edge can be followed between, from its first
+// node to its second.
+type arc [2]*Node
+
+// arcs returns the arcs an edge with the given direction between the given
+// nodes can be followed along, like Edges.successors: from the first node
+// to the second unless it is an inward edge, and back unless it is an
+// outward edge.
+func arcs(from, to *Node, direction EdgeDirection) []arc {
+	var result []arc
+	if direction != In {
+		result = append(result, arc{from, to})
+	}
+	if direction != Out {
+		result = append(result, arc{to, from})
+	}
+	return result
+}
+
+// ForbidEdges returns a constraint forbidding edges from nodes with the
+// first label to nodes with the second label, like a rule that databases
+// may not depend on frontends:
+//
+//	orders-db → web    violates graph.ForbidEdges("db", "frontend")
+//
+// Edges that can be followed both ways, like undirected edges, are
+// forbidden in either direction.
+func ForbidEdges(fromLabel, toLabel string) EdgeConstraint {
+	return &forbidEdges{from: fromLabel, to: toLabel}
+}
+
+// forbidEdges is the constraint returned by ForbidEdges.
+type forbidEdges struct {
+	from, to string
+}
+
+// String describes the constraint.
+func (c *forbidEdges) String() string {
+	return fmt.Sprintf("no edges from %s to %s nodes", c.from, c.to)
+}
+
+// Check returns a violation for each forbidden edge of the graph.
+func (c *forbidEdges) Check(inst *Instance) []Violation {
+	var violations []Violation
+	for _, node := range inst.Nodes {
+		for _, next := range node.Edges.successors() {
+			violations = append(violations, c.check(arc{node, next})...)
+		}
+	}
+	return violations
+}
+
+// CheckEdge returns a violation if the given edge would be forbidden.
+func (c *forbidEdges) CheckEdge(inst *Instance, from, to *Node, direction EdgeDirection) []Violation {
+	var violations []Violation
+	for _, a := range arcs(from, to, direction) {
+		violations = append(violations, c.check(a)...)
+	}
+	return violations
+}
+
+// check returns a violation if the given arc is forbidden.
+func (c *forbidEdges) check(a arc) []Violation {
+	if !a[0].HasLabel(c.from) || !a[1].HasLabel(c.to) {
+		return nil
+	}
+	return []Violation{{
+		Constraint: c,
+		Node:       a[0],
+		Reason:     fmt.Sprintf("%s %s %s: edges from %s to %s nodes are forbidden", a[0].Name, Out, a[1].Name, c.from, c.to),
+	}}
+}
+
+// MaxOutDegree returns a constraint limiting the number of edges that can
+// be followed outward from each node, which are all edges that aren't
+// inward edges, like a rule that services have a bounded number of
+// dependencies.
+func MaxOutDegree(max int) EdgeConstraint {
+	return &maxOutDegree{max: max}
+}
+
+// maxOutDegree is the constraint returned by MaxOutDegree.
+type maxOutDegree struct {
+	max int
+}
+
+// String describes the constraint.
+func (c *maxOutDegree) String() string {
+	return fmt.Sprintf("out-degree of at most %d", c.max)
+}
+
+// Check returns a violation for each node of the graph with too many
+// outward edges.
+func (c *maxOutDegree) Check(inst *Instance) []Violation {
+	var violations []Violation
+	for _, node := range inst.Nodes {
+		if degree := len(node.Edges.successors()); degree > c.max {
+			violations = append(violations, c.violation(node, degree))
+		}
+	}
+	return violations
+}
+
+// CheckEdge returns a violation if the given edge would give one of its
+// nodes too many outward edges.
+func (c *maxOutDegree) CheckEdge(inst *Instance, from, to *Node, direction EdgeDirection) []Violation {
+	added := map[*Node]int{}
+	for _, a := range arcs(from, to, direction) {
+		added[a[0]]++
+	}
+
+	var violations []Violation
+	for _, node := range []*Node{from, to} {
+		if added[node] == 0 {
+			continue
+		}
+		if degree := len(node.Edges.successors()) + added[node]; degree > c.max {
+			violations = append(violations, c.violation(node, degree))
+		}
+		if from == to {
+			break
+		}
+	}
+	return violations
+}
+
+// violation returns the violation of the constraint by the given node
+// with the given out-degree.
+func (c *maxOutDegree) violation(node *Node, degree int) Violation {
+	return Violation{
+		Constraint: c,
+		Node:       node,
+		Reason:     fmt.Sprintf("%s has out-degree %d, more than %d", node.Name, degree, c.max),
+	}
+}
+
+// Acyclic returns a constraint forbidding cycles, like a rule that there
+// are no circular dependencies. Like TopologicalSort, edges that can be
+// followed both ways, like undirected edges, are cycles of their own.
+//
+// https://en.wikipedia.org/wiki/Directed_acyclic_graph
+func Acyclic() EdgeConstraint {
+	return &acyclic{}
+}
+
+// acyclic is the constraint returned by Acyclic.
+type acyclic struct{}
+
+// String describes the constraint.
+func (c *acyclic) String() string {
+	return "acyclic"
+}
+
+// Check returns a violation for each strongly connected component of the
+// graph with a cycle, for its first node in the graph.
+func (c *acyclic) Check(inst *Instance) []Violation {
+	// IDs are reused, so nodes are ordered by their position in the graph.
+	position := make(map[*Node]int, len(inst.Nodes))
+	for i := len(inst.Nodes) - 1; i >= 0; i-- {
+		position[inst.Nodes[i]] = i
+	}
+
+	var violations []Violation
+	for _, component := range inst.StronglyConnectedComponents() {
+		var first *Node
+		for _, node := range component.Nodes() {
+			if first == nil || position[node] < position[first] {
+				first = node
+			}
+		}
+
+		if component.Len() == 1 && !first.Edges.successors().Contains(first) {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Constraint: c,
+			Node:       first,
+			Reason:     fmt.Sprintf("cycle through %s", component),
+		})
+	}
+	return violations
+}
+
+// CheckEdge returns a violation if the given edge would make a cycle,
+// because its nodes can already reach each other the other way.
+func (c *acyclic) CheckEdge(inst *Instance, from, to *Node, direction EdgeDirection) []Violation {
+	var (
+		members = inst.members()
+		both    = arcs(from, to, direction)
+	)
+
+	for _, a := range both {
+		// Edges that can be followed both ways are cycles of their own.
+		if len(both) == 2 || reaches(a[1], a[0], members) {
+			return []Violation{{
+				Constraint: c,
+				Node:       from,
+				Reason:     fmt.Sprintf("%s %s %s would make a cycle", from.Name, direction, to.Name),
+			}}
+		}
+	}
+	return nil
+}
+
+// reaches checks if there is a path from the start node to the end node,
+// following the edges that can be followed outward to nodes in the given
+// set.
+func reaches(start, end *Node, members nodeSet) bool {
+	visited := NewNodeSet(start)
+	stack := Nodes{start}
+
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if node == end {
+			return true
+		}
+
+		for _, next := range node.Edges.successors() {
+			if members.Contains(next) && !visited.Contains(next) {
+				visited.Add(next)
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
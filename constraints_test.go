@@ -0,0 +1,113 @@
+package graph_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_CheckConstraints(t *testing.T) {
+	var (
+		web    = graph.NewNode("web", nil)
+		api    = graph.NewNode("api", nil)
+		orders = graph.NewNode("orders-db", nil)
+	)
+
+	web.AddLabel("frontend")
+	orders.AddLabel("db")
+
+	// web → api → orders-db
+	//  ↑            │
+	//  └────────────┘
+
+	web.AddEdge(api)
+	api.AddEdge(orders)
+	orders.AddEdge(web)
+
+	g := graph.New("services",
+		graph.WithNodes(graph.NewNodes(web, api, orders)),
+		graph.WithConstraints(
+			graph.ForbidEdges("db", "frontend"),
+			graph.MaxOutDegree(1),
+			graph.Acyclic(),
+		),
+	)
+
+	var reasons []string
+	for _, violation := range g.CheckConstraints() {
+		reasons = append(reasons, violation.Reason)
+	}
+
+	expected := "[orders-db → web: edges from db to frontend nodes are forbidden cycle through api, orders-db, web]"
+	if got := fmt.Sprint(reasons); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	orders.RemoveEdgesTo(web)
+
+	if violations := g.CheckConstraints(); len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+
+	// Edges that would violate a constraint are refused.
+	for _, test := range []struct {
+		from, to *graph.Node
+		reason   string
+	}{
+		{orders, web, "orders-db → web: edges from db to frontend nodes are forbidden"},
+		{web, orders, "web has out-degree 2, more than 1"},
+		{orders, api, "orders-db → api would make a cycle"},
+	} {
+		err := g.TryAddEdge(test.from, test.to)
+		if !errors.Is(err, graph.ErrConstraintViolated) {
+			t.Fatalf("expected a violation adding %s → %s, got %v", test.from.Name, test.to.Name, err)
+		}
+
+		var violation graph.Violation
+		if !errors.As(err, &violation) || violation.Reason != test.reason {
+			t.Fatalf("expected %q, got %v", test.reason, err)
+		}
+	}
+
+	if got := g.Edges().String(); got != "web → api, api → orders-db" {
+		t.Fatalf("expected refused edges not to be added, got %s", got)
+	}
+
+	cache := graph.NewNode("cache", nil)
+	g.AddNode(cache)
+
+	if err := g.TryAddEdge(orders, cache); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.TryAddEdgeWithDirection(cache, api, graph.None); !errors.Is(err, graph.ErrConstraintViolated) {
+		t.Fatalf("expected undirected edges to be cycles, got %v", err)
+	}
+}
+
+func TestConstraintFunc(t *testing.T) {
+	named := graph.ConstraintFunc(func(g *graph.Instance) []graph.Violation {
+		var violations []graph.Violation
+		for _, node := range g.Nodes {
+			if node.Name == "" {
+				violations = append(violations, graph.Violation{Node: node, Reason: "node without a name"})
+			}
+		}
+		return violations
+	})
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(graph.NewNode("a", nil), graph.NewNode("", nil))))
+	g.AddConstraint(named)
+
+	violations := g.CheckConstraints()
+	if len(violations) != 1 || violations[0].Error() != "graph constraint violated: node without a name" {
+		t.Fatalf("unexpected violations: %v", violations)
+	}
+
+	// Constraints that can't check edges aren't enforced on mutation.
+	if err := g.TryAddEdge(g.Nodes[0], g.Nodes[1]); err != nil {
+		t.Fatal(err)
+	}
+}
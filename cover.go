@@ -0,0 +1,104 @@
+package graph
+
+import "sort"
+
+// ApproxVertexCover returns a vertex cover of the graph, a set of nodes
+// such that every edge between nodes in the graph has at least one of its
+// nodes in the set, ignoring edge directions.
+//
+// Finding a minimum vertex cover is NP-hard, so the cover is built from a
+// maximal matching: both nodes of any edge that isn't covered yet are added
+// to the cover, which is at most twice the size of a minimum cover.
+//
+//	a → b → c → d    Cover: {a, b, c, d}
+//	                 Minimum: {b, c}
+//
+// https://en.wikipedia.org/wiki/Vertex_cover
+func (inst *Instance) ApproxVertexCover() NodeSet {
+	members := inst.members()
+
+	cover := NodeSet{}
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		if !members.Contains(edge.Node) {
+			return
+		}
+
+		if cover.Contains(from) || cover.Contains(edge.Node) {
+			return
+		}
+
+		cover.Add(from)
+		cover.Add(edge.Node)
+	})
+
+	return cover
+}
+
+// MaximalIndependentSet returns a maximal independent set of the graph,
+// a set of nodes where no two nodes are adjacent that can't be extended by
+// adding another node, ignoring edge directions. Nodes with edges to
+// themselves are never included.
+//
+// Nodes are added greedily, in order of fewest neighbors first, which
+// tends to produce large sets, but not necessarily a maximum independent
+// set, which is NP-hard to find. Every node in the graph that isn't in
+// the set is adjacent to a node in it, so the remaining nodes form a
+// vertex cover.
+//
+//	a → b → c → d    Set: {a, d}
+//
+// https://en.wikipedia.org/wiki/Maximal_independent_set
+func (inst *Instance) MaximalIndependentSet() NodeSet {
+	neighbors := undirectedNeighbors(inst.Nodes)
+
+	order := make(Nodes, len(inst.Nodes))
+	copy(order, inst.Nodes)
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(neighbors[order[i]]) < len(neighbors[order[j]])
+	})
+
+	var (
+		set      = NodeSet{}
+		excluded = NodeSet{}
+	)
+
+	for _, node := range order {
+		if excluded.Contains(node) || neighbors[node].Contains(node) {
+			continue
+		}
+
+		set.Add(node)
+		excluded.Add(node)
+
+		for neighbor := range neighbors[node] {
+			excluded.Add(neighbor)
+		}
+	}
+
+	return set
+}
+
+// undirectedNeighbors returns the adjacent nodes of each of the given
+// nodes, ignoring edge directions and nodes that aren't given.
+func undirectedNeighbors(nodes Nodes) map[*Node]NodeSet {
+	members := NewNodeSet(nodes...)
+
+	neighbors := make(map[*Node]NodeSet, len(nodes))
+	for _, node := range nodes {
+		neighbors[node] = NodeSet{}
+	}
+
+	for _, node := range nodes {
+		for _, edge := range node.Edges {
+			if !members.Contains(edge.Node) {
+				continue
+			}
+			neighbors[node].Add(edge.Node)
+			neighbors[edge.Node].Add(node)
+		}
+	}
+
+	return neighbors
+}
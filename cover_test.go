@@ -0,0 +1,74 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_ApproxVertexCover(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c → d    e ↺
+
+	graph.ConnectNodes(a, b, c, d)
+	e.AddEdge(e)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	cover := g.ApproxVertexCover()
+
+	for _, edge := range g.Edges() {
+		if !cover.Contains(edge.From()) && !cover.Contains(edge.Node) {
+			t.Fatalf("edge %s → %s is not covered by %v", edge.From().Name, edge.Node.Name, cover)
+		}
+	}
+
+	if cover.String() != "a, b, c, d, e" {
+		t.Fatalf("unexpected cover: %v", cover)
+	}
+}
+
+func TestInstance_MaximalIndependentSet(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+		f = graph.NewNode("f", nil)
+	)
+
+	//     b
+	//   ↗ ↓ ↘
+	// a → c → d    e ↺    f
+
+	a.AddEdge(b)
+	a.AddEdge(c)
+	b.AddEdge(c)
+	b.AddEdge(d)
+	c.AddEdge(d)
+	e.AddEdge(e)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e, f)))
+
+	set := g.MaximalIndependentSet()
+
+	if set.String() != "a, d, f" {
+		t.Fatalf("unexpected independent set: %v", set)
+	}
+
+	for node := range set {
+		for _, edge := range node.Edges {
+			if set.Contains(edge.Node) {
+				t.Fatalf("%s and %s are both in the set", node.Name, edge.Node.Name)
+			}
+		}
+	}
+}
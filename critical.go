@@ -0,0 +1,70 @@
+package graph
+
+import "time"
+
+// CriticalPath returns the longest path through the graph, where the length
+// of a path is the sum of the durations of its nodes, given by the duration
+// function, along with its total duration. It is the sequence of tasks that
+// determines how long a whole project or pipeline takes, even when every
+// other task runs in parallel, like with Run.
+//
+//	a (1s) → b (5s) → d (1s)
+//	  ↓                ↑        a → b → d, 7s
+//	c (2s) ────────────┘
+//
+// Like TopologicalSort, a node depends on the nodes with edges to it that
+// aren't inward edges. Durations are expected to be non-negative. When paths
+// have the same duration, the one ending at the node that comes first in
+// topological order is returned. If the graph is empty, contains a cycle,
+// or the duration function is nil, a nil path and a duration of 0 are
+// returned.
+//
+// https://en.wikipedia.org/wiki/Critical_path_method
+func (inst *Instance) CriticalPath(duration func(*Node) time.Duration) (Path, time.Duration) {
+	if duration == nil {
+		return nil, 0
+	}
+
+	sorted, err := inst.TopologicalSort()
+	if err != nil || len(sorted) == 0 {
+		return nil, 0
+	}
+
+	members := inst.members()
+
+	var (
+		// start and finish are the durations of the longest path ending
+		// at each node, without and with the node itself, and prev is the
+		// node before it on that path.
+		finish = make(map[*Node]time.Duration, len(sorted))
+		prev   = make(map[*Node]*Node, len(sorted))
+		start  = make(map[*Node]time.Duration, len(sorted))
+	)
+
+	var end *Node
+
+	for _, node := range sorted {
+		finish[node] = start[node] + duration(node)
+
+		if end == nil || finish[node] > finish[end] {
+			end = node
+		}
+
+		for _, next := range node.Edges.successors() {
+			if !members.Contains(next) {
+				continue
+			}
+			if _, ok := prev[next]; !ok || finish[node] > start[next] {
+				start[next] = finish[node]
+				prev[next] = node
+			}
+		}
+	}
+
+	var path Path
+	for node := end; node != nil; node = prev[node] {
+		path = append(Path{node}, path...)
+	}
+
+	return path, finish[end]
+}
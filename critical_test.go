@@ -0,0 +1,58 @@
+package graph_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_CriticalPath(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"duration": time.Second})
+		b = graph.NewNode("b", graph.Attributes{"duration": 5 * time.Second})
+		c = graph.NewNode("c", graph.Attributes{"duration": 2 * time.Second})
+		d = graph.NewNode("d", graph.Attributes{"duration": time.Second})
+		e = graph.NewNode("e", graph.Attributes{"duration": 3 * time.Second})
+	)
+
+	// a (1s) → b (5s) → d (1s)
+	//   ↓                ↑
+	// c (2s) ────────────┘      e (3s)
+
+	a.AddEdge(b)
+	a.AddEdge(c)
+	b.AddEdge(d)
+	c.AddEdge(d)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	duration := func(n *graph.Node) time.Duration {
+		return n.Attributes["duration"].(time.Duration)
+	}
+
+	path, total := g.CriticalPath(duration)
+
+	if path.String() != "a → b → d" || total != 7*time.Second {
+		t.Fatalf("unexpected critical path: %v, %v", path, total)
+	}
+
+	// Make the other branch longer.
+	c.SetAttribute("duration", 10*time.Second)
+
+	path, total = g.CriticalPath(duration)
+
+	if path.String() != "a → c → d" || total != 12*time.Second {
+		t.Fatalf("unexpected critical path: %v, %v", path, total)
+	}
+
+	d.AddEdge(a)
+
+	if path, total := g.CriticalPath(duration); path != nil || total != 0 {
+		t.Fatalf("expected no critical path for a graph with a cycle, got: %v, %v", path, total)
+	}
+
+	if path, total := graph.New("empty").CriticalPath(duration); path != nil || total != 0 {
+		t.Fatalf("expected no critical path for an empty graph, got: %v, %v", path, total)
+	}
+}
@@ -0,0 +1,88 @@
+package graph
+
+// CyclesContaining returns every simple cycle in the graph that goes
+// through the given node, following outward and bi-directional edges.
+// Each cycle starts and ends with the given node, and only visits nodes
+// in the graph instance.
+//
+//	a → b → c    a: a → b → c → d → a
+//	↑   ↓   │       a → b → d → a
+//	└── d ←─┘
+//
+// https://en.wikipedia.org/wiki/Cycle_(graph_theory)
+func (inst *Instance) CyclesContaining(n *Node) []Path {
+	members := inst.members()
+	if !members.Contains(n) {
+		return nil
+	}
+
+	var cycles []Path
+
+	eachCycleThrough(n, members, func(cycle Path) bool {
+		cycles = append(cycles, cycle)
+		return true
+	})
+
+	return cycles
+}
+
+// eachCycleThrough calls the given function with each simple cycle that
+// starts and ends at the given node, following outward and bi-directional
+// edges, until the function returns false. If members is not nil, only
+// cycles made of nodes in the set are found.
+func eachCycleThrough(start *Node, members nodeSet, fn func(Path) bool) {
+	var (
+		path   = Path{start}
+		onPath = NewNodeSet(start)
+	)
+
+	var search func(node *Node) bool
+	search = func(node *Node) bool {
+		seen := NodeSet{}
+
+		for _, edge := range node.Edges {
+			if edge.Direction != Out && edge.Direction != Both {
+				continue
+			}
+
+			next := edge.Node
+
+			// Parallel edges to the same node make the same cycles.
+			if seen.Contains(next) {
+				continue
+			}
+			seen.Add(next)
+
+			if members != nil && !members.Contains(next) {
+				continue
+			}
+
+			if next == start {
+				cycle := make(Path, len(path), len(path)+1)
+				copy(cycle, path)
+				if !fn(append(cycle, start)) {
+					return false
+				}
+				continue
+			}
+
+			if onPath.Contains(next) {
+				continue
+			}
+
+			path = append(path, next)
+			onPath.Add(next)
+
+			if !search(next) {
+				return false
+			}
+
+			path = path[:len(path)-1]
+			delete(onPath, next)
+		}
+
+		return true
+	}
+
+	search(start)
+}
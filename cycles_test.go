@@ -0,0 +1,62 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_CyclesContaining(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c
+	// ↑   ↓   │
+	// └── d ←─┘
+	//
+	// e → a
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	b.AddEdge(d)
+	c.AddEdge(d)
+	d.AddEdge(a)
+	e.AddEdge(a)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	cycles := g.CyclesContaining(a)
+
+	expected := []string{
+		"a → b → c → d → a",
+		"a → b → d → a",
+	}
+
+	if len(cycles) != len(expected) {
+		t.Fatalf("expected %d cycles, got %d: %v", len(expected), len(cycles), cycles)
+	}
+
+	for i, cycle := range cycles {
+		if cycle.String() != expected[i] {
+			t.Fatalf("expected cycle %q, got %q", expected[i], cycle)
+		}
+	}
+
+	if cycles := g.CyclesContaining(e); len(cycles) != 0 {
+		t.Fatalf("did not expect cycles through e: %v", cycles)
+	}
+
+	// The shortest way back to a skips c, but a longer cycle goes through it.
+	if !a.HasCycleContaining(c) {
+		t.Fatal("expected a cycle through a containing c")
+	}
+
+	if a.HasCycleContaining(e) {
+		t.Fatal("did not expect a cycle through a containing e")
+	}
+}
@@ -0,0 +1,391 @@
+// Package cypher converts graph instances to and from the Cypher query
+// language used by Neo4j and other property graph databases.
+//
+// Export writes a graph as a single CREATE statement, which can be run using
+// cypher-shell or any Neo4j driver. Import builds a graph from the values
+// returned by a Cypher query, using the Node, Relationship, and Path types,
+// which mirror the fields of the values returned by the Bolt driver, so they
+// can be converted without this package depending on the driver:
+//
+//	var values []any
+//	for _, v := range record.Values {
+//		switch v := v.(type) {
+//		case dbtype.Node:
+//			values = append(values, cypher.Node{ElementId: v.ElementId, Labels: v.Labels, Props: v.Props})
+//		case dbtype.Relationship:
+//			values = append(values, cypher.Relationship{...})
+//		}
+//	}
+//	inst, err := cypher.Import("result", values...)
+//
+// https://neo4j.com/docs/cypher-manual/current/clauses/create/
+package cypher
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/picatz/graph"
+)
+
+// DefaultRelationshipType is the relationship type used to export edges
+// without a name, since every relationship in Cypher needs a type.
+const DefaultRelationshipType = "RELATED_TO"
+
+// Export writes the graph as a Cypher CREATE statement.
+//
+// Each node is created with its labels, its name as the "name" property,
+// and its attributes as the remaining properties. Edges are created as
+// relationships with their name as the type. Since relationships in Cypher
+// are always directed, undirected (None) edges are created once, from the
+// first of their nodes, and bi-directional (Both) edges are created in both
+// directions. Edges to nodes outside of the graph are skipped.
+func Export(w io.Writer, inst *graph.Instance) error {
+	if len(inst.Nodes) == 0 {
+		return nil
+	}
+
+	ids := make(map[*graph.Node]int, len(inst.Nodes))
+	for i, node := range inst.Nodes {
+		ids[node] = i
+	}
+
+	var clauses []string
+
+	for i, node := range inst.Nodes {
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "(n%d", i)
+		for _, label := range node.Labels {
+			b.WriteString(":" + identifier(label))
+		}
+
+		props := graph.Attributes{}
+		for k, v := range node.Attributes {
+			props[k] = v
+		}
+		props["name"] = node.Name
+
+		b.WriteString(" " + properties(props) + ")")
+
+		clauses = append(clauses, b.String())
+	}
+
+	for i, node := range inst.Nodes {
+		// Undirected self-loops have both of their edges on the node.
+		loops := 0
+
+		for _, edge := range node.Edges {
+			j, ok := ids[edge.Node]
+			if !ok {
+				continue
+			}
+
+			switch edge.Direction {
+			case graph.In:
+				continue
+			case graph.Out, graph.Both:
+			default:
+				if j < i {
+					continue
+				}
+				if j == i {
+					loops++
+					if loops%2 == 0 {
+						continue
+					}
+				}
+			}
+
+			typ := edge.Name
+			if typ == "" {
+				typ = DefaultRelationshipType
+			}
+
+			rel := fmt.Sprintf("(n%d)-[:%s", i, identifier(typ))
+			if len(edge.Attributes) > 0 {
+				rel += " " + properties(edge.Attributes)
+			}
+			rel += fmt.Sprintf("]->(n%d)", j)
+
+			clauses = append(clauses, rel)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("CREATE\n  ")
+	bw.WriteString(strings.Join(clauses, ",\n  "))
+	bw.WriteString(";\n")
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("cypher failed to export graph: %w", err)
+	}
+	return nil
+}
+
+// identifier returns the given name as a Cypher identifier, quoted using
+// backticks if needed.
+func identifier(name string) string {
+	simple := name != ""
+	for i, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			simple = false
+			break
+		}
+	}
+
+	if simple {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// properties returns the Cypher map literal for the given attributes,
+// sorted by key.
+func properties(attrs graph.Attributes) string {
+	keys := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = identifier(k) + ": " + literal(attrs[k])
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// literal returns the Cypher literal for the given value. Slices and arrays
+// of any type, like []string or []int, are written as lists, and maps with
+// string keys as maps. Values without a Cypher equivalent are written as
+// strings.
+func literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case time.Time:
+		return "datetime(" + quote(v.Format(time.RFC3339Nano)) + ")"
+	case fmt.Stringer:
+		return quote(v.String())
+	}
+
+	// Values of other types, like typed slices and named numbers, are
+	// written from their kind.
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "null"
+		}
+		return literal(rv.Elem().Interface())
+	case reflect.String:
+		return quote(rv.String())
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = literal(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			attrs := graph.Attributes{}
+			iter := rv.MapRange()
+			for iter.Next() {
+				attrs[iter.Key().String()] = iter.Value().Interface()
+			}
+			return properties(attrs)
+		}
+	}
+
+	return quote(fmt.Sprint(v))
+}
+
+// quote returns the given string as a single-quoted Cypher string literal.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return "'" + s + "'"
+}
+
+// Node is a node returned by a Cypher query.
+type Node struct {
+	ElementId string
+	Labels    []string
+	Props     map[string]any
+}
+
+// Relationship is a relationship returned by a Cypher query.
+type Relationship struct {
+	ElementId      string
+	StartElementId string
+	EndElementId   string
+	Type           string
+	Props          map[string]any
+}
+
+// Path is a path returned by a Cypher query.
+type Path struct {
+	Nodes         []Node
+	Relationships []Relationship
+}
+
+// Import builds a graph with the given name from the values returned by a
+// Cypher query, which can be Node, Relationship, or Path values, pointers
+// to them, or slices of them of any type, like []any or []Node, as
+// returned for lists. Values appearing more than once, identified by their
+// element IDs, are only added once.
+//
+// Nodes are named using their "name" property if it is a string, or their
+// element ID otherwise. Relationships become outward edges named after
+// their type. Relationships to nodes that weren't returned by the query
+// add placeholder nodes named after the missing element IDs.
+func Import(name string, values ...any) (*graph.Instance, error) {
+	var (
+		nodes []Node
+		rels  []Relationship
+	)
+
+	var collect func(v any) error
+	collect = func(v any) error {
+		switch v := v.(type) {
+		case Node:
+			nodes = append(nodes, v)
+		case *Node:
+			nodes = append(nodes, *v)
+		case Relationship:
+			rels = append(rels, v)
+		case *Relationship:
+			rels = append(rels, *v)
+		case Path:
+			nodes = append(nodes, v.Nodes...)
+			rels = append(rels, v.Relationships...)
+		case *Path:
+			nodes = append(nodes, v.Nodes...)
+			rels = append(rels, v.Relationships...)
+		case nil:
+		default:
+			// Lists can be of any slice type, like []any or []Node.
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return fmt.Errorf("cypher cannot import value of type %T", v)
+			}
+			for i := 0; i < rv.Len(); i++ {
+				if err := collect(rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, v := range values {
+		if err := collect(v); err != nil {
+			return nil, err
+		}
+	}
+
+	inst := graph.New(name)
+
+	byID := map[string]*graph.Node{}
+
+	for _, n := range nodes {
+		if _, ok := byID[n.ElementId]; ok {
+			continue
+		}
+
+		attrs := graph.Attributes{}
+		for k, v := range n.Props {
+			attrs[k] = v
+		}
+
+		nodeName := n.ElementId
+		if s, ok := attrs["name"].(string); ok {
+			nodeName = s
+			delete(attrs, "name")
+		}
+
+		node := graph.NewNode(nodeName, attrs)
+		node.AddLabel(n.Labels...)
+
+		byID[n.ElementId] = node
+		inst.AddNode(node)
+	}
+
+	node := func(id string) *graph.Node {
+		n, ok := byID[id]
+		if !ok {
+			n = graph.NewNode(id, nil)
+			byID[id] = n
+			inst.AddNode(n)
+		}
+		return n
+	}
+
+	seen := map[string]bool{}
+
+	for _, rel := range rels {
+		if rel.ElementId != "" {
+			if seen[rel.ElementId] {
+				continue
+			}
+			seen[rel.ElementId] = true
+		}
+
+		from, to := node(rel.StartElementId), node(rel.EndElementId)
+
+		from.AddEdgeTyped(to, rel.Type)
+
+		if len(rel.Props) == 0 {
+			continue
+		}
+
+		// Both sides of the relationship share its properties.
+		attrs := graph.Attributes{}
+		for k, v := range rel.Props {
+			attrs[k] = v
+		}
+
+		halves := graph.Edges{from.Edges[len(from.Edges)-1], to.Edges[len(to.Edges)-1]}
+		if from == to {
+			halves = from.Edges[len(from.Edges)-2:]
+		}
+		for _, edge := range halves {
+			edge.Attributes = attrs
+		}
+	}
+
+	return inst, nil
+}
@@ -0,0 +1,89 @@
+package cypher_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/cypher"
+)
+
+// tier is a named number, which is exported as a number.
+type tier int
+
+func TestExport(t *testing.T) {
+	var (
+		alice = graph.NewNode("alice", graph.Attributes{"age": 30})
+		bob   = graph.NewNode("bob's", nil)
+		acme  = graph.NewNode("acme", graph.Attributes{"tags": []string{"a", "b"}, "ports": []int{80, 443}, "tier": tier(1)})
+	)
+
+	alice.AddLabel("Person")
+	bob.AddLabel("Person")
+	acme.AddLabel("Company")
+
+	alice.AddEdgeTyped(acme, "WORKS_AT")
+	alice.AddEdgeWithDirection(bob, graph.None)
+	bob.AddWeightedEdge(acme, 2)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(alice, bob, acme)))
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := cypher.Export(buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE
+  (n0:Person {age: 30, name: 'alice'}),
+  (n1:Person {name: 'bob\'s'}),
+  (n2:Company {name: 'acme', ports: [80, 443], tags: ['a', 'b'], tier: 1}),
+  (n0)-[:WORKS_AT]->(n2),
+  (n0)-[:RELATED_TO]->(n1),
+  (n1)-[:RELATED_TO {weight: 2}]->(n2);
+`
+
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestImport(t *testing.T) {
+	alice := cypher.Node{ElementId: "1", Labels: []string{"Person"}, Props: map[string]any{"name": "alice", "age": int64(30)}}
+	acme := cypher.Node{ElementId: "2", Labels: []string{"Company"}, Props: map[string]any{"name": "acme"}}
+
+	worksAt := cypher.Relationship{ElementId: "3", StartElementId: "1", EndElementId: "2", Type: "WORKS_AT", Props: map[string]any{"since": int64(2020)}}
+	knows := cypher.Relationship{ElementId: "4", StartElementId: "1", EndElementId: "5", Type: "KNOWS"}
+
+	g, err := cypher.Import("result",
+		alice, worksAt, acme,
+		cypher.Path{Nodes: []cypher.Node{alice, acme}, Relationships: []cypher.Relationship{worksAt}},
+		[]any{&knows},
+		[]cypher.Node{alice},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Nodes.String() != "alice, acme, 5" {
+		t.Fatalf("unexpected nodes: %v", g.Nodes)
+	}
+
+	a := g.Nodes[0]
+
+	if !a.HasLabel("Person") || a.Attributes["age"] != int64(30) {
+		t.Fatalf("unexpected alice node: %v %v", a.Labels, a.Attributes)
+	}
+
+	if works := a.Edges.OfType("WORKS_AT"); len(works) != 1 || works[0].Node.Name != "acme" || works[0].Attributes["since"] != int64(2020) {
+		t.Fatalf("unexpected WORKS_AT edges: %v", works)
+	}
+
+	if len(g.Nodes[1].Edges.In()) != 1 {
+		t.Fatalf("expected acme to have one inward edge")
+	}
+
+	if _, err := cypher.Import("bad", 42); err == nil {
+		t.Fatalf("expected an error importing an unsupported value")
+	}
+}
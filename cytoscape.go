@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type cytoscapeGraph struct {
+	Data     Attributes        `json:"data,omitempty"`
+	Elements cytoscapeElements `json:"elements"`
+}
+
+type cytoscapeElements struct {
+	Nodes []cytoscapeElement `json:"nodes"`
+	Edges []cytoscapeElement `json:"edges"`
+}
+
+type cytoscapeElement struct {
+	Data    Attributes `json:"data"`
+	Classes string     `json:"classes,omitempty"`
+}
+
+// EncodeCytoscapeJSON encodes the graph as the JSON used by Cytoscape.js to
+// load elements, like cy.json(), so graphs can be given to Cytoscape.js
+// frontends as is.
+//
+//	{
+//	  "data": {"name": "deps"},
+//	  "elements": {
+//	    "nodes": [{"data": {"id": "a"}}, {"data": {"id": "b"}}],
+//	    "edges": [{"data": {"id": "e0", "source": "a", "target": "b"}}]
+//	  }
+//	}
+//
+// Nodes are identified by their name, with their attributes in their data,
+// and their labels as their classes. Each relationship is written once,
+// like Edges, identified by its position, like e0, skipping the IDs that
+// are names of nodes, with its attributes, and its name as "name", in its
+// data. Undirected (None) and bi-directional (Both)
+// edges have the "undirected" or "bidirectional" class, to style them.
+// The graph's name and attributes are written as its data. Attributes named
+// "id", "source", or "target" are not written, since Cytoscape.js uses
+// them to identify elements.
+//
+// The WithName and WithCanonical encoding options are supported. An error
+// is returned if two nodes have the same name.
+//
+// https://js.cytoscape.org/#notation/elements-json
+func EncodeCytoscapeJSON(w io.Writer, inst *Instance, opts ...func(*EncodeOptions)) error {
+	options := newEncodeOptions(opts...)
+
+	nodes := inst.Nodes
+	if options.Canonical {
+		nodes = canonicalOrder(nodes)
+	}
+
+	name := inst.Name
+	if options.Name != "" {
+		name = options.Name
+	}
+
+	var data Attributes
+	if name != "" {
+		data = cytoscapeData(inst.Attributes, "name", name)
+	} else {
+		data = cytoscapeData(inst.Attributes)
+	}
+
+	g := cytoscapeGraph{
+		Data: data,
+		Elements: cytoscapeElements{
+			Nodes: make([]cytoscapeElement, 0, len(nodes)),
+			Edges: []cytoscapeElement{},
+		},
+	}
+
+	seen := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if seen[node.Name] {
+			return fmt.Errorf("graph cannot encode Cytoscape JSON with duplicate node name %q", node.Name)
+		}
+		seen[node.Name] = true
+
+		g.Elements.Nodes = append(g.Elements.Nodes, cytoscapeElement{
+			Data:    cytoscapeData(node.Attributes, "id", node.Name),
+			Classes: strings.Join(node.Labels, " "),
+		})
+	}
+
+	members := NewNodeSet(nodes...)
+
+	// Edges share the IDs of nodes, so those that are names of nodes are
+	// skipped.
+	var next int
+	edgeID := func() string {
+		for {
+			id := fmt.Sprintf("e%d", next)
+			next++
+			if !seen[id] {
+				return id
+			}
+		}
+	}
+
+	eachUniqueEdge(nodes, func(from *Node, edge *Edge) {
+		if !members.Contains(edge.Node) {
+			return
+		}
+
+		source, target := from.Name, edge.Node.Name
+		if edge.Direction == In {
+			source, target = target, source
+		}
+
+		pairs := []string{"id", edgeID(), "source", source, "target", target}
+		if edge.Name != "" {
+			pairs = append(pairs, "name", edge.Name)
+		}
+
+		var classes string
+		switch edge.Direction {
+		case None, Unknown:
+			classes = "undirected"
+		case Both:
+			classes = "bidirectional"
+		}
+
+		g.Elements.Edges = append(g.Elements.Edges, cytoscapeElement{
+			Data:    cytoscapeData(edge.Attributes, pairs...),
+			Classes: classes,
+		})
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(g); err != nil {
+		return fmt.Errorf("graph failed to encode Cytoscape JSON: %w", err)
+	}
+	return nil
+}
+
+// cytoscapeData returns the data of a Cytoscape.js element: a copy of the
+// given attributes, without the attributes Cytoscape.js uses to identify
+// elements, and with the given key and value pairs.
+func cytoscapeData(attrs Attributes, pairs ...string) Attributes {
+	data := make(Attributes, len(attrs)+len(pairs)/2)
+	for k, v := range attrs {
+		switch k {
+		case "id", "source", "target":
+			continue
+		}
+		data[k] = v
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		data[pairs[i]] = pairs[i+1]
+	}
+	return data
+}
@@ -0,0 +1,138 @@
+package graph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+const cytoscape_golden = `{
+  "data": {
+    "env": "prod",
+    "name": "deps"
+  },
+  "elements": {
+    "nodes": [
+      {
+        "data": {
+          "id": "api",
+          "port": 8080
+        },
+        "classes": "Service"
+      },
+      {
+        "data": {
+          "id": "db"
+        }
+      },
+      {
+        "data": {
+          "id": "cache"
+        }
+      }
+    ],
+    "edges": [
+      {
+        "data": {
+          "id": "e0",
+          "name": "queries",
+          "source": "api",
+          "target": "db",
+          "weight": 2
+        }
+      },
+      {
+        "data": {
+          "id": "e1",
+          "source": "api",
+          "target": "cache"
+        },
+        "classes": "bidirectional"
+      },
+      {
+        "data": {
+          "id": "e2",
+          "source": "db",
+          "target": "cache"
+        },
+        "classes": "undirected"
+      }
+    ]
+  }
+}
+`
+
+func TestEncodeCytoscapeJSON(t *testing.T) {
+	var (
+		api   = graph.NewNode("api", graph.Attributes{"port": 8080, "id": 1})
+		db    = graph.NewNode("db", nil)
+		cache = graph.NewNode("cache", nil)
+	)
+
+	api.Labels = []string{"Service"}
+
+	api.AddEdgeTyped(db, "queries")
+	api.Edges[0].Attributes = graph.Attributes{"weight": 2}
+	db.Edges[0].Attributes = api.Edges[0].Attributes
+	api.AddEdgeWithDirection(cache, graph.Both)
+	cache.AddEdgeWithDirection(db, graph.None)
+
+	g := graph.New("deps", graph.WithNodes(graph.Nodes{api, db, cache}), graph.WithAttributes(graph.Attributes{"env": "prod"}))
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := graph.EncodeCytoscapeJSON(buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != cytoscape_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), cytoscape_golden)
+	}
+
+	g.AddNode(graph.NewNode("db", nil))
+
+	if err := graph.EncodeCytoscapeJSON(buf, g); err == nil {
+		t.Fatal("expected an error for duplicate node names")
+	}
+}
+
+func TestEncodeCytoscapeJSON_edgeIDs(t *testing.T) {
+	var (
+		e0 = graph.NewNode("e0", nil)
+		e1 = graph.NewNode("e1", nil)
+	)
+
+	e0.AddEdge(e1)
+	e1.AddEdge(e0)
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := graph.EncodeCytoscapeJSON(buf, graph.New("", graph.WithNodes(graph.Nodes{e0, e1}))); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Elements struct {
+			Nodes, Edges []struct {
+				Data map[string]any `json:"data"`
+			}
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[any]bool{}
+	for _, elem := range append(decoded.Elements.Nodes, decoded.Elements.Edges...) {
+		if ids[elem.Data["id"]] {
+			t.Fatalf("duplicate element ID %v", elem.Data["id"])
+		}
+		ids[elem.Data["id"]] = true
+	}
+
+	if len(ids) != 4 {
+		t.Fatalf("expected 4 elements, got %v", ids)
+	}
+}
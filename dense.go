@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// DenseNodeSet is a set of nodes with the same API as NodeSet, using a bit
+// for each node ID of a graph, see Instance.ID, instead of a map entry, so
+// large sets of a graph's nodes take a fraction of the memory, and checking
+// if a node is in the set doesn't need to hash it.
+//
+// Nodes that aren't in the graph are kept in a map, so any node can be
+// added. Sets of the same graph are combined a word of bits at a time by
+// Union, Intersect, and Difference. The IDs of removed nodes are reused,
+// so a set of a graph's nodes shouldn't be kept while nodes are removed
+// from the graph and others added.
+//
+// Algorithms of an Instance use dense sets automatically for graphs with
+// many nodes, where they use less memory and time than a NodeSet.
+type DenseNodeSet struct {
+	graph  *Instance
+	bits   []uint64
+	others NodeSet
+}
+
+// NewDenseNodeSet returns a new DenseNodeSet for the nodes of the given
+// graph, which includes the given nodes. The graph can be nil, to only use
+// a map, like a NodeSet.
+func NewDenseNodeSet(inst *Instance, nodes ...*Node) *DenseNodeSet {
+	ns := &DenseNodeSet{graph: inst}
+	if inst != nil {
+		ns.bits = make([]uint64, (len(inst.byID)+63)/64)
+	}
+	for _, n := range nodes {
+		ns.Add(n)
+	}
+	return ns
+}
+
+// id returns the ID of the node in the set's graph, or -1.
+func (ns *DenseNodeSet) id(n *Node) int {
+	if ns.graph == nil {
+		return -1
+	}
+	return ns.graph.ID(n)
+}
+
+// Contains returns true if the given node is in the set.
+func (ns *DenseNodeSet) Contains(n *Node) bool {
+	if id := ns.id(n); id >= 0 {
+		return id/64 < len(ns.bits) && ns.bits[id/64]&(1<<(id%64)) != 0
+	}
+	return ns.others.Contains(n)
+}
+
+// Add adds the given node to the set.
+func (ns *DenseNodeSet) Add(n *Node) {
+	if id := ns.id(n); id >= 0 {
+		// Nodes added to the graph after the set was created can
+		// have larger IDs, so the bits grow to fit them.
+		for id/64 >= len(ns.bits) {
+			ns.bits = append(ns.bits, 0)
+		}
+		ns.bits[id/64] |= 1 << (id % 64)
+		return
+	}
+	if ns.others == nil {
+		ns.others = NodeSet{}
+	}
+	ns.others.Add(n)
+}
+
+// Remove removes the given node from the set.
+func (ns *DenseNodeSet) Remove(n *Node) {
+	if id := ns.id(n); id >= 0 {
+		if id/64 < len(ns.bits) {
+			ns.bits[id/64] &^= 1 << (id % 64)
+		}
+		return
+	}
+	delete(ns.others, n)
+}
+
+// Len returns the number of nodes in the set.
+func (ns *DenseNodeSet) Len() int {
+	count := len(ns.others)
+	for _, word := range ns.bits {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Empty returns true if the set is empty, false otherwise.
+func (ns *DenseNodeSet) Empty() bool {
+	if len(ns.others) > 0 {
+		return false
+	}
+	for _, word := range ns.bits {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Nodes returns a slice of nodes in the set, ordered by ID, followed by
+// the nodes that aren't in the graph.
+func (ns *DenseNodeSet) Nodes() []*Node {
+	nodes := make([]*Node, 0, ns.Len())
+	for i, word := range ns.bits {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			word &^= 1 << bit
+			if n := ns.graph.ByID(i*64 + bit); n != nil {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	for n := range ns.others {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// NodeSet returns the nodes in the set as a NodeSet.
+func (ns *DenseNodeSet) NodeSet() NodeSet {
+	return NewNodeSet(ns.Nodes()...)
+}
+
+// Sorted returns the nodes in the set ordered by name, the same order
+// used by String.
+func (ns *DenseNodeSet) Sorted() Nodes {
+	nodes := Nodes(ns.Nodes())
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// String returns a comma-separated list of node names in the set
+// in alphabetical order.
+func (ns *DenseNodeSet) String() string {
+	return strings.Join(ns.Sorted().Names(), ", ")
+}
+
+// Each calls the given function with each node in the set, ordered by
+// name, until the function returns false.
+func (ns *DenseNodeSet) Each(fn func(*Node) bool) {
+	for _, n := range ns.Sorted() {
+		if !fn(n) {
+			return
+		}
+	}
+}
+
+// SameAs returns true if the set contains the same nodes as the other set.
+func (ns *DenseNodeSet) SameAs(other *DenseNodeSet) bool {
+	return ns.Len() == other.Len() && ns.Difference(other).Empty()
+}
+
+// Union returns a new set of the nodes in the set or the other set.
+func (ns *DenseNodeSet) Union(other *DenseNodeSet) *DenseNodeSet {
+	return ns.combine(other, func(a, b uint64) uint64 { return a | b }, func(inSet, inOther bool) bool { return inSet || inOther })
+}
+
+// Intersect returns a new set of the nodes in both the set and the other set.
+func (ns *DenseNodeSet) Intersect(other *DenseNodeSet) *DenseNodeSet {
+	return ns.combine(other, func(a, b uint64) uint64 { return a & b }, func(inSet, inOther bool) bool { return inSet && inOther })
+}
+
+// Difference returns a new set of the nodes in the set that aren't in the
+// other set.
+func (ns *DenseNodeSet) Difference(other *DenseNodeSet) *DenseNodeSet {
+	return ns.combine(other, func(a, b uint64) uint64 { return a &^ b }, func(inSet, inOther bool) bool { return inSet && !inOther })
+}
+
+// combine returns a new set of the set's graph, combining the bits of the
+// sets with the given word function when they are of the same graph, and
+// the other nodes with the given membership function.
+func (ns *DenseNodeSet) combine(other *DenseNodeSet, word func(a, b uint64) uint64, keep func(inSet, inOther bool) bool) *DenseNodeSet {
+	result := NewDenseNodeSet(ns.graph)
+
+	if ns.graph == other.graph {
+		size := len(ns.bits)
+		if len(other.bits) > size {
+			size = len(other.bits)
+		}
+		result.bits = make([]uint64, size)
+		for i := range result.bits {
+			var a, b uint64
+			if i < len(ns.bits) {
+				a = ns.bits[i]
+			}
+			if i < len(other.bits) {
+				b = other.bits[i]
+			}
+			result.bits[i] = word(a, b)
+		}
+
+		for n := range ns.others {
+			if keep(true, other.others.Contains(n)) {
+				result.Add(n)
+			}
+		}
+		for n := range other.others {
+			if keep(ns.others.Contains(n), true) {
+				result.Add(n)
+			}
+		}
+		return result
+	}
+
+	for _, n := range ns.Nodes() {
+		if keep(true, other.Contains(n)) {
+			result.Add(n)
+		}
+	}
+	for _, n := range other.Nodes() {
+		if keep(ns.Contains(n), true) {
+			result.Add(n)
+		}
+	}
+	return result
+}
+
+// denseNodeSetThreshold is the number of nodes from which algorithms use a
+// DenseNodeSet instead of a NodeSet. Dense sets are faster and smaller even
+// for small graphs, see BenchmarkNodeSets, but the difference only matters
+// once the sets of large graphs dominate the time and memory used.
+const denseNodeSetThreshold = 1024
+
+// nodeSet is a set of nodes, either a NodeSet or a DenseNodeSet.
+type nodeSet interface {
+	Contains(*Node) bool
+	Add(*Node)
+	Len() int
+}
+
+// members returns the set of the graph's nodes, which is a DenseNodeSet
+// for graphs with many nodes, or a NodeSet otherwise.
+func (inst *Instance) members() nodeSet {
+	return inst.setOf(inst.Nodes)
+}
+
+// setOf returns a set of the given nodes of the graph, which is a
+// DenseNodeSet if there are many of them, or a NodeSet otherwise.
+func (inst *Instance) setOf(nodes Nodes) nodeSet {
+	if len(nodes) >= denseNodeSetThreshold {
+		return NewDenseNodeSet(inst, nodes...)
+	}
+	return NewNodeSet(nodes...)
+}
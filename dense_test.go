@@ -0,0 +1,129 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestDenseNodeSet(t *testing.T) {
+	g := graph.New("test")
+	for i := 0; i < 100; i++ {
+		g.AddNode(graph.NewNode(fmt.Sprintf("n%02d", i), nil))
+	}
+
+	outside := graph.NewNode("outside", nil)
+
+	a := graph.NewDenseNodeSet(g, g.Nodes[0], g.Nodes[70], outside)
+	b := graph.NewDenseNodeSet(g, g.Nodes[70], g.Nodes[99])
+
+	if !a.Contains(g.Nodes[70]) || !a.Contains(outside) || a.Contains(g.Nodes[99]) {
+		t.Fatalf("unexpected membership of %v", a)
+	}
+
+	if a.Len() != 3 || a.Empty() || !graph.NewDenseNodeSet(g).Empty() {
+		t.Fatalf("unexpected length %d of %v", a.Len(), a)
+	}
+
+	if got := a.Union(b).String(); got != "n00, n70, n99, outside" {
+		t.Fatalf("unexpected union: %s", got)
+	}
+
+	if got := a.Intersect(b).String(); got != "n70" {
+		t.Fatalf("unexpected intersection: %s", got)
+	}
+
+	if got := a.Difference(b).String(); got != "n00, outside" {
+		t.Fatalf("unexpected difference: %s", got)
+	}
+
+	if !a.NodeSet().SameAs(graph.NewNodeSet(g.Nodes[0], g.Nodes[70], outside)) {
+		t.Fatalf("unexpected node set: %v", a.NodeSet())
+	}
+
+	// Nodes added to the graph after the set was created can be added.
+	late := graph.NewNode("late", nil)
+	g.AddNode(late)
+	a.Add(late)
+	a.Remove(g.Nodes[0])
+	a.Remove(outside)
+
+	if got := graph.Nodes(a.Nodes()).String(); got != "n70, late" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+
+	if !a.SameAs(graph.NewDenseNodeSet(g, late, g.Nodes[70])) || a.SameAs(b) {
+		t.Fatalf("unexpected comparison of %v", a)
+	}
+
+	// Sets of different graphs are combined node by node.
+	other := graph.New("other", graph.WithNodes(graph.NewNodes(graph.NewNode("x", nil))))
+	if got := a.Union(graph.NewDenseNodeSet(other, other.Nodes...)).String(); got != "late, n70, x" {
+		t.Fatalf("unexpected union of different graphs: %s", got)
+	}
+}
+
+func TestDenseNodeSet_largeGraphs(t *testing.T) {
+	// Graphs with many nodes use dense sets in their algorithms.
+	g := graph.New("chain")
+	for i := 0; i < 2000; i++ {
+		node := graph.NewNode(fmt.Sprintf("n%d", i), nil)
+		if i > 0 {
+			g.Nodes[i-1].AddEdge(node)
+		}
+		g.AddNode(node)
+	}
+
+	sorted, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sorted) != 2000 || sorted[0] != g.Nodes[0] || sorted[1999] != g.Nodes[1999] {
+		t.Fatalf("unexpected topological order")
+	}
+
+	g.Nodes[1999].AddEdge(g.Nodes[0])
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fatal("expected an error for a cycle")
+	}
+
+	if cycles := g.CyclesContaining(g.Nodes[1000]); len(cycles) != 1 || len(cycles[0]) != 2001 {
+		t.Fatalf("unexpected cycles: %d", len(cycles))
+	}
+}
+
+func BenchmarkNodeSets(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		g := graph.New("bench")
+		for i := 0; i < size; i++ {
+			g.AddNode(graph.NewNode(fmt.Sprintf("n%d", i), nil))
+		}
+
+		b.Run(fmt.Sprintf("NodeSet/%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				set := graph.NodeSet{}
+				for _, node := range g.Nodes {
+					if !set.Contains(node) {
+						set.Add(node)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("DenseNodeSet/%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				set := graph.NewDenseNodeSet(g)
+				for _, node := range g.Nodes {
+					if !set.Contains(node) {
+						set.Add(node)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,504 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Diff describes the changes between two versions of a graph, returned
+// by Compare.
+type Diff struct {
+	// AddedNodes and RemovedNodes are the nodes only in the new or the old
+	// graph, and ChangedNodes are the nodes in both whose labels or
+	// attributes changed.
+	AddedNodes   Nodes
+	RemovedNodes Nodes
+	ChangedNodes []NodeChange
+
+	// AddedEdges and RemovedEdges are the edges only in the new or the old
+	// graph, and ChangedEdges are the edges in both whose attributes
+	// changed. Each relationship is included once, like Edges.
+	AddedEdges   Edges
+	RemovedEdges Edges
+	ChangedEdges []EdgeChange
+}
+
+// NodeChange is a node whose labels or attributes changed between two
+// versions of a graph.
+type NodeChange struct {
+	Before, After *Node
+}
+
+// EdgeChange is an edge whose attributes changed between two versions of
+// a graph.
+type EdgeChange struct {
+	Before, After *Edge
+}
+
+// Compare returns the changes from the before graph to the after graph.
+//
+// Nodes are matched by name, and edges by the names of their nodes, their
+// direction, and their name, so undirected edges match regardless of which
+// side they were added from. Parallel edges are matched in order. Like
+// Equal, attribute values are compared using their Go syntax
+// representation. Changes are ordered by name, then by the names of
+// their nodes, so they can be compared and rendered reproducibly.
+func Compare(before, after *Instance) *Diff {
+	d := &Diff{}
+
+	beforeNodes, afterNodes := nodesByName(before.Nodes), nodesByName(after.Nodes)
+
+	for name, node := range afterNodes {
+		old, ok := beforeNodes[name]
+		switch {
+		case !ok:
+			d.AddedNodes = append(d.AddedNodes, node)
+		case !labelsEqual(old.Labels, node.Labels) || canonicalAttributes(old.Attributes) != canonicalAttributes(node.Attributes):
+			d.ChangedNodes = append(d.ChangedNodes, NodeChange{Before: old, After: node})
+		}
+	}
+
+	for name, node := range beforeNodes {
+		if _, ok := afterNodes[name]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, node)
+		}
+	}
+
+	beforeEdges, afterEdges := edgesByKey(before.Nodes), edgesByKey(after.Nodes)
+
+	for key, edges := range afterEdges {
+		old := beforeEdges[key]
+		for i, edge := range edges {
+			switch {
+			case i >= len(old):
+				d.AddedEdges = append(d.AddedEdges, edge)
+			case canonicalAttributes(old[i].Attributes) != canonicalAttributes(edge.Attributes):
+				d.ChangedEdges = append(d.ChangedEdges, EdgeChange{Before: old[i], After: edge})
+			}
+		}
+	}
+
+	for key, edges := range beforeEdges {
+		for i := len(afterEdges[key]); i < len(edges); i++ {
+			d.RemovedEdges = append(d.RemovedEdges, edges[i])
+		}
+	}
+
+	sortNodesByName(d.AddedNodes)
+	sortNodesByName(d.RemovedNodes)
+	sort.Slice(d.ChangedNodes, func(i, j int) bool {
+		return d.ChangedNodes[i].After.Name < d.ChangedNodes[j].After.Name
+	})
+
+	sortEdgesByKey(d.AddedEdges)
+	sortEdgesByKey(d.RemovedEdges)
+	sort.SliceStable(d.ChangedEdges, func(i, j int) bool {
+		return edgeKey(d.ChangedEdges[i].After) < edgeKey(d.ChangedEdges[j].After)
+	})
+
+	return d
+}
+
+// Empty checks if the diff doesn't have any changes.
+func (d *Diff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ChangedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.ChangedEdges) == 0
+}
+
+// nodesByName returns the given nodes by name, keeping the first node with
+// each name.
+func nodesByName(nodes Nodes) map[string]*Node {
+	byName := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		if _, ok := byName[node.Name]; !ok {
+			byName[node.Name] = node
+		}
+	}
+	return byName
+}
+
+// labelsEqual checks if the given labels are the same, in any order.
+func labelsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// edgesByKey returns the unique edges of the given nodes by their key, see
+// edgeKey, in the order they were found.
+func edgesByKey(nodes Nodes) map[string]Edges {
+	byKey := map[string]Edges{}
+	eachUniqueEdge(nodes, func(_ *Node, edge *Edge) {
+		key := edgeKey(edge)
+		byKey[key] = append(byKey[key], edge)
+	})
+	return byKey
+}
+
+// edgeKey returns the key edges are matched by, which is the same for
+// both edges of a relationship: the names of its nodes, in the direction
+// of the relationship, or sorted if it is undirected, its direction, and
+// its name.
+func edgeKey(edge *Edge) string {
+	from, to, dir := nodeName(edge.from), nodeName(edge.Node), edge.Direction
+	switch {
+	case dir == In:
+		from, to, dir = to, from, Out
+	case dir != Out && to < from:
+		from, to = to, from
+	}
+	return fmt.Sprintf("%q %s %q %q", from, dir, to, edge.Name)
+}
+
+// sortNodesByName sorts the given nodes by name.
+func sortNodesByName(nodes Nodes) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// sortEdgesByKey sorts the given edges by their key, see edgeKey.
+func sortEdgesByKey(edges Edges) {
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edgeKey(edges[i]) < edgeKey(edges[j])
+	})
+}
+
+// DiffFormat is a format a Diff can be rendered using.
+type DiffFormat string
+
+const (
+	// DiffText renders one change per line, prefixed with "+" for added,
+	// "-" for removed, and "~" for changed nodes and edges.
+	DiffText DiffFormat = "text"
+
+	// DiffMarkdown renders a section for each kind of change, suitable
+	// for posting to pull request comments.
+	DiffMarkdown DiffFormat = "markdown"
+
+	// DiffJSON renders the changes as a JSON object.
+	DiffJSON DiffFormat = "json"
+)
+
+// Render writes a report of the changes using the given format.
+//
+//	graph.Compare(before, after).Render(os.Stdout, graph.DiffText)
+//	+ node c {color=red}
+//	- node d {labels=db}
+//	~ node a {color=blue} → {color=red}
+//	+ edge a → c
+//	- edge b → d
+//	~ edge a → b {weight=1} → {weight=2}
+//
+// Edges are written like Edge.String, from the node they were added from.
+// Reports of empty diffs are empty, except for JSON, which always writes
+// an object with every kind of change.
+func (d *Diff) Render(w io.Writer, format DiffFormat) error {
+	var err error
+
+	switch format {
+	case DiffText:
+		err = d.renderText(w)
+	case DiffMarkdown:
+		err = d.renderMarkdown(w)
+	case DiffJSON:
+		err = d.renderJSON(w)
+	default:
+		return fmt.Errorf("graph cannot render diff using unknown format %q", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("graph failed to render diff: %w", err)
+	}
+	return nil
+}
+
+// diffLine is a change in a diff, rendered as a line of a text or markdown
+// report.
+type diffLine struct {
+	kind   string // "node" or "edge"
+	name   string
+	before Attributes
+	after  Attributes
+}
+
+// attributes returns the attributes of an added or removed node or edge.
+func (line diffLine) attributes() Attributes {
+	if line.after != nil {
+		return line.after
+	}
+	return line.before
+}
+
+// lines returns the added, removed, and changed nodes and edges of the diff.
+func (d *Diff) lines() (added, removed, changed []diffLine) {
+	for _, node := range d.AddedNodes {
+		added = append(added, diffLine{kind: "node", name: node.Name, after: nodeReportAttributes(node)})
+	}
+	for _, edge := range d.AddedEdges {
+		added = append(added, diffLine{kind: "edge", name: edge.String(), after: edge.Attributes})
+	}
+
+	for _, node := range d.RemovedNodes {
+		removed = append(removed, diffLine{kind: "node", name: node.Name, before: nodeReportAttributes(node)})
+	}
+	for _, edge := range d.RemovedEdges {
+		removed = append(removed, diffLine{kind: "edge", name: edge.String(), before: edge.Attributes})
+	}
+
+	for _, change := range d.ChangedNodes {
+		changed = append(changed, diffLine{
+			kind:   "node",
+			name:   change.After.Name,
+			before: nodeReportAttributes(change.Before),
+			after:  nodeReportAttributes(change.After),
+		})
+	}
+	for _, change := range d.ChangedEdges {
+		changed = append(changed, diffLine{
+			kind:   "edge",
+			name:   change.After.String(),
+			before: change.Before.Attributes,
+			after:  change.After.Attributes,
+		})
+	}
+
+	return added, removed, changed
+}
+
+// nodeReportAttributes returns the attributes of the node to report,
+// including its labels, joined by colons like EncodeDOT.
+func nodeReportAttributes(node *Node) Attributes {
+	if len(node.Labels) == 0 {
+		return node.Attributes
+	}
+
+	labels := append([]string(nil), node.Labels...)
+	sort.Strings(labels)
+
+	attrs := copyAttributes(node.Attributes)
+	if attrs == nil {
+		attrs = Attributes{}
+	}
+	attrs["labels"] = strings.Join(labels, ":")
+	return attrs
+}
+
+// reportAttributes returns a human-readable representation of the given
+// attributes, sorted by name.
+//
+//	{color=red, weight=2}
+func reportAttributes(attrs Attributes) string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", key, attrs[key])
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func (d *Diff) renderText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	added, removed, changed := d.lines()
+
+	for _, lines := range []struct {
+		prefix string
+		lines  []diffLine
+	}{
+		{"+", added},
+		{"-", removed},
+	} {
+		for _, line := range lines.lines {
+			bw.WriteString(fmt.Sprintf("%s %s %s", lines.prefix, line.kind, line.name))
+			if attrs := line.attributes(); len(attrs) > 0 {
+				bw.WriteString(" " + reportAttributes(attrs))
+			}
+			bw.WriteString("\n")
+		}
+	}
+
+	for _, line := range changed {
+		bw.WriteString(fmt.Sprintf("~ %s %s %s %s %s\n", line.kind, line.name, reportAttributes(line.before), Stringer.Out, reportAttributes(line.after)))
+	}
+
+	return bw.Flush()
+}
+
+func (d *Diff) renderMarkdown(w io.Writer) error {
+	if d.Empty() {
+		return nil
+	}
+
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("### Graph changes\n\n")
+	bw.WriteString("| | Added | Removed | Changed |\n|---|---|---|---|\n")
+	bw.WriteString(fmt.Sprintf("| Nodes | %d | %d | %d |\n", len(d.AddedNodes), len(d.RemovedNodes), len(d.ChangedNodes)))
+	bw.WriteString(fmt.Sprintf("| Edges | %d | %d | %d |\n", len(d.AddedEdges), len(d.RemovedEdges), len(d.ChangedEdges)))
+
+	added, removed, changed := d.lines()
+
+	for _, section := range []struct {
+		title string
+		lines []diffLine
+	}{
+		{"Added", added},
+		{"Removed", removed},
+		{"Changed", changed},
+	} {
+		if len(section.lines) == 0 {
+			continue
+		}
+
+		bw.WriteString(fmt.Sprintf("\n#### %s\n\n", section.title))
+
+		for _, line := range section.lines {
+			bw.WriteString(fmt.Sprintf("- %s `%s`", line.kind, line.name))
+			switch {
+			case section.title == "Changed":
+				bw.WriteString(fmt.Sprintf(": `%s` → `%s`", reportAttributes(line.before), reportAttributes(line.after)))
+			case len(line.attributes()) > 0:
+				bw.WriteString(fmt.Sprintf(" `%s`", reportAttributes(line.attributes())))
+			}
+			bw.WriteString("\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+type diffNodeJSON struct {
+	Name       string   `json:"name"`
+	Labels     []string `json:"labels,omitempty"`
+	Attributes `json:"attributes,omitempty"`
+}
+
+type diffEdgeJSON struct {
+	From       string        `json:"from"`
+	Direction  EdgeDirection `json:"direction"`
+	To         string        `json:"to"`
+	Name       string        `json:"name,omitempty"`
+	Attributes `json:"attributes,omitempty"`
+}
+
+type diffNodeChangeJSON struct {
+	Before diffNodeJSON `json:"before"`
+	After  diffNodeJSON `json:"after"`
+}
+
+type diffEdgeChangeJSON struct {
+	Before diffEdgeJSON `json:"before"`
+	After  diffEdgeJSON `json:"after"`
+}
+
+type diffJSON struct {
+	AddedNodes   []diffNodeJSON       `json:"added_nodes"`
+	RemovedNodes []diffNodeJSON       `json:"removed_nodes"`
+	ChangedNodes []diffNodeChangeJSON `json:"changed_nodes"`
+	AddedEdges   []diffEdgeJSON       `json:"added_edges"`
+	RemovedEdges []diffEdgeJSON       `json:"removed_edges"`
+	ChangedEdges []diffEdgeChangeJSON `json:"changed_edges"`
+}
+
+func (d *Diff) renderJSON(w io.Writer) error {
+	node := func(n *Node) (diffNodeJSON, error) {
+		attrs, err := marshalAttributes(n.Attributes)
+		return diffNodeJSON{Name: n.Name, Labels: n.Labels, Attributes: attrs}, err
+	}
+
+	edge := func(e *Edge) (diffEdgeJSON, error) {
+		attrs, err := marshalAttributes(e.Attributes)
+		return diffEdgeJSON{From: nodeName(e.from), Direction: e.Direction, To: nodeName(e.Node), Name: e.Name, Attributes: attrs}, err
+	}
+
+	dj := diffJSON{
+		AddedNodes:   []diffNodeJSON{},
+		RemovedNodes: []diffNodeJSON{},
+		ChangedNodes: []diffNodeChangeJSON{},
+		AddedEdges:   []diffEdgeJSON{},
+		RemovedEdges: []diffEdgeJSON{},
+		ChangedEdges: []diffEdgeChangeJSON{},
+	}
+
+	for _, nodes := range []struct {
+		nodes Nodes
+		into  *[]diffNodeJSON
+	}{
+		{d.AddedNodes, &dj.AddedNodes},
+		{d.RemovedNodes, &dj.RemovedNodes},
+	} {
+		for _, n := range nodes.nodes {
+			nj, err := node(n)
+			if err != nil {
+				return err
+			}
+			*nodes.into = append(*nodes.into, nj)
+		}
+	}
+
+	for _, edges := range []struct {
+		edges Edges
+		into  *[]diffEdgeJSON
+	}{
+		{d.AddedEdges, &dj.AddedEdges},
+		{d.RemovedEdges, &dj.RemovedEdges},
+	} {
+		for _, e := range edges.edges {
+			ej, err := edge(e)
+			if err != nil {
+				return err
+			}
+			*edges.into = append(*edges.into, ej)
+		}
+	}
+
+	for _, change := range d.ChangedNodes {
+		before, err := node(change.Before)
+		if err != nil {
+			return err
+		}
+		after, err := node(change.After)
+		if err != nil {
+			return err
+		}
+		dj.ChangedNodes = append(dj.ChangedNodes, diffNodeChangeJSON{before, after})
+	}
+
+	for _, change := range d.ChangedEdges {
+		before, err := edge(change.Before)
+		if err != nil {
+			return err
+		}
+		after, err := edge(change.After)
+		if err != nil {
+			return err
+		}
+		dj.ChangedEdges = append(dj.ChangedEdges, diffEdgeChangeJSON{before, after})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(dj)
+}
@@ -0,0 +1,201 @@
+package graph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+// diffGraphs returns two versions of a graph:
+//
+//	a → b → d       a → b
+//	    ↓               ↓
+//	    c               c - e
+func diffGraphs() (before, after *graph.Instance) {
+	var (
+		a1 = graph.NewNode("a", graph.Attributes{"color": "blue"})
+		b1 = graph.NewNode("b", nil)
+		c1 = graph.NewNode("c", nil)
+		d1 = graph.NewNode("d", nil)
+	)
+
+	d1.AddLabel("db")
+
+	a1.AddWeightedEdge(b1, 1)
+	b1.AddEdge(c1)
+	b1.AddEdge(d1)
+
+	var (
+		a2 = graph.NewNode("a", graph.Attributes{"color": "red"})
+		b2 = graph.NewNode("b", nil)
+		c2 = graph.NewNode("c", nil)
+		e2 = graph.NewNode("e", graph.Attributes{"tier": "cache"})
+	)
+
+	a2.AddWeightedEdge(b2, 2)
+	b2.AddEdge(c2)
+	c2.AddEdgeWithDirection(e2, graph.None)
+
+	before = graph.New("before", graph.WithNodes(graph.NewNodes(a1, b1, c1, d1)))
+	after = graph.New("after", graph.WithNodes(graph.NewNodes(a2, b2, c2, e2)))
+
+	return before, after
+}
+
+func TestCompare(t *testing.T) {
+	before, after := diffGraphs()
+
+	d := graph.Compare(before, after)
+
+	if d.Empty() {
+		t.Fatal("expected changes")
+	}
+
+	if len(d.AddedNodes) != 1 || d.AddedNodes[0].Name != "e" {
+		t.Fatalf("unexpected added nodes: %v", d.AddedNodes)
+	}
+
+	if len(d.RemovedNodes) != 1 || d.RemovedNodes[0].Name != "d" {
+		t.Fatalf("unexpected removed nodes: %v", d.RemovedNodes)
+	}
+
+	if len(d.ChangedNodes) != 1 || d.ChangedNodes[0].After.Name != "a" {
+		t.Fatalf("unexpected changed nodes: %v", d.ChangedNodes)
+	}
+
+	if len(d.AddedEdges) != 1 || d.AddedEdges[0].String() != "c - e" {
+		t.Fatalf("unexpected added edges: %v", d.AddedEdges)
+	}
+
+	if len(d.RemovedEdges) != 1 || d.RemovedEdges[0].String() != "b → d" {
+		t.Fatalf("unexpected removed edges: %v", d.RemovedEdges)
+	}
+
+	if len(d.ChangedEdges) != 1 || d.ChangedEdges[0].After.Weight() != 2 {
+		t.Fatalf("unexpected changed edges: %v", d.ChangedEdges)
+	}
+
+	// Undirected edges match regardless of the side they were added from.
+	var (
+		x = graph.NewNode("x", nil)
+		y = graph.NewNode("y", nil)
+	)
+	y.AddEdgeWithDirection(x, graph.None)
+
+	if d := graph.Compare(after, graph.New("copy", graph.WithNodes(append(after.Clone().Nodes, x, y)))); len(d.AddedEdges) != 1 || len(d.AddedNodes) != 2 {
+		t.Fatalf("unexpected diff: %+v", d)
+	}
+
+	if d := graph.Compare(after, after.Clone()); !d.Empty() {
+		t.Fatalf("expected no changes, got: %+v", d)
+	}
+}
+
+const diff_text_golden = `+ node e {tier=cache}
++ edge c - e
+- node d {labels=db}
+- edge b → d
+~ node a {color=blue} → {color=red}
+~ edge a → b {weight=1} → {weight=2}
+`
+
+const diff_markdown_golden = "### Graph changes\n" +
+	"\n" +
+	"| | Added | Removed | Changed |\n" +
+	"|---|---|---|---|\n" +
+	"| Nodes | 1 | 1 | 1 |\n" +
+	"| Edges | 1 | 1 | 1 |\n" +
+	"\n" +
+	"#### Added\n" +
+	"\n" +
+	"- node `e` `{tier=cache}`\n" +
+	"- edge `c - e`\n" +
+	"\n" +
+	"#### Removed\n" +
+	"\n" +
+	"- node `d` `{labels=db}`\n" +
+	"- edge `b → d`\n" +
+	"\n" +
+	"#### Changed\n" +
+	"\n" +
+	"- node `a`: `{color=blue}` → `{color=red}`\n" +
+	"- edge `a → b`: `{weight=1}` → `{weight=2}`\n"
+
+func TestDiff_Render(t *testing.T) {
+	before, after := diffGraphs()
+
+	d := graph.Compare(before, after)
+
+	for format, golden := range map[graph.DiffFormat]string{
+		graph.DiffText:     diff_text_golden,
+		graph.DiffMarkdown: diff_markdown_golden,
+	} {
+		buf := bytes.NewBuffer(nil)
+
+		if err := d.Render(buf, format); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != golden {
+			t.Fatalf("%s: got:\n%s\ngolden:\n%s\n", format, buf.String(), golden)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := d.Render(buf, graph.DiffJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var report struct {
+		AddedNodes []struct {
+			Name       string         `json:"name"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"added_nodes"`
+		RemovedEdges []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"removed_edges"`
+		ChangedEdges []struct {
+			After struct {
+				Attributes map[string]any `json:"attributes"`
+			} `json:"after"`
+		} `json:"changed_edges"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.AddedNodes) != 1 || report.AddedNodes[0].Name != "e" || report.AddedNodes[0].Attributes["tier"] != "cache" {
+		t.Fatalf("unexpected added nodes: %+v", report.AddedNodes)
+	}
+
+	if len(report.RemovedEdges) != 1 || report.RemovedEdges[0].From != "b" || report.RemovedEdges[0].To != "d" {
+		t.Fatalf("unexpected removed edges: %+v", report.RemovedEdges)
+	}
+
+	if len(report.ChangedEdges) != 1 || report.ChangedEdges[0].After.Attributes["weight"] != 2.0 {
+		t.Fatalf("unexpected changed edges: %+v", report.ChangedEdges)
+	}
+
+	buf.Reset()
+
+	empty := graph.Compare(after, after)
+
+	for _, format := range []graph.DiffFormat{graph.DiffText, graph.DiffMarkdown} {
+		if err := empty.Render(buf, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected empty reports, got:\n%s", buf.String())
+	}
+
+	if err := d.Render(buf, "html"); err == nil {
+		t.Fatal("expected an error rendering an unknown format")
+	}
+}
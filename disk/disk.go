@@ -0,0 +1,265 @@
+// Package disk provides a read-only, disk-backed graph for graphs that don't
+// fit in memory, along with a builder to write them.
+//
+// The graph is stored in a single file using a compressed sparse row (CSR)
+// layout, so only a small header is kept in memory, and nodes, names, and
+// edges are read from the file on demand.
+//
+// The file format only depends on the standard library, and is laid out as:
+//
+//	header     magic, version, node count, edge count, section offsets
+//	nodes      per node offset of its record in the data section
+//	out        per node offset into the out targets, followed by the targets
+//	in         per node offset into the in targets, followed by the targets
+//	names      node IDs sorted by name, for binary search lookups
+//	data       per node name and JSON encoded attributes
+package disk
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/picatz/graph"
+)
+
+// magic identifies the disk graph file format.
+const magic = "GRAPHDSK"
+
+// version is the current version of the file format.
+const version = 1
+
+// headerSize is the size of the file header in bytes.
+const headerSize = 8 + 8*8
+
+// header is the fixed size header at the start of the file.
+type header struct {
+	Version  uint64
+	Nodes    uint64
+	Edges    uint64
+	NodesOff uint64
+	OutOff   uint64
+	InOff    uint64
+	NamesOff uint64
+	DataOff  uint64
+}
+
+// Builder accumulates nodes and edges to write a disk graph file.
+//
+// Only the node names and edge endpoints are kept in memory while
+// building, attributes are written to a temporary file as nodes are added.
+type Builder struct {
+	names []string
+	data  *os.File
+	offs  []uint64
+	size  uint64
+	from  []uint32
+	to    []uint32
+}
+
+// NewBuilder returns a new builder, using a temporary file in the given
+// directory (or the default directory if empty) to hold node data.
+func NewBuilder(dir string) (*Builder, error) {
+	data, err := os.CreateTemp(dir, "graph-disk-data-*")
+	if err != nil {
+		return nil, fmt.Errorf("disk failed to create builder: %w", err)
+	}
+	return &Builder{data: data}, nil
+}
+
+// AddNode adds a node with the given name and attributes, returning its ID.
+func (b *Builder) AddNode(name string, attrs graph.Attributes) (int32, error) {
+	var attrsJSON []byte
+	if len(attrs) > 0 {
+		var err error
+		attrsJSON, err = json.Marshal(attrs)
+		if err != nil {
+			return -1, fmt.Errorf("disk failed to encode attributes of %q: %w", name, err)
+		}
+	}
+
+	record := make([]byte, 0, 8+len(name)+len(attrsJSON))
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(name)))
+	record = append(record, name...)
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(attrsJSON)))
+	record = append(record, attrsJSON...)
+
+	if _, err := b.data.Write(record); err != nil {
+		return -1, fmt.Errorf("disk failed to write node %q: %w", name, err)
+	}
+
+	id := int32(len(b.names))
+	b.names = append(b.names, name)
+	b.offs = append(b.offs, b.size)
+	b.size += uint64(len(record))
+
+	return id, nil
+}
+
+// AddEdge adds a directed edge between the nodes with the given IDs.
+func (b *Builder) AddEdge(from, to int32) error {
+	if from < 0 || int(from) >= len(b.names) || to < 0 || int(to) >= len(b.names) {
+		return fmt.Errorf("disk invalid edge %d → %d for %d nodes", from, to, len(b.names))
+	}
+	b.from = append(b.from, uint32(from))
+	b.to = append(b.to, uint32(to))
+	return nil
+}
+
+// Close discards the builder's temporary data, without writing a graph.
+func (b *Builder) Close() error {
+	b.data.Close()
+	return os.Remove(b.data.Name())
+}
+
+// WriteFile writes the disk graph file to the given path, and closes the builder.
+func (b *Builder) WriteFile(path string) (err error) {
+	defer func() {
+		if closeErr := b.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("disk failed to create %q: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("disk failed to close %q: %w", path, closeErr)
+		}
+	}()
+
+	n, m := uint64(len(b.names)), uint64(len(b.from))
+
+	h := header{Version: version, Nodes: n, Edges: m}
+	h.NodesOff = headerSize
+	h.OutOff = h.NodesOff + 8*n
+	h.InOff = h.OutOff + 8*(n+1) + 4*m
+	h.NamesOff = h.InOff + 8*(n+1) + 4*m
+	h.DataOff = h.NamesOff + 4*n
+
+	w := &errWriter{w: f}
+
+	w.write([]byte(magic))
+	w.uint64s(h.Version, h.Nodes, h.Edges, h.NodesOff, h.OutOff, h.InOff, h.NamesOff, h.DataOff)
+
+	w.uint64s(b.offs...)
+
+	writeCSR(w, len(b.names), b.from, b.to)
+	writeCSR(w, len(b.names), b.to, b.from)
+
+	ids := make([]uint32, n)
+	for i := range ids {
+		ids[i] = uint32(i)
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return b.names[ids[i]] < b.names[ids[j]]
+	})
+	w.uint32s(ids...)
+
+	if w.err != nil {
+		return fmt.Errorf("disk failed to write %q: %w", path, w.err)
+	}
+
+	if _, err := b.data.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("disk failed to read builder data: %w", err)
+	}
+
+	if _, err := io.Copy(f, b.data); err != nil {
+		return fmt.Errorf("disk failed to write %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeCSR writes the compressed sparse row offsets and targets of the
+// given edges, grouped by their source node.
+func writeCSR(w *errWriter, n int, sources, targets []uint32) {
+	offsets := make([]uint64, n+1)
+	for _, s := range sources {
+		offsets[s+1]++
+	}
+	for i := 0; i < n; i++ {
+		offsets[i+1] += offsets[i]
+	}
+
+	sorted := make([]uint32, len(targets))
+	fill := append([]uint64{}, offsets[:n]...)
+	for i, s := range sources {
+		sorted[fill[s]] = targets[i]
+		fill[s]++
+	}
+
+	w.uint64s(offsets...)
+	w.uint32s(sorted...)
+}
+
+// errWriter is a writer that remembers the first error, so a sequence of
+// writes can be checked once.
+type errWriter struct {
+	w   io.Writer
+	err error
+	buf []byte
+}
+
+func (ew *errWriter) write(b []byte) {
+	if ew.err == nil {
+		_, ew.err = ew.w.Write(b)
+	}
+}
+
+func (ew *errWriter) uint64s(vs ...uint64) {
+	ew.buf = ew.buf[:0]
+	for _, v := range vs {
+		ew.buf = binary.LittleEndian.AppendUint64(ew.buf, v)
+	}
+	ew.write(ew.buf)
+}
+
+func (ew *errWriter) uint32s(vs ...uint32) {
+	ew.buf = ew.buf[:0]
+	for _, v := range vs {
+		ew.buf = binary.LittleEndian.AppendUint32(ew.buf, v)
+	}
+	ew.write(ew.buf)
+}
+
+// Write writes the given graph instance to a disk graph file at the given
+// path. Every edge that isn't an inward edge is written as a directed edge,
+// and edges to nodes outside of the graph instance are ignored.
+func Write(path string, inst *graph.Instance) error {
+	b, err := NewBuilder("")
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[*graph.Node]int32, len(inst.Nodes))
+	for _, node := range inst.Nodes {
+		id, err := b.AddNode(node.Name, node.Attributes)
+		if err != nil {
+			b.Close()
+			return err
+		}
+		ids[node] = id
+	}
+
+	for _, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if edge.Direction == graph.In {
+				continue
+			}
+			if to, ok := ids[edge.Node]; ok {
+				if err := b.AddEdge(ids[node], to); err != nil {
+					b.Close()
+					return err
+				}
+			}
+		}
+	}
+
+	return b.WriteFile(path)
+}
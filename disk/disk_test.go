@@ -0,0 +1,155 @@
+package disk_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/disk"
+)
+
+func TestDiskGraph(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"example": true})
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c    d ↔ e
+	// └───────↑
+
+	graph.ConnectNodes(a, b, c)
+	a.AddEdge(c)
+	d.AddLink(e)
+
+	path := filepath.Join(t.TempDir(), "graph.disk")
+
+	err := disk.Write(path, graph.New("test", graph.WithNodes(graph.NewNodes(e, d, c, b, a))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := disk.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	if g.Len() != 5 || g.EdgeCount() != 5 {
+		t.Fatalf("expected 5 nodes and edges, got %d and %d", g.Len(), g.EdgeCount())
+	}
+
+	ia, ok := g.Lookup("a")
+	if !ok {
+		t.Fatalf("expected to find node a")
+	}
+
+	ic, _ := g.Lookup("c")
+	ie, _ := g.Lookup("e")
+
+	if _, ok := g.Lookup("missing"); ok {
+		t.Fatalf("did not expect to find a missing node")
+	}
+
+	if g.Attributes(ia)["example"] != true {
+		t.Fatalf("expected attributes to be kept: %v", g.Attributes(ia))
+	}
+
+	if path := g.PathString(g.PathTo(ia, ic)); path != "a → c" {
+		t.Fatalf("expected shortest path a → c, got %q", path)
+	}
+
+	if g.HasPath(ic, ia) {
+		t.Fatalf("did not expect c to have a path to a")
+	}
+
+	if len(g.In(ic)) != 2 {
+		t.Fatalf("expected c to have 2 inward edges, got %d", len(g.In(ic)))
+	}
+
+	var visited []string
+	g.BFS(ia, func(id int32) bool {
+		visited = append(visited, g.Name(id))
+		return true
+	})
+
+	if len(visited) != 3 || visited[0] != "a" {
+		t.Fatalf("unexpected BFS visit order: %v", visited)
+	}
+
+	components := g.Components()
+	if len(components) != 2 || components[0][0] != ie || len(components[0]) != 2 {
+		t.Fatalf("unexpected components: %v", components)
+	}
+
+	start, _ := g.FindNode("a")
+	end, _ := g.FindNode("c")
+
+	if refs, weight := graph.ShortestPath(g, start, end); len(refs) != 2 || weight != 1 {
+		t.Fatalf("unexpected shortest path with weight %v: %v", weight, refs)
+	}
+
+	if err := g.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskGraph_corrupted(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	a.AddEdge(b)
+
+	path := filepath.Join(t.TempDir(), "graph.disk")
+
+	if err := disk.Write(path, graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite the first target of the out section, after the magic, the
+	// version, the counts, the offset of the nodes section, and the node
+	// offsets into the targets.
+	out := binary.LittleEndian.Uint64(data[8+8*4:])
+	binary.LittleEndian.PutUint32(data[out+8*3:], 1000)
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := disk.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.DFS(0, func(int32) bool { return true })
+			g.Components()
+		}()
+	}
+	wg.Wait()
+
+	if g.Err() == nil {
+		t.Fatal("expected an error for a node ID out of range")
+	}
+
+	if g.Out(-1) != nil || g.Name(5) != "" {
+		t.Fatal("expected nothing for node IDs out of range")
+	}
+}
@@ -0,0 +1,486 @@
+package disk
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/picatz/graph"
+)
+
+// Graph is a read-only graph backed by a file written by a Builder, where
+// nodes are identified by their int32 index like graph.CompactGraph.
+//
+// Reads happen on demand, so methods don't return errors directly. Instead,
+// the first read error, or the first node ID out of range, whether given or
+// read from a corrupted file, is recorded and returned by Err, after which
+// methods return zero values. Graphs can be read concurrently.
+type Graph struct {
+	file *os.File
+	h    header
+
+	mu  sync.Mutex
+	err error
+}
+
+// Open opens the disk graph file at the given path.
+func Open(path string) (*Graph, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("disk failed to open %q: %w", path, err)
+	}
+
+	g := &Graph{file: file}
+
+	buf := make([]byte, headerSize)
+	if _, err := file.ReadAt(buf, 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("disk failed to read header of %q: %w", path, err)
+	}
+
+	if string(buf[:len(magic)]) != magic {
+		file.Close()
+		return nil, fmt.Errorf("disk file %q is not a disk graph", path)
+	}
+
+	fields := []*uint64{&g.h.Version, &g.h.Nodes, &g.h.Edges, &g.h.NodesOff, &g.h.OutOff, &g.h.InOff, &g.h.NamesOff, &g.h.DataOff}
+	for i, field := range fields {
+		*field = binary.LittleEndian.Uint64(buf[len(magic)+8*i:])
+	}
+
+	if g.h.Version != version {
+		file.Close()
+		return nil, fmt.Errorf("disk file %q has unsupported version %d", path, g.h.Version)
+	}
+
+	// Each node takes at least 8 bytes of the file, and each edge 4, so
+	// corrupted counts aren't used to size the bitsets of traversals.
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("disk failed to stat %q: %w", path, err)
+	}
+	if size := uint64(info.Size()); g.h.Nodes > size/8 || g.h.Edges > size/4 {
+		file.Close()
+		return nil, fmt.Errorf("disk file %q is corrupted: %d nodes and %d edges don't fit in %d bytes", path, g.h.Nodes, g.h.Edges, size)
+	}
+
+	return g, nil
+}
+
+// Close closes the underlying file.
+func (g *Graph) Close() error {
+	return g.file.Close()
+}
+
+// Err returns the first error that occurred while reading the file.
+func (g *Graph) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.err
+}
+
+// failed checks if an error was recorded.
+func (g *Graph) failed() bool {
+	return g.Err() != nil
+}
+
+// fail records the given error, unless one was recorded before.
+func (g *Graph) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+// valid checks if the given node ID is in range, recording an error if not.
+func (g *Graph) valid(id int32) bool {
+	if id >= 0 && uint64(id) < g.h.Nodes {
+		return true
+	}
+	g.fail(fmt.Errorf("disk node ID %d is out of range", id))
+	return false
+}
+
+// read reads len(buf) bytes at the given offset, recording any error.
+func (g *Graph) read(buf []byte, off uint64) bool {
+	if g.failed() {
+		return false
+	}
+	if _, err := g.file.ReadAt(buf, int64(off)); err != nil {
+		g.fail(fmt.Errorf("disk failed to read at offset %d: %w", off, err))
+		return false
+	}
+	return true
+}
+
+// uint64At reads a uint64 at the given offset.
+func (g *Graph) uint64At(off uint64) uint64 {
+	var buf [8]byte
+	if !g.read(buf[:], off) {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// uint32At reads a uint32 at the given offset.
+func (g *Graph) uint32At(off uint64) uint32 {
+	var buf [4]byte
+	if !g.read(buf[:], off) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(buf[:])
+}
+
+// Len returns the number of nodes in the graph.
+func (g *Graph) Len() int {
+	return int(g.h.Nodes)
+}
+
+// EdgeCount returns the number of directed edges in the graph.
+func (g *Graph) EdgeCount() int {
+	return int(g.h.Edges)
+}
+
+// record reads the name and attributes JSON of the node with the given ID.
+func (g *Graph) record(id int32) (string, []byte) {
+	if !g.valid(id) {
+		return "", nil
+	}
+
+	off := g.h.DataOff + g.uint64At(g.h.NodesOff+8*uint64(id))
+
+	nameLen := uint64(g.uint32At(off))
+	name := make([]byte, nameLen)
+	g.read(name, off+4)
+
+	attrsLen := uint64(g.uint32At(off + 4 + nameLen))
+	attrs := make([]byte, attrsLen)
+	g.read(attrs, off+8+nameLen)
+
+	return string(name), attrs
+}
+
+// Name returns the name of the node with the given ID.
+func (g *Graph) Name(id int32) string {
+	name, _ := g.record(id)
+	return name
+}
+
+// Attributes returns the attributes of the node with the given ID.
+func (g *Graph) Attributes(id int32) graph.Attributes {
+	_, b := g.record(id)
+	if len(b) == 0 {
+		return nil
+	}
+
+	attrs := graph.Attributes{}
+	if err := json.Unmarshal(b, &attrs); err != nil {
+		g.fail(fmt.Errorf("disk failed to decode attributes of node %d: %w", id, err))
+	}
+	return attrs
+}
+
+// Lookup returns the ID of a node with the given name, using a binary
+// search of the sorted names.
+func (g *Graph) Lookup(name string) (int32, bool) {
+	n := g.Len()
+
+	i := sort.Search(n, func(i int) bool {
+		return g.Name(g.nameAt(i)) >= name
+	})
+
+	if i < n && !g.failed() {
+		if id := g.nameAt(i); g.Name(id) == name {
+			return id, true
+		}
+	}
+
+	return -1, false
+}
+
+// nameAt returns the ID of the node at the given position in the sorted names.
+func (g *Graph) nameAt(i int) int32 {
+	return int32(g.uint32At(g.h.NamesOff + 4*uint64(i)))
+}
+
+// targets reads the targets of the given node from a CSR section.
+func (g *Graph) targets(section uint64, id int32) []int32 {
+	if !g.valid(id) {
+		return nil
+	}
+
+	start := g.uint64At(section + 8*uint64(id))
+	end := g.uint64At(section + 8*uint64(id+1))
+
+	if end <= start {
+		return nil
+	}
+	if end > g.h.Edges {
+		g.fail(fmt.Errorf("disk edges of node %d are out of range", id))
+		return nil
+	}
+
+	buf := make([]byte, 4*(end-start))
+	if !g.read(buf, section+8*(g.h.Nodes+1)+4*start) {
+		return nil
+	}
+
+	ids := make([]int32, end-start)
+	for i := range ids {
+		ids[i] = int32(binary.LittleEndian.Uint32(buf[4*i:]))
+		if !g.valid(ids[i]) {
+			return nil
+		}
+	}
+	return ids
+}
+
+// Out returns the IDs of the nodes the given node has edges to.
+func (g *Graph) Out(id int32) []int32 {
+	return g.targets(g.h.OutOff, id)
+}
+
+// In returns the IDs of the nodes that have edges to the given node.
+func (g *Graph) In(id int32) []int32 {
+	return g.targets(g.h.InOff, id)
+}
+
+// bitset is a compact set of node IDs, used to track visited nodes.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) has(id int32) bool {
+	return b[id/64]&(1<<(uint(id)%64)) != 0
+}
+
+func (b bitset) add(id int32) {
+	b[id/64] |= 1 << (uint(id) % 64)
+}
+
+// DFS performs a depth-first-search starting at the given node following
+// outward edges. The given function can return false to stop traversal.
+func (g *Graph) DFS(start int32, fn func(int32) bool) {
+	if !g.valid(start) {
+		return
+	}
+
+	visited := newBitset(g.Len())
+
+	stack := []int32{start}
+
+	for len(stack) > 0 && !g.failed() {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if visited.has(id) {
+			continue
+		}
+		visited.add(id)
+
+		if !fn(id) {
+			return
+		}
+
+		out := g.Out(id)
+		for i := len(out) - 1; i >= 0; i-- {
+			if !visited.has(out[i]) {
+				stack = append(stack, out[i])
+			}
+		}
+	}
+}
+
+// BFS performs a breadth-first-search starting at the given node following
+// outward edges. The given function can return false to stop traversal.
+func (g *Graph) BFS(start int32, fn func(int32) bool) {
+	if !g.valid(start) {
+		return
+	}
+
+	visited := newBitset(g.Len())
+	visited.add(start)
+
+	queue := []int32{start}
+
+	for len(queue) > 0 && !g.failed() {
+		id := queue[0]
+		queue = queue[1:]
+
+		if !fn(id) {
+			return
+		}
+
+		for _, next := range g.Out(id) {
+			if !visited.has(next) {
+				visited.add(next)
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// PathTo returns the shortest path, by number of edges, from the start
+// node to the end node as a slice of node IDs, nil if no path was found.
+func (g *Graph) PathTo(start, end int32) []int32 {
+	parents := map[int32]int32{start: start}
+
+	queue := []int32{start}
+
+	for len(queue) > 0 && !g.failed() {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.Out(id) {
+			if next == end {
+				path := []int32{end}
+				for at := id; ; at = parents[at] {
+					path = append(path, at)
+					if at == start {
+						break
+					}
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+
+			if _, ok := parents[next]; !ok {
+				parents[next] = id
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HasPath checks if there is a path from the start node to the end node.
+func (g *Graph) HasPath(start, end int32) bool {
+	return g.PathTo(start, end) != nil
+}
+
+// PathString returns a human-readable string for the given path of node IDs.
+func (g *Graph) PathString(path []int32) string {
+	names := make([]string, len(path))
+	for i, id := range path {
+		names[i] = g.Name(id)
+	}
+	return strings.Join(names, " "+graph.Stringer.Out+" ")
+}
+
+// Components returns the weakly connected components of the graph as
+// slices of node IDs, in the order their first node appears.
+func (g *Graph) Components() [][]int32 {
+	visited := newBitset(g.Len())
+
+	components := [][]int32{}
+
+	for i := int32(0); int(i) < g.Len() && !g.failed(); i++ {
+		if visited.has(i) {
+			continue
+		}
+		visited.add(i)
+
+		component := []int32{i}
+
+		for j := 0; j < len(component); j++ {
+			id := component[j]
+			for _, neighbors := range [][]int32{g.Out(id), g.In(id)} {
+				for _, next := range neighbors {
+					if !visited.has(next) {
+						visited.add(next)
+						component = append(component, next)
+					}
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components
+}
+
+var _ graph.Graph = (*Graph)(nil)
+
+// node is a reference to a node in a disk graph.
+type node struct {
+	g  *Graph
+	id int32
+}
+
+func (n node) Key() string             { return n.g.Name(n.id) }
+func (n node) Attrs() graph.Attributes { return n.g.Attributes(n.id) }
+
+// edge is a reference to an edge in a disk graph, which has a weight of 1.
+type edge struct {
+	source, target node
+}
+
+func (e edge) Source() graph.NodeRef { return e.source }
+func (e edge) Target() graph.NodeRef { return e.target }
+func (e edge) Weight() float64       { return 1 }
+
+// NodeCount returns the number of nodes in the graph.
+func (g *Graph) NodeCount() int {
+	return g.Len()
+}
+
+// EachNode calls the given function for each node in the graph, in order
+// of their IDs, until it returns false.
+func (g *Graph) EachNode(fn func(graph.NodeRef) bool) {
+	for i := int32(0); int(i) < g.Len() && !g.failed(); i++ {
+		if !fn(node{g, i}) {
+			return
+		}
+	}
+}
+
+// FindNode returns a node in the graph with the given name.
+func (g *Graph) FindNode(key string) (graph.NodeRef, bool) {
+	id, ok := g.Lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return node{g, id}, true
+}
+
+// OutEdges returns the outward edges of the given node.
+func (g *Graph) OutEdges(n graph.NodeRef) []graph.EdgeRef {
+	from, ok := n.(node)
+	if !ok || from.g != g {
+		return nil
+	}
+
+	var edges []graph.EdgeRef
+	for _, id := range g.Out(from.id) {
+		edges = append(edges, edge{source: from, target: node{g, id}})
+	}
+	return edges
+}
+
+// InEdges returns the inward edges of the given node.
+func (g *Graph) InEdges(n graph.NodeRef) []graph.EdgeRef {
+	to, ok := n.(node)
+	if !ok || to.g != g {
+		return nil
+	}
+
+	var edges []graph.EdgeRef
+	for _, id := range g.In(to.id) {
+		edges = append(edges, edge{source: node{g, id}, target: to})
+	}
+	return edges
+}
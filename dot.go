@@ -4,31 +4,325 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func EncodeDOT(w io.Writer, nodes Nodes) error {
+// Attribute names with special meaning when encoding a graph using DOT.
+const (
+	// RankAttribute places nodes with the same value in the same rank,
+	// or in the minimum, maximum, source, or sink rank for the values
+	// "min", "max", "source", and "sink".
+	RankAttribute = "rank"
+
+	// ClusterAttribute groups nodes with the same value into a cluster,
+	// labeled with the value.
+	ClusterAttribute = "cluster"
+)
+
+// dotEdgeAttributes returns the attributes to write for the given edge:
+// all of its attributes, with its Name as the "name" attribute, and the
+// glyph of its direction prefixed to its label if the DirectionGlyphs
+// option is enabled.
+func dotEdgeAttributes(edge *Edge, options *EncodeOptions) Attributes {
+	attrs := copyAttributes(edge.Attributes)
+	if attrs == nil {
+		attrs = Attributes{}
+	}
+
+	if edge.Name != "" {
+		attrs["name"] = edge.Name
+	}
+
+	if options.DirectionGlyphs {
+		glyph := Out.String()
+		if edge.Direction != Out && edge.Direction != In {
+			glyph = edge.Direction.String()
+		}
+		if label, ok := attrs["label"]; ok {
+			glyph += " " + fmt.Sprint(label)
+		}
+		attrs["label"] = glyph
+	}
+
+	return attrs
+}
+
+// EncodeDOT encodes the given nodes and their edges using the Graphviz DOT
+// language. Every node is written with its attributes, including nodes
+// without any edges, and node labels are emitted as a "labels" attribute,
+// joined by colons.
+//
+// Graphs whose edges are all undirected (None), or paired with an edge in
+// the opposite direction, like meshes built using AddLink, are encoded as
+// an undirected graph, writing each relationship once using "--", with a
+// "dir" attribute of "both" for paired edges, so DecodeDOT can add them
+// back as pairs. Otherwise, the graph is encoded as a directed graph, where
+// undirected and bi-directional edges use the DOT "dir" attribute. This can
+// be overridden using the WithUndirected option, which writes the other
+// directed edges of undirected graphs with a "dir" attribute of "forward",
+// and bi-directional (Both) edges like paired edges.
+//
+// The Name of edges, like the type of edges added using AddEdgeTyped, is
+// written as their "name" attribute, taking precedence over an attribute
+// with the same name.
+//
+// The WithHeader option adds a comment block before the graph with its
+// name, set using WithName, generation time, node and edge counts, and a
+// legend of the node and edge attributes used, which is useful to record
+// the provenance of archived DOT files.
+//
+// Node attributes are written as DOT attributes, so attributes like "shape",
+// "color", "style", and "label" control how nodes are drawn. Edges are
+// written with their attributes the same way, and the WithDirectionGlyphs
+// option prefixes their labels with the glyph of their direction, like
+// "→ queries", for human-oriented exports. Nodes with the RankAttribute or
+// ClusterAttribute are grouped into ranks or clusters.
+// Compound nodes are written as clusters of their child graphs, with their
+// edges drawn to the nodes inside of them and clipped at the cluster.
+//
+//	digraph {                graph {
+//	    "a" -> { "b" }           "a" -- { "b" "c" }
+//	}                        }
+//
+// https://graphviz.org/doc/info/lang.html
+func EncodeDOT(w io.Writer, nodes Nodes, opts ...func(*EncodeOptions)) error {
 	var err error
 
+	options := newEncodeOptions(opts...)
+
+	undirected := isUndirected(nodes)
+	if options.Undirected != nil {
+		undirected = *options.Undirected
+	}
+
 	bw := bufio.NewWriter(w)
 
-	bw.WriteString("digraph {\n")
+	if options.Canonical {
+		nodes = canonicalOrder(nodes)
+	}
+
+	if options.Header {
+		writeDOTHeader(bw, nodes, options)
+	}
+
+	if undirected {
+		bw.WriteString("graph {\n")
+	} else {
+		bw.WriteString("digraph {\n")
+	}
+
+	if len(options.NodeDefaults) > 0 {
+		bw.WriteString(fmt.Sprintf("\tnode %s\n", dotAttributes(options.NodeDefaults)))
+	}
+
+	if len(options.EdgeDefaults) > 0 {
+		bw.WriteString(fmt.Sprintf("\tedge %s\n", dotAttributes(options.EdgeDefaults)))
+	}
 
 	for _, node := range nodes {
-		if len(node.Edges.Out()) > 0 {
-			_, err = bw.WriteString(
-				fmt.Sprintf(
-					"\t%q -> { %s }\n",
-					node.Name,
-					func() string {
-						var names []string
-						for _, edge := range node.Edges.Out() {
-							names = append(names, fmt.Sprintf("%q", edge.Node.Name))
-						}
-						return strings.Join(names, " ")
-					}(),
-				),
-			)
+		if isDOTCluster(node) {
+			// Allow edges to be clipped at the boundary of clusters.
+			bw.WriteString("\tcompound=true\n")
+			break
+		}
+	}
+
+	var (
+		clusters     []string
+		clusterNodes = map[string][]string{}
+		ranks        []string
+		rankNodes    = map[string][]string{}
+	)
+
+	var compounds Nodes
+
+	for _, node := range nodes {
+		if isDOTCluster(node) {
+			compounds = append(compounds, node)
+			continue
+		}
+
+		attrs := copyAttributes(node.Attributes)
+		if attrs == nil {
+			attrs = Attributes{}
+		}
+		if len(node.Labels) > 0 {
+			attrs["labels"] = strings.Join(node.Labels, ":")
+		}
+
+		if rank, ok := attrs[RankAttribute]; ok {
+			delete(attrs, RankAttribute)
+
+			key := fmt.Sprint(rank)
+			if _, ok := rankNodes[key]; !ok {
+				ranks = append(ranks, key)
+			}
+			rankNodes[key] = append(rankNodes[key], fmt.Sprintf("%q", node.Name))
+		}
+
+		stmt := fmt.Sprintf("%q", node.Name)
+		if cluster, ok := attrs[ClusterAttribute]; ok {
+			delete(attrs, ClusterAttribute)
+			if len(attrs) > 0 {
+				stmt += " " + dotAttributes(attrs)
+			}
+
+			key := fmt.Sprint(cluster)
+			if _, ok := clusterNodes[key]; !ok {
+				clusters = append(clusters, key)
+			}
+			clusterNodes[key] = append(clusterNodes[key], stmt)
+			continue
+		}
+
+		if len(attrs) > 0 {
+			stmt += " " + dotAttributes(attrs)
+		}
+		bw.WriteString("\t" + stmt + "\n")
+	}
+
+	for _, cluster := range clusters {
+		bw.WriteString(fmt.Sprintf("\tsubgraph %q {\n", "cluster_"+cluster))
+		bw.WriteString(fmt.Sprintf("\t\tlabel=%q\n", cluster))
+		for _, stmt := range clusterNodes[cluster] {
+			bw.WriteString("\t\t" + stmt + "\n")
+		}
+		bw.WriteString("\t}\n")
+	}
+
+	for _, node := range compounds {
+		writeDOTCompound(bw, node, "\t", undirected, options)
+	}
+
+	for _, rank := range ranks {
+		kind := "same"
+		switch rank {
+		case "min", "max", "source", "sink":
+			kind = rank
+		}
+		bw.WriteString(fmt.Sprintf("\t{ rank=%s; %s }\n", kind, strings.Join(rankNodes[rank], "; ")))
+	}
+
+	// written tracks the edges already written as part of a relationship,
+	// so each relationship is only written once.
+	written := map[*Edge]bool{}
+
+	// pairs are the other edges of the relationships, and outward the
+	// outward edges between each pair of nodes, to pair them with outward
+	// edges in the opposite direction.
+	pairs := pairEdges(nodes)
+
+	outward := map[[2]*Node]Edges{}
+	if undirected {
+		for _, node := range nodes {
+			for _, edge := range node.Edges {
+				if edge.Direction == Out {
+					key := [2]*Node{node, edge.Node}
+					outward[key] = append(outward[key], edge)
+				}
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		var (
+			targets []string
+			links   []string
+			extra   []string
+		)
+
+		for _, edge := range node.Edges {
+			if edge.Direction == In || written[edge] {
+				continue
+			}
+			written[edge] = true
+
+			attrs := dotEdgeAttributes(edge, options)
+
+			// Edges of compound nodes are drawn to the nodes inside of
+			// their clusters, clipped at the cluster boundary.
+			from, to := node.Name, edge.Node.Name
+			if isDOTCluster(node) {
+				from = dotAnchor(node, edge.origin)
+				attrs["ltail"] = "cluster_" + node.Name
+			}
+			if isDOTCluster(edge.Node) {
+				to = dotAnchor(edge.Node, edge.inner)
+				attrs["lhead"] = "cluster_" + edge.Node.Name
+			}
+
+			switch {
+			case edge.Direction == Out && !undirected && len(attrs) == 0:
+				targets = append(targets, fmt.Sprintf("%q", edge.Node.Name))
+				continue
+			case edge.Direction == Out && !undirected:
+				extra = append(extra, fmt.Sprintf("\t%q -> %q %s\n", from, to, dotAttributes(attrs)))
+				continue
+			case edge.Direction == Out:
+				// Pair the edge with an outward edge in the opposite direction.
+				attrs["dir"] = "forward"
+				key := [2]*Node{edge.Node, node}
+				for len(outward[key]) > 0 {
+					other := outward[key][0]
+					outward[key] = outward[key][1:]
+					if other != edge && !written[other] {
+						written[other] = true
+						attrs["dir"] = "both"
+						break
+					}
+				}
+				if len(attrs) == 1 && attrs["dir"] == "both" {
+					links = append(links, fmt.Sprintf("%q", edge.Node.Name))
+					continue
+				}
+			default:
+				if other := pairs[edge]; other != nil {
+					written[other] = true
+				}
+				if undirected && edge.Direction == Both {
+					attrs["dir"] = "both"
+				}
+			}
+
+			if undirected && len(attrs) == 0 {
+				targets = append(targets, fmt.Sprintf("%q", edge.Node.Name))
+				continue
+			}
+
+			op := "--"
+			if !undirected {
+				op = "->"
+				attrs["dir"] = "none"
+				if edge.Direction == Both {
+					attrs["dir"] = "both"
+				}
+			}
+			extra = append(extra, fmt.Sprintf("\t%q %s %q %s\n", from, op, to, dotAttributes(attrs)))
+		}
+
+		if options.Canonical {
+			sort.Strings(targets)
+			sort.Strings(links)
+			sort.Strings(extra)
+		}
+
+		if len(targets) > 0 {
+			op := "->"
+			if undirected {
+				op = "--"
+			}
+			bw.WriteString(fmt.Sprintf("\t%q %s { %s }\n", node.Name, op, strings.Join(targets, " ")))
+		}
+
+		if len(links) > 0 {
+			bw.WriteString(fmt.Sprintf("\t%q -- { %s } [\"dir\"=\"both\"]\n", node.Name, strings.Join(links, " ")))
+		}
+
+		for _, line := range extra {
+			_, err = bw.WriteString(line)
 			if err != nil {
 				return fmt.Errorf("graph failed to encode DOT: %w", err)
 			}
@@ -44,6 +338,835 @@ func EncodeDOT(w io.Writer, nodes Nodes) error {
 	return nil
 }
 
+// isDOTCluster checks if the node is a compound node with nodes in its child
+// graph, which is encoded as a DOT cluster.
+func isDOTCluster(n *Node) bool {
+	return n.Child != nil && len(n.Child.Nodes) > 0
+}
+
+// dotAnchor returns the name of the node inside of the given compound node
+// that edges of the compound node are drawn to: the given inner node, or the
+// first node of its child graph.
+func dotAnchor(compound, inner *Node) string {
+	if inner == nil {
+		inner = compound.Child.Nodes[0]
+	}
+	if isDOTCluster(inner) {
+		return dotAnchor(inner, nil)
+	}
+	return inner.Name
+}
+
+// writeDOTCompound writes the given compound node as a DOT cluster with the
+// nodes and edges of its child graph, using the given indentation.
+func writeDOTCompound(bw *bufio.Writer, compound *Node, indent string, undirected bool, options *EncodeOptions) {
+	bw.WriteString(fmt.Sprintf("%ssubgraph %q {\n", indent, "cluster_"+compound.Name))
+	bw.WriteString(fmt.Sprintf("%s\tlabel=%q\n", indent, compound.Name))
+
+	child := compound.Child
+
+	for _, node := range child.Nodes {
+		if isDOTCluster(node) {
+			writeDOTCompound(bw, node, indent+"\t", undirected, options)
+			continue
+		}
+
+		stmt := fmt.Sprintf("%q", node.Name)
+		if len(node.Attributes) > 0 {
+			stmt += " " + dotAttributes(node.Attributes)
+		}
+		bw.WriteString(indent + "\t" + stmt + "\n")
+	}
+
+	eachUniqueEdge(child.Nodes, func(node *Node, edge *Edge) {
+		var (
+			op    = "->"
+			attrs = dotEdgeAttributes(edge, options)
+		)
+
+		tail, head := node, edge.Node
+		from, to := tail.Name, head.Name
+		if isDOTCluster(tail) {
+			from = dotAnchor(tail, edge.origin)
+		}
+		if isDOTCluster(head) {
+			to = dotAnchor(head, edge.inner)
+		}
+
+		if edge.Direction == In {
+			tail, head, from, to = head, tail, to, from
+		}
+
+		if isDOTCluster(tail) {
+			attrs["ltail"] = "cluster_" + tail.Name
+		}
+		if isDOTCluster(head) {
+			attrs["lhead"] = "cluster_" + head.Name
+		}
+
+		switch {
+		case undirected:
+			op = "--"
+			switch edge.Direction {
+			case Out, In:
+				attrs["dir"] = "forward"
+			case Both:
+				attrs["dir"] = "both"
+			}
+		case edge.Direction != Out && edge.Direction != In:
+			attrs["dir"] = "none"
+			if edge.Direction == Both {
+				attrs["dir"] = "both"
+			}
+		}
+
+		stmt := fmt.Sprintf("%q %s %q", from, op, to)
+		if len(attrs) > 0 {
+			stmt += " " + dotAttributes(attrs)
+		}
+		bw.WriteString(indent + "\t" + stmt + "\n")
+	})
+
+	bw.WriteString(indent + "}\n")
+}
+
+// isUndirected checks if the given nodes have at least one edge, and all
+// of their edges are undirected, or directed edges paired with an edge in
+// the opposite direction.
+func isUndirected(nodes Nodes) bool {
+	type pair struct{ from, to *Node }
+
+	outward := map[pair]int{}
+
+	for _, node := range nodes {
+		for _, edge := range node.Edges {
+			if edge.Direction == Out {
+				outward[pair{node, edge.Node}]++
+			}
+		}
+	}
+
+	hasEdges := false
+
+	for _, node := range nodes {
+		for _, edge := range node.Edges {
+			hasEdges = true
+			if edge.Direction == Both {
+				return false
+			}
+			if edge.Direction == Out && outward[pair{node, edge.Node}] != outward[pair{edge.Node, node}] {
+				return false
+			}
+		}
+	}
+
+	return hasEdges
+}
+
+// EncodeDOT encodes the graph using the Graphviz DOT language, including
+// the graph's default node and edge attributes, and its name.
+func (inst *Instance) EncodeDOT(w io.Writer, opts ...func(*EncodeOptions)) error {
+	opts = append([]func(*EncodeOptions){
+		WithNodeDefaults(inst.NodeDefaults),
+		WithEdgeDefaults(inst.EdgeDefaults),
+		WithName(inst.Name),
+	}, opts...)
+
+	return EncodeDOT(w, inst.Nodes, opts...)
+}
+
+// writeDOTHeader writes the header comment block for the given nodes.
+//
+//	// Graph: topology
+//	// Generated: 2006-01-02T15:04:05Z
+//	// Nodes: 3
+//	// Edges: 2
+//	// Node attributes: color (2), shape (1)
+//	// Edge attributes: weight (2)
+func writeDOTHeader(bw *bufio.Writer, nodes Nodes, options *EncodeOptions) {
+	if options.Name != "" {
+		bw.WriteString(fmt.Sprintf("// Graph: %s\n", options.Name))
+	}
+
+	if !options.Generated.IsZero() {
+		bw.WriteString(fmt.Sprintf("// Generated: %s\n", options.Generated.UTC().Format(time.RFC3339)))
+	}
+
+	var (
+		edges     int
+		nodeAttrs = map[string]int{}
+		edgeAttrs = map[string]int{}
+	)
+
+	for _, node := range nodes {
+		for key := range node.Attributes {
+			nodeAttrs[key]++
+		}
+	}
+
+	eachUniqueEdge(nodes, func(_ *Node, edge *Edge) {
+		edges++
+		for key := range edge.Attributes {
+			edgeAttrs[key]++
+		}
+	})
+
+	bw.WriteString(fmt.Sprintf("// Nodes: %d\n", len(nodes)))
+	bw.WriteString(fmt.Sprintf("// Edges: %d\n", edges))
+
+	for _, legend := range []struct {
+		kind  string
+		attrs map[string]int
+	}{
+		{"Node", nodeAttrs},
+		{"Edge", edgeAttrs},
+	} {
+		if len(legend.attrs) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(legend.attrs))
+		for key := range legend.attrs {
+			keys = append(keys, fmt.Sprintf("%s (%d)", key, legend.attrs[key]))
+		}
+		sort.Strings(keys)
+
+		bw.WriteString(fmt.Sprintf("// %s attributes: %s\n", legend.kind, strings.Join(keys, ", ")))
+	}
+}
+
+// dotAttributes returns the DOT attribute list for the given attributes,
+// sorted by name.
+func dotAttributes(attrs Attributes) string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%q=%q", name, fmt.Sprint(attrs[name]))
+	}
+
+	return "[" + strings.Join(pairs, ", ") + "]"
+}
+
+// DecodeDOT decodes nodes and their edges from the Graphviz DOT language,
+// like the output of EncodeDOT.
+//
+// Nodes are returned in the order they first appear. Edges of a directed
+// graph ("->") are added as outward edges, unless their "dir" attribute is
+// "none" or "both", and edges of an undirected graph ("--") are added as
+// undirected (None) edges, unless their "dir" attribute is "forward" or
+// "back", which adds an outward edge, or "both", which adds a pair of
+// outward edges, like AddLink. The "name" attribute of edges is decoded as
+// their Name. Edges to and from subgraphs, like "a" -> { "b" "c" }, are
+// added for every node of the subgraph.
+//
+// Node and edge attributes are decoded as booleans or numbers when they are
+// "true", "false", or numeric, like JSON, and as strings otherwise. The
+// "labels" node attribute is split into the node's Labels. Nodes in a
+// "cluster_" subgraph get the ClusterAttribute of the cluster's name, and
+// nodes in a subgraph with a "rank" attribute get the RankAttribute. Other
+// graph attributes are ignored.
+//
+// Default attribute statements, "node [...]" and "edge [...]", set the
+// attributes of the nodes created, and the edges added, after them in the
+// same graph or subgraph, including nested subgraphs, unless they set
+// their own.
+//
+// https://graphviz.org/doc/info/lang.html
 func DecodeDOT(r io.Reader) (Nodes, error) {
-	return nil, fmt.Errorf("graph decode DOT not implemented yet")
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("graph failed to decode DOT: %w", err)
+	}
+
+	p := &dotParser{
+		lexer:  &dotLexer{src: string(src), line: 1},
+		byName: map[string]*Node{},
+	}
+
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("graph failed to decode DOT: %w", err)
+	}
+
+	return p.nodes, nil
+}
+
+// dotToken is a token of the DOT language: an ID, keyword, or operator.
+type dotToken struct {
+	text   string
+	quoted bool
+	line   int
+}
+
+// dotLexer splits DOT source into tokens.
+type dotLexer struct {
+	src  string
+	pos  int
+	line int
+	peek *dotToken
+}
+
+// next returns the next token, or an empty token at the end of the source.
+func (l *dotLexer) next() (dotToken, error) {
+	if l.peek != nil {
+		tok := *l.peek
+		l.peek = nil
+		return tok, nil
+	}
+
+	l.skipSpace()
+
+	if l.pos >= len(l.src) {
+		return dotToken{line: l.line}, nil
+	}
+
+	start, line := l.pos, l.line
+
+	switch c := l.src[l.pos]; {
+	case c == '"':
+		var sb strings.Builder
+		for l.pos++; l.pos < len(l.src); l.pos++ {
+			switch c := l.src[l.pos]; c {
+			case '"':
+				l.pos++
+				return dotToken{text: sb.String(), quoted: true, line: line}, nil
+			case '\\':
+				if l.pos+1 < len(l.src) {
+					l.pos++
+					switch l.src[l.pos] {
+					case '"':
+						sb.WriteByte('"')
+					case '\n':
+						// Line continuation.
+						l.line++
+					default:
+						sb.WriteByte('\\')
+						sb.WriteByte(l.src[l.pos])
+					}
+				}
+			case '\n':
+				l.line++
+				sb.WriteByte(c)
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return dotToken{}, fmt.Errorf("line %d: unterminated string", line)
+	case c == '<':
+		depth := 0
+		for ; l.pos < len(l.src); l.pos++ {
+			switch l.src[l.pos] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+			case '\n':
+				l.line++
+			}
+			if depth == 0 {
+				l.pos++
+				return dotToken{text: l.src[start+1 : l.pos-1], quoted: true, line: line}, nil
+			}
+		}
+		return dotToken{}, fmt.Errorf("line %d: unterminated HTML string", line)
+	case c == '-' && l.pos+1 < len(l.src) && (l.src[l.pos+1] == '>' || l.src[l.pos+1] == '-'):
+		l.pos += 2
+		return dotToken{text: l.src[start:l.pos], line: line}, nil
+	case strings.IndexByte("{}[];,=:", c) >= 0:
+		l.pos++
+		return dotToken{text: l.src[start:l.pos], line: line}, nil
+	case isDOTIDByte(c):
+		for l.pos < len(l.src) && isDOTIDByte(l.src[l.pos]) {
+			l.pos++
+		}
+		return dotToken{text: l.src[start:l.pos], line: line}, nil
+	default:
+		return dotToken{}, fmt.Errorf("line %d: unexpected character %q", line, c)
+	}
+}
+
+// skipSpace skips whitespace and comments.
+func (l *dotLexer) skipSpace() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+		case c == '#' || strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				end = len(l.src) - l.pos - 4
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+end+4], "\n")
+			l.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// isDOTIDByte checks if the byte can be part of an unquoted DOT ID, which
+// are alphanumeric, including underscores and non-ASCII characters, or
+// numerals like "-1.5".
+func isDOTIDByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// dotScope holds the attributes given to the nodes created and the edges
+// added in a graph or subgraph, by default attribute statements and
+// clusters.
+type dotScope struct {
+	node Attributes
+	edge Attributes
+}
+
+// dotParser builds nodes from the tokens of a DOT graph.
+type dotParser struct {
+	lexer    *dotLexer
+	directed bool
+	nodes    Nodes
+	byName   map[string]*Node
+}
+
+// expect consumes the next token, returning an error if it isn't the given
+// operator.
+func (p *dotParser) expect(text string) error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	if tok.quoted || tok.text != text {
+		return fmt.Errorf("line %d: expected %q, got %q", tok.line, text, tok.text)
+	}
+	return nil
+}
+
+// peek returns the next token without consuming it.
+func (p *dotParser) peek() (dotToken, error) {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return tok, err
+	}
+	p.lexer.peek = &tok
+	return tok, nil
+}
+
+// is checks if the token is the given operator or keyword, which are case
+// insensitive, unless quoted.
+func (tok dotToken) is(text string) bool {
+	return !tok.quoted && strings.EqualFold(tok.text, text)
+}
+
+// isID checks if the token is an ID, rather than an operator.
+func (tok dotToken) isID() bool {
+	return tok.quoted || (tok.text != "" && tok.text != "->" && tok.text != "--" && isDOTIDByte(tok.text[0]))
+}
+
+// parse parses a whole graph:
+//
+//	[ strict ] ( graph | digraph ) [ ID ] '{' stmt_list '}'
+func (p *dotParser) parse() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	if tok.is("strict") {
+		if tok, err = p.lexer.next(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case tok.is("digraph"):
+		p.directed = true
+	case tok.is("graph"):
+	default:
+		return fmt.Errorf("line %d: expected graph or digraph, got %q", tok.line, tok.text)
+	}
+
+	if tok, err = p.peek(); err != nil {
+		return err
+	}
+	if tok.isID() {
+		p.lexer.next()
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	if _, err := p.stmts(dotScope{}); err != nil {
+		return err
+	}
+
+	if tok, err = p.lexer.next(); err != nil {
+		return err
+	}
+	if tok.text != "" || tok.quoted {
+		return fmt.Errorf("line %d: unexpected %q after graph", tok.line, tok.text)
+	}
+
+	return nil
+}
+
+// stmts parses statements until the closing brace of the current graph or
+// subgraph, returning the nodes they contain. Nodes created and edges
+// added in the statements are given the attributes of the scope, which
+// default attribute statements only change for the rest of the subgraph.
+func (p *dotParser) stmts(scope dotScope) (Nodes, error) {
+	var members Nodes
+
+	seen := NodeSet{}
+	addMembers := func(nodes Nodes) {
+		for _, node := range nodes {
+			if !seen.Contains(node) {
+				seen.Add(node)
+				members = append(members, node)
+			}
+		}
+	}
+
+	// Subgraph attributes, like rank=same, apply to the subgraph's nodes,
+	// even those that appear before the attribute.
+	subAttrs := Attributes{}
+
+	for {
+		tok, err := p.lexer.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case tok.text == "" && !tok.quoted:
+			return nil, fmt.Errorf("line %d: unexpected end of graph", tok.line)
+		case tok.is("}"):
+			for key, value := range subAttrs {
+				for _, node := range members {
+					node.SetAttribute(key, value)
+				}
+			}
+			return members, nil
+		case tok.is(";") || tok.is(","):
+			continue
+		case tok.is("graph") || tok.is("node") || tok.is("edge"):
+			attrs, err := p.attrList()
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case tok.is("node"):
+				scope.node = mergeAttributes(scope.node, attrs)
+			case tok.is("edge"):
+				scope.edge = mergeAttributes(scope.edge, attrs)
+			default:
+				if rank, ok := attrs[RankAttribute]; ok {
+					subAttrs[RankAttribute] = fmt.Sprint(rank)
+				}
+			}
+			continue
+		}
+
+		next, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+
+		// ID '=' ID
+		if next.is("=") {
+			p.lexer.next()
+			value, err := p.lexer.next()
+			if err != nil {
+				return nil, err
+			}
+			if !value.isID() {
+				return nil, fmt.Errorf("line %d: expected value of %q, got %q", value.line, tok.text, value.text)
+			}
+			if tok.is(RankAttribute) {
+				subAttrs[RankAttribute] = value.text
+			}
+			continue
+		}
+
+		operand, err := p.operand(tok, scope)
+		if err != nil {
+			return nil, err
+		}
+		addMembers(operand)
+
+		if next, err = p.peek(); err != nil {
+			return nil, err
+		}
+
+		if !next.is("->") && !next.is("--") {
+			if next.is("[") {
+				attrs, err := p.attrList()
+				if err != nil {
+					return nil, err
+				}
+				for _, node := range operand {
+					p.setNodeAttributes(node, attrs)
+				}
+			}
+			continue
+		}
+
+		// edge_stmt: operand ( edgeop operand )+ [ attr_list ]
+		operands := []Nodes{operand}
+		for next.is("->") || next.is("--") {
+			p.lexer.next()
+			if next.is("->") != p.directed {
+				return nil, fmt.Errorf("line %d: unexpected %q in %s", next.line, next.text, map[bool]string{true: "digraph", false: "graph"}[p.directed])
+			}
+
+			tok, err := p.lexer.next()
+			if err != nil {
+				return nil, err
+			}
+
+			operand, err := p.operand(tok, scope)
+			if err != nil {
+				return nil, err
+			}
+			addMembers(operand)
+			operands = append(operands, operand)
+
+			if next, err = p.peek(); err != nil {
+				return nil, err
+			}
+		}
+
+		var attrs Attributes
+		if next.is("[") {
+			if attrs, err = p.attrList(); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(scope.edge) > 0 {
+			attrs = mergeAttributes(scope.edge, attrs)
+		}
+
+		for i := 1; i < len(operands); i++ {
+			for _, from := range operands[i-1] {
+				for _, to := range operands[i] {
+					p.addEdge(from, to, attrs)
+				}
+			}
+		}
+	}
+}
+
+// operand parses a node ID or subgraph, starting with the given token,
+// returning its nodes.
+//
+//	node_id  : ID [ ':' ID [ ':' ID ] ]
+//	subgraph : [ subgraph [ ID ] ] '{' stmt_list '}'
+func (p *dotParser) operand(tok dotToken, scope dotScope) (Nodes, error) {
+	if tok.is("subgraph") || tok.is("{") {
+		if tok.is("subgraph") {
+			next, err := p.lexer.next()
+			if err != nil {
+				return nil, err
+			}
+			if next.isID() {
+				if name := next.text; strings.HasPrefix(name, "cluster_") {
+					scope.node = mergeAttributes(scope.node, Attributes{
+						ClusterAttribute: strings.TrimPrefix(name, "cluster_"),
+					})
+				}
+				if next, err = p.lexer.next(); err != nil {
+					return nil, err
+				}
+			}
+			if !next.is("{") {
+				return nil, fmt.Errorf("line %d: expected \"{\", got %q", next.line, next.text)
+			}
+		}
+		return p.stmts(scope)
+	}
+
+	if !tok.isID() {
+		return nil, fmt.Errorf("line %d: unexpected %q", tok.line, tok.text)
+	}
+
+	// Ports, like "a":n, are ignored.
+	for {
+		next, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !next.is(":") {
+			break
+		}
+		p.lexer.next()
+		if _, err := p.lexer.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	node, ok := p.byName[tok.text]
+	if !ok {
+		node = NewNode(tok.text, nil)
+		p.setNodeAttributes(node, scope.node)
+		p.byName[tok.text] = node
+		p.nodes = append(p.nodes, node)
+	}
+
+	return Nodes{node}, nil
+}
+
+// attrList parses one or more attribute lists, which are optional.
+//
+//	attr_list : '[' [ a_list ] ']' [ attr_list ]
+//	a_list    : ID '=' ID [ ( ';' | ',' ) ] [ a_list ]
+func (p *dotParser) attrList() (Attributes, error) {
+	attrs := Attributes{}
+
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !tok.is("[") {
+			return attrs, nil
+		}
+		p.lexer.next()
+
+		for {
+			key, err := p.lexer.next()
+			if err != nil {
+				return nil, err
+			}
+			if key.is("]") {
+				break
+			}
+			if key.is(",") || key.is(";") {
+				continue
+			}
+			if !key.isID() {
+				return nil, fmt.Errorf("line %d: expected attribute name, got %q", key.line, key.text)
+			}
+
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+
+			value, err := p.lexer.next()
+			if err != nil {
+				return nil, err
+			}
+			if !value.isID() {
+				return nil, fmt.Errorf("line %d: expected value of %q, got %q", value.line, key.text, value.text)
+			}
+
+			attrs[key.text] = dotValue(value.text)
+		}
+	}
+}
+
+// dotValue returns the given DOT attribute value, quoted or not, as a
+// boolean or number, if it is one, or as a string. Only decimal numbers,
+// like JSON's, are numbers, so values like "inf" or "0x10" stay strings.
+func dotValue(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if strings.Trim(s, "0123456789.+-eE") != "" {
+		return s
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+// mergeAttributes returns a copy of the given attributes, with the
+// attributes of overrides replacing them.
+func mergeAttributes(attrs, overrides Attributes) Attributes {
+	merged := copyAttributes(attrs)
+	if merged == nil {
+		merged = Attributes{}
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+// setNodeAttributes sets the given attributes of the node, splitting the
+// "labels" attribute into the node's labels.
+func (p *dotParser) setNodeAttributes(node *Node, attrs Attributes) {
+	for key, value := range attrs {
+		if key == "labels" {
+			node.Labels = strings.Split(fmt.Sprint(value), ":")
+			continue
+		}
+		node.SetAttribute(key, value)
+	}
+}
+
+// addEdge adds an edge between the given nodes, using the direction given
+// by the graph's kind and the "dir" attribute, named after the "name"
+// attribute. Both edges of the relationship share the given attributes.
+func (p *dotParser) addEdge(from, to *Node, attrs Attributes) {
+	var (
+		dir  = None
+		link bool
+		name string
+	)
+	if p.directed {
+		dir = Out
+	}
+
+	if len(attrs) > 0 {
+		attrs = copyAttributes(attrs)
+		switch attrs["dir"] {
+		case "none":
+			dir = None
+		case "forward":
+			dir = Out
+		case "both":
+			dir = Both
+			// Undirected graphs write pairs of outward edges this way.
+			if !p.directed {
+				dir, link = Out, true
+			}
+		case "back":
+			dir = Out
+			from, to = to, from
+		}
+		delete(attrs, "dir")
+
+		if v, ok := attrs["name"]; ok {
+			name = fmt.Sprint(v)
+			delete(attrs, "name")
+		}
+	}
+
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+
+	from.Edges = append(from.Edges, &Edge{Name: name, Node: to, Direction: dir, Attributes: attrs, from: from})
+	to.Edges = append(to.Edges, &Edge{Name: name, Node: from, Direction: dir.Reverse(), Attributes: attrs, from: to})
+
+	if link {
+		to.Edges = append(to.Edges, &Edge{Name: name, Node: from, Direction: Out, Attributes: attrs, from: to})
+		from.Edges = append(from.Edges, &Edge{Name: name, Node: to, Direction: In, Attributes: attrs, from: from})
+	}
 }
@@ -3,7 +3,10 @@ package graph_test
 import (
 	"bytes"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/picatz/graph"
 )
@@ -35,6 +38,9 @@ func TestEncodeDOT(t *testing.T) {
 }
 
 const again_golden = `digraph {
+	"a" ["example"="true"]
+	"b" ["example"="yes"]
+	"c" ["example"="1"]
 	"a" -> { "b" "c" }
 }
 `
@@ -63,3 +69,519 @@ func TestEncodeDOT_again(t *testing.T) {
 		t.Fatalf("got:\n%q\ngolden:\n%q\n", buf.String(), again_golden)
 	}
 }
+
+const defaults_golden = `digraph {
+	node ["color"="blue", "shape"="box"]
+	edge ["style"="dashed"]
+	"a" ["color"="red"]
+	"b"
+	"a" -> { "b" }
+}
+`
+
+func TestInstance_EncodeDOT_defaults(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"color": "red"})
+		b = graph.NewNode("b", nil)
+	)
+
+	// a → b
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	g.SetDefaultNodeAttr("color", "blue")
+	g.SetDefaultNodeAttr("shape", "box")
+	g.SetDefaultEdgeAttr("style", "dashed")
+
+	color, err := graph.GetAttribute[string](a.Attributes, "color", g.NodeDefaults)
+	if err != nil || color != "red" {
+		t.Fatalf("expected node attribute to take precedence, got %q: %v", color, err)
+	}
+
+	shape, err := graph.GetAttribute[string](b.Attributes, "shape", g.NodeDefaults)
+	if err != nil || shape != "box" {
+		t.Fatalf("expected default node attribute, got %q: %v", shape, err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	err = g.EncodeDOT(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != defaults_golden {
+		t.Fatalf("got:\n%q\ngolden:\n%q\n", buf.String(), defaults_golden)
+	}
+}
+
+const undirected_golden = `graph {
+	"a"
+	"b"
+	"c"
+	"a" -- { "b" "c" } ["dir"="both"]
+	"b" -- { "c" } ["dir"="both"]
+}
+`
+
+func TestEncodeDOT_undirected(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// a ↔ b ↔ c ↔ a
+
+	graph.MeshNodes(a, b, c)
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeDOT(buf, graph.Nodes{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != undirected_golden {
+		t.Fatalf("got:\n%q\ngolden:\n%q\n", buf.String(), undirected_golden)
+	}
+
+	buf.Reset()
+
+	err = graph.EncodeDOT(buf, graph.Nodes{a, b, c}, graph.WithUndirected(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "\t\"a\" -> { \"b\" \"c\" }\n") {
+		t.Fatalf("expected a directed graph:\n%s", buf.String())
+	}
+}
+
+const mixed_golden = `digraph {
+	"a"
+	"b"
+	"c"
+	"a" -> { "b" }
+	"a" -> "c" ["dir"="none"]
+	"b" -> "c" ["dir"="both"]
+}
+`
+
+func TestEncodeDOT_mixed(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdge(b)
+	a.AddEdgeWithDirection(c, graph.None)
+	b.AddEdgeWithDirection(c, graph.Both)
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeDOT(buf, graph.Nodes{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != mixed_golden {
+		t.Fatalf("got:\n%q\ngolden:\n%q\n", buf.String(), mixed_golden)
+	}
+}
+
+const isolated_golden = `digraph {
+	"a"
+	"b" ["color"="red"]
+	"c"
+	"a" -> { "b" }
+}
+`
+
+func TestEncodeDOT_isolated(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", graph.Attributes{"color": "red"})
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b    c
+
+	a.AddEdge(b)
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeDOT(buf, graph.Nodes{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != isolated_golden {
+		t.Fatalf("got:\n%q\ngolden:\n%q\n", buf.String(), isolated_golden)
+	}
+
+	buf.Reset()
+
+	err = graph.EncodeDOT(buf, graph.Nodes{c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "digraph {\n\t\"c\"\n}\n" {
+		t.Fatalf("expected a single isolated node, got:\n%s", buf.String())
+	}
+}
+
+const layout_golden = `digraph {
+	"lb" ["shape"="box"]
+	subgraph "cluster_backend" {
+		label="backend"
+		"api" ["color"="blue"]
+		"db" ["shape"="cylinder"]
+	}
+	subgraph "cluster_jobs" {
+		label="jobs"
+		"worker"
+	}
+	{ rank=source; "lb" }
+	{ rank=same; "api"; "worker" }
+	"lb" -> { "api" }
+	"api" -> "db" ["label"="queries", "style"="dashed", "weight"="2"]
+	"worker" -> { "db" }
+}
+`
+
+func TestEncodeDOT_layout(t *testing.T) {
+	var (
+		lb     = graph.NewNode("lb", graph.Attributes{"shape": "box", "rank": "source"})
+		api    = graph.NewNode("api", graph.Attributes{"color": "blue", "cluster": "backend", "rank": 1})
+		db     = graph.NewNode("db", graph.Attributes{"shape": "cylinder", "cluster": "backend"})
+		worker = graph.NewNode("worker", graph.Attributes{"cluster": "jobs", "rank": 1})
+	)
+
+	lb.AddEdge(api)
+	api.AddEdge(db)
+	worker.AddEdge(db)
+
+	api.Edges[len(api.Edges)-1].Attributes = graph.Attributes{"label": "queries", "style": "dashed", "weight": 2}
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeDOT(buf, graph.Nodes{lb, api, db, worker})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != layout_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), layout_golden)
+	}
+}
+
+const header_golden = `// Graph: topology
+// Generated: 2024-01-02T03:04:05Z
+// Nodes: 3
+// Edges: 2
+// Node attributes: color (2), shape (1)
+// Edge attributes: weight (1)
+digraph {
+	"a" ["color"="red", "shape"="box"]
+	"b" ["color"="blue"]
+	"c"
+	"a" -> { "c" }
+	"a" -> "b" ["weight"="2"]
+}
+`
+
+func TestEncodeDOT_header(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"color": "red", "shape": "box"})
+		b = graph.NewNode("b", graph.Attributes{"color": "blue"})
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b
+	// ↓
+	// c
+
+	a.AddWeightedEdge(b, 2)
+	a.AddEdge(c)
+
+	g := graph.New("topology", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	generated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	buf := bytes.NewBuffer(nil)
+
+	err := g.EncodeDOT(buf, graph.WithHeader(generated))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != header_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), header_golden)
+	}
+
+	buf.Reset()
+
+	err = graph.EncodeDOT(buf, g.Nodes, graph.WithHeader(time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "// Nodes: 3\n// Edges: 2\n") {
+		t.Fatalf("expected the header without a name or generation time, got:\n%s", buf.String())
+	}
+}
+
+func TestDecodeDOT(t *testing.T) {
+	for _, golden := range []string{again_golden, undirected_golden, mixed_golden, layout_golden} {
+		nodes, err := graph.DecodeDOT(strings.NewReader(golden))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := bytes.NewBuffer(nil)
+
+		err = graph.EncodeDOT(buf, nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != golden {
+			t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), golden)
+		}
+	}
+}
+
+func TestDecodeDOT_syntax(t *testing.T) {
+	nodes, err := graph.DecodeDOT(strings.NewReader(`
+		/* A small
+		   pipeline. */
+		strict digraph pipeline {
+			rankdir=LR
+			node [shape=box]
+			build [weight=2, labels="job:ci"]; // the first step
+			build -> test -> { deploy notify } [color=red]
+			# ports are ignored
+			deploy:e -> "build":w [dir=back]
+		}
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes) != 4 {
+		t.Fatalf("expected 4 nodes, got %d: %v", len(nodes), nodes)
+	}
+
+	build, test, deploy, notify := nodes[0], nodes[1], nodes[2], nodes[3]
+
+	if build.Name != "build" || build.Attributes["weight"] != 2.0 || !build.HasLabel("ci") {
+		t.Fatalf("unexpected build node: %+v", build)
+	}
+
+	if path := build.PathTo(notify); path.String() != "build → test → notify" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	for _, edge := range test.Edges {
+		if edge.Direction == graph.Out && edge.Attributes["color"] != "red" {
+			t.Fatalf("expected red edges from test, got: %v", edge.Attributes)
+		}
+	}
+
+	if path := build.PathTo(deploy); path.String() != "build → deploy" {
+		t.Fatalf("expected the edge drawn back to build, got: %v", path)
+	}
+
+	if path := deploy.PathTo(build); path != nil {
+		t.Fatalf("expected no path from deploy to build, got: %v", path)
+	}
+
+	for _, bad := range []string{
+		``,
+		`digraph {`,
+		`digraph { a -- b }`,
+		`graph { a -> b }`,
+		`digraph { "a }`,
+		`digraph { a [color] }`,
+		`tree { a }`,
+	} {
+		if _, err := graph.DecodeDOT(strings.NewReader(bad)); err == nil {
+			t.Fatalf("expected an error decoding %q", bad)
+		}
+	}
+}
+
+func TestDecodeDOT_defaults(t *testing.T) {
+	nodes, err := graph.DecodeDOT(strings.NewReader(`
+		digraph {
+			a
+			node [shape=box, "size"="2"]
+			edge [color=red, weight=3]
+			a -> b
+			subgraph {
+				node [shape=circle]
+				edge [weight=1]
+				c -> d [color=blue]
+			}
+			d -> e
+			e [shape=oval, size=inf]
+		}
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %d: %v", len(nodes), nodes)
+	}
+
+	// Node defaults only apply to nodes created after them, and subgraphs
+	// change them for their own nodes.
+	for i, expected := range []graph.Attributes{
+		nil,
+		{"shape": "box", "size": 2.0},
+		{"shape": "circle", "size": 2.0},
+		{"shape": "circle", "size": 2.0},
+		{"shape": "oval", "size": "inf"},
+	} {
+		if !reflect.DeepEqual(nodes[i].Attributes, expected) {
+			t.Fatalf("expected %s attributes %v, got %v", nodes[i].Name, expected, nodes[i].Attributes)
+		}
+	}
+
+	// Edge defaults apply unless the edge sets its own attributes, and
+	// numbers decode as numbers, quoted or not.
+	for _, test := range []struct {
+		from  *graph.Node
+		color string
+		w     float64
+	}{
+		{nodes[0], "red", 3},
+		{nodes[2], "blue", 1},
+		{nodes[3], "red", 3},
+	} {
+		edge := test.from.Edges[len(test.from.Edges)-1]
+		if edge.Direction != graph.Out || edge.Attributes["color"] != test.color || edge.Weight() != test.w {
+			t.Fatalf("unexpected edge from %s: %+v", test.from.Name, edge)
+		}
+		if _, ok := edge.Attributes["weight"].(float64); !ok {
+			t.Fatalf("expected a float64 weight, got %T", edge.Attributes["weight"])
+		}
+	}
+}
+
+const glyphs_golden = `digraph {
+	"a"
+	"b"
+	"c"
+	"a" -> "b" ["label"="→ calls", "weight"="2"]
+	"b" -> "c" ["dir"="both", "label"="↔"]
+}
+`
+
+func TestEncodeDOT_directionGlyphs(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdgeWithDirection(b, graph.Out)
+	b.AddEdgeWithDirection(c, graph.Both)
+
+	a.Edges[0].Attributes = graph.Attributes{"label": "calls", "weight": 2}
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeDOT(buf, graph.Nodes{a, b, c}, graph.WithDirectionGlyphs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != glyphs_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), glyphs_golden)
+	}
+}
+
+func TestDecodeDOT_roundTrip(t *testing.T) {
+	build := func() graph.Nodes {
+		var (
+			a = graph.NewNode("a", nil)
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+			d = graph.NewNode("d", nil)
+		)
+
+		a.AddLink(b)
+		b.AddEdgeTyped(c, "calls")
+		c.AddEdgeWithDirection(d, graph.Both)
+		d.AddEdgeWithDirection(a, graph.None)
+		d.AddEdgeTyped(a, "owns")
+
+		return graph.NewNodes(a, b, c, d)
+	}
+
+	links := func() graph.Nodes {
+		var (
+			a = graph.NewNode("a", nil)
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+		)
+
+		a.AddLink(b)
+		b.AddLink(c)
+		c.AddEdgeWithDirection(a, graph.None)
+		c.Edges[len(c.Edges)-1].Name = "peers"
+		a.Edges[len(a.Edges)-1].Name = "peers"
+
+		return graph.NewNodes(a, b, c)
+	}
+
+	chain := func() graph.Nodes {
+		var (
+			a = graph.NewNode("a", nil)
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+		)
+
+		a.AddEdgeTyped(b, "calls")
+		c.AddEdge(b)
+		b.AddLink(c)
+
+		return graph.NewNodes(a, b, c)
+	}
+
+	for name, test := range map[string]struct {
+		nodes graph.Nodes
+		opts  []func(*graph.EncodeOptions)
+	}{
+		"directed":   {nodes: build()},
+		"undirected": {nodes: links()},
+		"forced":     {nodes: chain(), opts: []func(*graph.EncodeOptions){graph.WithUndirected(true)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+
+			if err := graph.EncodeDOT(buf, test.nodes, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := graph.DecodeDOT(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := graph.New("test", graph.WithNodes(test.nodes))
+			got := graph.New("test", graph.WithNodes(decoded))
+
+			if !graph.Equal(want, got) {
+				t.Fatalf("expected DOT encoding to round-trip, got edges %v from:\n%s", got.Edges(), buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package graph
+
+// Edges returns every unique edge between the nodes of the graph, in the
+// order of the nodes they start from.
+//
+// Each relationship is stored as a pair of edges, one in the adjacency list
+// of each of its nodes, so only one edge of each pair is returned: the
+// outward edge of a directed relationship, or the edge of the first node
+// for undirected (None) and bi-directional (Both) relationships. The node
+// an edge starts from is available using From.
+func (inst *Instance) Edges() Edges {
+	edges := Edges{}
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		edges = append(edges, edge)
+	})
+	return edges
+}
+
+// EdgeCount returns the number of unique edges in the graph, see Edges.
+func (inst *Instance) EdgeCount() int {
+	var count int
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		count++
+	})
+	return count
+}
+
+// eachUniqueEdge calls the given function with each unique edge of the
+// given nodes, and the node whose adjacency list contains it, skipping
+// the other edge of each relationship's pair, see pairEdges.
+//
+// Inward edges are only included if the other side of the relationship
+// can't be found among the given nodes.
+func eachUniqueEdge(nodes Nodes, fn func(from *Node, edge *Edge)) {
+	pairs := pairEdges(nodes)
+
+	var (
+		seen    = make(map[*Edge]bool, len(pairs)/2)
+		visited = make(map[*Node]bool, len(nodes))
+	)
+
+	for _, node := range nodes {
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+
+		for _, edge := range node.Edges {
+			if seen[edge] {
+				continue
+			}
+
+			other := pairs[edge]
+
+			if edge.Direction == In && other != nil {
+				continue
+			}
+
+			if other != nil {
+				seen[other] = true
+			}
+
+			fn(node, edge)
+		}
+	}
+}
+
+// pairKey identifies the edges that can be the other edge of a
+// relationship: the nodes it is between, its name, and its direction.
+type pairKey struct {
+	from, to  *Node
+	name      string
+	direction EdgeDirection
+}
+
+// pairEdges returns the other edge of each relationship of the given
+// nodes, like Edge.reciprocal, in a single pass over their edges. Edges
+// are paired one-to-one, in the order they were added, so parallel edges,
+// like two undirected edges between the same nodes, each get their own
+// partner. Edges whose other edge isn't in the adjacency list of one of
+// the given nodes have none.
+func pairEdges(nodes Nodes) map[*Edge]*Edge {
+	var (
+		pairs   = map[*Edge]*Edge{}
+		waiting = map[pairKey][]*Edge{}
+		visited = make(map[*Node]bool, len(nodes))
+	)
+
+	for _, node := range nodes {
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+
+		for _, edge := range node.Edges {
+			want := pairKey{edge.Node, node, edge.Name, edge.Direction.Reverse()}
+			if queue := waiting[want]; len(queue) > 0 {
+				other := queue[0]
+				if len(queue) == 1 {
+					delete(waiting, want)
+				} else {
+					waiting[want] = queue[1:]
+				}
+				pairs[edge], pairs[other] = other, edge
+				continue
+			}
+
+			key := pairKey{node, edge.Node, edge.Name, edge.Direction}
+			waiting[key] = append(waiting[key], edge)
+		}
+	}
+
+	return pairs
+}
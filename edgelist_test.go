@@ -0,0 +1,158 @@
+package graph_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Edges(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b ↔ c - a
+
+	a.AddEdgeTyped(b, "calls")
+	b.AddLink(c)
+	c.AddEdgeWithDirection(a, graph.None)
+	a.AddEdgeWithDirection(a, graph.None)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	var edges []string
+	for _, edge := range g.Edges() {
+		edges = append(edges, edge.From().Name+" "+edge.Direction.String()+" "+edge.Node.Name)
+	}
+
+	if strings.Join(edges, ", ") != "a → b, a - c, a - a, b → c, c → b" {
+		t.Fatalf("unexpected edges: %v", edges)
+	}
+
+	if g.EdgeCount() != 5 {
+		t.Fatalf("expected 5 edges, got %d", g.EdgeCount())
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := graph.EncodeJSON(buf, g.Nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := graph.DecodeJSON(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := graph.New("decoded", graph.WithNodes(nodes))
+
+	if !graph.Equal(g, decoded) {
+		t.Fatalf("expected decoded graph to equal the encoded graph")
+	}
+}
+
+func TestInstance_Edges_parallel(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// Parallel edges each have their own reciprocal edge.
+	a.AddEdgeWithDirection(b, graph.None)
+	a.AddEdgeWithDirection(b, graph.None)
+	b.AddEdgeWithDirection(c, graph.Both)
+	c.AddEdgeWithDirection(b, graph.Both)
+	c.AddEdgeWithDirection(c, graph.None)
+	c.AddEdgeWithDirection(c, graph.None)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	if got := g.Edges().String(); got != "a - b, a - b, b ↔ c, b ↔ c, c - c, c - c" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	if g.EdgeCount() != 6 {
+		t.Fatalf("expected 6 edges, got %d", g.EdgeCount())
+	}
+
+	encoders := map[string]func(*graph.Instance) (*graph.Instance, error){
+		"dot": func(g *graph.Instance) (*graph.Instance, error) {
+			buf := bytes.NewBuffer(nil)
+			if err := graph.EncodeDOT(buf, g.Nodes); err != nil {
+				return nil, err
+			}
+			nodes, err := graph.DecodeDOT(buf)
+			return graph.New("decoded", graph.WithNodes(nodes)), err
+		},
+		"yaml": func(g *graph.Instance) (*graph.Instance, error) {
+			buf := bytes.NewBuffer(nil)
+			if err := graph.EncodeYAML(buf, g); err != nil {
+				return nil, err
+			}
+			return graph.DecodeYAML(buf)
+		},
+	}
+
+	for name, roundTrip := range encoders {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := roundTrip(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !graph.Equal(g, decoded) {
+				t.Fatalf("expected decoded graph to equal the encoded graph, got edges %v", decoded.Edges())
+			}
+		})
+	}
+}
+
+func TestInstance_EdgeCount_star(t *testing.T) {
+	center := graph.NewNode("center", nil)
+
+	g := graph.New("star", graph.WithNodes(graph.NewNodes(center)))
+
+	const leaves = 50000
+
+	for i := 0; i < leaves; i++ {
+		leaf := graph.NewNode(fmt.Sprint(i), nil)
+		g.AddNode(leaf)
+		leaf.AddEdgeWithDirection(center, graph.None)
+	}
+
+	// Edges are paired in a single pass, rather than by scanning the
+	// edges of the center for each of its edges.
+	if n := g.EdgeCount(); n != leaves {
+		t.Fatalf("expected %d edges, got %d", leaves, n)
+	}
+
+	if err := graph.EncodeJSON(io.Discard, g.Nodes); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeJSON_legacy(t *testing.T) {
+	// Older encodings list both edges of each relationship.
+	legacy := `{"nodes":[{"name":"a"},{"name":"b"}],"edges":[` +
+		`{"from_index":0,"direction":3,"to_index":1},` +
+		`{"from_index":1,"direction":2,"to_index":0}]}`
+
+	nodes, err := graph.DecodeJSON(strings.NewReader(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes[0].Edges) != 1 || len(nodes[1].Edges) != 1 {
+		t.Fatalf("expected one edge on each node, got %d and %d", len(nodes[0].Edges), len(nodes[1].Edges))
+	}
+
+	if !nodes[0].HasPath(nodes[1]) || nodes[1].HasPath(nodes[0]) {
+		t.Fatalf("expected a single directed relationship from a to b")
+	}
+}
@@ -1,5 +1,10 @@
 package graph
 
+import (
+	"fmt"
+	"strings"
+)
+
 // EdgeDirection describes the "direction" of an edge relative
 // to a node. A direction can be in one of five states:
 //  0. Unknown
@@ -17,19 +22,22 @@ const (
 	Both    EdgeDirection = 4 // [ ↔ ] Edge has both inward and outward direction.
 )
 
-// String returns a human and command-line friendly representation of the edge direction.
+// String returns a human and command-line friendly representation of the
+// edge direction, using the glyphs of the package's Stringer configuration.
 func (d EdgeDirection) String() string {
+	return Stringer.glyph(d)
+}
+
+// Reverse returns the direction as seen from the other side of an edge,
+// swapping In and Out. All other directions are returned unchanged.
+func (d EdgeDirection) Reverse() EdgeDirection {
 	switch d {
-	case None:
-		return "-"
 	case In:
-		return "←"
+		return Out
 	case Out:
-		return "→"
-	case Both:
-		return "↔"
-	default: // Unknown and anything else.
-		return "┄"
+		return In
+	default:
+		return d
 	}
 }
 
@@ -74,11 +82,82 @@ type Edge struct {
 	Node      *Node
 	Direction EdgeDirection
 	Attributes
+
+	// from is the node whose adjacency list contains the edge.
+	from *Node
+
+	// inner is the node inside of the edge's compound node that the edge
+	// pointed to before the node was collapsed, and origin is the node
+	// inside of the compound node the edge belongs to that the edge
+	// belonged to, if any.
+	inner, origin *Node
+}
+
+// WeightAttribute is the name of the edge attribute used for edge weights.
+const WeightAttribute = "weight"
+
+// Weight returns the numeric weight of the edge, stored using the
+// WeightAttribute attribute. Edges without a weight have a weight of 1.
+func (e *Edge) Weight() float64 {
+	if w, ok := toFloat(e.Attributes[WeightAttribute]); ok {
+		return w
+	}
+	return 1
+}
+
+// From returns the node whose adjacency list contains the edge, which is
+// the node on the other side of the relationship from the edge's Node.
+//
+// This is nil for edges that were not added using the Node methods.
+func (e *Edge) From() *Node {
+	return e.from
+}
+
+// String returns a human-readable string for the edge, using the names of
+// the node it belongs to and its node, with the glyph of its direction.
+//
+//	a → b, a ← b, a ↔ b, a - b
+func (e *Edge) String() string {
+	return fmt.Sprintf("%s %s %s", nodeName(e.from), e.Direction, nodeName(e.Node))
+}
+
+// follows checks if the edge can be followed in the given direction, like
+// Edges.successors: outward unless it is an inward edge, inward unless it
+// is an outward edge, and in any other direction always.
+func (e *Edge) follows(direction EdgeDirection) bool {
+	switch direction {
+	case Out:
+		return e.Direction != In
+	case In:
+		return e.Direction != Out
+	default:
+		return true
+	}
+}
+
+// reciprocal returns the corresponding edge in the adjacency list of the
+// edge's node that points back to the given node, or nil if there is none.
+func (e *Edge) reciprocal(from *Node) *Edge {
+	for _, other := range e.Node.Edges {
+		if other != e && other.Node == from && other.Direction == e.Direction.Reverse() && other.Name == e.Name {
+			return other
+		}
+	}
+	return nil
 }
 
 // Edges is a collection of Node relationships.
 type Edges []*Edge
 
+// String returns a comma-separated list of the edges' strings.
+func (edges Edges) String() string {
+	strs := make([]string, len(edges))
+	for i, edge := range edges {
+		strs[i] = edge.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
 func (edges Edges) Contains(n *Node) bool {
 	for _, edge := range edges {
 		if edge.Node == n {
@@ -140,3 +219,53 @@ func AddEdges(addEdge ...AddEdge) {
 
 // EdgeMap is a map of nodes to a slice of nodes.
 type EdgeMap map[*Node]Nodes
+
+// successors returns the nodes of the edges that can be followed outward,
+// which are all edges that aren't inward edges.
+func (edges Edges) successors() Nodes {
+	var nodes Nodes
+	for _, edge := range edges {
+		if edge.Direction != In {
+			nodes = append(nodes, edge.Node)
+		}
+	}
+	return nodes
+}
+
+// successorsWhere returns the successors of the edges, like successors, only
+// following the edges allowed by the given filter, if not nil.
+func (edges Edges) successorsWhere(filter EdgeFilter) Nodes {
+	if filter == nil {
+		return edges.successors()
+	}
+
+	var nodes Nodes
+	for _, edge := range edges {
+		if edge.Direction != In && filter(edge) {
+			nodes = append(nodes, edge.Node)
+		}
+	}
+	return nodes
+}
+
+// without returns the edges, excluding the given edge.
+func (edges Edges) without(e *Edge) Edges {
+	other := Edges{}
+	for _, edge := range edges {
+		if edge != e {
+			other = append(other, edge)
+		}
+	}
+	return other
+}
+
+// find returns the first edge to the given node with the given direction
+// and name, or nil if there is none.
+func (edges Edges) find(n *Node, direction EdgeDirection, name string) *Edge {
+	for _, edge := range edges {
+		if edge.Node == n && edge.Direction == direction && edge.Name == name {
+			return edge
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"sort"
+	"time"
+)
+
+// EncodeOptions configures the behavior of the graph encoders.
+type EncodeOptions struct {
+	// NodeDefaults are attributes that apply to all nodes, unless
+	// a node has an attribute with the same name.
+	NodeDefaults Attributes
+
+	// EdgeDefaults are attributes that apply to all edges, unless
+	// an edge has an attribute with the same name.
+	EdgeDefaults Attributes
+
+	// Undirected controls whether encoders that distinguish directed and
+	// undirected graphs, like DOT, encode the graph as undirected. If nil,
+	// it is detected from the graph's edges.
+	Undirected *bool
+
+	// Canonical sorts nodes, edges, and attribute keys so the encoding
+	// doesn't depend on the order they were added in, which makes it
+	// reproducible for diffing and hashing.
+	Canonical bool
+
+	// Name is the name of the graph, used by encoders that include it,
+	// like the DOT header.
+	Name string
+
+	// Header enables a comment block at the start of encodings that
+	// support comments, like DOT, describing the graph: its name, when it
+	// was generated, its node and edge counts, and the attributes used.
+	Header bool
+
+	// Generated is the time written in the header. If zero, it is omitted,
+	// keeping the encoding reproducible.
+	Generated time.Time
+
+	// DirectionGlyphs adds the glyph of each edge's direction, like "→",
+	// to the labels of edges, for encoders that support them, like DOT and
+	// JSON, to make exports easier for humans to read.
+	DirectionGlyphs bool
+
+	// Compression is the gzip compression level of the encoding, for
+	// encoders that support it, like JSON, from gzip.BestSpeed to
+	// gzip.BestCompression, or gzip.DefaultCompression. If zero, the
+	// encoding isn't compressed.
+	Compression int
+}
+
+// WithNodeDefaults is a functional option that sets the default
+// node attributes to encode.
+func WithNodeDefaults(attrs Attributes) func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.NodeDefaults = attrs
+	}
+}
+
+// WithEdgeDefaults is a functional option that sets the default
+// edge attributes to encode.
+func WithEdgeDefaults(attrs Attributes) func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.EdgeDefaults = attrs
+	}
+}
+
+// WithUndirected is a functional option that sets whether the graph is
+// encoded as an undirected graph, instead of detecting it from the edges.
+func WithUndirected(undirected bool) func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.Undirected = &undirected
+	}
+}
+
+// WithCanonical is a functional option that enables the canonical,
+// deterministically sorted encoding.
+func WithCanonical() func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.Canonical = true
+	}
+}
+
+// WithName is a functional option that sets the name of the graph to
+// encode, for encoders that include it.
+func WithName(name string) func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.Name = name
+	}
+}
+
+// WithHeader is a functional option that enables the header comment block,
+// written with the given generation time, which is omitted if zero.
+func WithHeader(generated time.Time) func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.Header = true
+		opts.Generated = generated
+	}
+}
+
+// WithDirectionGlyphs is a functional option that adds the glyph of each
+// edge's direction to the labels of edges.
+func WithDirectionGlyphs() func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.DirectionGlyphs = true
+	}
+}
+
+// WithCompression is a functional option that compresses the encoding
+// using gzip with the given compression level, like gzip.BestSpeed or
+// gzip.DefaultCompression, for encoders that support it, so large graphs
+// take less space when they are stored or sent.
+func WithCompression(level int) func(*EncodeOptions) {
+	return func(opts *EncodeOptions) {
+		opts.Compression = level
+	}
+}
+
+// newEncodeOptions returns the encode options with the given
+// functional options applied.
+func newEncodeOptions(opts ...func(*EncodeOptions)) *EncodeOptions {
+	options := &EncodeOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// canonicalOrder returns a copy of the given nodes sorted by name, and
+// then by their attributes for nodes with the same name.
+func canonicalOrder(nodes Nodes) Nodes {
+	sorted := append(Nodes{}, nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return canonicalAttributes(sorted[i].Attributes) < canonicalAttributes(sorted[j].Attributes)
+	})
+	return sorted
+}
@@ -0,0 +1,208 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Equal checks if the given graphs are structurally equal, having the same
+// nodes, by name, labels, and attributes, and the same edges between them,
+// by direction, name, and attributes, regardless of the order they were
+// added in. The names and attributes of the graphs themselves are ignored.
+//
+// Attribute values are compared using their Go syntax representation, like
+// %#v, so numbers are compared by value, like int(1) and float64(1), which
+// decoded graphs have, but composite values of different types, like
+// []int{1} and []float64{1}, are not equal. Pointers are compared by the
+// values they point to, so graphs decoded or copied separately can be
+// equal, while functions and channels, which have no value to compare, are
+// compared by identity.
+func Equal(a, b *Instance) bool {
+	ca, cb := a.canonical(), b.canonical()
+
+	if len(ca) != len(cb) {
+		return false
+	}
+
+	for i := range ca {
+		if ca[i] != cb[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Hash returns a hex encoded SHA-256 hash of the graph's canonical form,
+// which is the same for graphs that are Equal, so it can be used to
+// deduplicate or cache results computed for a graph. Like Equal, pointer
+// attribute values are hashed by the values they point to, not their
+// addresses.
+func (inst *Instance) Hash() string {
+	h := sha256.New()
+	for _, line := range inst.canonical() {
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonical returns a sorted list of lines describing the graph's nodes
+// and edges, which doesn't depend on the order they were added in.
+func (inst *Instance) canonical() []string {
+	var lines []string
+
+	for _, node := range inst.Nodes {
+		labels := append([]string(nil), node.Labels...)
+		sort.Strings(labels)
+
+		lines = append(lines, fmt.Sprintf("node %q %q %s", node.Name, labels, canonicalAttributes(node.Attributes)))
+
+		for _, edge := range node.Edges {
+			lines = append(lines, fmt.Sprintf("edge %q %s %q %q %s", node.Name, edge.Direction, edge.Node.Name, edge.Name, canonicalAttributes(edge.Attributes)))
+		}
+	}
+
+	sort.Strings(lines)
+
+	return lines
+}
+
+// canonicalAttributes returns a representation of the given attributes
+// with their keys sorted.
+func canonicalAttributes(attrs Attributes) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%q=%s", k, canonicalValue(attrs[k]))
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// canonicalValue returns the Go syntax representation of the given value,
+// like %#v, with the values pointers point to instead of their addresses,
+// and map entries sorted by their keys.
+func canonicalValue(v any) string {
+	var b strings.Builder
+	writeCanonical(&b, reflect.ValueOf(v), map[uintptr]bool{})
+	return b.String()
+}
+
+// writeCanonical writes the canonical representation of the given value,
+// see canonicalValue, using the given pointers that are being written to
+// stop at cycles.
+func writeCanonical(b *strings.Builder, v reflect.Value, seen map[uintptr]bool) {
+	if !v.IsValid() {
+		b.WriteString("nil")
+		return
+	}
+
+	// Values that describe themselves, like time.Time, are written the
+	// way they do, rather than by their internal fields.
+	if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface && v.CanInterface() {
+		if gs, ok := v.Interface().(fmt.GoStringer); ok {
+			b.WriteString(gs.GoString())
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			fmt.Fprintf(b, "(%s)(nil)", v.Type())
+			return
+		}
+		if seen[v.Pointer()] {
+			fmt.Fprintf(b, "(%s)(cycle)", v.Type())
+			return
+		}
+		seen[v.Pointer()] = true
+		defer delete(seen, v.Pointer())
+
+		b.WriteString("&")
+		writeCanonical(b, v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprintf(b, "%s(nil)", v.Type())
+			return
+		}
+		writeCanonical(b, v.Elem(), seen)
+	case reflect.Struct:
+		fmt.Fprintf(b, "%s{", v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(v.Type().Field(i).Name)
+			b.WriteString(":")
+			writeCanonical(b, v.Field(i), seen)
+		}
+		b.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			fmt.Fprintf(b, "%s(nil)", v.Type())
+			return
+		}
+		fmt.Fprintf(b, "%s{", v.Type())
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeCanonical(b, v.Index(i), seen)
+		}
+		b.WriteString("}")
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(b, "%s(nil)", v.Type())
+			return
+		}
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			writeCanonical(&entry, iter.Key(), seen)
+			entry.WriteString(":")
+			writeCanonical(&entry, iter.Value(), seen)
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		fmt.Fprintf(b, "%s{%s}", v.Type(), strings.Join(entries, ", "))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		fmt.Fprintf(b, "(%s)(%#x)", v.Type(), v.Pointer())
+	case reflect.String:
+		fmt.Fprintf(b, "%q", v.String())
+	default:
+		// Unexported fields can't be converted back to interfaces, so
+		// basic values are formatted from their kind.
+		b.WriteString(canonicalBasic(v))
+	}
+}
+
+// canonicalBasic returns the representation of a value of a basic kind,
+// like a number or a boolean.
+func canonicalBasic(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return fmt.Sprint(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	default:
+		return v.Kind().String()
+	}
+}
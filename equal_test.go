@@ -0,0 +1,127 @@
+package graph_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestEqual(t *testing.T) {
+	build := func(reverse bool) *graph.Instance {
+		var (
+			a = graph.NewNode("a", graph.Attributes{"x": 1, "y": "z"})
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+		)
+
+		nodes := graph.NewNodes(a, b, c)
+
+		if reverse {
+			c.AddEdge(a)
+			a.AddWeightedEdge(b, 2)
+			nodes = graph.NewNodes(c, b, a)
+		} else {
+			a.AddWeightedEdge(b, 2)
+			c.AddEdge(a)
+		}
+
+		return graph.New("test", graph.WithNodes(nodes))
+	}
+
+	g1, g2 := build(false), build(true)
+
+	if !graph.Equal(g1, g2) {
+		t.Fatalf("expected graphs built in a different order to be equal")
+	}
+
+	if g1.Hash() != g2.Hash() {
+		t.Fatalf("expected equal graphs to have the same hash")
+	}
+
+	g2.Nodes[0].Attributes = graph.Attributes{"x": 1.0}
+
+	if graph.Equal(g1, g2) || g1.Hash() == g2.Hash() {
+		t.Fatalf("expected graphs with different attributes to not be equal")
+	}
+
+	g3 := build(false)
+	g3.Nodes[1].AddEdge(g3.Nodes[2])
+
+	if graph.Equal(g1, g3) || g1.Hash() == g3.Hash() {
+		t.Fatalf("expected graphs with different edges to not be equal")
+	}
+}
+
+func TestWithCanonical(t *testing.T) {
+	build := func(reverse bool) graph.Nodes {
+		var (
+			a = graph.NewNode("a", graph.Attributes{"x": 1, "y": "z"})
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+		)
+
+		if reverse {
+			a.AddEdge(c)
+			a.AddEdge(b)
+			return graph.NewNodes(c, b, a)
+		}
+
+		a.AddEdge(b)
+		a.AddEdge(c)
+		return graph.NewNodes(a, b, c)
+	}
+
+	encoders := map[string]func(w io.Writer, nodes graph.Nodes, opts ...func(*graph.EncodeOptions)) error{
+		"json": graph.EncodeJSON,
+		"dot":  graph.EncodeDOT,
+	}
+
+	for name, encode := range encoders {
+		t.Run(name, func(t *testing.T) {
+			var buf1, buf2 bytes.Buffer
+
+			if err := encode(&buf1, build(false), graph.WithCanonical()); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := encode(&buf2, build(true), graph.WithCanonical()); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf1.String() != buf2.String() {
+				t.Fatalf("expected canonical encodings to match:\n%s\n%s", buf1.String(), buf2.String())
+			}
+		})
+	}
+}
+
+func TestInstance_Hash_pointers(t *testing.T) {
+	type config struct {
+		Replicas *int
+		Tags     map[string]any
+	}
+
+	build := func(replicas int) *graph.Instance {
+		self := &struct{ Next any }{}
+		self.Next = self
+
+		a := graph.NewNode("a", graph.Attributes{
+			"replicas": &replicas,
+			"config":   &config{Replicas: &replicas, Tags: map[string]any{"b": 2, "a": 1}},
+			"self":     self,
+		})
+		return graph.New("test", graph.WithNodes(graph.NewNodes(a)))
+	}
+
+	g1, g2 := build(3), build(3)
+
+	if !graph.Equal(g1, g2) || g1.Hash() != g2.Hash() {
+		t.Fatalf("expected pointers to equal values to be equal")
+	}
+
+	if g3 := build(4); graph.Equal(g1, g3) || g1.Hash() == g3.Hash() {
+		t.Fatalf("expected pointers to different values to not be equal")
+	}
+}
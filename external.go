@@ -0,0 +1,110 @@
+package graph
+
+// Graph returns the graph the node was last added to, or nil if it wasn't
+// added to a graph, like the graph used by its ID, see Instance.ID.
+func (n *Node) Graph() *Instance {
+	if n == nil || n.graph == nil || n.graph.ID(n) < 0 {
+		return nil
+	}
+	return n.graph
+}
+
+// IsExternal checks if the edge crosses a graph boundary, connecting nodes
+// that belong to different graphs, like a service of one team's graph that
+// depends on a service of another team's graph:
+//
+//	team-a: api ──→ auth :team-b
+//
+// Edges between nodes that weren't added to any graph, or whose node it
+// belongs to isn't known, see From, aren't external.
+func (e *Edge) IsExternal() bool {
+	if e.from == nil || e.Node == nil {
+		return false
+	}
+	return e.from.Graph() != e.Node.Graph()
+}
+
+// ExternalEdge is an edge from a node of a graph to a node of another
+// graph, see Instance.ExternalEdges.
+type ExternalEdge struct {
+	// From is the node of the graph the edge belongs to.
+	From *Node
+
+	// Edge is the edge, as seen from the From node, so its direction
+	// is relative to it.
+	Edge *Edge
+
+	// Graph is the graph the other node belongs to, or nil if it isn't
+	// in a graph.
+	Graph *Instance
+
+	// Node is the node on the other side of the edge, outside of the graph.
+	Node *Node
+}
+
+// String returns the edge, qualified with the names of the graphs of its
+// nodes.
+//
+//	team-a:api → team-b:auth
+func (e ExternalEdge) String() string {
+	return graphName(e.From.Graph()) + ":" + e.From.Name + " " + e.Edge.Direction.String() + " " + graphName(e.Graph) + ":" + e.Node.Name
+}
+
+// graphName returns the name of the graph, or an empty string for nil.
+func graphName(inst *Instance) string {
+	if inst == nil {
+		return ""
+	}
+	return inst.Name
+}
+
+// ExternalEdges returns the edges of the graph's nodes to nodes that aren't
+// in the graph, which cross the graph's boundary, like the dependencies
+// of one team's graph on the services of other teams, in the order of the
+// graph's nodes and their edges. Both inward and outward edges are
+// included, so the dependencies of other graphs on the graph are found too.
+//
+// Traversals can follow external edges into other graphs, which is the
+// default, or stop at the graph's boundary, see StopAtGraphBoundary.
+func (inst *Instance) ExternalEdges() []ExternalEdge {
+	members := inst.members()
+
+	var external []ExternalEdge
+	for _, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if edge.Node == nil || members.Contains(edge.Node) {
+				continue
+			}
+			external = append(external, ExternalEdge{
+				From:  node,
+				Edge:  edge,
+				Graph: edge.Node.Graph(),
+				Node:  edge.Node,
+			})
+		}
+	}
+	return external
+}
+
+// StopAtGraphBoundary makes traversals, and the algorithms accepting
+// traversal options, like Node.PathTo, only follow edges between nodes of
+// the same graph, without following external edges into other graphs, see
+// Edge.IsExternal. By default, traversals follow edges into other graphs.
+//
+//	team-a: web → api ──→ auth :team-b
+//
+//	web.Visit(fn):                        web, api, auth
+//	web.Visit(fn, StopAtGraphBoundary()): web, api
+func StopAtGraphBoundary() func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.StopAtBoundary = true
+	}
+}
+
+// FollowExternalEdges makes traversals follow external edges into other
+// graphs, which is the default, to undo StopAtGraphBoundary.
+func FollowExternalEdges() func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.StopAtBoundary = false
+	}
+}
@@ -0,0 +1,69 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_ExternalEdges(t *testing.T) {
+	var (
+		web  = graph.NewNode("web", nil)
+		api  = graph.NewNode("api", nil)
+		auth = graph.NewNode("auth", nil)
+		db   = graph.NewNode("db", nil)
+	)
+
+	web.AddEdge(api)
+	api.AddEdge(auth)
+	auth.AddEdge(db)
+
+	teamA := graph.New("team-a", graph.WithNodes(graph.Nodes{web, api}))
+	teamB := graph.New("team-b", graph.WithNodes(graph.Nodes{auth, db}))
+
+	if web.Graph() != teamA || auth.Graph() != teamB || graph.NewNode("x", nil).Graph() != nil {
+		t.Fatalf("unexpected graphs of nodes")
+	}
+
+	if web.Edges[0].IsExternal() || !api.Edges[1].IsExternal() || !auth.Edges[0].IsExternal() {
+		t.Fatalf("unexpected external edges")
+	}
+
+	external := teamA.ExternalEdges()
+	if got := fmt.Sprint(external); got != "[team-a:api → team-b:auth]" {
+		t.Fatalf("unexpected external edges of team-a: %s", got)
+	}
+
+	if external[0].Graph != teamB || external[0].Node != auth || external[0].From != api {
+		t.Fatalf("unexpected external edge: %+v", external[0])
+	}
+
+	if got := fmt.Sprint(teamB.ExternalEdges()); got != "[team-b:auth ← team-a:api]" {
+		t.Fatalf("unexpected external edges of team-b: %s", got)
+	}
+
+	visit := func(opts ...func(*graph.VisitOrder)) string {
+		var visited graph.Nodes
+		web.Visit(func(n *graph.Node) {
+			visited = append(visited, n)
+		}, opts...)
+		return visited.String()
+	}
+
+	if got := visit(); got != "web, api, auth, db" {
+		t.Fatalf("unexpected visited nodes: %s", got)
+	}
+
+	if got := visit(graph.StopAtGraphBoundary()); got != "web, api" {
+		t.Fatalf("unexpected visited nodes stopping at the boundary: %s", got)
+	}
+
+	if got := visit(graph.StopAtGraphBoundary(), graph.FollowExternalEdges()); got != "web, api, auth, db" {
+		t.Fatalf("unexpected visited nodes following external edges: %s", got)
+	}
+
+	if web.HasPath(db, graph.StopAtGraphBoundary()) || !web.HasPath(db) {
+		t.Fatalf("unexpected paths across the boundary")
+	}
+}
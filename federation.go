@@ -0,0 +1,179 @@
+package graph
+
+import "fmt"
+
+// Resolver resolves placeholder nodes, see NewPlaceholder, so graphs can be
+// expanded lazily from APIs or databases, only loading the parts of a
+// federated graph that are used. It is set using WithResolver.
+//
+// Resolve returns the node with the given name, with its attributes,
+// labels, and edges to other nodes, which can be placeholders themselves,
+// to be resolved when they are reached in turn.
+type Resolver interface {
+	Resolve(name string) (*Node, error)
+}
+
+// ResolverFunc is a function that implements the Resolver interface.
+type ResolverFunc func(name string) (*Node, error)
+
+// Resolve implements the Resolver interface.
+func (f ResolverFunc) Resolve(name string) (*Node, error) {
+	return f(name)
+}
+
+// WithResolver is a functional option that sets the resolver consulted when
+// a placeholder node of the graph is reached by a traversal, like
+// Node.Visit, Instance.DFS, Instance.BFS, or Node.PathTo, or resolved using
+// Instance.Resolve. Clones of the graph share its resolver. Errors of
+// resolving the placeholders reached by traversals are reported by Err.
+//
+//	g := graph.New("services", graph.WithResolver(graph.ResolverFunc(
+//		func(name string) (*graph.Node, error) {
+//			return fetchService(ctx, name)
+//		},
+//	)))
+//
+//	g.AddNode(graph.NewPlaceholder("api"))
+func WithResolver(resolver Resolver) func(*Instance) {
+	return func(inst *Instance) {
+		inst.resolver = resolver
+	}
+}
+
+// NewPlaceholder returns a new placeholder node with the given name, which
+// stands for a node that hasn't been loaded yet, and is resolved using the
+// resolver of the graph it is added to, see WithResolver, when it is
+// reached.
+func NewPlaceholder(name string) *Node {
+	return &Node{Name: name, placeholder: true}
+}
+
+// IsPlaceholder checks if the node is a placeholder that hasn't been
+// resolved yet, see NewPlaceholder.
+func (n *Node) IsPlaceholder() bool {
+	return n.placeholder
+}
+
+// Placeholders returns the nodes of the graph that haven't been resolved
+// yet, in the order of the graph.
+func (inst *Instance) Placeholders() Nodes {
+	return inst.Nodes.Filter(func(n *Node) bool {
+		return n.placeholder
+	})
+}
+
+// Resolve resolves the given placeholder node using the graph's resolver,
+// see WithResolver, filling it in place with the attributes, labels, and
+// edges of the node returned by the resolver, so edges to the placeholder
+// stay valid. Nothing is done for nodes that aren't placeholders.
+//
+// Edges of the resolved node to nodes that aren't in the graph add them to
+// the graph, unless the graph has a node with the same name, which is used
+// instead, so placeholders are shared by the nodes that refer to them.
+// Edges the placeholder already has, with the same direction and name, are
+// not added twice.
+//
+// An error is returned, and the node stays a placeholder, if the graph
+// doesn't have a resolver, or the resolver fails, or doesn't find the node.
+func (inst *Instance) Resolve(n *Node) error {
+	if !n.placeholder {
+		return nil
+	}
+
+	if inst.resolver == nil {
+		return fmt.Errorf("graph cannot resolve placeholder %q without a resolver", n.Name)
+	}
+
+	resolved, err := inst.resolver.Resolve(n.Name)
+	if err != nil {
+		return fmt.Errorf("graph failed to resolve placeholder %q: %w", n.Name, err)
+	}
+	if resolved == nil {
+		return fmt.Errorf("graph failed to resolve placeholder %q: not found", n.Name)
+	}
+
+	n.placeholder = false
+	n.touch()
+
+	for k, v := range resolved.Attributes {
+		n.SetAttribute(k, v)
+	}
+	n.AddLabel(resolved.Labels...)
+
+	members := inst.members()
+
+	for _, edge := range resolved.Edges {
+		target := edge.Node
+		if target == resolved {
+			target = n
+		} else {
+			// The edges of the resolved node's neighbors refer to it,
+			// and are replaced by edges to the placeholder.
+			target.Edges = target.Edges.withoutNode(resolved)
+
+			if !members.Contains(target) {
+				if existing, ok := inst.Lookup(target.Name); ok {
+					target = existing
+				} else {
+					inst.AddNode(target)
+				}
+			}
+		}
+
+		if n.Edges.find(target, edge.Direction, edge.Name) != nil {
+			continue
+		}
+
+		n.AddEdgeWithDirection(target, edge.Direction)
+
+		added := Edges{n.Edges[len(n.Edges)-1], target.Edges[len(target.Edges)-1]}
+		if target == n {
+			added[1] = n.Edges[len(n.Edges)-2]
+		}
+		for _, e := range added {
+			e.Name = edge.Name
+			e.Attributes = edge.Attributes
+		}
+	}
+
+	inst.Touch()
+
+	return nil
+}
+
+// withoutNode returns the edges that don't point to the given node.
+func (edges Edges) withoutNode(n *Node) Edges {
+	var kept Edges
+	for _, edge := range edges {
+		if edge.Node != n {
+			kept = append(kept, edge)
+		}
+	}
+	return kept
+}
+
+// Err returns the first error of resolving the placeholder nodes reached by
+// traversals, see WithResolver, or nil if none failed. Traversals continue
+// past placeholders that can't be resolved, which stay placeholders, so Err
+// should be checked after them:
+//
+//	api.Visit(fn)
+//	if err := g.Err(); err != nil {
+//		return err
+//	}
+func (inst *Instance) Err() error {
+	return inst.resolveErr
+}
+
+// resolvePlaceholder resolves the node if it is a placeholder of a graph
+// with a resolver, when it is reached by a traversal. Nodes that can't be
+// resolved stay placeholders, and can be resolved again later, while the
+// first error is recorded for Err.
+func (n *Node) resolvePlaceholder() {
+	if n == nil || !n.placeholder || n.graph == nil || n.graph.resolver == nil {
+		return
+	}
+	if err := n.graph.Resolve(n); err != nil && n.graph.resolveErr == nil {
+		n.graph.resolveErr = err
+	}
+}
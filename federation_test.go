@@ -0,0 +1,96 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestWithResolver(t *testing.T) {
+	dependencies := map[string][]string{
+		"api":  {"auth", "db"},
+		"auth": {"db"},
+		"db":   nil,
+	}
+
+	var calls []string
+
+	resolver := graph.ResolverFunc(func(name string) (*graph.Node, error) {
+		calls = append(calls, name)
+
+		deps, ok := dependencies[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %q", name)
+		}
+
+		node := graph.NewNode(name, graph.Attributes{"loaded": true})
+		for _, dep := range deps {
+			node.AddEdgeTyped(graph.NewPlaceholder(dep), "depends_on")
+		}
+		return node, nil
+	})
+
+	api := graph.NewPlaceholder("api")
+
+	g := graph.New("services", graph.WithResolver(resolver))
+	g.AddNode(api)
+
+	if !api.IsPlaceholder() || len(g.Placeholders()) != 1 {
+		t.Fatalf("expected api to be a placeholder")
+	}
+
+	var visited graph.Nodes
+	api.Visit(func(n *graph.Node) {
+		visited = append(visited, n)
+	})
+
+	if got := visited.String(); got != "api, auth, db" {
+		t.Fatalf("unexpected visited nodes: %s", got)
+	}
+
+	if got := fmt.Sprint(calls); got != "[api auth db]" {
+		t.Fatalf("expected each node to be resolved once, got %s", got)
+	}
+
+	if len(g.Placeholders()) != 0 || len(g.Nodes) != 3 || api.Attributes["loaded"] != true {
+		t.Fatalf("expected every node to be resolved, got %v", g.Nodes)
+	}
+
+	if got := g.Edges().String(); got != "api → auth, api → db, auth → db" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	for _, edge := range g.Edges() {
+		if edge.Name != "depends_on" {
+			t.Fatalf("unexpected edge name %q", edge.Name)
+		}
+	}
+
+	// Nodes that can't be resolved stay placeholders.
+	missing := graph.NewPlaceholder("missing")
+	g.AddNode(missing)
+
+	if err := g.Resolve(missing); err == nil || !missing.IsPlaceholder() {
+		t.Fatalf("expected an error resolving an unknown node, got %v", err)
+	}
+
+	// Errors of placeholders reached by traversals are kept for Err.
+	if err := g.Err(); err != nil {
+		t.Fatalf("expected no traversal error yet, got %v", err)
+	}
+
+	missing.Visit(func(*graph.Node) {})
+
+	if err := g.Err(); err == nil || !missing.IsPlaceholder() {
+		t.Fatalf("expected the traversal error to be reported, got %v", err)
+	}
+
+	if err := graph.New("other").Resolve(graph.NewPlaceholder("api")); err == nil {
+		t.Fatalf("expected an error resolving without a resolver")
+	}
+
+	if err := g.Resolve(api); err != nil {
+		t.Fatalf("expected resolving a resolved node to do nothing, got %v", err)
+	}
+}
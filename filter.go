@@ -0,0 +1,100 @@
+package graph
+
+// EdgeFilter reports whether an edge should be considered by an algorithm,
+// so analyses can be run on a logical subset of a graph's edges, like only
+// "runtime" dependencies, without copying the graph. Edges are given from
+// the side of the node they are followed from, and both edges of a
+// relationship are expected to be filtered the same way.
+type EdgeFilter func(*Edge) bool
+
+// WithEdgeFilter makes traversals, like Node.Visit, Instance.DFS, and
+// Instance.BFS, and the algorithms accepting traversal options, like
+// Node.PathTo, Node.PathToAvoiding, Node.PathToMatching, Node.Reachable,
+// Node.Distances, Instance.Components, Instance.StronglyConnectedComponents,
+// Instance.Bridges, Instance.FindBridges, and Instance.FindCliques, only
+// follow the edges the given filter returns true for.
+//
+// Algorithms that don't accept traversal options ignore it, like the
+// algorithms of the Graph interface, such as ShortestPath. Instance's
+// ShortestPathWhere takes the filter as its edge predicate instead.
+//
+//	runtime := graph.WithEdgeFilter(func(e *graph.Edge) bool {
+//		return e.Name != "dev"
+//	})
+//
+//	a.PathTo(b, runtime)
+//	g.Components(runtime)
+func WithEdgeFilter(filter EdgeFilter) func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.Filter = filter
+	}
+}
+
+// edgeFilter returns the filter of the edges that can be followed, combining
+// the Filter, AsOf, and StopAtBoundary options, or nil if every edge can be.
+// It is built once, the first time it is needed by the traversal.
+func (o *VisitOrder) edgeFilter() EdgeFilter {
+	if o == nil {
+		return nil
+	}
+	if !o.built {
+		o.compiled, o.built = o.buildEdgeFilter(), true
+	}
+	return o.compiled
+}
+
+// buildEdgeFilter returns the filter of the edges that can be followed, see
+// edgeFilter.
+func (o *VisitOrder) buildEdgeFilter() EdgeFilter {
+	if !o.StopAtBoundary && o.AsOf.IsZero() {
+		return o.Filter
+	}
+
+	filter, asOf, boundary := o.Filter, o.AsOf, o.StopAtBoundary
+	return func(edge *Edge) bool {
+		if boundary && edge.IsExternal() {
+			return false
+		}
+		if !asOf.IsZero() && !edge.ValidAt(asOf) {
+			return false
+		}
+		return filter == nil || filter(edge)
+	}
+}
+
+// filter returns the given edges that can be followed, which are the given
+// edges themselves if there is no filter.
+func (o *VisitOrder) filter(edges Edges) Edges {
+	filter := o.edgeFilter()
+	if filter == nil {
+		return edges
+	}
+
+	var filtered Edges
+	for _, edge := range edges {
+		if filter(edge) {
+			filtered = append(filtered, edge)
+		}
+	}
+	return filtered
+}
+
+// filteredGraph is a Graph backend for an Instance, reporting only the
+// edges allowed by a filter, so backend algorithms, like BridgesOf, can be
+// run on a subset of the graph's edges without copying it.
+type filteredGraph struct {
+	*Instance
+	filter EdgeFilter
+}
+
+// OutEdges returns the edges of the given node that aren't inward edges,
+// and are allowed by the filter.
+func (g filteredGraph) OutEdges(n NodeRef) []EdgeRef {
+	return outEdges(n, g.filter)
+}
+
+// InEdges returns the edges pointing to the given node, which are allowed
+// by the filter.
+func (g filteredGraph) InEdges(n NodeRef) []EdgeRef {
+	return inEdges(n, g.filter)
+}
@@ -0,0 +1,139 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestWithEdgeFilter(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a → b → c is a runtime path, and c → d and b ↔ d are dev only.
+	a.AddEdgeTyped(b, "runtime")
+	b.AddEdgeTyped(c, "runtime")
+	c.AddEdgeTyped(d, "dev")
+	b.AddEdgeWithDirection(d, graph.Both)
+	b.Edges[len(b.Edges)-1].Name = "dev"
+	d.Edges[len(d.Edges)-1].Name = "dev"
+
+	g := graph.New("deps", graph.WithNodes(graph.Nodes{a, b, c, d}))
+
+	runtime := graph.WithEdgeFilter(func(e *graph.Edge) bool {
+		return e.Name == "runtime"
+	})
+
+	var visited graph.Nodes
+	a.Visit(func(n *graph.Node) {
+		visited = append(visited, n)
+	}, runtime)
+
+	if got := visited.String(); got != "a, b, c" {
+		t.Fatalf("unexpected visited nodes: %s", got)
+	}
+
+	visited = nil
+	g.BFS(func(n *graph.Node) {
+		visited = append(visited, n)
+	}, runtime)
+
+	if got := visited.String(); got != "a, b, c, d" {
+		t.Fatalf("unexpected BFS order: %s", got)
+	}
+
+	if path := a.PathTo(d); path.String() != "a → b → d" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	if a.HasPath(d, runtime) {
+		t.Fatalf("expected no runtime path from a to d")
+	}
+
+	if components := g.Components(runtime); len(components) != 2 || components[1].String() != "d" {
+		t.Fatalf("unexpected components: %v", components)
+	}
+
+	if components := g.Components(); len(components) != 1 {
+		t.Fatalf("expected the filter to not change cached components: %v", components)
+	}
+
+	if got := g.Bridges(runtime).String(); got != "b → c, a → b" {
+		t.Fatalf("unexpected bridges: %s", got)
+	}
+
+	// Without the filter, b → c is part of the b → c → d ↔ b cycle.
+	if got := g.Bridges().String(); got != "a → b" {
+		t.Fatalf("unexpected bridges without a filter: %s", got)
+	}
+
+	for _, bridge := range g.FindBridges(runtime) {
+		for i := 1; i < len(bridge); i++ {
+			if bridge[i] == d || bridge[i-1] == d {
+				t.Fatalf("unexpected bridge through a dev edge: %v", bridge)
+			}
+		}
+	}
+
+	for _, clique := range g.FindCliques(2, runtime) {
+		if clique.Contains(d) {
+			t.Fatalf("unexpected clique through a dev edge: %v", clique)
+		}
+	}
+}
+
+func TestWithEdgeFilter_algorithms(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b → c is a runtime path, and c → a is dev only.
+	a.AddEdgeTyped(b, "runtime")
+	b.AddEdgeTyped(c, "runtime")
+	c.AddEdgeTyped(a, "dev")
+
+	g := graph.New("deps", graph.WithNodes(graph.Nodes{a, b, c}))
+
+	runtime := graph.WithEdgeFilter(func(e *graph.Edge) bool {
+		return e.Name == "runtime"
+	})
+
+	if sccs := g.StronglyConnectedComponents(); len(sccs) != 1 {
+		t.Fatalf("expected 1 strongly connected component, got %v", sccs)
+	}
+	if sccs := g.StronglyConnectedComponents(runtime); len(sccs) != 3 {
+		t.Fatalf("expected 3 strongly connected components, got %v", sccs)
+	}
+
+	if reachable := c.Reachable(graph.Out, runtime); reachable.Len() != 0 {
+		t.Fatalf("expected nothing to be reachable from c, got %v", reachable)
+	}
+	if reachable := a.ReachableFrom(runtime); reachable.Len() != 0 {
+		t.Fatalf("expected nothing to reach a, got %v", reachable)
+	}
+
+	if distances := c.Distances(runtime); len(distances) != 1 {
+		t.Fatalf("expected only c to be at a distance from c, got %v", distances)
+	}
+	if distances := c.WeightedDistances(runtime); len(distances) != 1 {
+		t.Fatalf("expected only c to be at a distance from c, got %v", distances)
+	}
+
+	isA := func(n *graph.Node) bool { return n == a }
+	if path := c.PathToMatching(isA); path.String() != "c → a" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+	if path := c.PathToMatching(isA, runtime); path != nil {
+		t.Fatalf("expected no runtime path from c to a, got %v", path)
+	}
+
+	if path := c.PathToAvoiding(b, nil, nil, runtime); path != nil {
+		t.Fatalf("expected no runtime path from c to b, got %v", path)
+	}
+}
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+	"sort"
+)
+
+// Fingerprint returns a hex encoded SHA-256 hash of the graph's structure,
+// ignoring the names, labels, and attributes of its nodes and edges, which
+// is the same for isomorphic graphs. Graphs with different fingerprints
+// can't be isomorphic, so it can be used to quickly reject graphs before
+// matching their nodes, or to bucket graphs with the same shape:
+//
+//	a → b → c    x → y → z    same fingerprint
+//	a → b ← c    x → y → z    different fingerprints
+//
+// The fingerprint is made of the number of nodes and edges, the sorted
+// degree sequence, and the colors of the nodes after each iteration of the
+// Weisfeiler–Lehman algorithm, which refines the color of each node with
+// the colors of its neighbors, by edge direction, until the number of colors
+// stops growing. Edges to nodes outside of the graph are ignored.
+//
+// Some graphs that aren't isomorphic, like regular graphs with the same
+// degree, have the same fingerprint, so equal fingerprints don't prove that
+// graphs are isomorphic. Unlike Hash, the fingerprint doesn't change when
+// nodes are renamed.
+//
+// https://en.wikipedia.org/wiki/Weisfeiler_Leman_graph_isomorphism_test
+func (inst *Instance) Fingerprint() string {
+	rounds := inst.weisfeilerLehman(-1)
+
+	h := sha256.New()
+
+	var buf [8]byte
+	write := func(v uint64) {
+		binary.BigEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+
+	var edges int
+	eachUniqueEdge(inst.Nodes, func(_ *Node, edge *Edge) {
+		edges++
+	})
+
+	write(uint64(len(inst.Nodes)))
+	write(uint64(edges))
+
+	// The colors of the first round are made of the degrees of the nodes,
+	// so the degree sequence is part of the fingerprint.
+	for _, colors := range rounds {
+		sorted := append([]uint64(nil), colors...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		for _, color := range sorted {
+			write(color)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// weisfeilerLehman returns the colors of the graph's nodes, in the order of
+// its Nodes, initially made of their degrees by edge direction, and after
+// each round of the Weisfeiler–Lehman algorithm. Colors only depend on the
+// structure of the graph, so they can be compared across graphs. If rounds
+// is negative, rounds stop once the number of distinct colors stops growing.
+func (inst *Instance) weisfeilerLehman(rounds int) [][]uint64 {
+	index := inst.nodeIndex()
+
+	type neighbor struct {
+		direction EdgeDirection
+		node      int
+	}
+
+	neighbors := make([][]neighbor, len(inst.Nodes))
+	for i, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if j, ok := index[edge.Node]; ok {
+				neighbors[i] = append(neighbors[i], neighbor{edge.Direction, j})
+			}
+		}
+	}
+
+	colors := make([]uint64, len(inst.Nodes))
+	for i := range inst.Nodes {
+		// Decoded edges can have any direction, so directions other than
+		// Unknown to Both are counted after them, in order.
+		var (
+			degrees = make([]uint64, Both+1)
+			other   []EdgeDirection
+		)
+		for _, n := range neighbors[i] {
+			if n.direction >= Unknown && n.direction <= Both {
+				degrees[n.direction]++
+			} else {
+				other = append(other, n.direction)
+			}
+		}
+		sort.Slice(other, func(i, j int) bool { return other[i] < other[j] })
+		for _, d := range other {
+			degrees = append(degrees, uint64(d))
+		}
+		colors[i] = wlHash(degrees...)
+	}
+
+	result := [][]uint64{colors}
+	distinct := countDistinct(colors)
+
+	for round := 0; rounds < 0 || round < rounds; round++ {
+		next := make([]uint64, len(colors))
+
+		var signature []uint64
+		for i := range colors {
+			signature = signature[:0]
+			for _, n := range neighbors[i] {
+				signature = append(signature, wlHash(uint64(n.direction), colors[n.node]))
+			}
+			sort.Slice(signature, func(a, b int) bool { return signature[a] < signature[b] })
+			next[i] = wlHash(append([]uint64{colors[i]}, signature...)...)
+		}
+
+		if rounds < 0 {
+			d := countDistinct(next)
+			if d <= distinct {
+				break
+			}
+			distinct = d
+		}
+
+		result = append(result, next)
+		colors = next
+	}
+
+	return result
+}
+
+// wlHash returns the 64-bit FNV-1a hash of the given values.
+func wlHash(values ...uint64) uint64 {
+	h := fnv.New64a()
+
+	var buf [8]byte
+	for _, v := range values {
+		binary.BigEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+
+	return h.Sum64()
+}
+
+// countDistinct returns the number of distinct values.
+func countDistinct(values []uint64) int {
+	seen := make(map[uint64]bool, len(values))
+	for _, v := range values {
+		seen[v] = true
+	}
+	return len(seen)
+}
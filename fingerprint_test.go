@@ -0,0 +1,70 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Fingerprint(t *testing.T) {
+	build := func(names [3]string, direction graph.EdgeDirection) *graph.Instance {
+		var (
+			a = graph.NewNode(names[0], graph.Attributes{"x": 1})
+			b = graph.NewNode(names[1], nil)
+			c = graph.NewNode(names[2], nil)
+		)
+
+		a.AddEdgeWithDirection(b, graph.Out)
+		c.AddEdgeWithDirection(b, direction)
+
+		return graph.New("test", graph.WithNodes(graph.Nodes{c, b, a}))
+	}
+
+	abc := build([3]string{"a", "b", "c"}, graph.In)
+	xyz := build([3]string{"x", "y", "z"}, graph.In)
+
+	if abc.Fingerprint() != xyz.Fingerprint() {
+		t.Fatalf("expected renamed graphs to have the same fingerprint")
+	}
+
+	if abc.Hash() == xyz.Hash() {
+		t.Fatalf("expected renamed graphs to have different hashes")
+	}
+
+	for _, direction := range []graph.EdgeDirection{graph.Out, graph.None, graph.Both} {
+		if other := build([3]string{"a", "b", "c"}, direction); abc.Fingerprint() == other.Fingerprint() {
+			t.Fatalf("expected a graph with a %v edge to have a different fingerprint", direction)
+		}
+	}
+
+	// A path and a star have the same number of nodes and edges, but not
+	// the same degrees.
+	path := graph.New("path", graph.WithNodes(graph.NewNodes(graph.NewNode("a", nil), graph.NewNode("b", nil), graph.NewNode("c", nil), graph.NewNode("d", nil))))
+	path.Nodes[0].AddEdgeWithDirection(path.Nodes[1], graph.None)
+	path.Nodes[1].AddEdgeWithDirection(path.Nodes[2], graph.None)
+	path.Nodes[2].AddEdgeWithDirection(path.Nodes[3], graph.None)
+
+	star := graph.New("star", graph.WithNodes(graph.NewNodes(graph.NewNode("a", nil), graph.NewNode("b", nil), graph.NewNode("c", nil), graph.NewNode("d", nil))))
+	for _, leaf := range star.Nodes[1:] {
+		star.Nodes[0].AddEdgeWithDirection(leaf, graph.None)
+	}
+
+	if path.Fingerprint() == star.Fingerprint() {
+		t.Fatalf("expected a path and a star to have different fingerprints")
+	}
+
+	if empty := graph.New("empty"); empty.Fingerprint() == path.Fingerprint() || empty.Fingerprint() != graph.New("other").Fingerprint() {
+		t.Fatalf("unexpected fingerprint for empty graphs")
+	}
+
+	// Decoded edges can have directions other than the known ones.
+	nodes, err := graph.DecodeJSON(strings.NewReader(`{"nodes":[{"name":"a"},{"name":"b"}],"edges":[{"from_index":0,"to_index":1,"direction":7}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded := graph.New("decoded", graph.WithNodes(nodes)); decoded.Fingerprint() == abc.Fingerprint() {
+		t.Fatalf("expected a graph with an unknown direction to have a different fingerprint")
+	}
+}
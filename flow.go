@@ -0,0 +1,290 @@
+package graph
+
+import (
+	"math"
+	"sort"
+)
+
+// flowArc is an arc of a flowNetwork, with its remaining capacity, and the
+// index of its reverse arc in the adjacency list of the node it points to.
+type flowArc struct {
+	to       int
+	capacity float64
+	reverse  int
+
+	// edge is the edge of the graph the arc was created for, or nil for
+	// reverse arcs, and the arcs between the halves of split nodes.
+	edge *Edge
+}
+
+// flowNetwork is a residual network used to compute maximum flows.
+type flowNetwork struct {
+	arcs [][]flowArc
+}
+
+// newFlowNetwork returns a network of n nodes without any arcs.
+func newFlowNetwork(n int) *flowNetwork {
+	return &flowNetwork{arcs: make([][]flowArc, n)}
+}
+
+// add adds an arc from u to v with the given capacity, and its reverse arc,
+// without any capacity until flow is sent through the arc.
+func (f *flowNetwork) add(u, v int, capacity float64, edge *Edge) {
+	f.arcs[u] = append(f.arcs[u], flowArc{to: v, capacity: capacity, reverse: len(f.arcs[v]), edge: edge})
+	f.arcs[v] = append(f.arcs[v], flowArc{to: u, reverse: len(f.arcs[u]) - 1})
+}
+
+// flowEpsilon is the remaining capacity under which an arc is saturated, to
+// account for rounding errors of fractional capacities.
+const flowEpsilon = 1e-12
+
+// maxFlow returns the maximum flow from s to t, using the Edmonds–Karp
+// algorithm, leaving the network with the residual capacities.
+func (f *flowNetwork) maxFlow(s, t int) float64 {
+	var total float64
+
+	for {
+		type step struct{ node, arc int }
+
+		parents := make([]step, len(f.arcs))
+		for i := range parents {
+			parents[i] = step{-1, -1}
+		}
+		parents[s] = step{s, -1}
+
+		queue := []int{s}
+		for len(queue) > 0 && parents[t].node == -1 {
+			u := queue[0]
+			queue = queue[1:]
+
+			for i, arc := range f.arcs[u] {
+				if arc.capacity > flowEpsilon && parents[arc.to].node == -1 {
+					parents[arc.to] = step{u, i}
+					queue = append(queue, arc.to)
+				}
+			}
+		}
+
+		if parents[t].node == -1 {
+			return total
+		}
+
+		bottleneck := math.Inf(1)
+		for v := t; v != s; v = parents[v].node {
+			p := parents[v]
+			bottleneck = math.Min(bottleneck, f.arcs[p.node][p.arc].capacity)
+		}
+
+		for v := t; v != s; v = parents[v].node {
+			p := parents[v]
+			arc := &f.arcs[p.node][p.arc]
+			arc.capacity -= bottleneck
+			f.arcs[v][arc.reverse].capacity += bottleneck
+		}
+
+		total += bottleneck
+	}
+}
+
+// reachable returns the nodes reachable from s through arcs that aren't
+// saturated, which is the source side of a minimum cut after maxFlow.
+func (f *flowNetwork) reachable(s int) []bool {
+	seen := make([]bool, len(f.arcs))
+	seen[s] = true
+
+	queue := []int{s}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for _, arc := range f.arcs[u] {
+			if arc.capacity > flowEpsilon && !seen[arc.to] {
+				seen[arc.to] = true
+				queue = append(queue, arc.to)
+			}
+		}
+	}
+
+	return seen
+}
+
+// edgeFlowNetwork returns the flow network of the graph, where each edge
+// has its weight as capacity, and the index of each node in the network.
+// Undirected (None) and bi-directional (Both) edges have their capacity in
+// both directions.
+func (inst *Instance) edgeFlowNetwork() (*flowNetwork, map[*Node]int) {
+	index := inst.nodeIndex()
+	network := newFlowNetwork(len(inst.Nodes))
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		to, ok := index[edge.Node]
+		u := index[from]
+		if !ok || u == to {
+			return
+		}
+		w := edge.Weight()
+
+		switch edge.Direction {
+		case Out:
+			network.add(u, to, w, edge)
+		case In:
+			network.add(to, u, w, edge)
+		default:
+			network.add(u, to, w, edge)
+			network.add(to, u, w, edge)
+		}
+	})
+
+	return network, index
+}
+
+// MaxFlow returns the value of the maximum flow from the source node to the
+// sink node, where the capacity of each edge is its weight, see Edge.Weight,
+// using the Edmonds–Karp algorithm. Paths follow edges that aren't inward
+// edges, like Visit, and undirected (None) and bi-directional (Both) edges
+// have their capacity in both directions.
+//
+//	    ↗ b ↘           MaxFlow(a, d): 2, with weights of 1
+//	a         d
+//	    ↘ c ↗
+//
+// Capacities must not be negative. 0 is returned if either node isn't in
+// the graph, or they are the same node.
+//
+// https://en.wikipedia.org/wiki/Maximum_flow_problem
+func (inst *Instance) MaxFlow(source, sink *Node) float64 {
+	_, flow := inst.MinCut(source, sink)
+	return flow
+}
+
+// MinCut returns a minimum cut between the source node and the sink node,
+// the lightest set of edges that, once removed, leave no path from the
+// source to the sink, along with its total weight, which is the value of
+// the MaxFlow between them. This is the cheapest set of links to sever to
+// disconnect two regions of a network.
+//
+//	a → b → d    MinCut(a, d): [a → b, a → c], 2
+//	 ↘     ↗
+//	   c
+//
+// The cut closest to the source is returned, with the edges in the order
+// of the graph, like Edges, using the outward edge of each relationship
+// that has one. If either node isn't in the graph, or they are the same
+// node, nil and 0 are returned.
+//
+// https://en.wikipedia.org/wiki/Max-flow_min-cut_theorem
+func (inst *Instance) MinCut(source, sink *Node) (Edges, float64) {
+	network, index := inst.edgeFlowNetwork()
+
+	s, ok := index[source]
+	if !ok {
+		return nil, 0
+	}
+	t, ok := index[sink]
+	if !ok || s == t {
+		return nil, 0
+	}
+
+	flow := network.maxFlow(s, t)
+	side := network.reachable(s)
+
+	var cut Edges
+	for u, arcs := range network.arcs {
+		for _, arc := range arcs {
+			if arc.edge != nil && side[u] && !side[arc.to] {
+				cut = append(cut, arc.edge)
+			}
+		}
+	}
+
+	// Arcs are grouped by node, so they're put back in the order of the
+	// graph's edges.
+	order := map[*Edge]int{}
+	eachUniqueEdge(inst.Nodes, func(_ *Node, edge *Edge) {
+		order[edge] = len(order)
+	})
+	sort.SliceStable(cut, func(i, j int) bool {
+		return order[cut[i]] < order[cut[j]]
+	})
+
+	return cut, flow
+}
+
+// MinVertexCut returns a minimum vertex cut between the source node and
+// the sink node, the smallest set of other nodes that, once removed, leave
+// no path from the source to the sink, such as the fewest routers whose
+// failure would disconnect two hosts. Paths follow edges like MaxFlow, but
+// edge weights are ignored.
+//
+//	a → b → d    MinVertexCut(a, d): [b, c]
+//	 ↘     ↗
+//	   c
+//
+// Nodes are returned in the order of the graph. If either node isn't in the
+// graph, they are the same node, or the source has an edge to the sink, no
+// vertex cut exists, and nil is returned.
+//
+// https://en.wikipedia.org/wiki/Vertex_separator
+func (inst *Instance) MinVertexCut(source, sink *Node) Nodes {
+	index := inst.nodeIndex()
+
+	s, ok := index[source]
+	if !ok {
+		return nil
+	}
+	t, ok := index[sink]
+	if !ok || s == t {
+		return nil
+	}
+
+	for _, next := range source.Edges.successors() {
+		if next == sink {
+			return nil
+		}
+	}
+
+	// Each node is split into an inner half, i, and an outer half, i+n,
+	// joined by an arc with a capacity of 1, so cutting it removes the
+	// node. Edges go from outer halves to inner halves, and can't be cut.
+	n := len(inst.Nodes)
+	network := newFlowNetwork(2 * n)
+
+	for i := range inst.Nodes {
+		capacity := 1.0
+		if i == s || i == t {
+			capacity = math.Inf(1)
+		}
+		network.add(i, i+n, capacity, nil)
+	}
+
+	inf := math.Inf(1)
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		to, ok := index[edge.Node]
+		u := index[from]
+		if !ok || u == to {
+			return
+		}
+
+		switch edge.Direction {
+		case Out:
+			network.add(u+n, to, inf, nil)
+		case In:
+			network.add(to+n, u, inf, nil)
+		default:
+			network.add(u+n, to, inf, nil)
+			network.add(to+n, u, inf, nil)
+		}
+	})
+
+	network.maxFlow(s+n, t)
+	side := network.reachable(s + n)
+
+	var cut Nodes
+	for i, node := range inst.Nodes {
+		if side[i] && !side[i+n] {
+			cut = append(cut, node)
+		}
+	}
+
+	return cut
+}
@@ -0,0 +1,94 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_MinCut(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	//     3      1
+	//  a ──→ b ──→ d ── e
+	//  │     ↓1    ↑
+	//  └──2→ c ─2──┘
+
+	a.AddWeightedEdge(b, 3)
+	a.AddWeightedEdge(c, 2)
+	b.AddWeightedEdge(d, 1)
+	b.AddWeightedEdge(c, 1)
+	c.AddWeightedEdge(d, 2)
+	d.AddEdgeWithDirection(e, graph.None)
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b, c, d, e}))
+
+	cut, weight := g.MinCut(a, d)
+	if cut.String() != "b → d, c → d" || weight != 3 {
+		t.Fatalf("unexpected cut: %v (%v)", cut, weight)
+	}
+
+	if flow := g.MaxFlow(a, e); flow != 1 {
+		t.Fatalf("expected a flow of 1 through the undirected edge, got %v", flow)
+	}
+
+	cut, weight = g.MinCut(a, e)
+	if cut.String() != "d - e" || weight != 1 {
+		t.Fatalf("unexpected cut: %v (%v)", cut, weight)
+	}
+
+	// The undirected edge can be followed either way.
+	if flow := g.MaxFlow(e, d); flow != 1 {
+		t.Fatalf("expected a flow of 1, got %v", flow)
+	}
+
+	cut, weight = g.MinCut(d, a)
+	if cut != nil || weight != 0 {
+		t.Fatalf("did not expect a cut against the direction of edges: %v (%v)", cut, weight)
+	}
+
+	cut, weight = g.MinCut(a, graph.NewNode("outside", nil))
+	if cut != nil || weight != 0 {
+		t.Fatalf("did not expect a cut to a node outside of the graph: %v (%v)", cut, weight)
+	}
+}
+
+func TestInstance_MinVertexCut(t *testing.T) {
+	g := graph.New("test")
+
+	// a → b → d → f
+	//  ↘     ↗   ↗
+	//    c → e
+
+	err := g.LoadEdges([][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"c", "e"}, {"d", "f"}, {"e", "f"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := g.Lookup("a")
+	b, _ := g.Lookup("b")
+	d, _ := g.Lookup("d")
+	f, _ := g.Lookup("f")
+
+	if cut := g.MinVertexCut(a, f); cut.String() != "b, c" {
+		t.Fatalf("unexpected vertex cut: %v", cut)
+	}
+
+	if cut := g.MinVertexCut(a, d); cut.String() != "b, c" {
+		t.Fatalf("unexpected vertex cut: %v", cut)
+	}
+
+	if cut := g.MinVertexCut(a, b); cut != nil {
+		t.Fatalf("did not expect a vertex cut between adjacent nodes: %v", cut)
+	}
+
+	if cut := g.MinVertexCut(f, a); len(cut) != 0 {
+		t.Fatalf("did not expect a vertex cut without paths: %v", cut)
+	}
+}
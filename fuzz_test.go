@@ -0,0 +1,136 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func FuzzDecodeJSON(f *testing.F) {
+	f.Add(`{"nodes":[{"name":"a"},{"name":"b"}],"edges":[{"from_index":0,"to_index":1,"direction":2}]}`)
+	f.Add(`{"nodes":[{"name":"a"}],"edges":[{"from_index":0,"to_index":0,"direction":0,"name":"self"}]}`)
+	f.Add(`{"nodes":[{"name":"a"},{"name":"b"}],"edges":[{"from_index":0,"to_index":1,"direction":2},{"from_index":1,"to_index":0,"direction":1}]}`)
+	f.Add(`{"nodes":[{"name":"a"},{"name":"b"}],"edges":[{"from_index":0,"to_index":1,"direction":7}]}`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		nodes, err := graph.DecodeJSON(strings.NewReader(input))
+		if err != nil {
+			return
+		}
+
+		g := graph.New("fuzz", graph.WithNodes(nodes))
+
+		if err := graph.CheckInvariants(g); err != nil {
+			t.Fatal(err)
+		}
+
+		g.Fingerprint()
+
+		buf := bytes.NewBuffer(nil)
+
+		if err := graph.EncodeJSON(buf, g.Nodes); err != nil {
+			return
+		}
+
+		decoded, err := graph.DecodeJSON(buf)
+		if err != nil {
+			t.Fatalf("failed to decode encoded graph: %v", err)
+		}
+
+		if err := graph.CheckInvariants(graph.New("fuzz", graph.WithNodes(decoded))); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzDecodeYAML(f *testing.F) {
+	f.Add("nodes: [a, b]\nedges:\n  - a -> b\n  - b -- c\n")
+	f.Add("edges:\n  - a <-> a\n  - edge: b <- a\n    name: link\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		g, err := graph.DecodeYAML(strings.NewReader(input))
+		if err != nil {
+			return
+		}
+
+		if err := graph.CheckInvariants(g); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzDecodeDOT(f *testing.F) {
+	f.Add("digraph { a -> { b c } [color=red]; c -> a [dir=none] }")
+	f.Add("graph { subgraph cluster_x { a -- b } { rank=same; a; c } }")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		nodes, err := graph.DecodeDOT(strings.NewReader(input))
+		if err != nil {
+			return
+		}
+
+		g := graph.New("fuzz", graph.WithNodes(nodes))
+
+		if err := graph.CheckInvariants(g); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// FuzzOperations applies a sequence of operations, read from the input,
+// to a small graph and checks that its invariants hold after each one.
+func FuzzOperations(f *testing.F) {
+	f.Add([]byte{0, 0, 1, 1, 1, 2, 2, 3, 0, 4, 2, 1})
+	f.Add([]byte{5, 0, 1, 0, 0, 0, 6, 1, 0, 3, 1, 2})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		g := graph.New("fuzz")
+
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			g.AddNode(graph.NewNode(name, nil))
+		}
+
+		node := func(b byte) *graph.Node {
+			if len(g.Nodes) == 0 {
+				return nil
+			}
+			return g.Nodes[int(b)%len(g.Nodes)]
+		}
+
+		for len(ops) >= 3 {
+			op, x, y := ops[0], node(ops[1]), node(ops[2])
+			ops = ops[3:]
+
+			if x == nil {
+				break
+			}
+
+			switch op % 7 {
+			case 0:
+				x.AddEdgeWithDirection(y, graph.EdgeDirection(int(op/7)%5))
+			case 1:
+				x.AddLink(y)
+			case 2:
+				if len(x.Edges) > 0 {
+					x.RemoveEdge(x.Edges[int(op/7)%len(x.Edges)])
+				}
+			case 3:
+				g.RemoveNode(x)
+			case 4:
+				_ = g.MergeNodes(x, y, nil)
+			case 5:
+				if len(x.Edges) > 0 {
+					_ = g.ContractEdge(x.Edges[int(op/7)%len(x.Edges)])
+				}
+			case 6:
+				g.Reverse()
+			}
+
+			if err := graph.CheckInvariants(g); err != nil {
+				t.Fatalf("after operation %d: %v", op%7, err)
+			}
+		}
+	})
+}
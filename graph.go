@@ -1,5 +1,7 @@
 package graph
 
+import "sync"
+
 // Instance describes a graph of zero or more nodes.
 type Instance struct {
 	// Name is the name of the graph instance.
@@ -10,6 +12,70 @@ type Instance struct {
 
 	// Nodes is a slice of nodes that belong to the graph instance.
 	Nodes
+
+	// NodeDefaults are attributes that nodes fall back to when
+	// they don't have an attribute with the same name.
+	NodeDefaults Attributes
+
+	// EdgeDefaults are attributes that edges fall back to when
+	// they don't have an attribute with the same name.
+	EdgeDefaults Attributes
+
+	// indexes are the inverted attribute indexes created with CreateIndex,
+	// mapping attribute names to values to the nodes with that value.
+	indexes map[string]map[any]Nodes
+
+	// versions are the snapshots recorded with Commit, and head is the
+	// version that was last committed or checked out.
+	versions []*snapshot
+	head     VersionID
+
+	// subs are the named subgraphs created with AddSub, in the order
+	// they were created.
+	subs []*Sub
+
+	// hyperEdges are the hyperedges added with AddHyperEdge.
+	hyperEdges []*HyperEdge
+
+	// names indexes the nodes by name, for graphs created using
+	// WithUniqueNames, and is nil otherwise.
+	names map[string]*Node
+
+	// edgePool is preallocated storage for edges, grown using Grow.
+	edgePool []Edge
+
+	// byID holds the nodes of the graph by the ID they were assigned when
+	// they were added, with nil for removed nodes, whose IDs are in freeIDs
+	// until they are reused.
+	byID    []*Node
+	freeIDs []int
+
+	// tracer is notified of invocations of the graph's algorithms, set
+	// using WithTracer.
+	tracer Tracer
+
+	// resolver resolves placeholder nodes, set using WithResolver, and
+	// resolveErr is the first error of resolving them in traversals.
+	resolver   Resolver
+	resolveErr error
+
+	// constraints are the rules the graph must follow, see AddConstraint.
+	constraints []Constraint
+
+	// subscribers receive the mutations of the graph, see Subscribe.
+	subscribers subscribers
+
+	// published is the latest version of the graph published for readers,
+	// see Read and Write.
+	published published
+
+	// generation is incremented by changes to the graph, and cache holds
+	// the results of queries for the generation and number of nodes of the
+	// graph they were computed for, cacheShape.
+	generation uint64
+	cache      map[string]any
+	cacheShape cacheShape
+	cacheMu    sync.Mutex
 }
 
 // WithAttributes is a functional option that sets the attributes of the graph.
@@ -29,25 +95,76 @@ func WithNodes(nodes Nodes) func(*Instance) {
 // New returns a new instance of a graph.
 func New(name string, opts ...func(*Instance)) *Instance {
 	inst := &Instance{
-		Name:       name,
-		Nodes:      Nodes{},
-		Attributes: Attributes{},
+		Name:         name,
+		Nodes:        Nodes{},
+		Attributes:   Attributes{},
+		NodeDefaults: Attributes{},
+		EdgeDefaults: Attributes{},
 	}
 
 	for _, opt := range opts {
 		opt(inst)
 	}
 
+	if inst.names != nil {
+		nodes := inst.Nodes
+		inst.Nodes = make(Nodes, 0, len(nodes))
+		inst.AddNodes(nodes...)
+		return inst
+	}
+
+	for _, node := range inst.Nodes {
+		inst.adopt(node)
+	}
+
 	return inst
 }
 
+// adopt records that the node belongs to the graph, so changes made
+// through the node's setters can be reflected in the graph's indexes.
+func (inst *Instance) adopt(node *Node) {
+	inst.touchNode(node)
+	inst.published.reindex = true
+	inst.assignID(node)
+	node.graph = inst
+	inst.indexNode(node)
+	inst.publish(Mutation{Kind: NodeAdded, Node: node})
+}
+
+// SetDefaultNodeAttr sets a default node attribute for the graph, used
+// by nodes that don't have an attribute with the same name.
+func (inst *Instance) SetDefaultNodeAttr(name string, value any) {
+	if inst.NodeDefaults == nil {
+		inst.NodeDefaults = Attributes{}
+	}
+	inst.NodeDefaults[name] = value
+}
+
+// SetDefaultEdgeAttr sets a default edge attribute for the graph, used
+// by edges that don't have an attribute with the same name.
+func (inst *Instance) SetDefaultEdgeAttr(name string, value any) {
+	if inst.EdgeDefaults == nil {
+		inst.EdgeDefaults = Attributes{}
+	}
+	inst.EdgeDefaults[name] = value
+}
+
 // AddNode adds a node to the graph.
+//
+// If the graph was created using WithUniqueNames, a node with the same name
+// as a node already in the graph is merged into that node instead.
 func (inst *Instance) AddNode(node *Node) {
 	if node == nil {
 		return
 	}
 
+	if inst.names != nil {
+		inst.addNamed(node)
+		return
+	}
+
 	inst.Nodes = append(inst.Nodes, node)
+	inst.adopt(node)
 }
 
 // AddNodes adds a slice of nodes to the graph.
@@ -56,7 +173,82 @@ func (inst *Instance) AddNodes(nodes ...*Node) {
 		return
 	}
 
+	if inst.names != nil {
+		for _, node := range nodes {
+			if node != nil {
+				inst.addNamed(node)
+			}
+		}
+		return
+	}
+
 	inst.Nodes = append(inst.Nodes, nodes...)
+	for _, node := range nodes {
+		if node != nil {
+			inst.adopt(node)
+		}
+	}
+}
+
+// RemoveNode removes a node from the graph, along with all of the
+// edges other nodes have with it.
+func (inst *Instance) RemoveNode(node *Node) {
+	if node == nil {
+		return
+	}
+
+	for _, edge := range node.Edges {
+		if edge.Node != node {
+			edge.Node.Edges = edge.Node.Edges.ButNotWith(node)
+			edge.Node.touch()
+		}
+	}
+	node.Edges = nil
+
+	inst.detach(node)
+}
+
+// detach removes the node from the graph, leaving its edges unchanged.
+func (inst *Instance) detach(node *Node) {
+	inst.detachAll(NewNodeSet(node))
+}
+
+// detachAll removes the given nodes from the graph in a single pass over
+// its nodes, leaving their edges unchanged, and reports their removal to
+// the graph's subscribers.
+func (inst *Instance) detachAll(remove NodeSet) {
+	inst.generation++
+	inst.published.reindex = true
+
+	nodes := inst.Nodes[:0]
+	for _, n := range inst.Nodes {
+		if !remove.Contains(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	inst.Nodes = nodes
+
+	for _, node := range remove.Sorted() {
+		inst.unindexNode(node)
+		for _, sub := range inst.subs {
+			sub.RemoveNode(node)
+		}
+		member := inst.ID(node) >= 0
+		inst.releaseID(node)
+		if node.graph == inst {
+			node.graph = nil
+		}
+		if member {
+			inst.publish(Mutation{Kind: NodeRemoved, Node: node})
+		}
+	}
+
+	if len(inst.hyperEdges) > 0 {
+		removed := remove.Nodes()
+		for _, h := range inst.hyperEdges {
+			h.Nodes = h.Nodes.Difference(removed)
+		}
+	}
 }
 
 // AddEdge adds an edge to the graph from the source node to the target node.
@@ -68,6 +260,16 @@ func (inst *Instance) AddEdge(from, to *Node) {
 	from.AddEdge(to)
 }
 
+// AddEdgeTyped adds an edge of the given relationship type to the graph
+// from the source node to the target node.
+func (inst *Instance) AddEdgeTyped(from, to *Node, typ string) {
+	if from == nil || to == nil {
+		return
+	}
+
+	from.AddEdgeTyped(to, typ)
+}
+
 // AddEdges adds a slice of edges to the graph.
 func (inst *Instance) AddEdges(em EdgeMap) {
 	for from, to := range em {
@@ -94,14 +296,20 @@ func (inst *Instance) Visit(fn func(*Node)) {
 
 // DFS performs a depth-first-search of the graph.
 //
+// Options like WithSortedNeighbors can be given to visit the children of
+// each node in a deterministic order, instead of the reverse order of its
+// edges.
+//
 // https://en.wikipedia.org/wiki/Depth-first_search
-func (inst *Instance) DFS(fn func(*Node)) {
+func (inst *Instance) DFS(fn func(*Node), opts ...func(*VisitOrder)) {
 	if fn == nil {
 		return
 	}
 
-	// Create a map of nodes that have been visited.
-	visited := NodeSet{}
+	order := newVisitOrder(opts...)
+
+	// Create a set of nodes that have been visited.
+	visited := order.tracker(inst)
 
 	// Iterate over all the nodes in the graph.
 	for _, node := range inst.Nodes {
@@ -129,28 +337,42 @@ func (inst *Instance) DFS(fn func(*Node)) {
 				continue
 			}
 
-			// Visit the node.
+			// Visit the node, resolving it first if it's a placeholder.
+			node.resolvePlaceholder()
 			fn(node)
 
 			// Mark the node as visited.
 			visited.Add(node)
 
-			// Add the node's children to the stack.
-			stack = append(stack, node.Out().Nodes()...)
+			// Add the node's children to the stack. Ordered children
+			// are pushed in reverse, so they're popped in order.
+			children := order.filter(node.Out()).Nodes()
+			if order.Less != nil {
+				children = order.nodes(children)
+				for i, j := 0, len(children)-1; i < j; i, j = i+1, j-1 {
+					children[i], children[j] = children[j], children[i]
+				}
+			}
+			stack = append(stack, children...)
 		}
 	}
 }
 
 // BFS performs a breadth-first-search of the graph.
 //
+// Options like WithSortedNeighbors can be given to visit the children of
+// each node in a deterministic order, instead of the order of its edges.
+//
 // https://en.wikipedia.org/wiki/Breadth-first_search
-func (inst *Instance) BFS(fn func(*Node)) {
+func (inst *Instance) BFS(fn func(*Node), opts ...func(*VisitOrder)) {
 	if fn == nil {
 		return
 	}
 
-	// Create a map of nodes that have been visited.
-	visited := NodeSet{}
+	order := newVisitOrder(opts...)
+
+	// Create a set of nodes that have been visited.
+	visited := order.tracker(inst)
 
 	// Iterate over all the nodes in the graph.
 	for _, node := range inst.Nodes {
@@ -178,14 +400,15 @@ func (inst *Instance) BFS(fn func(*Node)) {
 				continue
 			}
 
-			// Visit the node.
+			// Visit the node, resolving it first if it's a placeholder.
+			node.resolvePlaceholder()
 			fn(node)
 
 			// Mark the node as visited.
 			visited.Add(node)
 
 			// Add the node's children to the queue.
-			queue = append(queue, node.Out().Nodes()...)
+			queue = append(queue, order.nodes(order.filter(node.Out()).Nodes())...)
 		}
 	}
 }
@@ -237,10 +237,14 @@ func TestFindBridges(t *testing.T) {
 		// "tree",
 	}
 
+	// Bridges are the bridges of the graph of the nodes connected to Root,
+	// found by Instance.Bridges, and Paths those found by FindBridges, if
+	// they are different.
 	tests := []struct {
 		Name    string
 		Root    *graph.Node
 		Bridges map[string]bool
+		Paths   map[string]bool
 	}{
 		{
 			Name: "simple dangling edge",
@@ -370,8 +374,13 @@ func TestFindBridges(t *testing.T) {
 			}(),
 		},
 		{
-			Name:    "TIE fighter (barbell) bi-directional",
+			Name: "TIE fighter (barbell) bi-directional",
 			Bridges: map[string]bool{
+				// Instance.Bridges treats the link as a single
+				// undirected relationship, and reports c → d.
+				"c → d": true,
+			},
+			Paths: map[string]bool{
 				// c ↔ d is a bi-directional relationship
 				//
 				// It's really two edges. If one of those edges
@@ -380,7 +389,6 @@ func TestFindBridges(t *testing.T) {
 				//
 				// "c → d": false,
 				// "d → c": false,
-				//
 			},
 			Root: func() *graph.Node {
 				a := &graph.Node{Name: "a"}
@@ -459,27 +467,89 @@ func TestFindBridges(t *testing.T) {
 			}
 		}
 		t.Run(test.Name, func(t *testing.T) {
-			bridges := graph.FindBridges(test.Root)
+			paths := test.Paths
+			if paths == nil {
+				paths = test.Bridges
+			}
 
-			for _, bridge := range bridges {
-				_, ok := test.Bridges[bridge.String()]
-				if !ok {
-					t.Logf("unexpected bridge found: %v", bridge)
-					t.Fail()
-				}
+			var found []string
+			for _, bridge := range graph.FindBridges(test.Root) {
+				found = append(found, bridge.String())
 			}
+			checkBridges(t, "FindBridges", found, paths)
 
-			if len(bridges) != len(test.Bridges) {
-				t.Logf("unexpected number of bridges found: expected: %d, got: %d", len(test.Bridges), len(bridges))
-				for _, bridge := range bridges {
-					t.Logf("\t%s", bridge)
-				}
-				t.Fail()
+			var nodes graph.Nodes
+			test.Root.VisitAll(func(n *graph.Node) {
+				nodes = append(nodes, n)
+			})
+
+			found = nil
+			for _, bridge := range graph.New(test.Name, graph.WithNodes(nodes)).Bridges() {
+				found = append(found, bridge.String())
 			}
+			checkBridges(t, "Bridges", found, test.Bridges)
 		})
 	}
 }
 
+// checkBridges checks that the bridges found by the given function are the
+// expected ones.
+func checkBridges(t *testing.T, fn string, found []string, expected map[string]bool) {
+	t.Helper()
+
+	for _, bridge := range found {
+		if !expected[bridge] {
+			t.Errorf("%s found unexpected bridge: %v", fn, bridge)
+		}
+	}
+
+	if len(found) != len(expected) {
+		t.Errorf("%s found an unexpected number of bridges: expected: %d, got: %d: %v", fn, len(expected), len(found), found)
+	}
+}
+
+func TestInstance_Bridges(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+		f = graph.NewNode("f", nil)
+		x = graph.NewNode("x", nil)
+		y = graph.NewNode("y", nil)
+	)
+
+	// a           e
+	// ↑ ⤡       ⤢ ↑
+	// |   c ↔ d   |    x → y
+	// ↓ ⤢       ⤡ ↓
+	// b           f
+
+	a.AddLink(b)
+	c.AddLink(a)
+	c.AddLink(b)
+	c.AddLink(d)
+	d.AddLink(e)
+	d.AddLink(f)
+	f.AddLink(e)
+	x.AddEdge(y)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e, f, x, y)))
+
+	bridges := g.Bridges()
+
+	if bridges.String() != "c → d, x → y" {
+		t.Fatalf("unexpected bridges: %v", bridges)
+	}
+
+	for _, bridge := range bridges {
+		if bridge.From() == nil || !bridge.From().Edges.Contains(bridge.Node) {
+			t.Fatalf("expected bridge %v to be an edge of the graph", bridge)
+		}
+	}
+}
+
 func TestFindAdjacentTo(t *testing.T) {
 	tests := []struct {
 		Name       string
@@ -710,6 +780,41 @@ func TestAttributes(t *testing.T) {
 	}
 }
 
+func TestAttr(t *testing.T) {
+	g := graph.New("test")
+	g.SetDefaultNodeAttr("color", "black")
+	g.SetDefaultEdgeAttr("style", "dashed")
+
+	a := graph.NewNode("a", graph.Attributes{"color": "red"})
+	b := graph.NewNode("b", nil)
+	g.AddNode(a)
+	g.AddNode(b)
+	a.AddEdge(b)
+
+	if v, ok := a.Attr("color"); !ok || v != "red" {
+		t.Fatalf("expected a's own color red, got %v", v)
+	}
+	if v, ok := b.Attr("color"); !ok || v != "black" {
+		t.Fatalf("expected b to fall back to the default color black, got %v", v)
+	}
+	if v, ok := b.Attr("shape"); ok {
+		t.Fatalf("expected no shape, got %v", v)
+	}
+
+	edge := a.Edges[0]
+	if v, ok := edge.Attr("style"); !ok || v != "dashed" {
+		t.Fatalf("expected the edge to fall back to the default style dashed, got %v", v)
+	}
+	edge.Attributes = graph.Attributes{"style": "bold"}
+	if v, ok := edge.Attr("style"); !ok || v != "bold" {
+		t.Fatalf("expected the edge's own style bold, got %v", v)
+	}
+
+	if v, ok := graph.NewNode("c", nil).Attr("color"); ok {
+		t.Fatalf("expected nodes without a graph to have no defaults, got %v", v)
+	}
+}
+
 func TestIsBipartite_false(t *testing.T) {
 	var (
 		a = graph.NewNode("a", nil)
@@ -946,3 +1051,209 @@ func TestInstance_BFS(t *testing.T) {
 		t.Errorf("visited nodes = %v, expected %v", visited, expected)
 	}
 }
+
+func TestInstance_FindCliques(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		x = graph.NewNode("x", nil)
+		y = graph.NewNode("y", nil)
+		z = graph.NewNode("z", nil)
+		w = graph.NewNode("w", nil)
+	)
+
+	// a → b    x → y
+	// ↓ ↙      ↓ ↙
+	// c        z → w
+
+	a.AddEdge(b)
+	a.AddEdge(c)
+	b.AddEdge(c)
+	x.AddEdge(y)
+	x.AddEdge(z)
+	y.AddEdge(z)
+	z.AddEdge(w)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, x, y, z, w)))
+
+	cliques := g.FindCliques(3)
+
+	if len(cliques) != 2 || !cliques.ContainsNode(a) || !cliques.ContainsNode(x) {
+		t.Fatalf("expected a clique in each component, got: %v", cliques)
+	}
+
+	if len(graph.FindCliques(a, 3)) != 1 {
+		t.Fatalf("expected the root based search to only find one clique")
+	}
+
+	bridges := graph.Paths(g.FindBridges())
+
+	for _, root := range []*graph.Node{a, x} {
+		for _, bridge := range graph.FindBridges(root) {
+			if !bridges.ContainsPath(bridge) {
+				t.Fatalf("expected bridge %v from root %v to be found", bridge, root.Name)
+			}
+		}
+	}
+
+	if !bridges.ContainsNode(w) {
+		t.Fatalf("expected bridges to cover the second component: %v", bridges)
+	}
+}
+
+func TestNode_PathToAvoiding(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → d
+	// ↓       ↑
+	// c → e ──┘
+
+	a.AddEdge(b)
+	a.AddEdge(c)
+	b.AddEdge(d)
+	c.AddEdge(e)
+	e.AddEdge(d)
+
+	tests := []struct {
+		Name         string
+		BlockedNodes graph.NodeSet
+		BlockedEdges graph.Edges
+		Expected     string
+	}{
+		{"unblocked", nil, nil, "a → b → d"},
+		{"blocked node", graph.NewNodeSet(b), nil, "a → c → e → d"},
+		{"blocked edge", nil, graph.Edges{b.Edges[1]}, "a → c → e → d"},
+		{"blocked reciprocal edge", nil, graph.Edges{d.Edges[0]}, "a → c → e → d"},
+		{"blocked everything", graph.NewNodeSet(b, e), nil, ""},
+		{"blocked end", graph.NewNodeSet(d), nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			path := a.PathToAvoiding(d, test.BlockedNodes, test.BlockedEdges)
+			if path.String() != test.Expected {
+				t.Fatalf("expected %q, got %q", test.Expected, path)
+			}
+		})
+	}
+
+	if !a.PathToWithout(d, b) {
+		t.Fatal("expected a path from a to d without b")
+	}
+
+	if a.PathToWithout(d, d) {
+		t.Fatal("did not expect a path from a to d without d")
+	}
+}
+
+func TestNodes_SetOperations(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	a.AddEdge(b)
+
+	x := graph.NewNodes(a, b, c, a)
+	y := graph.NewNodes(d, c, b)
+
+	tests := []struct {
+		Name     string
+		Nodes    graph.Nodes
+		Expected string
+	}{
+		{"union", x.Union(y), "a, b, c, d"},
+		{"intersect", x.Intersect(y), "b, c"},
+		{"difference", x.Difference(y), "a"},
+		{"filter", x.Filter(func(n *graph.Node) bool { return len(n.Edges) > 0 }), "a, b, a"},
+		{"map", y.Map(func(n *graph.Node) *graph.Node { return graph.NewNode(n.Name+"'", nil) }), "d', c', b'"},
+		{"empty", graph.Nodes{}.Union(nil), ""},
+	}
+
+	for _, test := range tests {
+		if test.Nodes.String() != test.Expected {
+			t.Errorf("%s: expected %q, got %q", test.Name, test.Expected, test.Nodes)
+		}
+	}
+
+	if !x.Contains(c) || x.Contains(d) {
+		t.Fatal("unexpected contains result")
+	}
+}
+
+func TestNodeSet_SetOperations(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	x := graph.NewNodeSet(a, b, c)
+	y := graph.NewNodeSet(b, c, d)
+
+	tests := []struct {
+		Name     string
+		Set      graph.NodeSet
+		Expected string
+	}{
+		{"union", x.Union(y), "a, b, c, d"},
+		{"intersect", x.Intersect(y), "b, c"},
+		{"intersect empty", x.Intersect(nil), ""},
+		{"difference", x.Difference(y), "a"},
+		{"difference empty", x.Difference(nil), "a, b, c"},
+		{"filter", x.Filter(func(n *graph.Node) bool { return n != b }), "a, c"},
+	}
+
+	for _, test := range tests {
+		if test.Set.String() != test.Expected {
+			t.Errorf("%s: expected %q, got %q", test.Name, test.Expected, test.Set)
+		}
+	}
+
+	if x.Len() != 3 || x.String() != "a, b, c" {
+		t.Fatalf("expected set operations to leave the set unchanged, got %v", x)
+	}
+
+	var visited graph.Nodes
+	y.Union(x).Each(func(n *graph.Node) bool {
+		visited = append(visited, n)
+		return n != c
+	})
+
+	if visited.String() != "a, b, c" {
+		t.Fatalf("unexpected iteration order: %v", visited)
+	}
+}
+
+func TestEdge_String(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	a.AddEdge(b)
+	a.AddEdgeWithDirection(b, graph.None)
+	a.AddEdgeWithDirection(b, graph.Both)
+
+	if a.Edges.String() != "a → b, a - b, a ↔ b" {
+		t.Fatalf("unexpected edges: %v", a.Edges)
+	}
+
+	if b.Edges.String() != "b ← a, b - a, b ↔ a" {
+		t.Fatalf("unexpected edges: %v", b.Edges)
+	}
+
+	if edge := (&graph.Edge{Node: b, Direction: graph.Out}); edge.String() != "<nil> → b" {
+		t.Fatalf("unexpected edge without a node it belongs to: %v", edge)
+	}
+}
@@ -0,0 +1,96 @@
+package graph
+
+import "errors"
+
+// ErrBudgetExhausted is returned by bounded searches that ran out of
+// budget before finding a result, or proving that none exists.
+var ErrBudgetExhausted = errors.New("graph search budget exhausted")
+
+// HamiltonianPath searches for a path that visits every node in the graph
+// exactly once using backtracking, following every edge that isn't an
+// inward edge.
+//
+// Since the problem is NP-complete, the search is bounded by the given
+// limit on the number of search steps (nodes added to a partial path).
+// A limit less than 1 means the search is unbounded.
+//
+// If a path is found, it is returned. If the search completes without
+// finding one, a nil path and error are returned. If the limit is reached
+// first, ErrBudgetExhausted is returned.
+//
+// https://en.wikipedia.org/wiki/Hamiltonian_path
+func (inst *Instance) HamiltonianPath(limit int) (Path, error) {
+	defer inst.trace("HamiltonianPath")()
+
+	n := len(inst.Nodes)
+	if n == 0 {
+		return nil, nil
+	}
+
+	ids := make(map[*Node]int, n)
+	for i, node := range inst.Nodes {
+		ids[node] = i
+	}
+
+	succ := make([][]int, n)
+	for i, node := range inst.Nodes {
+		for _, next := range node.Edges.successors() {
+			if j, ok := ids[next]; ok && j != i {
+				succ[i] = append(succ[i], j)
+			}
+		}
+	}
+
+	var (
+		steps   int
+		visited = make([]bool, n)
+		path    = make([]int, 0, n)
+	)
+
+	var search func(i int) (bool, error)
+	search = func(i int) (bool, error) {
+		steps++
+		if limit > 0 && steps > limit {
+			return false, ErrBudgetExhausted
+		}
+
+		visited[i] = true
+		path = append(path, i)
+
+		if len(path) == n {
+			return true, nil
+		}
+
+		for _, j := range succ[i] {
+			if visited[j] {
+				continue
+			}
+			found, err := search(j)
+			if found || err != nil {
+				return found, err
+			}
+		}
+
+		visited[i] = false
+		path = path[:len(path)-1]
+
+		return false, nil
+	}
+
+	for start := range inst.Nodes {
+		found, err := search(start)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			result := make(Path, n)
+			for i, id := range path {
+				result[i] = inst.Nodes[id]
+			}
+			return result, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,54 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_HamiltonianPath(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	//  a → b
+	//  ↓ ↗ ↓
+	//  c ← d
+
+	a.AddEdge(b)
+	a.AddEdge(c)
+	c.AddEdge(b)
+	b.AddEdge(d)
+	d.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	path, err := g.HamiltonianPath(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path.String() != "a → b → d → c" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	if _, err := g.HamiltonianPath(2); !errors.Is(err, graph.ErrBudgetExhausted) {
+		t.Fatalf("expected budget to be exhausted, got: %v", err)
+	}
+
+	e := graph.NewNode("e", nil)
+	g.AddNode(e)
+
+	path, err = g.HamiltonianPath(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != nil {
+		t.Fatalf("did not expect a path with an isolated node: %v", path)
+	}
+}
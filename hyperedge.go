@@ -0,0 +1,129 @@
+package graph
+
+// HyperEdge is a relationship between any number of nodes of a graph, like
+// a meeting, or a transaction between several parties, which can't be
+// represented by an edge between two nodes.
+//
+//	     ┌───────────┐
+//	a ───┤ meeting   ├─── c
+//	     └─────┬─────┘
+//	           b
+//
+// Hyperedges are added using Instance.AddHyperEdge, and can be projected
+// to ordinary edges using Instance.ProjectHyperEdges.
+//
+// https://en.wikipedia.org/wiki/Hypergraph
+type HyperEdge struct {
+	// Name is the name (or type) of the relationship.
+	Name string
+
+	// Nodes are the nodes connected by the hyperedge, in the order they
+	// were added.
+	Nodes
+
+	// Attributes is a map of key-value pairs that describe the hyperedge.
+	Attributes
+}
+
+// Contains checks if the given node is connected by the hyperedge.
+func (h *HyperEdge) Contains(n *Node) bool {
+	return h.Nodes.Contains(n)
+}
+
+// AddHyperEdge adds a hyperedge with the given name connecting the given
+// nodes to the graph, adding any of the nodes that don't belong to the
+// graph yet. Each node is connected once, even if it's given more than once.
+func (inst *Instance) AddHyperEdge(name string, nodes ...*Node) *HyperEdge {
+	h := &HyperEdge{
+		Name:       name,
+		Nodes:      Nodes{},
+		Attributes: Attributes{},
+	}
+
+	for _, node := range nodes {
+		if node == nil || h.Contains(node) {
+			continue
+		}
+		if node.graph != inst {
+			inst.AddNode(node)
+		}
+		h.Nodes = append(h.Nodes, node)
+	}
+
+	inst.hyperEdges = append(inst.hyperEdges, h)
+
+	return h
+}
+
+// RemoveHyperEdge removes the given hyperedge from the graph, leaving its
+// nodes in the graph.
+func (inst *Instance) RemoveHyperEdge(h *HyperEdge) {
+	hyperEdges := inst.hyperEdges[:0]
+	for _, other := range inst.hyperEdges {
+		if other != h {
+			hyperEdges = append(hyperEdges, other)
+		}
+	}
+	inst.hyperEdges = hyperEdges
+}
+
+// HyperEdges returns the hyperedges of the graph connecting all of the given
+// nodes, in the order they were added, or every hyperedge if no nodes are
+// given.
+func (inst *Instance) HyperEdges(nodes ...*Node) []*HyperEdge {
+	var hyperEdges []*HyperEdge
+	for _, h := range inst.hyperEdges {
+		if len(h.Nodes.Intersect(nodes)) == len(NewNodeSet(nodes...)) {
+			hyperEdges = append(hyperEdges, h)
+		}
+	}
+	return hyperEdges
+}
+
+// HyperEdges returns the hyperedges of the node's graph that connect the
+// node, in the order they were added.
+func (n *Node) HyperEdges() []*HyperEdge {
+	if n.graph == nil {
+		return nil
+	}
+	return n.graph.HyperEdges(n)
+}
+
+// ProjectHyperEdges returns a copy of the graph where each hyperedge is
+// replaced with undirected (None) edges between every pair of its nodes,
+// named after the hyperedge and sharing a copy of its attributes, so
+// algorithms that work with pairwise edges can be used. This is also
+// known as the clique expansion, or 2-section, of the hypergraph. Each
+// pair is only connected once for each hyperedge name.
+//
+//	     ┌───────────┐
+//	a ───┤ meeting   ├─── c        a ── c
+//	     └─────┬─────┘       ⇒      ╲  ╱
+//	           b                     b
+//
+// https://en.wikipedia.org/wiki/Hypergraph#Related_graphs
+func (inst *Instance) ProjectHyperEdges() *Instance {
+	projected := inst.Clone()
+
+	for _, h := range projected.hyperEdges {
+		for i, a := range h.Nodes {
+			for _, b := range h.Nodes[i+1:] {
+				if a.Edges.find(b, None, h.Name) != nil {
+					continue
+				}
+
+				a.AddEdgeWithDirection(b, None)
+
+				attrs := copyAttributes(h.Attributes)
+				for _, edge := range (Edges{a.Edges[len(a.Edges)-1], b.Edges[len(b.Edges)-1]}) {
+					edge.Name = h.Name
+					edge.Attributes = attrs
+				}
+			}
+		}
+	}
+
+	projected.hyperEdges = nil
+
+	return projected
+}
@@ -0,0 +1,76 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_HyperEdges(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	meeting := g.AddHyperEdge("meeting", a, b, c, a)
+	meeting.Attributes["room"] = "1A"
+
+	// Nodes that don't belong to the graph yet are added.
+	payment := g.AddHyperEdge("payment", c, d)
+
+	if len(g.Nodes) != 4 {
+		t.Fatalf("expected d to be added to the graph, got %v", g.Nodes)
+	}
+
+	if meeting.Nodes.String() != "a, b, c" || !meeting.Contains(b) || meeting.Contains(d) {
+		t.Fatalf("unexpected meeting nodes: %v", meeting.Nodes)
+	}
+
+	if hs := c.HyperEdges(); len(hs) != 2 || hs[0] != meeting || hs[1] != payment {
+		t.Fatalf("expected c to be connected by both hyperedges, got %v", hs)
+	}
+
+	if hs := g.HyperEdges(a, c); len(hs) != 1 || hs[0] != meeting {
+		t.Fatalf("expected only the meeting to connect a and c, got %v", hs)
+	}
+
+	if hs := g.HyperEdges(); len(hs) != 2 {
+		t.Fatalf("expected 2 hyperedges, got %d", len(hs))
+	}
+
+	projected := g.ProjectHyperEdges()
+
+	if edges := projected.Edges().String(); edges != "a → b, a - b, a - c, b - c, c - d" {
+		t.Fatalf("unexpected projected edges: %v", edges)
+	}
+
+	if room := projected.Nodes[0].Edges[1].Attributes["room"]; room != "1A" || projected.Nodes[0].Edges[1].Name != "meeting" {
+		t.Fatalf("expected projected edges to have the hyperedge's name and attributes, got %q", room)
+	}
+
+	if len(projected.HyperEdges()) != 0 {
+		t.Fatal("did not expect the projected graph to have hyperedges")
+	}
+
+	if g.EdgeCount() != 1 || len(g.Clone().HyperEdges()) != 2 {
+		t.Fatal("expected the original graph to be unchanged")
+	}
+
+	g.RemoveNode(c)
+
+	if meeting.Nodes.String() != "a, b" {
+		t.Fatalf("expected removed node to be disconnected from hyperedges, got %v", meeting.Nodes)
+	}
+
+	g.RemoveHyperEdge(meeting)
+
+	if hs := a.HyperEdges(); len(hs) != 0 {
+		t.Fatalf("expected a to no longer be connected by hyperedges, got %v", hs)
+	}
+}
@@ -0,0 +1,63 @@
+package graph
+
+// ID returns the integer ID of the node in the graph, or -1 if the node
+// wasn't added to the graph.
+//
+// IDs are assigned when nodes are added to the graph, starting at 0, and
+// are stable: they don't change as other nodes are added or removed. The
+// IDs of removed nodes are reused by the nodes added after them, so MaxID
+// stays proportional to the largest number of nodes the graph had, rather
+// than the number of nodes ever added. They can be used to index slices
+// instead of using maps keyed by nodes in performance sensitive code, as
+// long as the slices don't outlive the removal of nodes.
+//
+// A node that is added to more than one graph only has an ID in the graph
+// it was added to last, like the indexes maintained by Node.SetAttribute.
+func (inst *Instance) ID(n *Node) int {
+	if n == nil || n.graph != inst || n.id >= len(inst.byID) || inst.byID[n.id] != n {
+		return -1
+	}
+	return n.id
+}
+
+// ByID returns the node in the graph with the given ID, or nil if there is
+// none, because the ID was never assigned or the node was removed.
+func (inst *Instance) ByID(id int) *Node {
+	if id < 0 || id >= len(inst.byID) {
+		return nil
+	}
+	return inst.byID[id]
+}
+
+// MaxID returns one more than the largest ID assigned by the graph, which
+// is the length of a slice that can be indexed by the ID of any node. It
+// doesn't shrink as nodes are removed, but their IDs are reused.
+func (inst *Instance) MaxID() int {
+	return len(inst.byID)
+}
+
+// assignID gives the node the most recently released ID of the graph, or
+// the next one if none were released, unless it already has one in the
+// graph.
+func (inst *Instance) assignID(n *Node) {
+	if n.graph == inst && n.id < len(inst.byID) && inst.byID[n.id] == n {
+		return
+	}
+	if last := len(inst.freeIDs) - 1; last >= 0 {
+		n.id = inst.freeIDs[last]
+		inst.freeIDs = inst.freeIDs[:last]
+		inst.byID[n.id] = n
+		return
+	}
+	n.id = len(inst.byID)
+	inst.byID = append(inst.byID, n)
+}
+
+// releaseID removes the node's ID from the graph, leaving a gap that is
+// filled by the next node added.
+func (inst *Instance) releaseID(n *Node) {
+	if id := inst.ID(n); id >= 0 {
+		inst.byID[id] = nil
+		inst.freeIDs = append(inst.freeIDs, id)
+	}
+}
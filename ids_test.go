@@ -0,0 +1,91 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_ID(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	for i, node := range graph.NewNodes(a, b, c) {
+		if id := g.ID(node); id != i {
+			t.Fatalf("expected %s to have ID %d, got %d", node.Name, i, id)
+		}
+		if g.ByID(i) != node {
+			t.Fatalf("expected ID %d to be %s", i, node.Name)
+		}
+	}
+
+	if id := g.ID(d); id != -1 {
+		t.Fatalf("did not expect a node outside of the graph to have an ID, got %d", id)
+	}
+
+	// IDs are stable, and reused after a node is removed.
+	g.RemoveNode(b)
+
+	if g.ID(b) != -1 || g.ByID(1) != nil {
+		t.Fatal("expected removed node to lose its ID")
+	}
+
+	g.AddNode(d)
+
+	if g.ID(c) != 2 || g.ID(d) != 1 || g.ByID(1) != d || g.MaxID() != 3 {
+		t.Fatalf("unexpected IDs: c=%d d=%d max=%d", g.ID(c), g.ID(d), g.MaxID())
+	}
+
+	if g.ByID(-1) != nil || g.ByID(3) != nil {
+		t.Fatal("did not expect nodes for out of range IDs")
+	}
+
+	// Traversals handle nodes with and without IDs.
+	c.AddEdge(d)
+	d.AddEdge(graph.NewNode("e", nil))
+
+	var visited []string
+	g.BFS(func(n *graph.Node) {
+		visited = append(visited, n.Name)
+	})
+
+	// The node e is outside of the graph, without an ID.
+	if len(visited) != 4 {
+		t.Fatalf("expected to visit 4 nodes, got: %v", visited)
+	}
+
+	if got := c.Reachable(graph.Out).String(); got != "d, e" {
+		t.Fatalf("unexpected reachable nodes: %s", got)
+	}
+}
+
+func TestInstance_ID_reused(t *testing.T) {
+	g := graph.New("test")
+
+	for i := 0; i < 1000; i++ {
+		a, b := graph.NewNode("a", nil), graph.NewNode("b", nil)
+		g.AddNode(a)
+		g.AddNode(b)
+		a.AddEdge(b)
+
+		if got := a.Reachable(graph.Out).String(); got != "b" {
+			t.Fatalf("unexpected reachable nodes: %s", got)
+		}
+
+		g.RemoveNode(a)
+		g.RemoveNode(b)
+	}
+
+	if max := g.MaxID(); max != 2 {
+		t.Fatalf("expected the IDs of removed nodes to be reused, got MaxID %d", max)
+	}
+}
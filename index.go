@@ -0,0 +1,181 @@
+package graph
+
+import "reflect"
+
+// CreateIndex creates an inverted index of the nodes in the graph by the
+// values of the named attribute, used by NodesWhere to avoid scanning all
+// of the nodes in the graph.
+//
+// The index is maintained as nodes are added to or removed from the graph,
+// and when attributes are changed using Node.SetAttribute or
+// Node.DeleteAttribute. Changes made directly to a node's Attributes map
+// are not reflected in the index. Values that can not be compared, such
+// as slices or maps, are not indexed.
+func (inst *Instance) CreateIndex(name string) {
+	if inst.indexes == nil {
+		inst.indexes = map[string]map[any]Nodes{}
+	}
+
+	index := map[any]Nodes{}
+	inst.indexes[name] = index
+
+	for _, node := range inst.Nodes {
+		if v, ok := node.Attributes[name]; ok && isComparable(v) {
+			index[v] = append(index[v], node)
+		}
+	}
+}
+
+// DropIndex removes the index for the named attribute.
+func (inst *Instance) DropIndex(name string) {
+	delete(inst.indexes, name)
+}
+
+// HasIndex checks if an index exists for the named attribute.
+func (inst *Instance) HasIndex(name string) bool {
+	_, ok := inst.indexes[name]
+	return ok
+}
+
+// NodesWhere returns the nodes in the graph with the named attribute
+// equal to the given value, using an index if one was created for the
+// attribute, or scanning all of the nodes otherwise.
+func (inst *Instance) NodesWhere(name string, value any) Nodes {
+	if index, ok := inst.indexes[name]; ok {
+		if !isComparable(value) {
+			return Nodes{}
+		}
+		return append(Nodes{}, index[value]...)
+	}
+
+	nodes := Nodes{}
+	for _, node := range inst.Nodes {
+		if v, ok := node.Attributes[name]; ok && isComparable(v) && isComparable(value) && v == value {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// SetAttribute sets a named attribute of the node, updating the indexes
+// of the graph the node belongs to.
+func (n *Node) SetAttribute(name string, value any) {
+	n.touch()
+
+	if n.graph != nil {
+		n.graph.unindexAttribute(n, name)
+	}
+
+	if n.Attributes == nil {
+		n.Attributes = Attributes{}
+	}
+	n.Attributes[name] = value
+
+	if n.graph != nil {
+		n.graph.indexAttribute(n, name)
+	}
+
+	n.publish(Mutation{Kind: AttrChanged, Node: n, Attribute: name, Value: value})
+}
+
+// DeleteAttribute removes a named attribute of the node, updating the
+// indexes of the graph the node belongs to.
+func (n *Node) DeleteAttribute(name string) {
+	n.touch()
+
+	if n.graph != nil {
+		n.graph.unindexAttribute(n, name)
+	}
+
+	delete(n.Attributes, name)
+
+	n.publish(Mutation{Kind: AttrChanged, Node: n, Attribute: name})
+}
+
+// indexNode adds the node to all of the graph's indexes.
+func (inst *Instance) indexNode(n *Node) {
+	inst.indexName(n)
+	for name := range inst.indexes {
+		inst.indexAttribute(n, name)
+	}
+}
+
+// unindexNode removes the node from all of the graph's indexes.
+func (inst *Instance) unindexNode(n *Node) {
+	inst.unindexName(n)
+	for name := range inst.indexes {
+		inst.unindexAttribute(n, name)
+	}
+}
+
+// indexAttribute adds the node to the index of the named attribute.
+func (inst *Instance) indexAttribute(n *Node, name string) {
+	index, ok := inst.indexes[name]
+	if !ok {
+		return
+	}
+
+	if v, ok := n.Attributes[name]; ok && isComparable(v) {
+		index[v] = append(index[v], n)
+	}
+}
+
+// unindexAttribute removes the node from the index of the named attribute.
+func (inst *Instance) unindexAttribute(n *Node, name string) {
+	index, ok := inst.indexes[name]
+	if !ok {
+		return
+	}
+
+	v, ok := n.Attributes[name]
+	if !ok || !isComparable(v) {
+		return
+	}
+
+	nodes := index[v]
+	for i, node := range nodes {
+		if node == n {
+			nodes = append(nodes[:i:i], nodes[i+1:]...)
+			break
+		}
+	}
+
+	if len(nodes) == 0 {
+		delete(index, v)
+	} else {
+		index[v] = nodes
+	}
+}
+
+// isComparable checks if the given value can be used as a map key, which
+// depends on the dynamic values of its interface fields and elements, like
+// a struct with an `any` field holding a slice, not only on its type.
+func isComparable(v any) bool {
+	return v == nil || comparableValue(reflect.ValueOf(v))
+}
+
+// comparableValue checks if the given value can be compared using ==
+// without panicking.
+func comparableValue(v reflect.Value) bool {
+	if !v.Type().Comparable() {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || comparableValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !comparableValue(v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !comparableValue(v.Index(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
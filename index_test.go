@@ -0,0 +1,85 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_CreateIndex(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"type": "db"})
+		b = graph.NewNode("b", graph.Attributes{"type": "service"})
+		c = graph.NewNode("c", graph.Attributes{"type": "db", "tags": []string{"x"}})
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	g.CreateIndex("type")
+	g.CreateIndex("tags")
+
+	if !g.HasIndex("type") {
+		t.Fatalf("expected index to exist")
+	}
+
+	g.AddNode(c)
+
+	if nodes := g.NodesWhere("type", "db"); nodes.String() != "a, c" {
+		t.Fatalf("unexpected nodes: %v", nodes)
+	}
+
+	b.SetAttribute("type", "db")
+	a.SetAttribute("type", "cache")
+
+	if nodes := g.NodesWhere("type", "db"); nodes.String() != "c, b" {
+		t.Fatalf("unexpected nodes after update: %v", nodes)
+	}
+
+	if nodes := g.NodesWhere("type", "cache"); nodes.String() != "a" {
+		t.Fatalf("unexpected nodes after update: %v", nodes)
+	}
+
+	c.DeleteAttribute("type")
+	g.RemoveNode(b)
+
+	if nodes := g.NodesWhere("type", "db"); len(nodes) != 0 {
+		t.Fatalf("expected no nodes, got: %v", nodes)
+	}
+
+	g.DropIndex("type")
+
+	if nodes := g.NodesWhere("type", "cache"); nodes.String() != "a" {
+		t.Fatalf("unexpected nodes without index: %v", nodes)
+	}
+}
+
+func TestInstance_CreateIndex_uncomparable(t *testing.T) {
+	type owner struct {
+		Team any
+	}
+
+	var (
+		a = graph.NewNode("a", graph.Attributes{"owner": owner{Team: "core"}})
+		b = graph.NewNode("b", graph.Attributes{"owner": owner{Team: []string{"core", "infra"}}})
+		c = graph.NewNode("c", graph.Attributes{"owner": [1]any{map[string]int{}}})
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	// Values whose fields or elements can't be compared aren't indexed,
+	// rather than panicking.
+	g.CreateIndex("owner")
+
+	if nodes := g.NodesWhere("owner", owner{Team: "core"}); nodes.String() != "a" {
+		t.Fatalf("unexpected nodes: %v", nodes)
+	}
+
+	b.SetAttribute("owner", owner{Team: []int{1}})
+	g.RemoveNode(c)
+
+	g.DropIndex("owner")
+
+	if nodes := g.NodesWhere("owner", owner{Team: []string{"core"}}); len(nodes) != 0 {
+		t.Fatalf("expected no nodes, got: %v", nodes)
+	}
+}
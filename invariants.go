@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidGraph is returned by CheckInvariants, wrapped with a description
+// of the first invariant that doesn't hold.
+var ErrInvalidGraph = errors.New("graph invariant violated")
+
+// CheckInvariants verifies the structure of the graph, returning an error
+// wrapping ErrInvalidGraph describing the first problem found, or nil if
+// the graph is valid. It is useful for checking graphs built or decoded
+// from untrusted input before using them.
+//
+// The following invariants are checked:
+//
+//   - The graph doesn't contain nil nodes, or the same node more than once.
+//   - Edges aren't nil, and point to a node.
+//   - Edges belong to the node whose adjacency list contains them.
+//   - Every edge has a reciprocal edge in the adjacency list of its node,
+//     pointing back with the reverse direction and the same name, like the
+//     edges added by AddEdgeWithDirection.
+//   - Traversals of the graph terminate, visiting each node once.
+func CheckInvariants(inst *Instance) error {
+	if inst == nil {
+		return fmt.Errorf("%w: nil graph", ErrInvalidGraph)
+	}
+
+	members := NodeSet{}
+
+	for i, node := range inst.Nodes {
+		if node == nil {
+			return fmt.Errorf("%w: nil node at index %d", ErrInvalidGraph, i)
+		}
+
+		if members.Contains(node) {
+			return fmt.Errorf("%w: node %q is in the graph more than once", ErrInvalidGraph, node.Name)
+		}
+		members.Add(node)
+
+		for j, edge := range node.Edges {
+			if edge == nil {
+				return fmt.Errorf("%w: nil edge at index %d of node %q", ErrInvalidGraph, j, node.Name)
+			}
+
+			if edge.Node == nil {
+				return fmt.Errorf("%w: edge at index %d of node %q has no node", ErrInvalidGraph, j, node.Name)
+			}
+
+			if edge.from != nil && edge.from != node {
+				return fmt.Errorf("%w: edge from %q to %q belongs to node %q", ErrInvalidGraph, node.Name, edge.Node.Name, edge.from.Name)
+			}
+		}
+	}
+
+	// Count the edges of each kind, including those of nodes outside of the
+	// graph, so parallel edges each need their own reciprocal edge.
+	type key struct {
+		from, to  *Node
+		direction EdgeDirection
+		name      string
+	}
+
+	counted := NodeSet{}
+	counts := map[key]int{}
+
+	count := func(node *Node) {
+		if counted.Contains(node) {
+			return
+		}
+		counted.Add(node)
+
+		for _, edge := range node.Edges {
+			if edge != nil {
+				counts[key{node, edge.Node, edge.Direction, edge.Name}]++
+			}
+		}
+	}
+
+	for _, node := range inst.Nodes {
+		count(node)
+		for _, edge := range node.Edges {
+			count(edge.Node)
+		}
+	}
+
+	for _, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			k := key{node, edge.Node, edge.Direction, edge.Name}
+			r := key{edge.Node, node, edge.Direction.Reverse(), edge.Name}
+
+			if counts[k] != counts[r] {
+				return fmt.Errorf("%w: edge %s %s %s has no reciprocal edge", ErrInvalidGraph, node.Name, edge.Direction, edge.Node.Name)
+			}
+		}
+	}
+
+	visited := NodeSet{}
+
+	for _, node := range inst.Nodes {
+		if visited.Contains(node) {
+			continue
+		}
+
+		var err error
+
+		seen := NodeSet{}
+
+		visitWithTerminator(node, nil, Both, func(other *Node) bool {
+			if seen.Contains(other) {
+				err = fmt.Errorf("%w: traversal from %q visited %q more than once", ErrInvalidGraph, node.Name, other.Name)
+				return false
+			}
+			seen.Add(other)
+			visited.Add(other)
+			return true
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
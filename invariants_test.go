@@ -0,0 +1,78 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	valid := func() (*graph.Instance, *graph.Node, *graph.Node) {
+		var (
+			a = graph.NewNode("a", nil)
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+			d = graph.NewNode("d", nil)
+		)
+
+		// a → b ↔ c - d    d ↺
+
+		a.AddEdge(b)
+		b.AddLink(c)
+		c.AddEdgeWithDirection(d, graph.None)
+		d.AddEdge(d)
+
+		return graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d))), a, b
+	}
+
+	g, _, _ := valid()
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name  string
+		Break func(g *graph.Instance, a, b *graph.Node)
+	}{
+		{"nil graph", nil},
+		{"nil node", func(g *graph.Instance, a, b *graph.Node) {
+			g.Nodes = append(g.Nodes, nil)
+		}},
+		{"duplicate node", func(g *graph.Instance, a, b *graph.Node) {
+			g.Nodes = append(g.Nodes, a)
+		}},
+		{"nil edge", func(g *graph.Instance, a, b *graph.Node) {
+			a.Edges = append(a.Edges, nil)
+		}},
+		{"edge without node", func(g *graph.Instance, a, b *graph.Node) {
+			a.Edges = append(a.Edges, &graph.Edge{Direction: graph.Out})
+		}},
+		{"edge without reciprocal", func(g *graph.Instance, a, b *graph.Node) {
+			a.Edges = append(a.Edges, &graph.Edge{Node: b, Direction: graph.Out})
+		}},
+		{"edge with mismatched reciprocal", func(g *graph.Instance, a, b *graph.Node) {
+			a.Edges[0].Name = "renamed"
+		}},
+		{"edge belonging to another node", func(g *graph.Instance, a, b *graph.Node) {
+			a.Edges = append(a.Edges, b.Edges[0])
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			g, a, b := valid()
+
+			if test.Break == nil {
+				g = nil
+			} else {
+				test.Break(g, a, b)
+			}
+
+			if err := graph.CheckInvariants(g); !errors.Is(err, graph.ErrInvalidGraph) {
+				t.Fatalf("expected invalid graph error, got: %v", err)
+			}
+		})
+	}
+}
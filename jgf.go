@@ -0,0 +1,329 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jgfDocument is a JSON Graph Format document, with a single graph, or the
+// first of multiple graphs when decoding.
+type jgfDocument struct {
+	Graph  *jgfGraph  `json:"graph,omitempty"`
+	Graphs []jgfGraph `json:"graphs,omitempty"`
+}
+
+type jgfGraph struct {
+	ID       string     `json:"id,omitempty"`
+	Label    string     `json:"label,omitempty"`
+	Directed *bool      `json:"directed,omitempty"`
+	Metadata Attributes `json:"metadata,omitempty"`
+	Nodes    jgfNodes   `json:"nodes"`
+	Edges    []jgfEdge  `json:"edges,omitempty"`
+}
+
+type jgfNode struct {
+	ID       string     `json:"id,omitempty"`
+	Label    string     `json:"label,omitempty"`
+	Metadata Attributes `json:"metadata,omitempty"`
+}
+
+type jgfEdge struct {
+	Source   string     `json:"source"`
+	Target   string     `json:"target"`
+	Relation string     `json:"relation,omitempty"`
+	Directed *bool      `json:"directed,omitempty"`
+	Metadata Attributes `json:"metadata,omitempty"`
+}
+
+// jgfNodes are the nodes of a JSON Graph Format graph, encoded as an object
+// keyed by node identifier, as in version 2 of the format, and decoded from
+// such an object, or from an array of nodes with an "id", as in version 1,
+// keeping the order they appear in.
+type jgfNodes []jgfNode
+
+// MarshalJSON implements the json.Marshaler interface.
+func (nodes jgfNodes) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, node := range nodes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		id, err := json.Marshal(node.ID)
+		if err != nil {
+			return nil, err
+		}
+		node.ID = ""
+		value, err := json.Marshal(node)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(id)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (nodes *jgfNodes) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		return json.Unmarshal(b, (*[]jgfNode)(nodes))
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var node jgfNode
+		if err := dec.Decode(&node); err != nil {
+			return err
+		}
+		node.ID, _ = token.(string)
+		*nodes = append(*nodes, node)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// jgfLabelsKey and jgfBidirectionalKey are the metadata keys used for the
+// labels of nodes, and for bi-directional (Both) edges.
+const (
+	jgfLabelsKey        = "labels"
+	jgfBidirectionalKey = "bidirectional"
+)
+
+// EncodeJGF encodes the graph using the JSON Graph Format, which is
+// understood by JavaScript visualization libraries, like Sigma.js and
+// Cytoscape.js through their JGF readers.
+//
+//	{
+//	  "graph": {
+//	    "label": "deps",
+//	    "directed": true,
+//	    "nodes": {"a": {"label": "a"}, "b": {"label": "b"}},
+//	    "edges": [{"source": "a", "target": "b"}]
+//	  }
+//	}
+//
+// Nodes are identified by their name, and their attributes and labels are
+// written as metadata, like the graph's attributes. Each relationship is
+// written once, like Edges, with its name as relation, and its attributes
+// as metadata. Undirected (None) edges aren't directed, and bi-directional
+// (Both) edges have a "bidirectional" metadata value of true. Attribute
+// values of types registered with RegisterAttributeType keep their type
+// when decoded using DecodeJGF.
+//
+// The WithName, WithCanonical, and WithUndirected encoding options are
+// supported. An error is returned if two nodes have the same name.
+//
+// https://jsongraphformat.info
+func EncodeJGF(w io.Writer, inst *Instance, opts ...func(*EncodeOptions)) error {
+	options := newEncodeOptions(opts...)
+
+	nodes := inst.Nodes
+	if options.Canonical {
+		nodes = canonicalOrder(nodes)
+	}
+
+	directed := !isUndirected(nodes)
+	if options.Undirected != nil {
+		directed = !*options.Undirected
+	}
+
+	label := inst.Name
+	if options.Name != "" {
+		label = options.Name
+	}
+
+	metadata, err := marshalAttributes(inst.Attributes)
+	if err != nil {
+		return err
+	}
+
+	g := jgfGraph{
+		Label:    label,
+		Directed: &directed,
+		Metadata: metadata,
+		Nodes:    make(jgfNodes, 0, len(nodes)),
+	}
+
+	seen := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if seen[node.Name] {
+			return fmt.Errorf("graph cannot encode JGF with duplicate node name %q", node.Name)
+		}
+		seen[node.Name] = true
+
+		metadata, err := marshalAttributes(node.Attributes)
+		if err != nil {
+			return err
+		}
+		if len(node.Labels) > 0 {
+			metadata = copyAttributes(metadata)
+			if metadata == nil {
+				metadata = Attributes{}
+			}
+			metadata[jgfLabelsKey] = node.Labels
+		}
+
+		g.Nodes = append(g.Nodes, jgfNode{ID: node.Name, Label: node.Name, Metadata: metadata})
+	}
+
+	members := NewNodeSet(nodes...)
+
+	eachUniqueEdge(nodes, func(from *Node, edge *Edge) {
+		if err != nil || !members.Contains(edge.Node) {
+			return
+		}
+
+		source, target := from.Name, edge.Node.Name
+		if edge.Direction == In {
+			source, target = target, source
+		}
+
+		var metadata Attributes
+		metadata, err = marshalAttributes(edge.Attributes)
+
+		// Edges only say whether they're directed when the graph doesn't.
+		var edgeDirected *bool
+		if isDirected := edge.Direction != None && edge.Direction != Unknown; isDirected != directed {
+			edgeDirected = &isDirected
+		}
+
+		if edge.Direction == Both {
+			metadata = copyAttributes(metadata)
+			if metadata == nil {
+				metadata = Attributes{}
+			}
+			metadata[jgfBidirectionalKey] = true
+		}
+
+		g.Edges = append(g.Edges, jgfEdge{
+			Source:   source,
+			Target:   target,
+			Relation: edge.Name,
+			Directed: edgeDirected,
+			Metadata: metadata,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(jgfDocument{Graph: &g}); err != nil {
+		return fmt.Errorf("graph failed to encode JGF: %w", err)
+	}
+	return nil
+}
+
+// DecodeJGF decodes a graph encoded using the JSON Graph Format, like the
+// output of EncodeJGF, from versions 1 and 2 of the format. Documents with
+// multiple graphs are supported, but only the first graph is decoded.
+//
+// Nodes are named after their identifier, with their metadata as attributes,
+// and the "labels" metadata as their labels. Edges are added as outward
+// edges from their source to their target, or as undirected (None) edges
+// if they, or the graph, aren't directed, or as bi-directional (Both) edges
+// if their "bidirectional" metadata is true. Their relation is used as their
+// name. An error is returned for edges referring to nodes that don't exist.
+//
+// https://jsongraphformat.info
+func DecodeJGF(r io.Reader) (*Instance, error) {
+	var doc jgfDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("graph failed to decode JGF: %w", err)
+	}
+
+	g := doc.Graph
+	if g == nil {
+		if len(doc.Graphs) == 0 {
+			return nil, fmt.Errorf("graph failed to decode JGF: no graph")
+		}
+		g = &doc.Graphs[0]
+	}
+
+	if err := unmarshalAttributes(g.Metadata); err != nil {
+		return nil, err
+	}
+
+	inst := New(g.Label)
+	inst.Attributes = g.Metadata
+
+	byID := make(map[string]*Node, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if err := unmarshalAttributes(n.Metadata); err != nil {
+			return nil, err
+		}
+
+		node := NewNode(n.ID, n.Metadata)
+		if labels, ok := node.Attributes[jgfLabelsKey].([]any); ok {
+			for _, label := range labels {
+				if s, ok := label.(string); ok {
+					node.Labels = append(node.Labels, s)
+				}
+			}
+			delete(node.Attributes, jgfLabelsKey)
+			if len(node.Attributes) == 0 {
+				node.Attributes = nil
+			}
+		}
+
+		byID[n.ID] = node
+		inst.AddNode(node)
+	}
+
+	graphDirected := g.Directed == nil || *g.Directed
+
+	for i, e := range g.Edges {
+		source, ok := byID[e.Source]
+		if !ok {
+			return nil, fmt.Errorf("graph failed to decode JGF: edge %d source %q not found", i, e.Source)
+		}
+		target, ok := byID[e.Target]
+		if !ok {
+			return nil, fmt.Errorf("graph failed to decode JGF: edge %d target %q not found", i, e.Target)
+		}
+
+		if err := unmarshalAttributes(e.Metadata); err != nil {
+			return nil, err
+		}
+
+		direction := Out
+		if (e.Directed != nil && !*e.Directed) || (e.Directed == nil && !graphDirected) {
+			direction = None
+		}
+		if bidirectional, _ := e.Metadata[jgfBidirectionalKey].(bool); bidirectional {
+			direction = Both
+			delete(e.Metadata, jgfBidirectionalKey)
+			if len(e.Metadata) == 0 {
+				e.Metadata = nil
+			}
+		}
+
+		source.AddEdgeWithDirection(target, direction)
+
+		added := Edges{source.Edges[len(source.Edges)-1], target.Edges[len(target.Edges)-1]}
+		if source == target {
+			added[0] = source.Edges[len(source.Edges)-2]
+		}
+		for _, edge := range added {
+			edge.Name = e.Relation
+			edge.Attributes = e.Metadata
+		}
+	}
+
+	return inst, nil
+}
@@ -0,0 +1,136 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+const jgf_golden = `{
+  "graph": {
+    "label": "deps",
+    "directed": true,
+    "metadata": {
+      "env": "prod"
+    },
+    "nodes": {
+      "api": {
+        "label": "api",
+        "metadata": {
+          "labels": [
+            "Service"
+          ],
+          "port": 8080
+        }
+      },
+      "db": {
+        "label": "db"
+      },
+      "cache": {
+        "label": "cache"
+      }
+    },
+    "edges": [
+      {
+        "source": "api",
+        "target": "db",
+        "relation": "queries",
+        "metadata": {
+          "weight": 2
+        }
+      },
+      {
+        "source": "api",
+        "target": "cache",
+        "metadata": {
+          "bidirectional": true
+        }
+      },
+      {
+        "source": "db",
+        "target": "cache",
+        "directed": false
+      }
+    ]
+  }
+}
+`
+
+func TestEncodeJGF(t *testing.T) {
+	var (
+		api   = graph.NewNode("api", graph.Attributes{"port": 8080})
+		db    = graph.NewNode("db", nil)
+		cache = graph.NewNode("cache", nil)
+	)
+
+	api.Labels = []string{"Service"}
+
+	api.AddEdgeTyped(db, "queries")
+	api.Edges[0].Attributes = graph.Attributes{"weight": 2}
+	db.Edges[0].Attributes = api.Edges[0].Attributes
+	api.AddEdgeWithDirection(cache, graph.Both)
+	db.AddEdgeWithDirection(cache, graph.None)
+
+	g := graph.New("deps", graph.WithNodes(graph.Nodes{api, db, cache}), graph.WithAttributes(graph.Attributes{"env": "prod"}))
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := graph.EncodeJGF(buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != jgf_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), jgf_golden)
+	}
+
+	decoded, err := graph.DecodeJGF(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !graph.Equal(g, decoded) {
+		t.Fatalf("expected decoded graph to be equal, got nodes %v, edges %v", decoded.Nodes, decoded.Edges())
+	}
+}
+
+func TestDecodeJGF(t *testing.T) {
+	// Version 1 of the format has an array of nodes, and multiple graphs.
+	input := `{
+		"graphs": [{
+			"label": "first",
+			"directed": false,
+			"nodes": [{"id": "b"}, {"id": "a", "label": "A"}],
+			"edges": [
+				{"source": "a", "target": "b"},
+				{"source": "b", "target": "a", "directed": true, "relation": "owns"}
+			]
+		}, {
+			"label": "second"
+		}]
+	}`
+
+	g, err := graph.DecodeJGF(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Name != "first" || g.Nodes.String() != "b, a" {
+		t.Fatalf("unexpected graph %q with nodes %v", g.Name, g.Nodes)
+	}
+
+	if got := g.Edges().String(); got != "b - a, b → a" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	for _, input := range []string{
+		`{}`,
+		`{"graph": {"nodes": {"a": {}}, "edges": [{"source": "a", "target": "b"}]}}`,
+		`{"graph": `,
+	} {
+		if _, err := graph.DecodeJGF(strings.NewReader(input)); err == nil {
+			t.Fatalf("expected an error for %s", input)
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package graph
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 )
 
 type nodeJSON struct {
-	Name       string `json:"name,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
 	Attributes `json:"attributes,omitempty"`
 }
 
@@ -16,95 +22,445 @@ type edgeJSON struct {
 	FromIndex  int           `json:"from_index"`
 	Direction  EdgeDirection `json:"direction"`
 	ToIndex    int           `json:"to_index"`
+	Label      string        `json:"label,omitempty"`
 	Attributes `json:"attributes,omitempty"`
 }
 
 type graphJSON struct {
-	Nodes []nodeJSON `json:"nodes,omitempty"`
-	Edges []edgeJSON `json:"edges,omitempty"`
+	Version int        `json:"version"`
+	Nodes   []nodeJSON `json:"nodes,omitempty"`
+	Edges   []edgeJSON `json:"edges,omitempty"`
 }
 
-func EncodeJSON(w io.Writer, nodes Nodes) error {
-	return json.NewEncoder(w).Encode(graphJSON{
-		Nodes: func() []nodeJSON {
-			ns := make([]nodeJSON, len(nodes))
+// EncodeJSON encodes the given nodes and their edges as JSON, where edges
+// refer to nodes by their index. Attribute values of types registered with
+// RegisterAttributeType keep their type when decoded using DecodeJSON.
+//
+// Edges are encoded with their name, direction, and attributes. With the
+// WithDirectionGlyphs option, edges also have a label describing them with
+// the glyph of their direction, like "a → b", which is ignored by
+// DecodeJSON.
+//
+// With the WithCanonical option, nodes are sorted by name and edges by their
+// node indexes, direction, and name, so the output is reproducible.
+//
+// With the WithCompression option, the JSON is compressed using gzip as it
+// is written, which DecodeJSON detects and decompresses.
+func EncodeJSON(w io.Writer, nodes Nodes, opts ...func(*EncodeOptions)) error {
+	options := newEncodeOptions(opts...)
 
-			for i, n := range nodes {
-				ns[i] = nodeJSON{
-					Name:       n.Name,
-					Attributes: n.Attributes,
-				}
-			}
+	if options.Compression != 0 {
+		zw, err := gzip.NewWriterLevel(w, options.Compression)
+		if err != nil {
+			return fmt.Errorf("graph failed to compress JSON: %w", err)
+		}
 
-			return ns
-		}(),
-		Edges: func() []edgeJSON {
-			eix := []edgeJSON{}
-
-			eim := map[string]struct{}{}
-
-			for i, node := range nodes {
-				for _, edge := range node.Edges {
-					ei := edgeJSON{
-						FromIndex: i,
-						Direction: edge.Direction,
-						ToIndex:   nodes.IndexOf(edge.Node),
-					}
-					eik := fmt.Sprintf("%#+v", ei)
-					if _, ok := eim[eik]; !ok {
-						eim[eik] = struct{}{}
-					} else {
-						continue
-					}
-					eix = append(eix, ei)
-				}
+		err = encodeJSON(zw, nodes, options)
+		if closeErr := zw.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+
+	return encodeJSON(w, nodes, options)
+}
+
+// encodeJSON encodes the given nodes and their edges as JSON using the
+// given options, see EncodeJSON.
+func encodeJSON(w io.Writer, nodes Nodes, options *EncodeOptions) error {
+	if options.Canonical {
+		nodes = canonicalOrder(nodes)
+	}
+
+	ns := make([]nodeJSON, len(nodes))
+
+	for i, n := range nodes {
+		attrs, err := marshalAttributes(n.Attributes)
+		if err != nil {
+			return err
+		}
+
+		ns[i] = nodeJSON{
+			Name:       n.Name,
+			Labels:     n.Labels,
+			Attributes: attrs,
+		}
+	}
+
+	index := make(map[*Node]int, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		index[nodes[i]] = i
+	}
+
+	var (
+		es  = []edgeJSON{}
+		err error
+	)
+
+	eachUniqueEdge(nodes, func(from *Node, edge *Edge) {
+		to, ok := index[edge.Node]
+		if !ok || err != nil {
+			return
+		}
+
+		var attrs Attributes
+		attrs, err = marshalAttributes(edge.Attributes)
+
+		var label string
+		if options.DirectionGlyphs {
+			label = edge.String()
+		}
+
+		es = append(es, edgeJSON{
+			Name:       edge.Name,
+			FromIndex:  index[from],
+			Direction:  edge.Direction,
+			ToIndex:    to,
+			Label:      label,
+			Attributes: attrs,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if options.Canonical {
+		sort.SliceStable(es, func(i, j int) bool {
+			a, b := es[i], es[j]
+			switch {
+			case a.FromIndex != b.FromIndex:
+				return a.FromIndex < b.FromIndex
+			case a.ToIndex != b.ToIndex:
+				return a.ToIndex < b.ToIndex
+			case a.Direction != b.Direction:
+				return a.Direction < b.Direction
+			default:
+				return a.Name < b.Name
 			}
+		})
+	}
 
-			return eix
-		}(),
+	return json.NewEncoder(w).Encode(graphJSON{
+		Version: JSONVersion,
+		Nodes:   ns,
+		Edges:   es,
 	})
 }
 
+// DecodeJSON decodes nodes and their edges encoded using EncodeJSON, adding
+// both edges of each relationship. Encodings that list both edges of a
+// relationship, as older versions did, are also supported.
+//
+// Encodings compressed using gzip, like those of EncodeJSON with the
+// WithCompression option, are detected and decompressed as they are read.
 func DecodeJSON(r io.Reader) (Nodes, error) {
-	naej := &graphJSON{}
+	return decodeJSON(r, nil)
+}
+
+// DecodeJSONFilter decodes the nodes encoded using EncodeJSON that the given
+// function returns true for, and the edges between them, like DecodeJSON.
+// Nodes are given to the function with their name, labels, and attributes,
+// but without edges, which are only added once the nodes are decoded. If
+// the function is nil, every node is kept, like DecodeJSON.
+//
+//	nodes, err := graph.DecodeJSONFilter(r, func(n *graph.Node) bool {
+//		return strings.HasPrefix(n.Name, "payments/")
+//	})
+//
+// The encoding is decoded as it is read, one node or edge at a time, so
+// slices of graphs too large to decode whole can be loaded: only the nodes
+// that are kept, and the edges between them, are held in memory.
+func DecodeJSONFilter(r io.Reader, keep func(*Node) bool) (Nodes, error) {
+	return decodeJSON(r, keep)
+}
 
-	err := json.NewDecoder(r).Decode(naej)
+// decodeJSON decodes the nodes encoded using EncodeJSON that the given
+// function returns true for, or all of them if it is nil, and the edges
+// between them.
+func decodeJSON(r io.Reader, keep func(*Node) bool) (Nodes, error) {
+	r, err := decompressJSON(r)
 	if err != nil {
-		return nil, fmt.Errorf("grap failed to decode nodes and edges JSON: %w", err)
+		return nil, err
+	}
+	if rc, ok := r.(io.Closer); ok {
+		defer rc.Close()
 	}
 
-	nodes := make(Nodes, len(naej.Nodes))
+	nodes, err := decodeJSONStream(json.NewDecoder(r), keep)
+	if err != nil {
+		return nil, fmt.Errorf("graph failed to decode nodes and edges JSON: %w", err)
+	}
+	return nodes, nil
+}
+
+// decodeJSONStream decodes the nodes the given function returns true for,
+// or all of them if it is nil, and the edges between them, one at a time
+// from the given decoder.
+//
+// Migrations depend on the version of the encoding, which EncodeJSON writes
+// before the nodes and edges, but other encoders, like jq -S, can reorder.
+// If migrations are registered, elements read before the version are kept
+// undecoded until the end of the encoding, when its version is known.
+func decodeJSONStream(dec *json.Decoder, keep func(*Node) bool) (Nodes, error) {
+	var (
+		// version is the version of the encoding, or 0 for encodings
+		// without a version, and known is true once it was read.
+		version int
+		known   = len(jsonMigrationsFrom(0)) == 0
+
+		// indexed are the decoded nodes by their index in the encoding,
+		// with nil for the nodes that weren't kept.
+		indexed []*Node
+		nodes   = Nodes{}
+
+		// pending are the edges decoded before the nodes, if the nodes
+		// weren't encoded first, as EncodeJSON does.
+		pending []edgeJSON
+		decoded bool
+		implied = impliedEdges{}
 
-	for i, naejNode := range naej.Nodes {
-		nodes[i] = NewNode(naejNode.Name, naejNode.Attributes)
+		// rawNodes and rawEdges are the elements read before the version
+		// was known.
+		rawNodes []json.RawMessage
+		rawEdges []json.RawMessage
+	)
+
+	decodeNode := func(decode func(any) error) error {
+		var naejNode nodeJSON
+		if err := decodeJSONElement(decode, &naejNode, jsonMigrationsFrom(version), JSONMigration.node); err != nil {
+			return err
+		}
+		if err := unmarshalAttributes(naejNode.Attributes); err != nil {
+			return err
+		}
+
+		node := NewNode(naejNode.Name, naejNode.Attributes)
+		node.Labels = naejNode.Labels
+
+		if keep == nil || keep(node) {
+			nodes = append(nodes, node)
+		} else {
+			node = nil
+		}
+		indexed = append(indexed, node)
+		return nil
 	}
 
-	for _, naejEdge := range naej.Edges {
-		if naejEdge.FromIndex < 0 || naejEdge.FromIndex > len(nodes) {
-			continue
+	decodeEdge := func(decode func(any) error) error {
+		var naejEdge edgeJSON
+		if err := decodeJSONElement(decode, &naejEdge, jsonMigrationsFrom(version), JSONMigration.edge); err != nil {
+			return err
+		}
+		if !decoded {
+			pending = append(pending, naejEdge)
+			return nil
 		}
+		return implied.add(indexed, naejEdge)
+	}
 
-		if naejEdge.ToIndex < 0 || naejEdge.ToIndex > len(nodes) {
-			continue
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nodes, nil
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
 		}
 
-		var (
-			name  string        = naejEdge.Name
-			from  *Node         = nodes[naejEdge.FromIndex]
-			to    *Node         = nodes[naejEdge.ToIndex]
-			dir   EdgeDirection = naejEdge.Direction
-			attrs Attributes    = naejEdge.Attributes
-		)
+		// Keys are matched case-insensitively, like encoding/json does.
+		key, _ := tok.(string)
 
-		edge := &Edge{
-			Name:       name,
-			Node:       to,
-			Direction:  dir,
-			Attributes: attrs,
+		switch strings.ToLower(key) {
+		case "version":
+			err = dec.Decode(&version)
+			known = true
+		case "nodes":
+			err = decodeJSONArray(dec, func() error {
+				if !known {
+					return bufferJSONElement(dec, &rawNodes)
+				}
+				return decodeNode(dec.Decode)
+			})
+			decoded = known
+		case "edges":
+			err = decodeJSONArray(dec, func() error {
+				if !known {
+					return bufferJSONElement(dec, &rawEdges)
+				}
+				return decodeEdge(dec.Decode)
+			})
+		default:
+			var skipped json.RawMessage
+			err = dec.Decode(&skipped)
 		}
+		if err != nil {
+			return nil, err
+		}
+	}
 
-		from.Edges = append(from.Edges, edge)
+	// Read the end of the object.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	for _, raw := range rawNodes {
+		if err := decodeNode(rawJSONDecoder(raw)); err != nil {
+			return nil, err
+		}
+		decoded = true
+	}
+
+	for _, raw := range rawEdges {
+		if err := decodeEdge(rawJSONDecoder(raw)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, naejEdge := range pending {
+		if err := implied.add(indexed, naejEdge); err != nil {
+			return nil, err
+		}
 	}
 
 	return nodes, nil
 }
+
+// bufferJSONElement appends the next element of the decoder to the given
+// elements, without decoding it.
+func bufferJSONElement(dec *json.Decoder, elements *[]json.RawMessage) error {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	*elements = append(*elements, raw)
+	return nil
+}
+
+// rawJSONDecoder returns a function decoding the given element, like the
+// Decode method of a decoder reading it.
+func rawJSONDecoder(raw json.RawMessage) func(any) error {
+	return func(v any) error {
+		return json.Unmarshal(raw, v)
+	}
+}
+
+// decodeJSONArray calls the given function to decode each element of the
+// JSON array that is next in the decoder, which can be null.
+func decodeJSONArray(dec *json.Decoder, fn func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected an array, got %v", tok)
+	}
+
+	for dec.More() {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+// decompressJSON returns a reader of the given JSON encoding, which
+// decompresses it if it is compressed using gzip. The returned reader is
+// an io.Closer if it needs to be closed.
+func decompressJSON(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("graph failed to decompress JSON: %w", err)
+		}
+		return zr, nil
+	}
+
+	return br, nil
+}
+
+// gzipMagic is the header of gzip streams, which JSON can't start with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// impliedEdges is a set of edges added as the other side of a relationship.
+type impliedEdges map[*Edge]bool
+
+// add adds both edges of the relationship described by the given decoded
+// edge between the given nodes, unless it is the implied side of one that
+// was already added. Edges of nodes that are out of range, or nil, are
+// skipped.
+func (implied impliedEdges) add(nodes []*Node, naejEdge edgeJSON) error {
+	if naejEdge.FromIndex < 0 || naejEdge.FromIndex >= len(nodes) {
+		return nil
+	}
+
+	if naejEdge.ToIndex < 0 || naejEdge.ToIndex >= len(nodes) {
+		return nil
+	}
+
+	var (
+		name  string        = naejEdge.Name
+		from  *Node         = nodes[naejEdge.FromIndex]
+		to    *Node         = nodes[naejEdge.ToIndex]
+		dir   EdgeDirection = naejEdge.Direction
+		attrs Attributes    = naejEdge.Attributes
+	)
+
+	if from == nil || to == nil {
+		return nil
+	}
+
+	if err := unmarshalAttributes(attrs); err != nil {
+		return err
+	}
+
+	if edge := implied.find(from, to, dir, name); edge != nil {
+		delete(implied, edge)
+		return nil
+	}
+
+	from.Edges = append(from.Edges, &Edge{
+		Name:       name,
+		Node:       to,
+		Direction:  dir,
+		Attributes: attrs,
+		from:       from,
+	})
+
+	other := &Edge{
+		Name:       name,
+		Node:       from,
+		Direction:  dir.Reverse(),
+		Attributes: attrs,
+		from:       to,
+	}
+
+	to.Edges = append(to.Edges, other)
+	implied[other] = true
+
+	return nil
+}
+
+// find returns the implied edge of the given node matching the given edge.
+func (implied impliedEdges) find(from, to *Node, dir EdgeDirection, name string) *Edge {
+	for _, edge := range from.Edges {
+		if implied[edge] && edge.Node == to && edge.Direction == dir && edge.Name == name {
+			return edge
+		}
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package graph_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/picatz/graph"
@@ -34,3 +36,141 @@ func TestEncodeDecode(t *testing.T) {
 
 	fmt.Println(nodes)
 }
+
+func TestEncodeJSON_directionGlyphs(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdgeWithDirection(b, graph.Out)
+	b.AddEdgeWithDirection(c, graph.None)
+
+	a.Edges[0].Attributes = graph.Attributes{"weight": 2}
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeJSON(buf, graph.Nodes{a, b, c}, graph.WithDirectionGlyphs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, label := range []string{`"label":"a → b"`, `"label":"b - c"`, `"weight":2`} {
+		if !strings.Contains(buf.String(), label) {
+			t.Fatalf("expected %s in:\n%s", label, buf.String())
+		}
+	}
+
+	nodes, err := graph.DecodeJSON(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := nodes[0].Edges[0].Attributes["weight"]; got != 2.0 {
+		t.Fatalf("expected weight 2, got %v", got)
+	}
+}
+
+func TestEncodeJSON_compression(t *testing.T) {
+	nodes := graph.Nodes{}
+	for i := 0; i < 100; i++ {
+		node := graph.NewNode(fmt.Sprintf("service-%d", i), graph.Attributes{"team": "platform"})
+		if i > 0 {
+			nodes[i-1].AddEdge(node)
+		}
+		nodes = append(nodes, node)
+	}
+
+	plain := bytes.NewBuffer(nil)
+	if err := graph.EncodeJSON(plain, nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	compressed := bytes.NewBuffer(nil)
+	if err := graph.EncodeJSON(compressed, nodes, graph.WithCompression(gzip.BestCompression)); err != nil {
+		t.Fatal(err)
+	}
+
+	if compressed.Len() >= plain.Len()/2 {
+		t.Fatalf("expected the compressed encoding to be much smaller, got %d bytes, %d uncompressed", compressed.Len(), plain.Len())
+	}
+
+	decoded, err := graph.DecodeJSON(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(nodes) || decoded[99].Name != "service-99" || decoded[0].Attributes["team"] != "platform" {
+		t.Fatalf("unexpected decoded nodes: %v", decoded)
+	}
+
+	if got := graph.New("decoded", graph.WithNodes(decoded)).EdgeCount(); got != 99 {
+		t.Fatalf("expected 99 edges, got %d", got)
+	}
+
+	if err := graph.EncodeJSON(bytes.NewBuffer(nil), nodes, graph.WithCompression(42)); err == nil {
+		t.Fatal("expected an error for an invalid compression level")
+	}
+}
+
+func TestDecodeJSONFilter(t *testing.T) {
+	var (
+		api     = graph.NewNode("payments/api", graph.Attributes{"tier": 1})
+		ledger  = graph.NewNode("payments/ledger", nil)
+		auth    = graph.NewNode("identity/auth", nil)
+		reports = graph.NewNode("payments/reports", nil)
+	)
+
+	// payments/api → payments/ledger → payments/reports
+	//       ↓
+	// identity/auth
+
+	api.AddEdgeTyped(ledger, "writes")
+	api.AddEdge(auth)
+	ledger.AddEdge(reports)
+
+	buf := bytes.NewBuffer(nil)
+	if err := graph.EncodeJSON(buf, graph.Nodes{api, auth, ledger, reports}, graph.WithCompression(gzip.DefaultCompression)); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := graph.DecodeJSONFilter(buf, func(n *graph.Node) bool {
+		return strings.HasPrefix(n.Name, "payments/") && n.Name != "payments/reports"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := nodes.String(); got != "payments/api, payments/ledger" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+
+	g := graph.New("payments", graph.WithNodes(nodes))
+
+	if got := g.Edges().String(); got != "payments/api → payments/ledger" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	if nodes[0].Attributes["tier"] != 1.0 || g.Edges()[0].Name != "writes" {
+		t.Fatalf("expected attributes and edge names to be decoded")
+	}
+
+	// Edges encoded before the nodes are added once the nodes are decoded.
+	nodes, err = graph.DecodeJSONFilter(strings.NewReader(`{
+		"edges": [{"from_index": 0, "direction": 3, "to_index": 1}],
+		"version": 1,
+		"nodes": [{"name": "a"}, {"name": "b"}]
+	}`), func(*graph.Node) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := graph.New("test", graph.WithNodes(nodes)).Edges().String(); got != "a → b" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	if _, err := graph.DecodeJSONFilter(strings.NewReader(`[]`), func(*graph.Node) bool { return true }); err == nil {
+		t.Fatal("expected an error for JSON that isn't an object")
+	}
+}
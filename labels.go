@@ -0,0 +1,64 @@
+package graph
+
+// HasLabel checks if the node has the given label.
+func (n *Node) HasLabel(label string) bool {
+	for _, l := range n.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// AddLabel adds the given labels to the node, skipping any it already has.
+func (n *Node) AddLabel(labels ...string) {
+	n.touch()
+
+	for _, label := range labels {
+		if !n.HasLabel(label) {
+			n.Labels = append(n.Labels, label)
+		}
+	}
+}
+
+// RemoveLabel removes the given label from the node.
+func (n *Node) RemoveLabel(label string) {
+	n.touch()
+
+	labels := n.Labels[:0]
+	for _, l := range n.Labels {
+		if l != label {
+			labels = append(labels, l)
+		}
+	}
+	n.Labels = labels
+}
+
+// NodesWithLabel returns the nodes in the graph that have all of the given
+// labels, like the (n:Person:Employee) pattern in Cypher.
+func (inst *Instance) NodesWithLabel(labels ...string) Nodes {
+	nodes := Nodes{}
+	for _, node := range inst.Nodes {
+		if node.hasLabels(labels) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// hasLabels checks if the node has all of the given labels.
+func (n *Node) hasLabels(labels []string) bool {
+	for _, label := range labels {
+		if !n.HasLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasLabel keeps only the nodes with all of the given labels.
+func (t *Traversal) HasLabel(labels ...string) *Traversal {
+	return t.Where(func(n *Node) bool {
+		return n.hasLabels(labels)
+	})
+}
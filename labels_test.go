@@ -0,0 +1,93 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestNode_Labels(t *testing.T) {
+	var (
+		alice = graph.NewNode("alice", nil)
+		bob   = graph.NewNode("bob", nil)
+		acme  = graph.NewNode("acme", nil)
+	)
+
+	alice.AddLabel("Person", "Employee")
+	bob.AddLabel("Person", "Person")
+	acme.AddLabel("Company")
+
+	alice.AddEdgeTyped(acme, "works_at")
+	bob.AddEdgeTyped(alice, "knows")
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(alice, bob, acme)))
+
+	if len(bob.Labels) != 1 || !bob.HasLabel("Person") || bob.HasLabel("Employee") {
+		t.Fatalf("unexpected labels for bob: %v", bob.Labels)
+	}
+
+	if people := g.NodesWithLabel("Person"); people.String() != "alice, bob" {
+		t.Fatalf("unexpected people: %v", people)
+	}
+
+	if employees := g.Traverse().HasLabel("Person", "Employee").Nodes(); employees.String() != "alice" {
+		t.Fatalf("unexpected employees: %v", employees)
+	}
+
+	result, err := g.Query("MATCH (p:Person)-->(:Company) RETURN p")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Rows) != 1 || result.Rows[0].String() != "alice" {
+		t.Fatalf("unexpected query result: %v", result.Rows)
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := graph.EncodeJSON(buf, g.Nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := graph.DecodeJSON(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(nodes[0].Labels, ":") != "Person:Employee" {
+		t.Fatalf("expected labels to be decoded from JSON, got: %v", nodes[0].Labels)
+	}
+
+	buf.Reset()
+
+	if err := g.EncodeDOT(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "\t\"alice\" [\"labels\"=\"Person:Employee\"]\n") {
+		t.Fatalf("expected labels in DOT output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := graph.EncodeYAML(buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := graph.DecodeYAML(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if people := decoded.NodesWithLabel("Person"); people.String() != "alice, bob" {
+		t.Fatalf("expected labels to be decoded from YAML, got: %v", people)
+	}
+
+	alice.RemoveLabel("Employee")
+
+	if alice.HasLabel("Employee") {
+		t.Fatalf("did not expect alice to still be an employee")
+	}
+}
@@ -0,0 +1,80 @@
+package graph
+
+// Layers assigns each node of the graph to a layer using longest-path
+// layering, and returns the nodes of each layer, in topological order.
+//
+// Nodes without dependencies are in the first layer, and every other node
+// is in the layer after the last layer of the nodes it depends on, so the
+// nodes of a layer only depend on nodes in earlier layers. This makes
+// layers useful for scheduling, where the nodes of each layer can run in
+// parallel once the previous layers are done, and for layered drawings.
+//
+//	a → b → c
+//	        ↑        [a, d] [b, e] [c]
+//	d → e ──┘
+//
+// Like TopologicalSort, edges that aren't inward edges are dependencies,
+// and an error is returned if the graph contains a cycle.
+//
+// https://en.wikipedia.org/wiki/Layered_graph_drawing#Layering
+func (inst *Instance) Layers() ([]Nodes, error) {
+	levels, err := inst.Levels()
+	if err != nil {
+		return nil, err
+	}
+
+	sorted, _ := inst.TopologicalSort()
+
+	var layers []Nodes
+	for _, node := range sorted {
+		level := levels[node]
+		for len(layers) <= level {
+			layers = append(layers, Nodes{})
+		}
+		layers[level] = append(layers[level], node)
+	}
+
+	return layers, nil
+}
+
+// Levels returns the layer of each node of the graph, see Layers, which is
+// the length of the longest path to the node from a node without
+// dependencies. An error is returned if the graph contains a cycle.
+func (inst *Instance) Levels() (map[*Node]int, error) {
+	sorted, err := inst.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+
+	members := inst.members()
+
+	levels := make(map[*Node]int, len(sorted))
+	for _, node := range sorted {
+		level := levels[node]
+		for _, next := range node.Edges.successors() {
+			if members.Contains(next) && levels[next] < level+1 {
+				levels[next] = level + 1
+			}
+		}
+		levels[node] = level
+	}
+
+	return levels, nil
+}
+
+// TopologicalGenerations returns the nodes of the graph grouped into
+// generations, or waves, where the nodes of each generation only depend on
+// nodes of earlier generations, which is the shape parallel build systems
+// need. Generations are the Layers of the graph.
+func (inst *Instance) TopologicalGenerations() ([][]*Node, error) {
+	layers, err := inst.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	generations := make([][]*Node, len(layers))
+	for i, layer := range layers {
+		generations[i] = layer
+	}
+	return generations, nil
+}
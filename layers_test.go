@@ -0,0 +1,121 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Layers(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c
+	// │       ↑
+	// └───────┤
+	// d → e ──┘
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	a.AddEdge(c)
+	d.AddEdge(e)
+	e.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	layers, err := g.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a, d", "b, e", "c"}
+
+	if len(layers) != len(expected) {
+		t.Fatalf("expected %d layers, got %d: %v", len(expected), len(layers), layers)
+	}
+
+	for i, layer := range layers {
+		if layer.String() != expected[i] {
+			t.Fatalf("layer %d: expected %s, got %s", i, expected[i], layer)
+		}
+	}
+
+	levels, err := g.Levels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for node, level := range map[*graph.Node]int{a: 0, b: 1, c: 2, d: 0, e: 1} {
+		if levels[node] != level {
+			t.Fatalf("expected %s at level %d, got %d", node.Name, level, levels[node])
+		}
+	}
+
+	c.AddEdge(a)
+
+	if _, err := g.Layers(); err == nil {
+		t.Fatal("expected an error for a graph with a cycle")
+	}
+
+	if layers, err := graph.New("empty").Layers(); err != nil || len(layers) != 0 {
+		t.Fatalf("expected no layers, got: %v, %v", layers, err)
+	}
+}
+
+func TestInstance_TopologicalGenerations(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// e → d → c
+	//         ↑
+	// b → a ──┘
+
+	e.AddEdge(d)
+	d.AddEdge(c)
+	b.AddEdge(a)
+	a.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	generations, err := g.TopologicalGenerations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layers, err := g.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Generations are the layers of the graph.
+	expected := make([]string, len(layers))
+	for i, layer := range layers {
+		expected[i] = layer.String()
+	}
+
+	if len(generations) != len(expected) {
+		t.Fatalf("expected %d generations, got %d: %v", len(expected), len(generations), generations)
+	}
+
+	for i, generation := range generations {
+		if got := graph.Nodes(generation).String(); got != expected[i] {
+			t.Fatalf("generation %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+
+	c.AddEdge(e)
+
+	if _, err := g.TopologicalGenerations(); err == nil {
+		t.Fatal("expected an error for a graph with a cycle")
+	}
+}
@@ -0,0 +1,278 @@
+package graph
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// LCAIndex answers lowest common ancestor queries for a directed acyclic
+// graph, such as a tree, where edges point from a parent to its children.
+//
+// For forests, where each node has at most one parent, building the index
+// records an Euler tour of each tree, along with a sparse table of the
+// shallowest node of ranges of the tour, taking O(n log n) time and memory,
+// so each query takes constant time (range minimum query). For other
+// acyclic graphs, the index records the parents and depth of each node,
+// and each query walks the ancestors of both nodes.
+//
+// https://en.wikipedia.org/wiki/Lowest_common_ancestor
+// https://en.wikipedia.org/wiki/Range_minimum_query
+type LCAIndex struct {
+	nodes Nodes
+	ids   map[*Node]int
+	depth []int
+
+	// parents are the parents of each node, for graphs that aren't forests.
+	parents [][]int
+
+	// tree is the root of the tree of each node, first is the position of
+	// the first visit of each node in the Euler tour, and sparse holds the
+	// shallowest node of the 2^k visits of the tour starting at each
+	// position, for forests.
+	tree   []int
+	first  []int
+	sparse [][]int
+}
+
+// NewLCAIndex returns a lowest common ancestor index for the given graph,
+// or an error if the graph contains a cycle.
+func NewLCAIndex(inst *Instance) (*LCAIndex, error) {
+	n := len(inst.Nodes)
+
+	idx := &LCAIndex{
+		nodes: inst.Nodes,
+		ids:   make(map[*Node]int, n),
+		depth: make([]int, n),
+	}
+
+	for i, node := range inst.Nodes {
+		idx.ids[node] = i
+	}
+
+	var (
+		parents  = make([][]int, n)
+		children = make([][]int, n)
+		forest   = true
+	)
+
+	for i, node := range inst.Nodes {
+		for _, edge := range node.Edges.Out() {
+			// Parallel edges are seen one after the other.
+			j, ok := idx.ids[edge.Node]
+			if !ok || (len(parents[j]) > 0 && parents[j][len(parents[j])-1] == i) {
+				continue
+			}
+			parents[j] = append(parents[j], i)
+			children[i] = append(children[i], j)
+			if len(parents[j]) > 1 {
+				forest = false
+			}
+		}
+	}
+
+	// Process the nodes in topological order using Kahn's algorithm, so
+	// the depth of each node is known before its children's.
+	remaining := make([]int, n)
+	queue := []int{}
+	for i := range inst.Nodes {
+		remaining[i] = len(parents[i])
+		if remaining[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	var processed int
+
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		processed++
+
+		for _, j := range children[i] {
+			if idx.depth[i]+1 > idx.depth[j] {
+				idx.depth[j] = idx.depth[i] + 1
+			}
+
+			remaining[j]--
+			if remaining[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+
+	if processed != n {
+		return nil, fmt.Errorf("graph contains a cycle, lowest common ancestors require an acyclic graph")
+	}
+
+	if !forest {
+		idx.parents = parents
+		return idx, nil
+	}
+
+	idx.tour(parents, children)
+
+	return idx, nil
+}
+
+// tour records the Euler tour of each tree of a forest with the given
+// parents and children of each node, and the sparse table of the tour.
+func (idx *LCAIndex) tour(parents, children [][]int) {
+	n := len(parents)
+
+	idx.tree = make([]int, n)
+	idx.first = make([]int, n)
+
+	euler := make([]int, 0, 2*n)
+
+	// frame is an explicit call stack entry, to avoid recursion on deep trees.
+	type frame struct {
+		node, child int
+	}
+
+	for root := range parents {
+		if len(parents[root]) > 0 {
+			continue
+		}
+
+		idx.tree[root], idx.first[root] = root, len(euler)
+		euler = append(euler, root)
+
+		stack := []frame{{node: root}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if top.child < len(children[top.node]) {
+				child := children[top.node][top.child]
+				top.child++
+
+				idx.tree[child], idx.first[child] = root, len(euler)
+				euler = append(euler, child)
+				stack = append(stack, frame{node: child})
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				euler = append(euler, stack[len(stack)-1].node)
+			}
+		}
+	}
+
+	idx.sparse = [][]int{euler}
+	for k := 1; 1<<k <= len(euler); k++ {
+		prev, half := idx.sparse[k-1], 1<<(k-1)
+		level := make([]int, len(euler)-1<<k+1)
+		for p := range level {
+			level[p] = idx.shallowest(prev[p], prev[p+half])
+		}
+		idx.sparse = append(idx.sparse, level)
+	}
+}
+
+// shallowest returns the shallowest of the given nodes.
+func (idx *LCAIndex) shallowest(i, j int) int {
+	if idx.depth[j] < idx.depth[i] {
+		return j
+	}
+	return i
+}
+
+// LCA returns the lowest common ancestor of the given nodes, which is the
+// deepest node that is an ancestor of both. A node is considered to be
+// its own ancestor.
+//
+// If multiple common ancestors share the same depth, which is possible
+// in graphs that aren't trees, the first one in the graph is returned.
+func (idx *LCAIndex) LCA(a, b *Node) (*Node, error) {
+	i, ok := idx.ids[a]
+	if !ok {
+		return nil, fmt.Errorf("graph lowest common ancestor node %q not found in graph", nodeName(a))
+	}
+
+	j, ok := idx.ids[b]
+	if !ok {
+		return nil, fmt.Errorf("graph lowest common ancestor node %q not found in graph", nodeName(b))
+	}
+
+	lowest := -1
+	if idx.parents != nil {
+		lowest = idx.walk(i, j)
+	} else if idx.tree[i] == idx.tree[j] {
+		lowest = idx.query(i, j)
+	}
+
+	if lowest == -1 {
+		return nil, fmt.Errorf("graph nodes %q and %q have no common ancestor", a.Name, b.Name)
+	}
+
+	return idx.nodes[lowest], nil
+}
+
+// query returns the lowest common ancestor of the given nodes of the same
+// tree, which is the shallowest node visited between their first visits in
+// the Euler tour.
+func (idx *LCAIndex) query(i, j int) int {
+	l, r := idx.first[i], idx.first[j]
+	if l > r {
+		l, r = r, l
+	}
+
+	k := bits.Len(uint(r-l+1)) - 1
+	return idx.shallowest(idx.sparse[k][l], idx.sparse[k][r-1<<k+1])
+}
+
+// walk returns the deepest common ancestor of the given nodes, and the first
+// one in the graph among those of the same depth, found by walking the
+// ancestors of both nodes, or -1 if they have none.
+func (idx *LCAIndex) walk(i, j int) int {
+	ancestors := idx.ancestors(i)
+
+	lowest := -1
+	for k := range idx.ancestors(j) {
+		if !ancestors[k] {
+			continue
+		}
+		if lowest == -1 || idx.depth[k] > idx.depth[lowest] || (idx.depth[k] == idx.depth[lowest] && k < lowest) {
+			lowest = k
+		}
+	}
+	return lowest
+}
+
+// ancestors returns the ancestors of the given node, including itself.
+func (idx *LCAIndex) ancestors(i int) map[int]bool {
+	ancestors := map[int]bool{i: true}
+
+	stack := []int{i}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, parent := range idx.parents[node] {
+			if !ancestors[parent] {
+				ancestors[parent] = true
+				stack = append(stack, parent)
+			}
+		}
+	}
+	return ancestors
+}
+
+// LCA returns the lowest common ancestor of the given nodes in the graph.
+//
+// To answer many queries for the same graph, use NewLCAIndex instead.
+func (inst *Instance) LCA(a, b *Node) (*Node, error) {
+	idx, err := NewLCAIndex(inst)
+	if err != nil {
+		return nil, err
+	}
+	return idx.LCA(a, b)
+}
+
+// nodeName returns the name of the given node, which may be nil.
+func nodeName(n *Node) string {
+	if n == nil {
+		return "<nil>"
+	}
+	return n.Name
+}
@@ -0,0 +1,154 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_LCA(t *testing.T) {
+	var (
+		ceo  = graph.NewNode("ceo", nil)
+		cto  = graph.NewNode("cto", nil)
+		cfo  = graph.NewNode("cfo", nil)
+		eng1 = graph.NewNode("eng1", nil)
+		eng2 = graph.NewNode("eng2", nil)
+		acct = graph.NewNode("acct", nil)
+	)
+
+	//         ceo
+	//       ↙     ↘
+	//    cto       cfo
+	//   ↙   ↘        ↘
+	// eng1  eng2     acct
+
+	ceo.AddEdge(cto)
+	ceo.AddEdge(cfo)
+	cto.AddEdge(eng1)
+	cto.AddEdge(eng2)
+	cfo.AddEdge(acct)
+
+	g := graph.New("org", graph.WithNodes(graph.NewNodes(ceo, cto, cfo, eng1, eng2, acct)))
+
+	idx, err := graph.NewLCAIndex(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		A, B     *graph.Node
+		Expected *graph.Node
+	}{
+		{eng1, eng2, cto},
+		{eng1, acct, ceo},
+		{eng1, cto, cto},
+		{acct, acct, acct},
+	}
+
+	for _, test := range tests {
+		lca, err := idx.LCA(test.A, test.B)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lca != test.Expected {
+			t.Errorf("LCA(%s, %s): expected %s, got %s", test.A.Name, test.B.Name, test.Expected.Name, lca.Name)
+		}
+	}
+
+	other := graph.NewNode("other", nil)
+	g.AddNode(other)
+
+	if _, err := g.LCA(other, eng1); err == nil {
+		t.Fatalf("expected error for nodes without a common ancestor")
+	}
+
+	eng1.AddEdge(ceo)
+
+	if _, err := g.LCA(eng1, eng2); err == nil {
+		t.Fatalf("expected error for cyclic graph")
+	}
+}
+
+func TestInstance_LCA_dag(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	//     a
+	//   ↙   ↘
+	//  b     c
+	//  ↓ ↘ ↙ ↓
+	//  d   e
+
+	a.AddEdge(b)
+	a.AddEdge(c)
+	b.AddEdge(d)
+	b.AddEdge(e)
+	c.AddEdge(e)
+
+	g := graph.New("dag", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	tests := []struct {
+		A, B     *graph.Node
+		Expected *graph.Node
+	}{
+		{d, e, b},
+		{c, d, a},
+		{e, c, c},
+	}
+
+	for _, test := range tests {
+		lca, err := g.LCA(test.A, test.B)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lca != test.Expected {
+			t.Errorf("LCA(%s, %s): expected %s, got %s", test.A.Name, test.B.Name, test.Expected.Name, lca.Name)
+		}
+	}
+}
+
+func TestNewLCAIndex_forest(t *testing.T) {
+	// Two chains deep enough to be too deep for recursion, 0 → 1 → … and
+	// x0 → x1 → …, with a branch off the middle of the first one.
+	g := graph.New("forest")
+
+	var first, second graph.Nodes
+	for i := 0; i < 100000; i++ {
+		first = append(first, graph.NewNode(fmt.Sprint(i), nil))
+		second = append(second, graph.NewNode(fmt.Sprintf("x%d", i), nil))
+	}
+	graph.ConnectNodes(first...)
+	graph.ConnectNodes(second...)
+
+	branch := graph.NewNode("branch", nil)
+	first[500].AddEdge(branch)
+
+	g.AddNodes(first...)
+	g.AddNodes(second...)
+	g.AddNode(branch)
+
+	idx, err := graph.NewLCAIndex(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lca, err := idx.LCA(first[99999], branch); err != nil || lca != first[500] {
+		t.Fatalf("expected the LCA to be 500, got %v, %v", lca, err)
+	}
+
+	if lca, err := idx.LCA(second[10], second[20]); err != nil || lca != second[10] {
+		t.Fatalf("expected the LCA to be x10, got %v, %v", lca, err)
+	}
+
+	if _, err := idx.LCA(first[1], second[1]); err == nil {
+		t.Fatal("expected an error for nodes of different trees")
+	}
+}
@@ -0,0 +1,123 @@
+package graph
+
+import "fmt"
+
+// Grow grows the capacity of the graph to fit at least the given number of
+// additional nodes and edges, avoiding repeated allocations when adding
+// them in bulk, like when using LoadEdges.
+//
+// Every relationship is made of two edges, one on each side, so the number
+// of edges is the number of relationships (like the pairs given to
+// LoadEdges), not the number of Edge values.
+func (inst *Instance) Grow(nodes, edges int) {
+	if nodes > 0 && cap(inst.Nodes)-len(inst.Nodes) < nodes {
+		grown := make(Nodes, len(inst.Nodes), len(inst.Nodes)+nodes)
+		copy(grown, inst.Nodes)
+		inst.Nodes = grown
+	}
+
+	if nodes > 0 && cap(inst.byID)-len(inst.byID) < nodes {
+		grown := make([]*Node, len(inst.byID), len(inst.byID)+nodes)
+		copy(grown, inst.byID)
+		inst.byID = grown
+	}
+
+	if edges > 0 && cap(inst.edgePool)-len(inst.edgePool) < 2*edges {
+		inst.edgePool = make([]Edge, 0, 2*edges)
+	}
+}
+
+// LoadEdges adds a directed edge to the graph for each pair of node names,
+// from the first node to the second, adding a node for each name that
+// isn't in the graph yet.
+//
+//	g.LoadEdges([][2]string{{"a", "b"}, {"b", "c"}})   a → b → c
+//
+// This is much faster than adding the nodes and edges one at a time when
+// importing large graphs: names are looked up using an index built once,
+// and nodes, edges, and adjacency lists are allocated up front. Existing
+// nodes are matched like Lookup. An error is returned, before anything is
+// added, if any name is empty.
+//
+// Edges are added like AddEdge, so they are reported to the subscribers of
+// the graph, see Subscribe, and are checked against the graph's edge
+// constraints like TryAddEdge: the first edge that would violate one of
+// them is returned as an error, leaving the new nodes and the edges before
+// it in the graph.
+func (inst *Instance) LoadEdges(pairs [][2]string) error {
+	for i, pair := range pairs {
+		if pair[0] == "" || pair[1] == "" {
+			return fmt.Errorf("graph cannot load edge %d with an empty node name: %q -> %q", i, pair[0], pair[1])
+		}
+	}
+
+	byName := inst.names
+	if byName == nil {
+		byName = make(map[string]*Node, len(inst.Nodes))
+		for _, node := range inst.Nodes {
+			if _, ok := byName[node.Name]; !ok {
+				byName[node.Name] = node
+			}
+		}
+	}
+
+	// Find the names of new nodes, and the degree of every node.
+	var added []string
+	degrees := map[string]int{}
+	for _, pair := range pairs {
+		for _, name := range pair {
+			if _, ok := byName[name]; !ok && degrees[name] == 0 {
+				added = append(added, name)
+			}
+			degrees[name]++
+		}
+	}
+
+	inst.Grow(len(added), len(pairs))
+
+	nodes := make([]Node, len(added))
+	for i, name := range added {
+		node := &nodes[i]
+		node.Name = name
+		node.Edges = make(Edges, 0, degrees[name])
+		byName[name] = node
+		inst.Nodes = append(inst.Nodes, node)
+		inst.adopt(node)
+	}
+
+	for name, degree := range degrees {
+		node := byName[name]
+		if cap(node.Edges)-len(node.Edges) < degree {
+			grown := make(Edges, len(node.Edges), len(node.Edges)+degree)
+			copy(grown, node.Edges)
+			node.Edges = grown
+		}
+	}
+
+	for i, pair := range pairs {
+		from, to := byName[pair[0]], byName[pair[1]]
+
+		if err := inst.checkEdgeConstraints(from, to, Out); err != nil {
+			return fmt.Errorf("graph cannot load edge %d: %w", i, err)
+		}
+
+		out, in := inst.newEdge(), inst.newEdge()
+		*out = Edge{Node: to, Direction: Out, from: from}
+		*in = Edge{Node: from, Direction: In, from: to}
+
+		from.addEdge(to, out, in)
+	}
+
+	return nil
+}
+
+// newEdge returns a new edge, allocated from the pool of edges grown using
+// Grow if it isn't empty.
+func (inst *Instance) newEdge() *Edge {
+	if len(inst.edgePool) == cap(inst.edgePool) {
+		return &Edge{}
+	}
+
+	inst.edgePool = inst.edgePool[:len(inst.edgePool)+1]
+	return &inst.edgePool[len(inst.edgePool)-1]
+}
@@ -0,0 +1,87 @@
+package graph_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_LoadEdges(t *testing.T) {
+	a := graph.NewNode("a", nil)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a)))
+
+	g.Grow(3, 3)
+
+	if cap(g.Nodes) < 4 {
+		t.Fatalf("expected capacity for 4 nodes, got %d", cap(g.Nodes))
+	}
+
+	if _, err := g.TopologicalSort(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := g.LoadEdges([][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := g.Nodes.String(); got != "a, b, c" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+
+	if got := a.Edges.String(); got != "a → b, a ← c" {
+		t.Fatalf("unexpected edges of existing node: %s", got)
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fatal("expected loaded cycle to invalidate cached results")
+	}
+
+	if err := g.LoadEdges([][2]string{{"c", "d"}, {"d", ""}}); err == nil {
+		t.Fatal("expected an error for an empty name")
+	}
+
+	if len(g.Nodes) != 3 {
+		t.Fatalf("did not expect nodes to be added on error, got: %s", g.Nodes)
+	}
+}
+
+func TestInstance_LoadEdges_mutations(t *testing.T) {
+	g := graph.New("test", graph.WithConstraints(graph.Acyclic()))
+
+	mutations, cancel := g.Subscribe()
+	defer cancel()
+
+	if err := g.LoadEdges([][2]string{{"a", "b"}, {"b", "c"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var edges []string
+	for len(mutations) > 0 {
+		if m := <-mutations; m.Kind == graph.EdgeAdded {
+			edges = append(edges, m.Edge.String())
+		}
+	}
+	if got := strings.Join(edges, ", "); got != "a → b, b → c" {
+		t.Fatalf("unexpected edges reported: %s", got)
+	}
+
+	err := g.LoadEdges([][2]string{{"c", "d"}, {"d", "a"}})
+	if !errors.Is(err, graph.ErrConstraintViolated) {
+		t.Fatalf("expected a constraint violation, got %v", err)
+	}
+
+	if got := g.Nodes.String(); got != "a, b, c, d" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+	if edges := g.EdgeCount(); edges != 3 {
+		t.Fatalf("expected the edges before the violation to be loaded, got %d", edges)
+	}
+}
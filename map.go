@@ -0,0 +1,177 @@
+package graph
+
+import "reflect"
+
+// Map returns a transformed copy of the graph, with the node returned by the
+// given function for each node of the graph, in the order of the graph, and
+// the edges between them, so a graph can be converted from one schema to
+// another without rebuilding it by hand:
+//
+//	services := graph.Map(deps, func(n *graph.Node) *graph.Node {
+//		if n.Attributes["kind"] != "service" {
+//			return nil
+//		}
+//		return graph.NewNode(strings.ToUpper(n.Name), graph.Attributes{
+//			"team": n.Attributes["owner"],
+//		})
+//	})
+//
+// The function is given a copy of each node, like Clone, which it can change
+// and return, or it can return a new node. Returning nil drops the node, and
+// its edges, from the copy. Returning the same node for several nodes merges
+// them into it, with the edges of all of them, except the edges between
+// them, like contracting them.
+//
+// The edges of the returned nodes are replaced with copies of the edges of
+// the nodes they were returned for, between nodes of the copy, keeping their
+// names, directions, and attributes. Edges to nodes outside of the graph
+// are dropped. Subgraphs and hyperedges are kept with
+// the returned nodes.
+func Map(inst *Instance, fn func(*Node) *Node) *Instance {
+	clone := inst.Clone()
+
+	mapped := make(map[*Node]*Node, len(clone.Nodes))
+	edges := make(map[*Node]Edges, len(clone.Nodes))
+
+	var nodes Nodes
+	seen := NodeSet{}
+
+	for _, node := range clone.Nodes {
+		edges[node] = node.Edges
+
+		m := fn(node)
+		if m == nil {
+			continue
+		}
+
+		mapped[node] = m
+		if !seen.Contains(m) {
+			seen.Add(m)
+			nodes = append(nodes, m)
+		}
+	}
+
+	for _, m := range nodes {
+		m.Edges = nil
+	}
+
+	for _, node := range clone.Nodes {
+		from, ok := mapped[node]
+		if !ok {
+			continue
+		}
+		for _, edge := range edges[node] {
+			to, ok := mapped[edge.Node]
+			if !ok || (to == from && edge.Node != node) {
+				continue
+			}
+			from.Edges = append(from.Edges, &Edge{
+				Name:       edge.Name,
+				Node:       to,
+				Direction:  edge.Direction,
+				Attributes: edge.Attributes,
+				from:       from,
+			})
+		}
+	}
+
+	result := New(inst.Name)
+	result.Attributes = clone.Attributes
+	result.NodeDefaults = clone.NodeDefaults
+	result.EdgeDefaults = clone.EdgeDefaults
+	result.tracer = inst.tracer
+
+	for name := range inst.indexes {
+		result.CreateIndex(name)
+	}
+
+	if inst.names != nil {
+		result.names = map[string]*Node{}
+	}
+
+	result.AddNodes(nodes...)
+
+	for _, h := range clone.hyperEdges {
+		var members Nodes
+		in := NodeSet{}
+		for _, node := range h.Nodes {
+			if m, ok := mapped[node]; ok && !in.Contains(m) {
+				in.Add(m)
+				members = append(members, m)
+			}
+		}
+		if len(members) > 0 {
+			result.AddHyperEdge(h.Name, members...).Attributes = h.Attributes
+		}
+	}
+
+	for _, sub := range clone.subs {
+		s := result.AddSub(sub.Name)
+		s.Attributes = sub.Attributes
+		for _, node := range sub.nodes {
+			if m, ok := mapped[node]; ok {
+				s.AddNode(m)
+			}
+		}
+	}
+
+	return result
+}
+
+// MapAttributes returns a copy of the graph, like Clone, with the attributes
+// of its nodes and edges replaced by those returned by the given function,
+// to rename, convert, or drop attributes:
+//
+//	renamed := graph.MapAttributes(g, func(attrs graph.Attributes) graph.Attributes {
+//		attrs["owner"] = attrs["team"]
+//		delete(attrs, "team")
+//		return attrs
+//	})
+//
+// The function is given a copy of each attribute map of the nodes and edges,
+// and edges that shared a map, like the two edges of a weighted relationship,
+// share the returned one. Nodes and edges without attributes are given an
+// empty map, and keep not having attributes if an empty map is returned.
+func MapAttributes(inst *Instance, fn func(Attributes) Attributes) *Instance {
+	clone := inst.Clone()
+
+	apply := func(attrs Attributes) Attributes {
+		if attrs == nil {
+			if attrs = fn(Attributes{}); len(attrs) == 0 {
+				return nil
+			}
+			return attrs
+		}
+		return fn(attrs)
+	}
+
+	// The clone's edges share copied attribute maps like the graph's, so
+	// each map is only given to the function once.
+	done := map[uintptr]Attributes{}
+
+	for _, node := range clone.Nodes {
+		node.Attributes = apply(node.Attributes)
+
+		for _, edge := range node.Edges {
+			if edge.Attributes == nil {
+				edge.Attributes = apply(nil)
+				continue
+			}
+
+			ptr := reflect.ValueOf(edge.Attributes).Pointer()
+			if mapped, ok := done[ptr]; ok {
+				edge.Attributes = mapped
+				continue
+			}
+			mapped := apply(edge.Attributes)
+			done[ptr] = mapped
+			edge.Attributes = mapped
+		}
+	}
+
+	for name := range clone.indexes {
+		clone.CreateIndex(name)
+	}
+
+	return clone
+}
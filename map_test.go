@@ -0,0 +1,121 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestMap(t *testing.T) {
+	var (
+		api    = graph.NewNode("api", graph.Attributes{"kind": "service", "owner": "web"})
+		worker = graph.NewNode("worker", graph.Attributes{"kind": "service", "owner": "jobs"})
+		db     = graph.NewNode("db", graph.Attributes{"kind": "database"})
+		queue  = graph.NewNode("queue", graph.Attributes{"kind": "service", "owner": "jobs"})
+	)
+
+	api.AddEdgeWithDirection(db, graph.Out)
+	api.AddEdgeWithDirection(queue, graph.Out)
+	queue.AddEdgeWithDirection(worker, graph.Out)
+	worker.AddEdgeWithDirection(db, graph.Out)
+
+	api.Edges[1].Attributes = graph.Attributes{"weight": 2}
+
+	g := graph.New("deps", graph.WithNodes(graph.Nodes{api, worker, db, queue}))
+
+	services := graph.Map(g, func(n *graph.Node) *graph.Node {
+		if n.Attributes["kind"] != "service" {
+			return nil
+		}
+		n.Name = strings.ToUpper(n.Name)
+		return n
+	})
+
+	if got := services.Nodes.String(); got != "API, WORKER, QUEUE" {
+		t.Fatalf("unexpected nodes %s", got)
+	}
+
+	if got := services.Edges().String(); got != "API → QUEUE, QUEUE → WORKER" {
+		t.Fatalf("unexpected edges %s", got)
+	}
+
+	if w := services.Nodes[0].Edges[0].Weight(); w != 2 {
+		t.Fatalf("expected edge weight to be kept, got %v", w)
+	}
+
+	if g.Nodes.String() != "api, worker, db, queue" || len(db.Edges) != 2 {
+		t.Fatal("expected the graph to be unchanged")
+	}
+
+	if err := graph.CheckInvariants(services); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nodes mapped to the same node are merged, without the edges between
+	// them.
+	teams := map[string]*graph.Node{}
+	owners := graph.Map(g, func(n *graph.Node) *graph.Node {
+		owner, ok := n.Attributes["owner"].(string)
+		if !ok {
+			return nil
+		}
+		if teams[owner] == nil {
+			teams[owner] = graph.NewNode(owner, nil)
+		}
+		return teams[owner]
+	})
+
+	if got := owners.Nodes.String(); got != "web, jobs" {
+		t.Fatalf("unexpected nodes %s", got)
+	}
+
+	if got := owners.Edges().String(); got != "web → jobs" {
+		t.Fatalf("unexpected edges %s", got)
+	}
+}
+
+func TestMapAttributes(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"team": "web"})
+		b = graph.NewNode("b", nil)
+	)
+
+	a.AddEdgeWithDirection(b, graph.Out)
+	a.Edges[0].Attributes = graph.Attributes{"team": "db"}
+	b.Edges[0].Attributes = a.Edges[0].Attributes
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b}))
+
+	calls := 0
+	renamed := graph.MapAttributes(g, func(attrs graph.Attributes) graph.Attributes {
+		calls++
+		if team, ok := attrs["team"]; ok {
+			attrs["owner"] = team
+			delete(attrs, "team")
+		}
+		return attrs
+	})
+
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, for a, b, and the shared edge attributes, got %d", calls)
+	}
+
+	ra, rb := renamed.Nodes[0], renamed.Nodes[1]
+
+	if ra.Attributes["owner"] != "web" || ra.Attributes["team"] != nil {
+		t.Fatalf("unexpected attributes %v", ra.Attributes)
+	}
+
+	if rb.Attributes != nil {
+		t.Fatalf("expected no attributes, got %v", rb.Attributes)
+	}
+
+	if ra.Edges[0].Attributes["owner"] != "db" || rb.Edges[0].Attributes["owner"] != "db" {
+		t.Fatalf("unexpected edge attributes %v", ra.Edges[0].Attributes)
+	}
+
+	if a.Attributes["team"] != "web" || a.Edges[0].Attributes["team"] != "db" {
+		t.Fatal("expected the graph to be unchanged")
+	}
+}
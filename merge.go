@@ -0,0 +1,134 @@
+package graph
+
+import "fmt"
+
+// MergeNodes merges the remove node into the keep node, rewiring all of the
+// inward and outward edges of the removed node to the kept node, and then
+// removes it from the graph.
+//
+// Edges between the two nodes are dropped instead of becoming self-loops,
+// and edges the kept node already has (same node, direction, and name) are
+// not duplicated.
+//
+// The given function decides the attributes of the kept node. If it is nil,
+// the kept node's attributes are used, with any missing keys filled in using
+// the attributes of the removed node. The kept node also gains the labels
+// of the removed node.
+//
+//	a → keep → c       a → keep → c
+//	      ↑        ⇒     ↗
+//	b → remove         b
+func (inst *Instance) MergeNodes(keep, remove *Node, mergeAttrs func(keep, remove Attributes) Attributes) error {
+	if keep == nil || remove == nil {
+		return fmt.Errorf("graph cannot merge nil nodes")
+	}
+
+	if keep == remove {
+		return fmt.Errorf("graph cannot merge node %q with itself", keep.Name)
+	}
+
+	for _, node := range []*Node{keep, remove} {
+		if inst.Nodes.IndexOf(node) == -1 {
+			return fmt.Errorf("graph cannot merge node %q that is not in the graph", node.Name)
+		}
+	}
+
+	// moved are the edges of the removed node moved to the kept node, which
+	// are reported as added to it, before the removed node is removed.
+	moved := map[*Edge]bool{}
+
+	for _, edge := range remove.Edges {
+		switch edge.Node {
+		case remove:
+			// A self-loop on the removed node becomes one on the kept node.
+			edge.Node = keep
+			edge.from = keep
+			keep.Edges = append(keep.Edges, edge)
+			moved[edge] = true
+		case keep:
+			// Edges between the two nodes are dropped below.
+		default:
+			other := edge.reciprocal(remove)
+
+			edge.Node.touch()
+
+			if keep.Edges.find(edge.Node, edge.Direction, edge.Name) != nil {
+				if other != nil {
+					edge.Node.Edges = edge.Node.Edges.without(other)
+				}
+				continue
+			}
+
+			if other != nil {
+				other.Node = keep
+			}
+
+			edge.from = keep
+			keep.Edges = append(keep.Edges, edge)
+			moved[edge] = true
+		}
+	}
+
+	keep.Edges = keep.Edges.ButNotWith(remove)
+	keep.touch()
+	remove.Edges = nil
+
+	inst.publishEdges(Nodes{keep}, false, func(edge *Edge) bool {
+		return moved[edge]
+	})
+
+	before := copyAttributes(keep.Attributes)
+
+	if mergeAttrs != nil {
+		keep.Attributes = mergeAttrs(keep.Attributes, remove.Attributes)
+	} else {
+		for key, value := range remove.Attributes {
+			if _, ok := keep.Attributes[key]; ok {
+				continue
+			}
+			if keep.Attributes == nil {
+				keep.Attributes = Attributes{}
+			}
+			keep.Attributes[key] = value
+		}
+	}
+
+	keep.publishAttributeChanges(before)
+
+	for _, label := range remove.Labels {
+		keep.AddLabel(label)
+	}
+
+	inst.RemoveNode(remove)
+
+	return nil
+}
+
+// ContractEdge contracts the given edge, merging the edge's node into the
+// node the edge belongs to using MergeNodes.
+//
+//	a → b → c   ⇒   a → b   (contracting b → c, merging c into b)
+//
+// https://en.wikipedia.org/wiki/Edge_contraction
+func (inst *Instance) ContractEdge(e *Edge) error {
+	if e == nil {
+		return fmt.Errorf("graph cannot contract nil edge")
+	}
+
+	from := e.From()
+	if from == nil {
+		for _, node := range inst.Nodes {
+			for _, edge := range node.Edges {
+				if edge == e {
+					from = node
+				}
+			}
+		}
+	}
+
+	if from == nil {
+		return fmt.Errorf("graph cannot contract edge to %q that is not in the graph", nodeName(e.Node))
+	}
+
+	return inst.MergeNodes(from, e.Node, nil)
+}
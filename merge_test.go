@@ -0,0 +1,90 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_MergeNodes(t *testing.T) {
+	var (
+		a      = graph.NewNode("a", nil)
+		b      = graph.NewNode("b", nil)
+		c      = graph.NewNode("c", nil)
+		keep   = graph.NewNode("keep", graph.Attributes{"team": "x"})
+		remove = graph.NewNode("remove", graph.Attributes{"team": "y", "tier": 1})
+	)
+
+	// a → keep → c
+	//       ↑
+	// b → remove → c
+
+	a.AddEdge(keep)
+	keep.AddEdge(c)
+	remove.AddEdge(keep)
+	b.AddEdge(remove)
+	remove.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, keep, remove)))
+
+	err := g.MergeNodes(keep, remove, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(g.Nodes) != 4 || g.Nodes.IndexOf(remove) != -1 {
+		t.Fatalf("expected removed node to be gone: %v", g.Nodes)
+	}
+
+	if !b.HasPath(keep) || !keep.HasPath(c) {
+		t.Fatalf("expected edges to be rewired to kept node")
+	}
+
+	if len(keep.Edges) != 3 {
+		t.Fatalf("expected 3 edges on kept node, got %d", len(keep.Edges))
+	}
+
+	if len(c.Edges) != 1 || len(b.Edges) != 1 {
+		t.Fatalf("expected reciprocal edges to be rewired without duplicates")
+	}
+
+	if keep.HasCycles() {
+		t.Fatalf("did not expect merging to create a self-loop")
+	}
+
+	if keep.Attributes["team"] != "x" || keep.Attributes["tier"] != 1 {
+		t.Fatalf("unexpected merged attributes: %v", keep.Attributes)
+	}
+
+	if err := g.MergeNodes(keep, remove, nil); err == nil {
+		t.Fatalf("expected error merging a node no longer in the graph")
+	}
+}
+
+func TestInstance_ContractEdge(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a → b → c → d
+
+	graph.ConnectNodes(a, b, c, d)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	err := g.ContractEdge(b.Edges.Out()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path := a.PathTo(d); path.String() != "a → b → d" {
+		t.Fatalf("unexpected path after contraction: %v", path)
+	}
+
+	if len(d.Edges) != 1 || d.Edges[0].Node != b {
+		t.Fatalf("expected d's inward edge to be rewired to b")
+	}
+}
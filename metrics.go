@@ -0,0 +1,97 @@
+package graph
+
+import "math"
+
+// Density returns the fraction of possible edges between the nodes of the
+// graph that exist, from 0 for a graph without edges to 1 for a complete
+// graph. Edges to nodes outside of the graph, edges from a node to itself,
+// and parallel edges are ignored. Graphs with fewer than two nodes have a
+// density of 0.
+//
+// Each node can have an edge to every other node, so undirected (None) and
+// bi-directional (Both) edges count as an edge in each direction, which
+// gives the same density as treating a fully undirected graph as such.
+//
+//	a → b → c    Density: 2 / 6 = 0.33
+//	a ── b ── c  Density: 4 / 6 = 0.67
+//
+// https://en.wikipedia.org/wiki/Dense_graph
+func (inst *Instance) Density() float64 {
+	n := len(inst.Nodes)
+	if n < 2 {
+		return 0
+	}
+
+	members := inst.members()
+
+	var arcs int
+	for _, node := range inst.Nodes {
+		successors := NodeSet{}
+		for _, next := range node.Edges.successors() {
+			if next != node && members.Contains(next) {
+				successors.Add(next)
+			}
+		}
+		arcs += len(successors)
+	}
+
+	return float64(arcs) / float64(n*(n-1))
+}
+
+// DegreeAssortativity returns the degree assortativity coefficient of the
+// graph, the Pearson correlation between the degrees of the nodes on either
+// side of each edge, ignoring edge directions. Positive values mean nodes
+// tend to be adjacent to nodes with a similar degree, and negative values
+// mean high degree nodes tend to be adjacent to low degree nodes, like the
+// center of a star.
+//
+// The degree of a node is its number of distinct neighbors in the graph,
+// not counting itself. If every edge joins nodes with the same degrees,
+// like in a graph without edges or a cycle, the coefficient is undefined
+// and NaN is returned.
+//
+// https://en.wikipedia.org/wiki/Assortativity
+func (inst *Instance) DegreeAssortativity() float64 {
+	neighbors := undirectedNeighbors(inst.Nodes)
+
+	degree := make(map[*Node]float64, len(inst.Nodes))
+	for node, adjacent := range neighbors {
+		degree[node] = float64(len(adjacent))
+		if adjacent.Contains(node) {
+			degree[node]--
+		}
+	}
+
+	// Each edge is counted from both of its sides, so the degrees on
+	// either side have the same mean and variance.
+	var (
+		count             float64
+		sum, sumSq, sumXY float64
+	)
+
+	for _, node := range inst.Nodes {
+		for other := range neighbors[node] {
+			if other == node {
+				continue
+			}
+			x, y := degree[node], degree[other]
+			count++
+			sum += x
+			sumSq += x * x
+			sumXY += x * y
+		}
+	}
+
+	if count == 0 {
+		return math.NaN()
+	}
+
+	mean := sum / count
+	variance := sumSq/count - mean*mean
+
+	if variance <= 0 {
+		return math.NaN()
+	}
+
+	return (sumXY/count - mean*mean) / variance
+}
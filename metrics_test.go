@@ -0,0 +1,96 @@
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Density(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	if density := g.Density(); density != 0 {
+		t.Fatalf("expected a density of 0 without edges, got %v", density)
+	}
+
+	graph.ConnectNodes(a, b, c)
+
+	if density := g.Density(); math.Abs(density-1.0/3.0) > 1e-9 {
+		t.Fatalf("expected a density of 1/3, got %v", density)
+	}
+
+	// Parallel edges and edges to itself are ignored.
+	a.AddEdge(b)
+	a.AddEdge(a)
+
+	if density := g.Density(); math.Abs(density-1.0/3.0) > 1e-9 {
+		t.Fatalf("expected a density of 1/3, got %v", density)
+	}
+
+	m := graph.New("mesh", graph.WithNodes(graph.NewNodes(
+		graph.NewNode("x", nil),
+		graph.NewNode("y", nil),
+		graph.NewNode("z", nil),
+	)))
+
+	graph.MeshNodes(m.Nodes...)
+
+	if density := m.Density(); density != 1 {
+		t.Fatalf("expected a density of 1 for a complete graph, got %v", density)
+	}
+}
+
+func TestInstance_DegreeAssortativity(t *testing.T) {
+	var (
+		hub    = graph.NewNode("hub", nil)
+		spokes = graph.NewNodes(
+			graph.NewNode("a", nil),
+			graph.NewNode("b", nil),
+			graph.NewNode("c", nil),
+		)
+	)
+
+	for _, spoke := range spokes {
+		hub.AddLink(spoke)
+	}
+
+	star := graph.New("star", graph.WithNodes(append(graph.Nodes{hub}, spokes...)))
+
+	if r := star.DegreeAssortativity(); math.Abs(r+1) > 1e-9 {
+		t.Fatalf("expected a star to be perfectly disassortative, got %v", r)
+	}
+
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a ── b ── c ── d
+
+	a.AddLink(b)
+	b.AddLink(c)
+	c.AddLink(d)
+
+	line := graph.New("line", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	if r := line.DegreeAssortativity(); math.Abs(r+0.5) > 1e-9 {
+		t.Fatalf("expected an assortativity of -0.5, got %v", r)
+	}
+
+	c.AddLink(a)
+	d.AddLink(a)
+	d.AddLink(b)
+
+	if r := line.DegreeAssortativity(); !math.IsNaN(r) {
+		t.Fatalf("expected an undefined assortativity for a complete graph, got %v", r)
+	}
+}
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// JSONVersion is the version of the JSON encoding written by EncodeJSON,
+// which is incremented when the schema of the encoding changes.
+//
+// Encodings without a version, written before it was added, are version 0.
+// DecodeJSON upgrades the nodes and edges of encodings of older versions
+// using the migrations registered with RegisterJSONMigration, and decodes
+// encodings of newer versions as well as it can, ignoring the fields it
+// doesn't know.
+const JSONVersion = 1
+
+// JSONMigration upgrades the nodes and edges of JSON encodings of a version
+// to the next version, see RegisterJSONMigration.
+//
+// Nodes and edges are given as generic JSON objects, with numbers as
+// json.Number values, so fields that were renamed, moved, or removed can
+// be migrated. Either function can be nil if nodes or edges didn't change.
+type JSONMigration struct {
+	Node func(node map[string]any) error
+	Edge func(edge map[string]any) error
+}
+
+// node returns the function migrating nodes.
+func (m JSONMigration) node() func(map[string]any) error { return m.Node }
+
+// edge returns the function migrating edges.
+func (m JSONMigration) edge() func(map[string]any) error { return m.Edge }
+
+// jsonMigrations is the registry of migrations by the version they
+// upgrade from.
+var jsonMigrations = struct {
+	sync.RWMutex
+	byVersion map[int]JSONMigration
+}{
+	byVersion: map[int]JSONMigration{},
+}
+
+// RegisterJSONMigration registers the migration of JSON encodings from the
+// given version to the next one, which DecodeJSON and DecodeJSONFilter apply
+// to the nodes and edges of encodings of that version, and older ones, so
+// graphs encoded before the schema changed keep loading. Like an edge
+// weight that used to be encoded as its own field:
+//
+//	graph.RegisterJSONMigration(0, graph.JSONMigration{
+//		Edge: func(edge map[string]any) error {
+//			if weight, ok := edge["weight"]; ok {
+//				attrs, _ := edge["attributes"].(map[string]any)
+//				if attrs == nil {
+//					attrs = map[string]any{}
+//				}
+//				attrs[graph.WeightAttribute] = weight
+//				edge["attributes"] = attrs
+//				delete(edge, "weight")
+//			}
+//			return nil
+//		},
+//	})
+//
+// Migrations are applied in order, from the version of the encoding up to
+// JSONVersion. Registering a migration for a version again replaces it.
+func RegisterJSONMigration(from int, migration JSONMigration) {
+	jsonMigrations.Lock()
+	defer jsonMigrations.Unlock()
+
+	jsonMigrations.byVersion[from] = migration
+}
+
+// jsonMigrationsFrom returns the registered migrations upgrading encodings
+// of the given version to JSONVersion, in the order they are applied.
+func jsonMigrationsFrom(version int) []JSONMigration {
+	jsonMigrations.RLock()
+	defer jsonMigrations.RUnlock()
+
+	var migrations []JSONMigration
+	for v := version; v < JSONVersion; v++ {
+		if migration, ok := jsonMigrations.byVersion[v]; ok && (migration.Node != nil || migration.Edge != nil) {
+			migrations = append(migrations, migration)
+		}
+	}
+	return migrations
+}
+
+// decodeJSONElement decodes an element using the given function, like the
+// Decode method of a decoder, into the given value, after upgrading it using
+// the function of each of the given migrations, if any, returned by the
+// given method, like JSONMigration.node.
+func decodeJSONElement(decode func(any) error, v any, migrations []JSONMigration, method func(JSONMigration) func(map[string]any) error) error {
+	if len(migrations) == 0 {
+		return decode(v)
+	}
+
+	var raw json.RawMessage
+	if err := decode(&raw); err != nil {
+		return err
+	}
+
+	elem := map[string]any{}
+
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	if err := d.Decode(&elem); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migrate := method(migration); migrate != nil {
+			if err := migrate(elem); err != nil {
+				return fmt.Errorf("migration failed: %w", err)
+			}
+		}
+	}
+
+	b, err := json.Marshal(elem)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,112 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestRegisterJSONMigration(t *testing.T) {
+	graph.RegisterJSONMigration(0, graph.JSONMigration{
+		Edge: func(edge map[string]any) error {
+			if weight, ok := edge["weight"]; ok {
+				attrs, _ := edge["attributes"].(map[string]any)
+				if attrs == nil {
+					attrs = map[string]any{}
+				}
+				attrs[graph.WeightAttribute] = weight
+				edge["attributes"] = attrs
+				delete(edge, "weight")
+			}
+			return nil
+		},
+	})
+	t.Cleanup(func() {
+		graph.RegisterJSONMigration(0, graph.JSONMigration{})
+	})
+
+	// Encodings without a version are migrated from version 0.
+	nodes, err := graph.DecodeJSON(strings.NewReader(`{
+		"nodes": [{"name": "a"}, {"name": "b"}],
+		"edges": [{"from_index": 0, "direction": 3, "to_index": 1, "weight": 2.5}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if weight := nodes[0].Edges[0].Weight(); weight != 2.5 {
+		t.Fatalf("expected the weight to be migrated, got %v", weight)
+	}
+
+	// Encodings of the current version aren't migrated.
+	nodes, err = graph.DecodeJSON(strings.NewReader(`{
+		"version": 1,
+		"nodes": [{"name": "a"}, {"name": "b"}],
+		"edges": [{"from_index": 0, "direction": 3, "to_index": 1, "weight": 2.5}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if weight := nodes[0].Edges[0].Weight(); weight != 1 {
+		t.Fatalf("expected the weight not to be migrated, got %v", weight)
+	}
+
+	// The version can come after the nodes and edges, like with sorted keys.
+	nodes, err = graph.DecodeJSON(strings.NewReader(`{
+		"edges": [{"direction": 3, "from_index": 0, "to_index": 1, "weight": 2.5}],
+		"nodes": [{"name": "a"}, {"name": "b"}],
+		"version": 1
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if weight := nodes[0].Edges[0].Weight(); weight != 1 {
+		t.Fatalf("expected the weight not to be migrated with a trailing version, got %v", weight)
+	}
+}
+
+func TestDecodeJSON_versions(t *testing.T) {
+	a := graph.NewNode("a", nil)
+	a.AddEdge(graph.NewNode("b", nil))
+
+	buf := bytes.NewBuffer(nil)
+	if err := graph.EncodeJSON(buf, graph.Nodes{a, a.Edges[0].Node}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), `{"version":1,`) {
+		t.Fatalf("expected the encoding to start with its version, got %s", buf)
+	}
+
+	// Encodings of newer versions are decoded, ignoring unknown fields.
+	nodes, err := graph.DecodeJSON(strings.NewReader(`{
+		"version": 99,
+		"nodes": [{"name": "a", "color": "red"}, {"name": "b"}],
+		"edges": [{"from_index": 0, "direction": 3, "to_index": 1, "style": "dashed"}],
+		"subgraphs": [{"name": "cluster", "nodes": [0, 1]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := graph.New("test", graph.WithNodes(nodes)).Edges().String(); got != "a → b" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	graph.RegisterJSONMigration(0, graph.JSONMigration{
+		Node: func(map[string]any) error {
+			return bytes.ErrTooLarge
+		},
+	})
+	t.Cleanup(func() {
+		graph.RegisterJSONMigration(0, graph.JSONMigration{})
+	})
+
+	if _, err := graph.DecodeJSONFilter(strings.NewReader(`{"nodes": [{"name": "a"}]}`), nil); err == nil {
+		t.Fatal("expected the error of the migration")
+	}
+}
@@ -0,0 +1,310 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ReadOnlyGraph is an immutable version of a graph, given to readers by
+// Instance.Read, which doesn't change while the graph is being written.
+//
+// It implements the Graph interface, so algorithms written against it,
+// like DepthFirst, BreadthFirst, ShortestPath, and BridgesOf, can analyze
+// it. Like CompactGraph, edges to nodes outside of the graph are ignored.
+type ReadOnlyGraph interface {
+	Graph
+
+	// Generation returns the generation of the graph the version was
+	// published for, see Instance.Generation.
+	Generation() uint64
+}
+
+// published holds the latest version of a graph published for readers,
+// and serializes its writers, see Instance.Write.
+type published struct {
+	mu      sync.Mutex
+	current atomic.Pointer[readVersion]
+
+	// dirty are the nodes changed since the latest version was published,
+	// reindex is set when nodes were added or removed, and all when any
+	// node may have changed, see Touch. Changes are only tracked once a
+	// version was published.
+	tracking bool
+	dirty    map[*Node]bool
+	reindex  bool
+	all      bool
+}
+
+// changed records that the given node changed since the latest version
+// was published.
+func (p *published) changed(node *Node) {
+	if !p.tracking || p.all {
+		return
+	}
+	if p.dirty == nil {
+		p.dirty = map[*Node]bool{}
+	}
+	p.dirty[node] = true
+}
+
+// changedAll records that any node may have changed since the latest
+// version was published.
+func (p *published) changedAll() {
+	if p.tracking {
+		p.all = true
+	}
+}
+
+// reset starts tracking the changes made after a version is published.
+func (p *published) reset() {
+	p.tracking = true
+	p.dirty = nil
+	p.reindex = false
+	p.all = false
+}
+
+// Write calls the given function to change the graph, then publishes the
+// new version of the graph to readers, see Read. Writers are serialized,
+// so only one of them changes the graph at a time, but they never wait for
+// readers, which continue to read the previous version until the new one
+// is published.
+//
+//	go func() {
+//		for event := range events {
+//			g.Write(func(g *graph.Instance) {
+//				g.AddEdge(event.From, event.To)
+//			})
+//		}
+//	}()
+//
+// Publishing a version only copies the nodes changed since the previous
+// version, using the methods of the graph and its nodes, which are tracked
+// like Generation, while the other nodes are shared with it. Adding or
+// removing nodes also reindexes the version, in time proportional to the
+// number of nodes, so batching many changes into a single Write is cheaper
+// than making them one at a time. Changes made directly to fields, like
+// the attributes of an edge, must be followed by a call to Touch, which
+// compares every node with the previous version instead.
+//
+// Attribute values that are slices or maps are copied, so changing them
+// in place doesn't change published versions, but other values, like
+// pointers, are shared with them.
+func (inst *Instance) Write(fn func(*Instance)) {
+	inst.published.mu.Lock()
+	defer inst.published.mu.Unlock()
+
+	fn(inst)
+
+	inst.published.current.Store(inst.freeze(inst.published.current.Load()))
+}
+
+// Read calls the given function with the latest version of the graph
+// published by Write, which is safe to read concurrently with writers and
+// other readers, and doesn't change while it is read, so queries see a
+// consistent snapshot of the graph (multiversion concurrency control).
+//
+//	g.Read(func(ro graph.ReadOnlyGraph) {
+//		path, _ := graph.ShortestPath(ro, from, to)
+//	})
+//
+// The first version is published by the first call to Read or Write.
+// Changes made to the graph without Write are only seen by readers once
+// the next version is published.
+//
+// https://en.wikipedia.org/wiki/Multiversion_concurrency_control
+func (inst *Instance) Read(fn func(ro ReadOnlyGraph)) {
+	v := inst.published.current.Load()
+	if v == nil {
+		inst.published.mu.Lock()
+		if v = inst.published.current.Load(); v == nil {
+			v = inst.freeze(nil)
+			inst.published.current.Store(v)
+		}
+		inst.published.mu.Unlock()
+	}
+
+	fn(v)
+}
+
+// readVersion is an immutable version of a graph, implementing the
+// ReadOnlyGraph interface.
+//
+// Versions that have the same nodes share their index, which holds the
+// position of each node in nodes, and the position of the first node with
+// each name.
+type readVersion struct {
+	generation uint64
+	nodes      []*nodeSnapshot
+	index      map[*Node]int
+	names      map[string]int
+}
+
+var _ ReadOnlyGraph = (*readVersion)(nil)
+
+// freeze returns the current state of the graph as a new version, sharing
+// the nodes that didn't change with the given previous version, if any.
+func (inst *Instance) freeze(previous *readVersion) *readVersion {
+	p := &inst.published
+	defer p.reset()
+
+	if previous == nil || p.all {
+		return inst.freezeAll(previous)
+	}
+
+	// Nodes that were renamed change the index of names.
+	reindex := p.reindex
+	for node := range p.dirty {
+		if i, ok := previous.index[node]; ok && previous.nodes[i].name != node.Name {
+			reindex = true
+		}
+	}
+
+	if !reindex {
+		v := &readVersion{
+			generation: inst.generation,
+			nodes:      append([]*nodeSnapshot(nil), previous.nodes...),
+			index:      previous.index,
+			names:      previous.names,
+		}
+		for node := range p.dirty {
+			if i, ok := v.index[node]; ok {
+				v.nodes[i] = snapshotNode(node)
+			}
+		}
+		return v
+	}
+
+	return inst.freezeNodes(func(node *Node) *nodeSnapshot {
+		if i, ok := previous.index[node]; ok && !p.dirty[node] {
+			return previous.nodes[i]
+		}
+		return snapshotNode(node)
+	})
+}
+
+// freezeAll returns the current state of the graph as a new version,
+// comparing every node with the given previous version, if any, to share
+// those that didn't change.
+func (inst *Instance) freezeAll(previous *readVersion) *readVersion {
+	return inst.freezeNodes(func(node *Node) *nodeSnapshot {
+		if previous != nil {
+			if i, ok := previous.index[node]; ok && previous.nodes[i].matches(node) {
+				return previous.nodes[i]
+			}
+		}
+		return snapshotNode(node)
+	})
+}
+
+// freezeNodes returns a new version of the graph with the frozen state of
+// each of its nodes returned by the given function.
+func (inst *Instance) freezeNodes(freeze func(*Node) *nodeSnapshot) *readVersion {
+	v := &readVersion{
+		generation: inst.generation,
+		nodes:      make([]*nodeSnapshot, 0, len(inst.Nodes)),
+		index:      make(map[*Node]int, len(inst.Nodes)),
+		names:      make(map[string]int, len(inst.Nodes)),
+	}
+
+	for _, node := range inst.Nodes {
+		if _, ok := v.index[node]; ok {
+			continue
+		}
+
+		ns := freeze(node)
+
+		v.index[node] = len(v.nodes)
+		if _, ok := v.names[ns.name]; !ok {
+			v.names[ns.name] = len(v.nodes)
+		}
+		v.nodes = append(v.nodes, ns)
+	}
+
+	return v
+}
+
+// readNode is a reference to a node of a readVersion.
+type readNode struct {
+	v  *readVersion
+	ns *nodeSnapshot
+}
+
+func (n readNode) Key() string       { return n.ns.name }
+func (n readNode) Attrs() Attributes { return n.ns.attrs }
+
+// Generation returns the generation of the graph the version was published
+// for.
+func (v *readVersion) Generation() uint64 {
+	return v.generation
+}
+
+// NodeCount returns the number of nodes in the version.
+func (v *readVersion) NodeCount() int {
+	return len(v.nodes)
+}
+
+// EachNode calls the given function for each node in the version, in the
+// order of the graph, until it returns false.
+func (v *readVersion) EachNode(fn func(NodeRef) bool) {
+	for _, ns := range v.nodes {
+		if !fn(readNode{v, ns}) {
+			return
+		}
+	}
+}
+
+// FindNode returns the first node in the version with the given name.
+func (v *readVersion) FindNode(key string) (NodeRef, bool) {
+	i, ok := v.names[key]
+	if !ok {
+		return nil, false
+	}
+	return readNode{v, v.nodes[i]}, true
+}
+
+// OutEdges returns the edges of the given node that aren't inward edges.
+func (v *readVersion) OutEdges(n NodeRef) []EdgeRef {
+	node, ok := n.(readNode)
+	if !ok || node.v != v {
+		return nil
+	}
+
+	var edges []EdgeRef
+	for _, es := range node.ns.edges {
+		if es.direction == In {
+			continue
+		}
+		if i, ok := v.index[es.node]; ok {
+			edges = append(edges, edgeRef{source: node, target: readNode{v, v.nodes[i]}, weight: es.weight()})
+		}
+	}
+	return edges
+}
+
+// InEdges returns the edges pointing to the given node, which are the other
+// sides of the node's edges that aren't outward edges.
+func (v *readVersion) InEdges(n NodeRef) []EdgeRef {
+	node, ok := n.(readNode)
+	if !ok || node.v != v {
+		return nil
+	}
+
+	var edges []EdgeRef
+	for _, es := range node.ns.edges {
+		if es.direction == Out {
+			continue
+		}
+		if i, ok := v.index[es.node]; ok {
+			edges = append(edges, edgeRef{source: readNode{v, v.nodes[i]}, target: node, weight: es.weight()})
+		}
+	}
+	return edges
+}
+
+// weight returns the weight of the frozen edge, like Edge.Weight.
+func (es edgeSnapshot) weight() float64 {
+	if w, ok := toFloat(es.attrs[WeightAttribute]); ok {
+		return w
+	}
+	return 1
+}
@@ -0,0 +1,180 @@
+package graph_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Read(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	var before graph.ReadOnlyGraph
+	g.Read(func(ro graph.ReadOnlyGraph) {
+		before = ro
+	})
+
+	g.Write(func(g *graph.Instance) {
+		b.AddWeightedEdge(c, 2)
+		g.RemoveNode(a)
+	})
+
+	// The version read before the write doesn't change.
+	if before.NodeCount() != 3 {
+		t.Fatalf("expected 3 nodes in the old version, got %d", before.NodeCount())
+	}
+
+	start, _ := before.FindNode("a")
+	end, _ := before.FindNode("c")
+	if path, _ := graph.ShortestPath(before, start, end); path != nil {
+		t.Fatalf("expected no path from a to c in the old version, got %v", path)
+	}
+
+	g.Read(func(ro graph.ReadOnlyGraph) {
+		if ro.Generation() != g.Generation() {
+			t.Fatalf("expected generation %d, got %d", g.Generation(), ro.Generation())
+		}
+
+		if _, ok := ro.FindNode("a"); ok {
+			t.Fatal("expected a to be removed in the new version")
+		}
+
+		start, _ := ro.FindNode("b")
+		end, _ := ro.FindNode("c")
+
+		path, weight := graph.ShortestPath(ro, start, end)
+		if len(path) != 2 || weight != 2 {
+			t.Fatalf("expected a path from b to c of weight 2, got %v, %v", path, weight)
+		}
+
+		if in := ro.InEdges(end); len(in) != 1 || in[0].Source() != start {
+			t.Fatalf("expected c to have one inward edge from b, got %v", in)
+		}
+	})
+}
+
+func TestInstance_Read_concurrent(t *testing.T) {
+	g := graph.New("test")
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		var prev *graph.Node
+		for i := 0; i < 100; i++ {
+			g.Write(func(g *graph.Instance) {
+				node := graph.NewNode(fmt.Sprint(i), nil)
+				g.AddNode(node)
+				if prev != nil {
+					prev.AddEdge(node)
+				}
+				prev = node
+			})
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 100; i++ {
+				g.Read(func(ro graph.ReadOnlyGraph) {
+					// Every version is a consistent chain of nodes,
+					// so the last node is reachable from the first.
+					n := ro.NodeCount()
+					if n == 0 {
+						return
+					}
+
+					start, _ := ro.FindNode("0")
+
+					var visited int
+					graph.DepthFirst(ro, start, func(graph.NodeRef) bool {
+						visited++
+						return true
+					})
+
+					if visited != n {
+						t.Errorf("expected %d nodes to be reachable, got %d", n, visited)
+					}
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestInstance_Write_changes(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	tags := []string{"x"}
+
+	g.Write(func(g *graph.Instance) {
+		a.SetAttribute("tags", tags)
+	})
+
+	var before graph.ReadOnlyGraph
+	g.Read(func(ro graph.ReadOnlyGraph) {
+		before = ro
+	})
+
+	// Slices are copied, so changing them in place doesn't change the
+	// published version.
+	tags[0] = "y"
+
+	ref, _ := before.FindNode("a")
+	if got := ref.Attrs()["tags"].([]string); got[0] != "x" {
+		t.Fatalf("expected the published tags to be unchanged, got %v", got)
+	}
+
+	// Changes made directly to fields are published after Touch.
+	g.Write(func(g *graph.Instance) {
+		a.Edges[0].Attributes = graph.Attributes{graph.WeightAttribute: 5.0}
+		b.Edges[0].Attributes = a.Edges[0].Attributes
+		g.Touch()
+	})
+
+	// Renamed nodes are found by their new name.
+	g.Write(func(g *graph.Instance) {
+		if err := g.RenameNode(b, "c"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	g.Read(func(ro graph.ReadOnlyGraph) {
+		if _, ok := ro.FindNode("b"); ok {
+			t.Fatal("expected b to be renamed")
+		}
+
+		start, _ := ro.FindNode("a")
+		end, ok := ro.FindNode("c")
+		if !ok {
+			t.Fatal("expected c to be found")
+		}
+
+		if _, weight := graph.ShortestPath(ro, start, end); weight != 5 {
+			t.Fatalf("expected a path of weight 5, got %v", weight)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateName is returned when adding or renaming a node would give
+// two nodes the same name in a graph created using WithUniqueNames.
+var ErrDuplicateName = errors.New("graph node name is not unique")
+
+// WithUniqueNames is a functional option that identifies the nodes of the
+// graph by name, instead of by pointer.
+//
+// Adding a node with the same name as a node already in the graph, using
+// AddNode, AddNodes, or WithNodes, merges it into the existing node using
+// MergeNodes, so edges added to either are kept. TryAddNode can be used to
+// reject duplicates instead. Lookups like Lookup and FindNode use an index
+// of names, which is maintained as long as nodes are renamed using
+// RenameNode.
+//
+//	g := graph.New("deps", graph.WithUniqueNames())
+//	g.AddNode(a1)          // "a"
+//	g.AddNode(a2)          // "a", merged into a1
+func WithUniqueNames() func(*Instance) {
+	return func(inst *Instance) {
+		inst.names = map[string]*Node{}
+	}
+}
+
+// UniqueNames reports whether the graph identifies nodes by name, because
+// it was created using WithUniqueNames.
+func (inst *Instance) UniqueNames() bool {
+	return inst.names != nil
+}
+
+// Lookup returns the node in the graph with the given name, using the index
+// of names if the graph was created using WithUniqueNames, or the first
+// node with the name otherwise.
+func (inst *Instance) Lookup(name string) (*Node, bool) {
+	if inst.names != nil {
+		node, ok := inst.names[name]
+		return node, ok
+	}
+
+	for _, node := range inst.Nodes {
+		if node.Name == name {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// RenameNode changes the name of a node in the graph, keeping the index of
+// names used by graphs created using WithUniqueNames up to date. An error
+// wrapping ErrDuplicateName is returned if another node already has the
+// name in such a graph. Nodes keep their attributes, labels, and edges,
+// and RelabelAll can be used to rename all of the nodes at once.
+func (inst *Instance) RenameNode(node *Node, name string) error {
+	if node == nil {
+		return fmt.Errorf("graph cannot rename nil node")
+	}
+
+	if inst.names == nil {
+		node.Name = name
+		inst.touchNode(node)
+		return nil
+	}
+
+	if inst.names[node.Name] != node {
+		return fmt.Errorf("graph cannot rename node %q that is not in the graph", node.Name)
+	}
+
+	if existing, ok := inst.names[name]; ok && existing != node {
+		return fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+
+	delete(inst.names, node.Name)
+	node.Name = name
+	inst.names[name] = node
+	inst.touchNode(node)
+
+	return nil
+}
+
+// RelabelAll renames every node of the graph to the name returned by the
+// given function, called once for each node in the order of the graph, like
+// to adopt the naming convention of another graph before merging them:
+//
+//	g.RelabelAll(func(n *graph.Node) string {
+//		return strings.TrimPrefix(n.Name, "svc-")
+//	})
+//
+// Like RenameNode, the index of names used by graphs created using
+// WithUniqueNames is kept up to date. For such graphs, an error wrapping
+// ErrDuplicateName is returned, without renaming any node, if two nodes
+// would have the same name.
+func (inst *Instance) RelabelAll(fn func(*Node) string) error {
+	names := make([]string, len(inst.Nodes))
+	for i, node := range inst.Nodes {
+		names[i] = fn(node)
+	}
+
+	if inst.names != nil {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if seen[name] {
+				return fmt.Errorf("%w: %q", ErrDuplicateName, name)
+			}
+			seen[name] = true
+		}
+		inst.names = make(map[string]*Node, len(names))
+	}
+
+	for i, node := range inst.Nodes {
+		node.Name = names[i]
+		inst.indexName(node)
+	}
+
+	inst.Touch()
+	return nil
+}
+
+// addNamed adds a node to a graph created using WithUniqueNames, merging it
+// into the node with the same name if there already is one.
+func (inst *Instance) addNamed(node *Node) {
+	existing, ok := inst.names[node.Name]
+	if ok && existing == node {
+		return
+	}
+
+	inst.Nodes = append(inst.Nodes, node)
+	inst.adopt(node)
+
+	if ok {
+		// Both nodes are in the graph, so this can't fail.
+		_ = inst.MergeNodes(existing, node, nil)
+	}
+}
+
+// indexName adds the node to the index of names, if the graph has one and
+// the name isn't taken.
+func (inst *Instance) indexName(n *Node) {
+	if inst.names == nil {
+		return
+	}
+	if _, ok := inst.names[n.Name]; !ok {
+		inst.names[n.Name] = n
+	}
+}
+
+// unindexName removes the node from the index of names, if the graph has one.
+func (inst *Instance) unindexName(n *Node) {
+	if inst.names != nil && inst.names[n.Name] == n {
+		delete(inst.names, n.Name)
+	}
+}
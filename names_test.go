@@ -0,0 +1,141 @@
+package graph_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestWithUniqueNames(t *testing.T) {
+	var (
+		a1 = graph.NewNode("a", graph.Attributes{"color": "red"})
+		a2 = graph.NewNode("a", graph.Attributes{"color": "blue", "size": 2})
+		b  = graph.NewNode("b", nil)
+		c  = graph.NewNode("c", nil)
+	)
+
+	a1.AddEdge(b)
+	a2.AddEdge(c)
+
+	g := graph.New("test", graph.WithUniqueNames(), graph.WithNodes(graph.NewNodes(a1, b)))
+
+	if !g.UniqueNames() {
+		t.Fatal("expected the graph to have unique names")
+	}
+
+	g.AddNodes(c, a2)
+
+	if got := g.Nodes.String(); got != "a, b, c" {
+		t.Fatalf("expected duplicate to be merged, got nodes: %s", got)
+	}
+
+	if n, ok := g.Lookup("a"); !ok || n != a1 {
+		t.Fatalf("expected lookup to return the first node named a, got: %v", n)
+	}
+
+	if got := a1.Edges.Out().Nodes().String(); got != "b, c" {
+		t.Fatalf("expected merged node to keep both edges, got: %s", got)
+	}
+
+	if a1.Attributes["color"] != "red" || a1.Attributes["size"] != 2 {
+		t.Fatalf("unexpected merged attributes: %v", a1.Attributes)
+	}
+
+	// Adding the same node again does nothing.
+	g.AddNode(b)
+	if len(g.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(g.Nodes))
+	}
+
+	err := g.TryAddNode(graph.NewNode("b", nil))
+	if !errors.Is(err, graph.ErrDuplicateName) {
+		t.Fatalf("expected duplicate name error, got: %v", err)
+	}
+
+	if err := g.RenameNode(c, "b"); !errors.Is(err, graph.ErrDuplicateName) {
+		t.Fatalf("expected duplicate name error, got: %v", err)
+	}
+
+	if err := g.RenameNode(c, "d"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := g.Lookup("c"); ok {
+		t.Fatal("did not expect to find the old name")
+	}
+
+	if n, ok := g.FindNode("d"); !ok || n != c {
+		t.Fatalf("expected to find renamed node, got: %v", n)
+	}
+
+	g.RemoveNode(c)
+
+	if _, ok := g.Lookup("d"); ok {
+		t.Fatal("did not expect to find removed node")
+	}
+
+	if err := g.TryAddNode(graph.NewNode("d", nil)); err != nil {
+		t.Fatalf("expected name to be free after removal, got: %v", err)
+	}
+
+	clone := g.Clone()
+	clone.AddNode(graph.NewNode("a", nil))
+
+	if !clone.UniqueNames() || len(clone.Nodes) != len(g.Nodes) {
+		t.Fatalf("expected clone to keep unique names, got nodes: %s", clone.Nodes)
+	}
+}
+
+func TestInstance_RelabelAll(t *testing.T) {
+	g := graph.New("test", graph.WithUniqueNames())
+
+	var (
+		a = graph.NewNode("svc-a", graph.Attributes{"color": "red"})
+		b = graph.NewNode("svc-b", nil)
+	)
+	a.AddEdge(b)
+	g.AddNodes(a, b)
+	g.CreateIndex("color")
+
+	err := g.RelabelAll(func(n *graph.Node) string {
+		return strings.TrimPrefix(n.Name, "svc-")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, ok := g.Lookup("a"); !ok || n != a || n.Attributes["color"] != "red" {
+		t.Fatalf("expected to find relabeled node with its attributes, got: %v", n)
+	}
+
+	if nodes := g.NodesWhere("color", "red"); len(nodes) != 1 || nodes[0].Name != "a" {
+		t.Fatalf("unexpected indexed nodes: %v", nodes)
+	}
+
+	// Names can be swapped, since they're only checked once all nodes are
+	// relabeled.
+	err = g.RelabelAll(func(n *graph.Node) string {
+		if n.Name == "a" {
+			return "b"
+		}
+		return "a"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, ok := g.Lookup("b"); !ok || n != a {
+		t.Fatalf("expected b to be the swapped node, got: %v", n)
+	}
+
+	err = g.RelabelAll(func(*graph.Node) string { return "x" })
+	if !errors.Is(err, graph.ErrDuplicateName) {
+		t.Fatalf("expected duplicate name error, got: %v", err)
+	}
+
+	if got := g.Nodes.String(); got != "b, a" {
+		t.Fatalf("expected names to be kept, got: %s", got)
+	}
+}
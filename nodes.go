@@ -14,6 +14,20 @@ type Node struct {
 	Edges
 	// Named attributes about the node.
 	Attributes
+	// Labels classify the node, like the labels of the property graph model.
+	Labels []string
+	// Child is the graph nested inside of the node, making it a compound
+	// node, see Instance.Collapse and Instance.Expand.
+	Child *Instance
+
+	// graph is the graph instance the node was added to, if any, and id
+	// is the ID it was assigned by that graph.
+	graph *Instance
+	id    int
+
+	// placeholder is true for nodes created using NewPlaceholder, until
+	// they are resolved, see Resolver.
+	placeholder bool
 }
 
 // NewNode returns a new node with the given name and attributes.
@@ -105,6 +119,19 @@ func (ns NodeSet) IsAdjacentWith(other ...*Node) bool {
 	return true
 }
 
+// isAdjacentToAny returns true if any node in the set is adjacent
+// to any of the other given nodes.
+func (ns NodeSet) isAdjacentToAny(other ...*Node) bool {
+	for n := range ns {
+		for _, o := range other {
+			if n.Edges.Contains(o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Nodes returns a slice of nodes in the set.
 func (ns NodeSet) Nodes() []*Node {
 	nodes := []*Node{}
@@ -138,6 +165,78 @@ func (ns NodeSet) Emtpy() bool {
 	return len(ns) == 0
 }
 
+// Len returns the number of nodes in the set.
+func (ns NodeSet) Len() int {
+	return len(ns)
+}
+
+// Union returns a new set of the nodes in the set or the other set.
+func (ns NodeSet) Union(other NodeSet) NodeSet {
+	union := make(NodeSet, len(ns)+len(other))
+	for n := range ns {
+		union.Add(n)
+	}
+	for n := range other {
+		union.Add(n)
+	}
+	return union
+}
+
+// Intersect returns a new set of the nodes in both the set and the other set.
+func (ns NodeSet) Intersect(other NodeSet) NodeSet {
+	// Iterate over the smaller set.
+	if len(other) < len(ns) {
+		ns, other = other, ns
+	}
+	return ns.Filter(other.Contains)
+}
+
+// Difference returns a new set of the nodes in the set that aren't in the
+// other set.
+func (ns NodeSet) Difference(other NodeSet) NodeSet {
+	return ns.Filter(func(n *Node) bool {
+		return !other.Contains(n)
+	})
+}
+
+// Filter returns a new set of the nodes in the set the given function
+// returns true for.
+func (ns NodeSet) Filter(fn func(*Node) bool) NodeSet {
+	filtered := NodeSet{}
+	for n := range ns {
+		if fn(n) {
+			filtered.Add(n)
+		}
+	}
+	return filtered
+}
+
+// Sorted returns the nodes in the set ordered by name, the same order
+// used by String.
+func (ns NodeSet) Sorted() Nodes {
+	nodes := make(Nodes, 0, len(ns))
+	for n := range ns {
+		nodes = append(nodes, n)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes
+}
+
+// Each calls the given function with each node in the set, ordered by
+// name, until the function returns false. Unlike ranging over the set,
+// the order is the same every time.
+func (ns NodeSet) Each(fn func(*Node) bool) {
+	for _, n := range ns.Sorted() {
+		if !fn(n) {
+			return
+		}
+	}
+}
+
 // IndexOf returns the index of the given node in the set.
 func (nodes Nodes) IndexOf(o *Node) int {
 	for i, node := range nodes {
@@ -148,6 +247,79 @@ func (nodes Nodes) IndexOf(o *Node) int {
 	return -1
 }
 
+// Contains returns true if the given node is in the collection.
+func (nodes Nodes) Contains(o *Node) bool {
+	return nodes.IndexOf(o) != -1
+}
+
+// Union returns the nodes in the collection followed by the other nodes
+// that aren't in it, including each node once.
+//
+//	[a, b] ∪ [b, c] = [a, b, c]
+func (nodes Nodes) Union(other Nodes) Nodes {
+	all := append(append(Nodes{}, nodes...), other...)
+	return all.unique(func(*Node) bool {
+		return true
+	})
+}
+
+// Intersect returns the nodes in the collection that are also in the other
+// nodes, in the order of the collection, including each node once.
+//
+//	[a, b] ∩ [b, c] = [b]
+func (nodes Nodes) Intersect(other Nodes) Nodes {
+	in := NewNodeSet(other...)
+	return nodes.unique(func(node *Node) bool {
+		return in.Contains(node)
+	})
+}
+
+// Difference returns the nodes in the collection that aren't in the other
+// nodes, in the order of the collection, including each node once.
+//
+//	[a, b] - [b, c] = [a]
+func (nodes Nodes) Difference(other Nodes) Nodes {
+	in := NewNodeSet(other...)
+	return nodes.unique(func(node *Node) bool {
+		return !in.Contains(node)
+	})
+}
+
+// Filter returns the nodes in the collection the given function returns
+// true for, in the order of the collection.
+func (nodes Nodes) Filter(fn func(*Node) bool) Nodes {
+	filtered := Nodes{}
+	for _, node := range nodes {
+		if fn(node) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// Map returns the results of calling the given function with each node in
+// the collection, in the order of the collection.
+func (nodes Nodes) Map(fn func(*Node) *Node) Nodes {
+	mapped := make(Nodes, len(nodes))
+	for i, node := range nodes {
+		mapped[i] = fn(node)
+	}
+	return mapped
+}
+
+// unique returns the nodes in the collection the given function returns
+// true for, skipping nodes that were already included.
+func (nodes Nodes) unique(fn func(*Node) bool) Nodes {
+	seen := NodeSet{}
+	return nodes.Filter(func(node *Node) bool {
+		if seen.Contains(node) || !fn(node) {
+			return false
+		}
+		seen.Add(node)
+		return true
+	})
+}
+
 // AtIndex returns the node at the given index.
 func (nodes Nodes) AtIndex(i int) (*Node, error) {
 	if len(nodes) <= i {
@@ -187,7 +359,7 @@ func (nodeSets NodeSets) GetSetThatContains(n *Node) (NodeSet, bool) {
 // GetSetNotAdjacentWith returns the NodeSet that is not adjacent to the given nodes.
 func (nodeSets NodeSets) GetSetNotAdjacentWith(nodes ...*Node) (NodeSet, bool) {
 	for _, nodeSet := range nodeSets {
-		if !nodeSet.IsAdjacentWith(nodes...) {
+		if !nodeSet.isAdjacentToAny(nodes...) {
 			return nodeSet, true
 		}
 	}
@@ -200,8 +372,37 @@ func (nodeSets NodeSets) GetSetNotAdjacentWith(nodes ...*Node) (NodeSet, bool) {
 //
 // To control the direction used for the relationship, use the AddEdgeWithDirection method.
 func (n *Node) AddEdge(e *Node) {
-	n.Edges = append(n.Edges, &Edge{Node: e, Direction: Out})
-	e.Edges = append(e.Edges, &Edge{Node: n, Direction: In})
+	n.addEdge(e, &Edge{Node: e, Direction: Out, from: n}, &Edge{Node: n, Direction: In, from: e})
+}
+
+// addEdge adds the given edges of a relationship between the node and the
+// given node, one on each side, reporting the change to the subscribers
+// of their graphs. It is the path all edges are added through.
+func (n *Node) addEdge(e *Node, edge, other *Edge) {
+	n.touch()
+	e.touch()
+
+	n.Edges = append(n.Edges, edge)
+	e.Edges = append(e.Edges, other)
+
+	n.publishEdge(e)
+}
+
+// AddWeightedEdge adds a directed relationship to a Node with the given
+// weight, which is shared by the edges on both sides of the relationship.
+//
+//	n → e
+func (n *Node) AddWeightedEdge(e *Node, weight float64) {
+	attrs := Attributes{WeightAttribute: weight}
+	n.addEdge(e, &Edge{Node: e, Direction: Out, Attributes: attrs, from: n}, &Edge{Node: n, Direction: In, Attributes: attrs, from: e})
+}
+
+// AddEdgeTyped adds a directed relationship of the given type to a Node,
+// which is stored as the Name of the edges on both sides of the relationship.
+//
+//	n -[typ]→ e
+func (n *Node) AddEdgeTyped(e *Node, typ string) {
+	n.addEdge(e, &Edge{Name: typ, Node: e, Direction: Out, from: n}, &Edge{Name: typ, Node: n, Direction: In, from: e})
 }
 
 // AddLink adds a bi-directional relationship to a Node.
@@ -225,14 +426,64 @@ func (n *Node) AddLink(e *Node) {
 func (n *Node) AddEdgeWithDirection(e *Node, direction EdgeDirection) {
 	switch direction {
 	case None, Unknown, Both:
-		n.Edges = append(n.Edges, &Edge{Node: e, Direction: direction})
-		e.Edges = append(e.Edges, &Edge{Node: n, Direction: direction})
+		n.addEdge(e, &Edge{Node: e, Direction: direction, from: n}, &Edge{Node: n, Direction: direction, from: e})
 	case Out:
-		n.Edges = append(n.Edges, &Edge{Node: e, Direction: Out})
-		e.Edges = append(e.Edges, &Edge{Node: n, Direction: In})
+		n.addEdge(e, &Edge{Node: e, Direction: Out, from: n}, &Edge{Node: n, Direction: In, from: e})
 	case In:
-		n.Edges = append(n.Edges, &Edge{Node: e, Direction: In})
-		e.Edges = append(e.Edges, &Edge{Node: n, Direction: Out})
+		n.addEdge(e, &Edge{Node: e, Direction: In, from: n}, &Edge{Node: n, Direction: Out, from: e})
+	}
+}
+
+// RemoveEdge removes the given edge from the Node, along with the
+// corresponding edge on the other side of the relationship.
+func (n *Node) RemoveEdge(edge *Edge) {
+	if edge == nil {
+		return
+	}
+
+	n.touch()
+	edge.Node.touch()
+
+	other := edge.reciprocal(n)
+	if other != nil {
+		edge.Node.Edges = edge.Node.Edges.without(other)
+	}
+
+	n.Edges = n.Edges.without(edge)
+
+	n.publishEdgeRemoved(edge, other)
+}
+
+// RemoveEdgesTo removes all edges between the Node and the given node,
+// in both directions.
+func (n *Node) RemoveEdgesTo(e *Node) {
+	n.touch()
+	e.touch()
+
+	// Each relationship is reported once, so the other side of self-loops
+	// is skipped.
+	type removal struct{ edge, other *Edge }
+
+	var (
+		removed []removal
+		skip    = map[*Edge]bool{}
+	)
+	for _, edge := range n.Edges {
+		if edge.Node != e || skip[edge] {
+			continue
+		}
+		other := edge.reciprocal(n)
+		if other != nil {
+			skip[other] = true
+		}
+		removed = append(removed, removal{edge, other})
+	}
+
+	n.Edges = n.Edges.ButNotWith(e)
+	e.Edges = e.Edges.ButNotWith(n)
+
+	for _, r := range removed {
+		n.publishEdgeRemoved(r.edge, r.other)
 	}
 }
 
@@ -260,17 +511,18 @@ func (n *Node) HasCycles() bool {
 }
 
 // HasCycleContaining checks if the Node is part of a cycle
-// that contains the given node.
+// that contains the given node, following outward and bi-directional
+// edges. Every simple cycle through the Node is considered, until
+// one containing the given node is found.
 func (n *Node) HasCycleContaining(node *Node) bool {
-	for _, edge := range n.Edges.Out() {
-		path := edge.Node.PathTo(n)
-		if len(path) > 0 {
-			if path.ContainsNode(node) {
-				return true
-			}
-		}
-	}
-	return false
+	var found bool
+
+	eachCycleThrough(n, nil, func(cycle Path) bool {
+		found = cycle.ContainsNode(node)
+		return !found
+	})
+
+	return found
 }
 
 // In returns the edges that are directed inwards (pointing to).
@@ -295,6 +547,27 @@ func (es Edges) Out() Edges {
 	return out
 }
 
+// OfType returns the edges with any of the given relationship types (names).
+func (es Edges) OfType(types ...string) Edges {
+	var typed Edges
+	for _, e := range es {
+		if e.isType(types) {
+			typed = append(typed, e)
+		}
+	}
+	return typed
+}
+
+// isType checks if the edge has any of the given relationship types.
+func (e *Edge) isType(types []string) bool {
+	for _, typ := range types {
+		if e.Name == typ {
+			return true
+		}
+	}
+	return false
+}
+
 func (es Edges) NodeSet() NodeSet {
 	var ns NodeSet
 	for _, e := range es {
@@ -320,20 +593,23 @@ func (es Edges) Nodes() Nodes {
 //	   |   c ↔ d   |     4. Go to edge node "d"
 //	   ↓ ⤢       ⤡ ↓     5. Go to edge node "e"
 //	2  b           f 6   6. Go to edge node "f"
-func (n *Node) Visit(fn func(*Node)) {
-	visit(n, nil, fn)
+//
+// Options like WithSortedNeighbors can be given to change the order
+// neighbors are visited in.
+func (n *Node) Visit(fn func(*Node), opts ...func(*VisitOrder)) {
+	visit(n, newVisitOrder(opts...), fn)
 }
 
 // VisitAll walks the the outwards and inwards nodes with a
-// depth-first-search algorithm.
-func (n *Node) VisitAll(fn func(*Node)) {
-	visitAll(n, nil, fn)
+// depth-first-search algorithm, using the given neighbor order options.
+func (n *Node) VisitAll(fn func(*Node), opts ...func(*VisitOrder)) {
+	visitAll(n, newVisitOrder(opts...), fn)
 }
 
 // visitWithTerminator is an internal function used to walk node
 // relationships starting at the root node using depth-first-search.
 //
-// The record node set keeps track of nodes which were already visited,
+// The record tracker keeps track of nodes which were already visited,
 // to prevent infinite loops that can be found during traversal. The first
 // call to this function can provide a nil record.
 //
@@ -343,32 +619,38 @@ func (n *Node) VisitAll(fn func(*Node)) {
 //
 // Lastly, the function given to run for each visited node can return true
 // to continue traversal, or false to stop traversal.
-func visitWithTerminator(root *Node, record NodeSet, direction EdgeDirection, fn func(*Node) bool) {
+func visitWithTerminator(root *Node, record VisitedTracker, direction EdgeDirection, fn func(*Node) bool) {
+	visitOrdered(root, record, direction, nil, fn)
+}
+
+// visitOrdered is visitWithTerminator, visiting neighbors in the given order.
+func visitOrdered(root *Node, record VisitedTracker, direction EdgeDirection, order *VisitOrder, fn func(*Node) bool) {
 	if root == nil {
 		return
 	}
 
 	if record == nil {
-		record = NodeSet{}
+		record = order.tracker(root.graph)
 	}
 
-	_, alreadyVisited := record[root]
-	if alreadyVisited {
+	if record.Contains(root) {
 		return
 	}
-	record[root] = struct{}{}
+	record.Add(root)
+
+	root.resolvePlaceholder()
 
 	if !fn(root) {
 		return
 	}
 
-	for _, edge := range root.Edges {
+	for _, edge := range order.edges(root.Edges) {
 		switch direction {
 		case Unknown, None, Both:
-			visitWithTerminator(edge.Node, record, direction, fn)
+			visitOrdered(edge.Node, record, direction, order, fn)
 		case In, Out:
 			if edge.Direction == direction || edge.Direction == Both {
-				visitWithTerminator(edge.Node, record, direction, fn)
+				visitOrdered(edge.Node, record, direction, order, fn)
 			}
 		}
 	}
@@ -376,42 +658,44 @@ func visitWithTerminator(root *Node, record NodeSet, direction EdgeDirection, fn
 
 // visit is an internal function that walks the outward nodes with
 // a depth-first algorithm.
-func visit(root *Node, record NodeSet, fn func(*Node)) {
+func visit(root *Node, order *VisitOrder, fn func(*Node)) {
 	wrapFn := func(n *Node) bool {
 		fn(n)
 		return true
 	}
 
-	visitWithTerminator(root, nil, Out, wrapFn)
+	visitOrdered(root, nil, Out, order, wrapFn)
 }
 
 // visitAll is an internal function that walks the outward and inward
 // nodes with a depth-first algorithm.
-func visitAll(root *Node, record NodeSet, fn func(*Node)) {
+func visitAll(root *Node, order *VisitOrder, fn func(*Node)) {
 	wrapFn := func(n *Node) bool {
 		fn(n)
 		return true
 	}
 
-	visitWithTerminator(root, nil, Both, wrapFn)
+	visitOrdered(root, nil, Both, order, wrapFn)
 }
 
 // PathTo returns the Path to the given end Node, nil if no path
-// was found.
-func (n *Node) PathTo(end *Node) Path {
+// was found. Traversal options, like WithEdgeFilter, can be given to
+// change which edges are followed.
+func (n *Node) PathTo(end *Node, opts ...func(*VisitOrder)) Path {
 	var (
 		hasPath bool
 		path    Path
+		order   = newVisitOrder(opts...)
 	)
 
-	visitWithTerminator(n, nil, Out, func(n *Node) bool {
+	visitOrdered(n, nil, Out, order, func(n *Node) bool {
 		if hasPath {
 			return false
 		}
 
 		path = append(path, n)
 
-		for _, edge := range n.Edges {
+		for _, edge := range order.filter(n.Edges) {
 			switch edge.Direction {
 			case Out, Both, None, Unknown:
 				if edge.Node == end {
@@ -435,8 +719,75 @@ func (n *Node) PathTo(end *Node) Path {
 // PathToWithout checks if there's a path to the given end node, without
 // having to "go through" or "use" the other given node.
 func (n *Node) PathToWithout(end, without *Node) bool {
-	path := n.PathTo(end)
-	return !path.ContainsNode(without)
+	return n.PathToAvoiding(end, NewNodeSet(without), nil) != nil
+}
+
+// PathToAvoiding returns the shortest Path to the given end Node that
+// doesn't go through any of the blocked nodes or use any of the blocked
+// edges, nil if no such path was found. Blocking an edge also blocks its
+// reciprocal edge, which represents the same relationship. If the end node
+// is the node itself, the shortest cycle through it is returned.
+//
+//	a → b → d    a.PathToAvoiding(d, {b}, nil): a → c → d
+//	 ↘     ↗
+//	   c
+//
+// Traversal options, like WithEdgeFilter, restrict the edges followed.
+func (n *Node) PathToAvoiding(end *Node, blockedNodes NodeSet, blockedEdges Edges, opts ...func(*VisitOrder)) Path {
+	if blockedNodes.Contains(n) || blockedNodes.Contains(end) {
+		return nil
+	}
+
+	filter := newVisitOrder(opts...).edgeFilter()
+
+	blocked := make(map[*Edge]struct{}, 2*len(blockedEdges))
+	for _, edge := range blockedEdges {
+		blocked[edge] = struct{}{}
+		if edge.from != nil {
+			if other := edge.reciprocal(edge.from); other != nil {
+				blocked[other] = struct{}{}
+			}
+		}
+	}
+
+	previous := map[*Node]*Node{n: nil}
+
+	queue := Nodes{n}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range node.Edges {
+			if edge.Direction == In {
+				continue
+			}
+
+			if _, ok := blocked[edge]; ok || (filter != nil && !filter(edge)) {
+				continue
+			}
+
+			next := edge.Node
+
+			if next == end {
+				var path Path
+				for at := node; at != nil; at = previous[at] {
+					path = append(Path{at}, path...)
+				}
+				return append(path, end)
+			}
+
+			if _, seen := previous[next]; seen || blockedNodes.Contains(next) {
+				continue
+			}
+
+			previous[next] = node
+
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
 }
 
 // HasPath checks if there is a Path to the given end Node.
@@ -448,8 +799,10 @@ func (n *Node) PathToWithout(end, without *Node) bool {
 //	        d       g → h
 //
 //	Path: a → b → c → e → g → h → i → e
-func (n *Node) HasPath(end *Node) bool {
-	return n.PathTo(end) != nil
+//
+// Traversal options, like WithEdgeFilter, are used like PathTo.
+func (n *Node) HasPath(end *Node, opts ...func(*VisitOrder)) bool {
+	return n.PathTo(end, opts...) != nil
 }
 
 // ConnectNodes creats an ordered, directed relationship between
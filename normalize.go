@@ -0,0 +1,91 @@
+package graph
+
+// Normalize converts the undirected (None), bi-directional (Both), and
+// Unknown relationships of the graph into pairs of directed relationships,
+// one in each direction, like those added using AddLink. Relationships
+// keep their name and attributes, and self-loops become a single directed
+// self-loop.
+//
+//	a ↔ b    ⇒    a → b, b → a
+//
+// Graphs built by mixing AddLink and AddEdgeWithDirection have the same
+// structure once normalized, so algorithms treat them consistently. See
+// NormalizeUndirected for the opposite conversion.
+func (inst *Instance) Normalize() {
+	inst.Touch()
+
+	converted := map[*Edge]bool{}
+
+	for _, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if converted[edge] || edge.Direction == In || edge.Direction == Out {
+				continue
+			}
+
+			other := edge.reciprocal(node)
+
+			edge.Direction = Out
+			converted[edge] = true
+
+			if other != nil {
+				other.Direction = In
+				converted[other] = true
+			}
+
+			if edge.Node == node {
+				continue
+			}
+
+			edge.Node.AddEdge(node)
+			for _, added := range (Edges{edge.Node.Edges[len(edge.Node.Edges)-1], node.Edges[len(node.Edges)-1]}) {
+				added.Name = edge.Name
+				added.Attributes = edge.Attributes
+				converted[added] = true
+			}
+		}
+	}
+}
+
+// NormalizeUndirected converts pairs of directed relationships in opposite
+// directions between two nodes, like those added using AddLink, into single
+// bi-directional (Both) relationships, like those added using
+// AddEdgeWithDirection. Only pairs with the same name and equal attributes
+// are converted, so no information is lost. See Normalize for the opposite
+// conversion.
+//
+//	a → b, b → a    ⇒    a ↔ b
+func (inst *Instance) NormalizeUndirected() {
+	inst.Touch()
+
+	for _, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if edge.Direction != Out || edge.Node == node {
+				continue
+			}
+
+			var opposite *Edge
+			for _, other := range edge.Node.Edges {
+				if other.Direction == Out && other.Node == node && other.Name == edge.Name && attributesEqual(other.Attributes, edge.Attributes) {
+					opposite = other
+					break
+				}
+			}
+
+			if opposite == nil {
+				continue
+			}
+
+			// Drop the inward halves of both relationships, pairing the
+			// outward edges with each other.
+			if back := edge.reciprocal(node); back != nil {
+				edge.Node.Edges = edge.Node.Edges.without(back)
+			}
+			if back := opposite.reciprocal(edge.Node); back != nil {
+				node.Edges = node.Edges.without(back)
+			}
+
+			edge.Direction = Both
+			opposite.Direction = Both
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package graph_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Normalize(t *testing.T) {
+	build := func(link func(a, b *graph.Node)) *graph.Instance {
+		var (
+			a = graph.NewNode("a", nil)
+			b = graph.NewNode("b", nil)
+			c = graph.NewNode("c", nil)
+		)
+
+		// a ↔ b → c ↺
+
+		link(a, b)
+		b.AddEdge(c)
+		c.AddEdgeWithDirection(c, graph.None)
+
+		return graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+	}
+
+	linked := build(func(a, b *graph.Node) { a.AddLink(b) })
+	both := build(func(a, b *graph.Node) { a.AddEdgeWithDirection(b, graph.Both) })
+	none := build(func(a, b *graph.Node) { a.AddEdgeWithDirection(b, graph.None) })
+
+	if graph.Equal(linked, both) {
+		t.Fatal("did not expect graphs built differently to be equal before normalizing")
+	}
+
+	for _, g := range []*graph.Instance{linked, both, none} {
+		g.Normalize()
+
+		if err := graph.CheckInvariants(g); err != nil {
+			t.Fatal(err)
+		}
+
+		var edges []string
+		for _, edge := range g.Edges() {
+			edges = append(edges, edge.String())
+		}
+		sort.Strings(edges)
+
+		if strings.Join(edges, ", ") != "a → b, b → a, b → c, c → c" {
+			t.Fatalf("unexpected normalized edges: %v", edges)
+		}
+	}
+
+	if !graph.Equal(linked, both) || !graph.Equal(linked, none) {
+		t.Fatal("expected normalized graphs to be equal")
+	}
+
+	linked.NormalizeUndirected()
+
+	if err := graph.CheckInvariants(linked); err != nil {
+		t.Fatal(err)
+	}
+
+	if edges := linked.Edges().String(); edges != "a ↔ b, b → c, c → c" {
+		t.Fatalf("unexpected undirected edges: %v", edges)
+	}
+
+	// Relationships with different attributes aren't merged.
+	var (
+		x = graph.NewNode("x", nil)
+		y = graph.NewNode("y", nil)
+	)
+
+	x.AddWeightedEdge(y, 1)
+	y.AddWeightedEdge(x, 2)
+
+	g := graph.New("weighted", graph.WithNodes(graph.NewNodes(x, y)))
+	g.NormalizeUndirected()
+
+	if edges := g.Edges().String(); edges != "x → y, y → x" {
+		t.Fatalf("unexpected weighted edges: %v", edges)
+	}
+}
@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"sort"
+	"time"
+)
+
+// VisitOrder configures the order in which traversals, like Node.Visit,
+// Instance.DFS, and Instance.BFS, visit the neighbors of each node, and
+// how they record visited nodes.
+//
+// By default, neighbors are visited in the order of the node's edges.
+// Ordered traversals are deterministic for graphs built the same way,
+// regardless of the order edges were added in.
+type VisitOrder struct {
+	// Less reports whether neighbor a should be visited before neighbor b.
+	// Neighbors for which neither is less keep the order of their edges.
+	Less func(a, b *Node) bool
+
+	// Filter, if not nil, reports whether an edge can be followed. Edges
+	// it returns false for are ignored, as if they didn't exist.
+	Filter EdgeFilter
+
+	// AsOf, if not zero, is the time edges must be valid at to be
+	// followed, see Edge.ValidAt.
+	AsOf time.Time
+
+	// StopAtBoundary, if true, stops traversals at the boundary of the
+	// graphs nodes belong to, without following external edges to nodes
+	// of other graphs, see Edge.IsExternal.
+	StopAtBoundary bool
+
+	// Visited returns the tracker used to record the nodes visited by a
+	// traversal of the given graph. By default, a bitset is used, see
+	// NewBitsetTracker.
+	Visited func(inst *Instance) VisitedTracker
+
+	// compiled is the filter combining the options that restrict the edges
+	// followed, built by edgeFilter once the options are set.
+	compiled EdgeFilter
+	built    bool
+}
+
+// WithSortedNeighbors visits neighbors sorted by name.
+//
+//	  a           a.Visit(fn, WithSortedNeighbors())
+//	↙ ↓ ↘
+//	d c b         a, b, c, d
+func WithSortedNeighbors() func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.Less = func(a, b *Node) bool {
+			return a.Name < b.Name
+		}
+	}
+}
+
+// WithNeighborPriority visits neighbors with higher values of the given
+// numeric attribute first. Neighbors without the attribute are visited
+// after those with it.
+func WithNeighborPriority(attribute string) func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.Less = func(a, b *Node) bool {
+			pa, aok := toFloat(a.Attributes[attribute])
+			pb, bok := toFloat(b.Attributes[attribute])
+			if aok != bok {
+				return aok
+			}
+			return pa > pb
+		}
+	}
+}
+
+// WithNeighborOrder visits neighbors in the order defined by the given
+// comparator, which reports whether a should be visited before b.
+func WithNeighborOrder(less func(a, b *Node) bool) func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.Less = less
+	}
+}
+
+// newVisitOrder returns the order configured by the given options.
+func newVisitOrder(opts ...func(*VisitOrder)) *VisitOrder {
+	o := &VisitOrder{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// edges returns the given edges that can be followed, in the order their
+// nodes should be visited.
+func (o *VisitOrder) edges(edges Edges) Edges {
+	edges = o.filter(edges)
+
+	if o == nil || o.Less == nil || len(edges) < 2 {
+		return edges
+	}
+
+	sorted := append(Edges{}, edges...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return o.Less(sorted[i].Node, sorted[j].Node)
+	})
+	return sorted
+}
+
+// nodes returns the given nodes in the order they should be visited.
+func (o *VisitOrder) nodes(nodes Nodes) Nodes {
+	if o == nil || o.Less == nil || len(nodes) < 2 {
+		return nodes
+	}
+
+	sorted := append(Nodes{}, nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return o.Less(sorted[i], sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,98 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestVisitOrder(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", graph.Attributes{"priority": 1})
+		c = graph.NewNode("c", graph.Attributes{"priority": 3})
+		d = graph.NewNode("d", graph.Attributes{"priority": 2})
+		e = graph.NewNode("e", nil)
+	)
+
+	//     a
+	//   ↙ ↓ ↘
+	//  d  c  b
+	//  ↓
+	//  e
+
+	a.AddEdge(d)
+	a.AddEdge(c)
+	a.AddEdge(b)
+	d.AddEdge(e)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	names := func(walk func(func(*graph.Node))) string {
+		var visited []string
+		walk(func(n *graph.Node) {
+			visited = append(visited, n.Name)
+		})
+		return strings.Join(visited, ", ")
+	}
+
+	tests := []struct {
+		name string
+		walk func(func(*graph.Node))
+		want string
+	}{
+		{
+			name: "visit",
+			walk: func(fn func(*graph.Node)) { a.Visit(fn) },
+			want: "a, d, e, c, b",
+		},
+		{
+			name: "visit sorted",
+			walk: func(fn func(*graph.Node)) { a.Visit(fn, graph.WithSortedNeighbors()) },
+			want: "a, b, c, d, e",
+		},
+		{
+			name: "visit priority",
+			walk: func(fn func(*graph.Node)) { a.Visit(fn, graph.WithNeighborPriority("priority")) },
+			want: "a, c, d, e, b",
+		},
+		{
+			name: "visit comparator",
+			walk: func(fn func(*graph.Node)) {
+				a.Visit(fn, graph.WithNeighborOrder(func(x, y *graph.Node) bool {
+					return x.Name > y.Name
+				}))
+			},
+			want: "a, d, e, c, b",
+		},
+		{
+			name: "dfs sorted",
+			walk: func(fn func(*graph.Node)) { g.DFS(fn, graph.WithSortedNeighbors()) },
+			want: "a, b, c, d, e",
+		},
+		{
+			name: "dfs priority",
+			walk: func(fn func(*graph.Node)) { g.DFS(fn, graph.WithNeighborPriority("priority")) },
+			want: "a, c, d, e, b",
+		},
+		{
+			name: "bfs sorted",
+			walk: func(fn func(*graph.Node)) { g.BFS(fn, graph.WithSortedNeighbors()) },
+			want: "a, b, c, d, e",
+		},
+		{
+			name: "bfs priority",
+			walk: func(fn func(*graph.Node)) { g.BFS(fn, graph.WithNeighborPriority("priority")) },
+			want: "a, c, d, b, e",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := names(test.walk); got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
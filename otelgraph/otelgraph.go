@@ -0,0 +1,80 @@
+// Package otelgraph instruments the algorithms of graphs with OpenTelemetry
+// spans, to diagnose slow graph operations in production.
+//
+//	g := graph.New("deps", otelgraph.WithTracerProvider(otel.GetTracerProvider()))
+//
+// Each invocation of a traced algorithm, see graph.Tracer, is recorded as a
+// span named after the algorithm, like "graph.TopologicalSort", with the
+// following attributes, and the duration of the invocation:
+//
+//	graph.name    the name of the graph
+//	graph.nodes   the number of nodes in the graph
+//
+// It is a separate module, so the graph module doesn't depend on the
+// OpenTelemetry API.
+package otelgraph
+
+import (
+	"context"
+
+	"github.com/picatz/graph"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer used to create spans.
+const InstrumentationName = "github.com/picatz/graph/otelgraph"
+
+// Tracer is a graph.Tracer that records invocations of the graph's
+// algorithms as OpenTelemetry spans.
+type Tracer struct {
+	tracer trace.Tracer
+	ctx    context.Context
+	name   string
+}
+
+// WithContext is a functional option that sets the context spans are
+// started with, so they are children of the span in the context. Graph
+// algorithms don't take a context, so spans are root spans by default.
+func WithContext(ctx context.Context) func(*Tracer) {
+	return func(t *Tracer) {
+		t.ctx = ctx
+	}
+}
+
+// NewTracer returns a new tracer for the graph with the given name, using
+// the given tracer provider.
+func NewTracer(name string, provider trace.TracerProvider, opts ...func(*Tracer)) *Tracer {
+	t := &Tracer{
+		tracer: provider.Tracer(InstrumentationName),
+		ctx:    context.Background(),
+		name:   name,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Start implements the graph.Tracer interface.
+func (t *Tracer) Start(algorithm string, nodes int) func() {
+	_, span := t.tracer.Start(t.ctx, "graph."+algorithm, trace.WithAttributes(
+		attribute.String("graph.name", t.name),
+		attribute.Int("graph.nodes", nodes),
+	))
+
+	return func() {
+		span.End()
+	}
+}
+
+// WithTracerProvider is a functional option for graph.New that records
+// invocations of the graph's algorithms as spans, using the given tracer
+// provider.
+func WithTracerProvider(provider trace.TracerProvider, opts ...func(*Tracer)) func(*graph.Instance) {
+	return func(inst *graph.Instance) {
+		graph.WithTracer(NewTracer(inst.Name, provider, opts...))(inst)
+	}
+}
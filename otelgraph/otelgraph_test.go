@@ -0,0 +1,59 @@
+package otelgraph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/otelgraph"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	// a → b
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)), otelgraph.WithTracerProvider(provider))
+
+	if _, err := g.TopologicalSort(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if name := spans[0].Name(); name != "graph.TopologicalSort" {
+		t.Fatalf("unexpected span name: %q", name)
+	}
+
+	expected := []attribute.KeyValue{
+		attribute.String("graph.name", "test"),
+		attribute.Int("graph.nodes", 2),
+	}
+
+	attrs := spans[0].Attributes()
+
+	if len(attrs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+
+	for i := range expected {
+		if attrs[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, attrs)
+		}
+	}
+}
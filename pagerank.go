@@ -0,0 +1,175 @@
+package graph
+
+import "math"
+
+// DefaultDamping is the commonly used damping factor for PageRank.
+const DefaultDamping = 0.85
+
+// PageRank computes the PageRank of each node in the graph using the
+// given damping factor and number of power iterations.
+//
+// Every edge that isn't an inward edge is followed, so undirected (None)
+// and bi-directional (Both) edges contribute rank in both directions.
+// The rank of nodes without any outward edges is evenly distributed
+// across the graph. The returned ranks sum to 1.
+//
+// https://en.wikipedia.org/wiki/PageRank
+func (inst *Instance) PageRank(damping float64, iterations int) map[*Node]float64 {
+	defer inst.trace("PageRank")()
+
+	return inst.ParallelPageRank(damping, iterations, 1)
+}
+
+// PersonalizedPageRank computes the PageRank of each node in the graph
+// for random walks that restart from the given seed nodes, rather than
+// from any node, with the given probability at each step. Nodes that are
+// close to the seed nodes, or reachable from them in many ways, rank
+// highest, which is useful for recommendations and local relevance.
+//
+// Edges are followed like PageRank, and the rank of nodes without any
+// outward edges is distributed across the seed nodes. The restart
+// probability should be between 0 and 1, where 0.15 corresponds to the
+// DefaultDamping factor. Seed nodes outside of the graph are ignored, and
+// if no seed nodes are in the graph, no ranks are returned. The returned
+// ranks sum to 1.
+//
+// https://en.wikipedia.org/wiki/PageRank#Variations
+func (inst *Instance) PersonalizedPageRank(seed NodeSet, restart float64) map[*Node]float64 {
+	defer inst.trace("PersonalizedPageRank")()
+
+	rg := newRankGraph(inst)
+
+	n := len(rg.nodes)
+
+	// The restart distribution, spread evenly across the seed nodes.
+	personal := make([]float64, n)
+
+	var seeds int
+	for i, node := range rg.nodes {
+		if seed.Contains(node) {
+			personal[i] = 1
+			seeds++
+		}
+	}
+
+	if seeds == 0 {
+		return map[*Node]float64{}
+	}
+
+	for i := range personal {
+		personal[i] /= float64(seeds)
+	}
+
+	rank := make([]float64, n)
+	next := make([]float64, n)
+
+	copy(rank, personal)
+
+	damping := 1 - restart
+
+	for iter := 0; iter < personalizedIterations; iter++ {
+		dangling := rg.dangling(rank)
+
+		var delta float64
+
+		for v := range next {
+			var sum float64
+			for _, u := range rg.in[v] {
+				sum += rank[u] / float64(rg.outDeg[u])
+			}
+			next[v] = restart*personal[v] + damping*(sum+dangling*personal[v])
+			delta += math.Abs(next[v] - rank[v])
+		}
+
+		rank, next = next, rank
+
+		if delta < personalizedTolerance {
+			break
+		}
+	}
+
+	return rg.ranks(rank)
+}
+
+const (
+	// personalizedIterations is the maximum number of power iterations
+	// used to compute personalized PageRank.
+	personalizedIterations = 100
+
+	// personalizedTolerance is the total change in rank between power
+	// iterations below which personalized PageRank has converged.
+	personalizedTolerance = 1e-10
+)
+
+// rankGraph is an index based representation of the graph used
+// to efficiently compute PageRank values.
+type rankGraph struct {
+	nodes  Nodes
+	in     [][]int
+	outDeg []int
+}
+
+// newRankGraph returns the index based representation of the graph,
+// ignoring edges to nodes outside of the graph instance.
+func newRankGraph(inst *Instance) *rankGraph {
+	index := make(map[*Node]int, len(inst.Nodes))
+	for i, node := range inst.Nodes {
+		index[node] = i
+	}
+
+	rg := &rankGraph{
+		nodes:  inst.Nodes,
+		in:     make([][]int, len(inst.Nodes)),
+		outDeg: make([]int, len(inst.Nodes)),
+	}
+
+	for i, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if edge.Direction == In {
+				continue
+			}
+
+			j, ok := index[edge.Node]
+			if !ok {
+				continue
+			}
+
+			rg.outDeg[i]++
+			rg.in[j] = append(rg.in[j], i)
+		}
+	}
+
+	return rg
+}
+
+// iterate performs a single power iteration for the nodes in the given
+// index range, writing the results into next.
+func (rg *rankGraph) iterate(rank, next []float64, damping, base float64, start, end int) {
+	for v := start; v < end; v++ {
+		var sum float64
+		for _, u := range rg.in[v] {
+			sum += rank[u] / float64(rg.outDeg[u])
+		}
+		next[v] = base + damping*sum
+	}
+}
+
+// dangling returns the total rank held by nodes without outward edges.
+func (rg *rankGraph) dangling(rank []float64) float64 {
+	var total float64
+	for i, deg := range rg.outDeg {
+		if deg == 0 {
+			total += rank[i]
+		}
+	}
+	return total
+}
+
+// ranks converts the given rank values into a map keyed by node.
+func (rg *rankGraph) ranks(rank []float64) map[*Node]float64 {
+	ranks := make(map[*Node]float64, len(rank))
+	for i, node := range rg.nodes {
+		ranks[node] = rank[i]
+	}
+	return ranks
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// partition splits n items into at most the given number of contiguous,
+// similarly sized [start, end) ranges.
+func partition(n, workers int) [][2]int {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	ranges := make([][2]int, 0, workers)
+
+	for w := 0; w < workers; w++ {
+		start := w * n / workers
+		end := (w + 1) * n / workers
+		ranges = append(ranges, [2]int{start, end})
+	}
+
+	return ranges
+}
+
+// parallelize runs fn for each range of the n items using the given
+// number of goroutines, and waits for them all to finish.
+func parallelize(n, workers int, fn func(worker, start, end int)) {
+	var wg sync.WaitGroup
+
+	for w, r := range partition(n, workers) {
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			fn(w, start, end)
+		}(w, r[0], r[1])
+	}
+
+	wg.Wait()
+}
+
+// ParallelBFS performs a level-synchronous breadth-first-search of the graph,
+// expanding each frontier using the given number of worker goroutines.
+//
+// Nodes are visited level by level like BFS, but the order of nodes within
+// a level is not deterministic. The given function is called concurrently
+// from multiple goroutines, so it must be safe for concurrent use.
+//
+// https://en.wikipedia.org/wiki/Parallel_breadth-first_search
+func (inst *Instance) ParallelBFS(fn func(*Node), workers int) {
+	if fn == nil {
+		return
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	// Create a map of nodes that have been visited, which is
+	// safe to claim nodes from using multiple goroutines.
+	visited := sync.Map{}
+
+	// Iterate over all the nodes in the graph.
+	for _, root := range inst.Nodes {
+		// If the node has already been visited, skip it.
+		if _, loaded := visited.LoadOrStore(root, struct{}{}); loaded {
+			continue
+		}
+
+		frontier := Nodes{root}
+
+		// While there are nodes in the frontier, expand them.
+		for len(frontier) > 0 {
+			next := make([]Nodes, workers)
+
+			parallelize(len(frontier), workers, func(w, start, end int) {
+				for _, node := range frontier[start:end] {
+					// Visit the node.
+					fn(node)
+
+					// Claim the node's unvisited children for the next frontier.
+					for _, child := range node.Out().Nodes() {
+						if _, loaded := visited.LoadOrStore(child, struct{}{}); !loaded {
+							next[w] = append(next[w], child)
+						}
+					}
+				}
+			})
+
+			frontier = frontier[:0:0]
+			for _, nodes := range next {
+				frontier = append(frontier, nodes...)
+			}
+		}
+	}
+}
+
+// ParallelComponents returns the weakly connected components of the graph
+// like Components, using the given number of worker goroutines to process
+// the edges of the graph with a concurrent union-find.
+//
+// https://en.wikipedia.org/wiki/Disjoint-set_data_structure
+func (inst *Instance) ParallelComponents(workers int) []NodeSet {
+	index := make(map[*Node]int32, len(inst.Nodes))
+	for i, node := range inst.Nodes {
+		if _, ok := index[node]; !ok {
+			index[node] = int32(i)
+		}
+	}
+
+	parent := make([]int32, len(inst.Nodes))
+	for i := range parent {
+		parent[i] = int32(i)
+	}
+
+	find := func(x int32) int32 {
+		for {
+			p := atomic.LoadInt32(&parent[x])
+			if p == x {
+				return x
+			}
+			gp := atomic.LoadInt32(&parent[p])
+			atomic.CompareAndSwapInt32(&parent[x], p, gp)
+			x = gp
+		}
+	}
+
+	union := func(a, b int32) {
+		for {
+			a, b = find(a), find(b)
+			if a == b {
+				return
+			}
+			// Always link the larger root to the smaller one, so
+			// concurrent unions can not create a cycle.
+			if a > b {
+				a, b = b, a
+			}
+			if atomic.CompareAndSwapInt32(&parent[b], b, a) {
+				return
+			}
+		}
+	}
+
+	parallelize(len(inst.Nodes), workers, func(_, start, end int) {
+		for i := start; i < end; i++ {
+			for _, edge := range inst.Nodes[i].Edges {
+				if j, ok := index[edge.Node]; ok {
+					union(int32(i), j)
+				}
+			}
+		}
+	})
+
+	components := []NodeSet{}
+	byRoot := map[int32]NodeSet{}
+
+	for i, node := range inst.Nodes {
+		root := find(int32(i))
+
+		component, ok := byRoot[root]
+		if !ok {
+			component = NodeSet{}
+			byRoot[root] = component
+			components = append(components, component)
+		}
+
+		component.Add(node)
+	}
+
+	return components
+}
+
+// ParallelPageRank computes the PageRank of each node like PageRank, using
+// the given number of worker goroutines for each power iteration.
+func (inst *Instance) ParallelPageRank(damping float64, iterations, workers int) map[*Node]float64 {
+	rg := newRankGraph(inst)
+
+	n := len(rg.nodes)
+	if n == 0 {
+		return map[*Node]float64{}
+	}
+
+	rank := make([]float64, n)
+	next := make([]float64, n)
+
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		base := (1-damping)/float64(n) + damping*rg.dangling(rank)/float64(n)
+
+		parallelize(n, workers, func(_, start, end int) {
+			rg.iterate(rank, next, damping, base, start, end)
+		})
+
+		rank, next = next, rank
+	}
+
+	return rg.ranks(rank)
+}
@@ -0,0 +1,175 @@
+package graph_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Components(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+		f = graph.NewNode("f", nil)
+	)
+
+	// a → b ← c   d ↔ e   f
+
+	a.AddEdge(b)
+	c.AddEdge(b)
+	d.AddLink(e)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e, f)))
+
+	expected := []string{"a, b, c", "d, e", "f"}
+
+	for _, workers := range []int{1, 2, 4, 16} {
+		for name, components := range map[string][]graph.NodeSet{
+			"sequential": g.Components(),
+			"parallel":   g.ParallelComponents(workers),
+		} {
+			if len(components) != len(expected) {
+				t.Fatalf("%s: expected %d components, got %d", name, len(expected), len(components))
+			}
+
+			for i, component := range components {
+				if component.String() != expected[i] {
+					t.Errorf("%s: component %d: expected %q, got %q", name, i, expected[i], component)
+				}
+			}
+		}
+	}
+}
+
+func TestInstance_PageRank(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	//  a → b → c
+	//  ↑   ↓   |
+	//  └── d ←─┘
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	b.AddEdge(d)
+	c.AddEdge(d)
+	d.AddEdge(a)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	ranks := g.PageRank(graph.DefaultDamping, 50)
+
+	var sum float64
+	for _, rank := range ranks {
+		sum += rank
+	}
+
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("expected ranks to sum to 1, got %v", sum)
+	}
+
+	if ranks[d] <= ranks[c] {
+		t.Fatalf("expected d to rank higher than c: %v <= %v", ranks[d], ranks[c])
+	}
+
+	parallelRanks := g.ParallelPageRank(graph.DefaultDamping, 50, 3)
+
+	for node, rank := range ranks {
+		if math.Abs(parallelRanks[node]-rank) > 1e-12 {
+			t.Errorf("node %s: expected rank %v, got %v", node.Name, rank, parallelRanks[node])
+		}
+	}
+}
+
+func TestInstance_PersonalizedPageRank(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+		f = graph.NewNode("f", nil)
+	)
+
+	// a ↔ b ↔ c    d ↔ e → f
+
+	a.AddLink(b)
+	b.AddLink(c)
+	d.AddLink(e)
+	e.AddEdge(f)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e, f)))
+
+	ranks := g.PersonalizedPageRank(graph.NewNodeSet(a), 0.15)
+
+	var sum float64
+	for _, rank := range ranks {
+		sum += rank
+	}
+
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("expected ranks to sum to 1, got %v", sum)
+	}
+
+	if ranks[a] <= ranks[c] {
+		t.Fatalf("expected a to rank higher than c: %v <= %v", ranks[a], ranks[c])
+	}
+
+	for _, node := range []*graph.Node{d, e, f} {
+		if ranks[node] != 0 {
+			t.Fatalf("expected %s to be unreachable from the seed, got %v", node.Name, ranks[node])
+		}
+	}
+
+	// Rank held by f, which has no outward edges, returns to the seed.
+	ranks = g.PersonalizedPageRank(graph.NewNodeSet(e), 0.15)
+
+	if ranks[a] != 0 || ranks[f] == 0 {
+		t.Fatalf("unexpected ranks seeded from e: a=%v f=%v", ranks[a], ranks[f])
+	}
+
+	if ranks := g.PersonalizedPageRank(graph.NewNodeSet(graph.NewNode("x", nil)), 0.15); len(ranks) != 0 {
+		t.Fatalf("expected no ranks without seed nodes in the graph, got %v", ranks)
+	}
+}
+
+func TestInstance_ParallelBFS(t *testing.T) {
+	g := graph.New("test")
+
+	nodes := graph.Nodes{}
+	for i := 0; i < 100; i++ {
+		nodes = append(nodes, graph.NewNode("", nil))
+	}
+
+	graph.ConnectNodes(nodes...)
+
+	g.AddNodes(nodes...)
+
+	var (
+		mu      sync.Mutex
+		visited = graph.NodeSet{}
+	)
+
+	g.ParallelBFS(func(n *graph.Node) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if visited.Contains(n) {
+			t.Errorf("node visited more than once")
+		}
+		visited.Add(n)
+	}, 8)
+
+	if len(visited) != len(nodes) {
+		t.Fatalf("expected %d visited nodes, got %d", len(nodes), len(visited))
+	}
+}
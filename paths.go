@@ -1,9 +1,6 @@
 package graph
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 // Path is an ordered set of Nodes that make a path from the start,
 // the first element in the slice, to the end, the last element in
@@ -32,15 +29,16 @@ func (path Path) ContainsNode(n *Node) bool {
 	return false
 }
 
-// String returns a human-readable string for the Path.
+// String returns a human-readable string for the Path, like "a → b → c",
+// using the Out glyph of the package's Stringer configuration.
 func (path Path) String() string {
-	var builder strings.Builder
-
-	for _, node := range path {
-		builder.WriteString(fmt.Sprintf("→ %s ", node.Name))
-	}
+	return path.Format(" " + Stringer.Out + " ")
+}
 
-	return strings.TrimSpace(strings.TrimPrefix(builder.String(), "→ "))
+// Format returns the names of the path's nodes joined by the given
+// separator, like "a -> b -> c" for " -> ".
+func (path Path) Format(sep string) string {
+	return strings.Join(Nodes(path).Names(), sep)
 }
 
 // ContainsPath checks if the given path is identical to any of one
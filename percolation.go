@@ -0,0 +1,93 @@
+package graph
+
+import "math/rand"
+
+// ReachabilityProbability estimates the probability that the destination
+// node is reachable from the source node when each edge of the graph is
+// only up with the probability returned by the given function, using the
+// given number of Monte Carlo trials. This is useful to model the
+// availability of connections over redundant network topologies:
+//
+//	    ↗ b ↘         p := g.ReachabilityProbability(a, d, func(*graph.Edge) float64 {
+//	a         d           return 0.9
+//	    ↘ c ↗         }, 10000) // ≈ 1 - (1 - 0.9²)² = 0.96
+//
+// Paths follow edges that aren't inward edges, like Visit, so undirected
+// (None) and bi-directional (Both) edges can be followed either way, with
+// the same outcome for both ways in a trial. Probabilities are clamped
+// between 0 and 1, and if the function is nil, every edge is up.
+//
+// Trials use a fixed seed, so the same estimate is returned for the same
+// graph. The standard error of the estimate p is √(p(1-p)/trials), so 10000
+// trials give estimates within about 0.01. A source node that is also the
+// destination is always reachable, and 0 is returned if either node isn't
+// in the graph, or trials is less than 1.
+//
+// https://en.wikipedia.org/wiki/Percolation_theory
+func (inst *Instance) ReachabilityProbability(src, dst *Node, pEdgeUp func(*Edge) float64, trials int) float64 {
+	members := inst.members()
+	if !members.Contains(src) || !members.Contains(dst) || trials < 1 {
+		return 0
+	}
+	if src == dst {
+		return 1
+	}
+
+	probability := func(edge *Edge) float64 {
+		if pEdgeUp == nil {
+			return 1
+		}
+		p := pEdgeUp(edge)
+		switch {
+		case p < 0:
+			return 0
+		case p > 1:
+			return 1
+		}
+		return p
+	}
+
+	rng := rand.New(rand.NewSource(1))
+
+	var reached int
+	for trial := 0; trial < trials; trial++ {
+		// Edges are only sampled when they're followed, and the outcome is
+		// shared with the other side of the relationship.
+		up := map[*Edge]bool{}
+
+		visited := NewNodeSet(src)
+		queue := Nodes{src}
+
+		for len(queue) > 0 && !visited.Contains(dst) {
+			node := queue[0]
+			queue = queue[1:]
+
+			for _, edge := range node.Edges {
+				if edge.Direction == In || !members.Contains(edge.Node) || visited.Contains(edge.Node) {
+					continue
+				}
+
+				ok, sampled := up[edge]
+				if !sampled {
+					ok = rng.Float64() < probability(edge)
+					up[edge] = ok
+					if other := edge.reciprocal(node); other != nil {
+						up[other] = ok
+					}
+				}
+				if !ok {
+					continue
+				}
+
+				visited.Add(edge.Node)
+				queue = append(queue, edge.Node)
+			}
+		}
+
+		if visited.Contains(dst) {
+			reached++
+		}
+	}
+
+	return float64(reached) / float64(trials)
+}
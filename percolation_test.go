@@ -0,0 +1,74 @@
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_ReachabilityProbability(t *testing.T) {
+	g := graph.New("test")
+
+	//     ↗ b ↘
+	// a         d    e
+	//     ↘ c ↗
+
+	err := g.LoadEdges([][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		a, _ = g.Lookup("a")
+		d, _ = g.Lookup("d")
+		e    = graph.NewNode("e", nil)
+	)
+	g.AddNode(e)
+
+	up := func(*graph.Edge) float64 { return 0.9 }
+
+	p := g.ReachabilityProbability(a, d, up, 10000)
+	if want := 1 - math.Pow(1-0.9*0.9, 2); math.Abs(p-want) > 0.01 {
+		t.Fatalf("expected probability close to %.4f, got %.4f", want, p)
+	}
+
+	if again := g.ReachabilityProbability(a, d, up, 10000); again != p {
+		t.Fatalf("expected the same estimate, got %v and %v", p, again)
+	}
+
+	tests := []struct {
+		name     string
+		src, dst *graph.Node
+		up       func(*graph.Edge) float64
+		want     float64
+	}{
+		{"always up", a, d, nil, 1},
+		{"always down", a, d, func(*graph.Edge) float64 { return -1 }, 0},
+		{"against direction", d, a, nil, 0},
+		{"unreachable", a, e, nil, 0},
+		{"same node", a, a, nil, 1},
+		{"outside", a, graph.NewNode("outside", nil), nil, 0},
+	}
+
+	for _, test := range tests {
+		if got := g.ReachabilityProbability(test.src, test.dst, test.up, 100); got != test.want {
+			t.Fatalf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestInstance_ReachabilityProbability_undirected(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+	a.AddEdgeWithDirection(b, graph.None)
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b}))
+
+	p := g.ReachabilityProbability(b, a, func(*graph.Edge) float64 { return 0.5 }, 10000)
+	if math.Abs(p-0.5) > 0.02 {
+		t.Fatalf("expected probability close to 0.5, got %.4f", p)
+	}
+}
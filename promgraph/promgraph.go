@@ -0,0 +1,153 @@
+// Package promgraph exposes statistics about a graph as Prometheus metrics,
+// to monitor the growth of graphs kept by long-lived services.
+//
+//	c := promgraph.NewCollector(g, promgraph.WithLock(&mu))
+//	prometheus.MustRegister(c)
+//
+// The collector exposes the following metrics, labeled with the name of
+// the graph:
+//
+//	graph_nodes           the number of nodes in the graph
+//	graph_edges           the number of edges (relationships) in the graph
+//	graph_components      the number of weakly connected components
+//	graph_updates_total   the number of changes made to the graph
+//
+// The update rate can be graphed using rate(graph_updates_total[5m]).
+// Updates are counted from the mutations of the graph delivered by
+// Subscribe, so each change, like adding an edge, counts once. Call Close
+// when the collector is no longer used, to cancel its subscription.
+//
+// It is a separate module, so the graph module doesn't depend on the
+// Prometheus client.
+package promgraph
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/picatz/graph"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector that exposes statistics about a graph,
+// computed each time the metrics are collected.
+type Collector struct {
+	graph *graph.Instance
+	lock  sync.Locker
+
+	namespace   string
+	constLabels prometheus.Labels
+
+	nodes      *prometheus.Desc
+	edges      *prometheus.Desc
+	components *prometheus.Desc
+	updates    *prometheus.Desc
+
+	// count is the number of mutations of the graph seen since the
+	// collector was created, and cancel cancels the subscription.
+	count  uint64
+	cancel func()
+	done   chan struct{}
+}
+
+// WithNamespace is a functional option that sets the namespace of the
+// collector's metrics, which is "graph" by default.
+func WithNamespace(namespace string) func(*Collector) {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithConstLabels is a functional option that adds constant labels to
+// the collector's metrics, in addition to the name of the graph.
+func WithConstLabels(labels prometheus.Labels) func(*Collector) {
+	return func(c *Collector) {
+		for name, value := range labels {
+			c.constLabels[name] = value
+		}
+	}
+}
+
+// WithLock is a functional option that sets the lock held while collecting
+// statistics, which should be the lock held by the service while changing
+// the graph, since collection happens concurrently with the service.
+func WithLock(lock sync.Locker) func(*Collector) {
+	return func(c *Collector) {
+		c.lock = lock
+	}
+}
+
+// NewCollector returns a new collector for the given graph, which
+// subscribes to its mutations to count updates, see Close.
+func NewCollector(inst *graph.Instance, opts ...func(*Collector)) *Collector {
+	c := &Collector{
+		graph:       inst,
+		namespace:   "graph",
+		constLabels: prometheus.Labels{"graph": inst.Name},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(c.namespace, "", name), help, nil, c.constLabels)
+	}
+
+	c.nodes = desc("nodes", "The number of nodes in the graph.")
+	c.edges = desc("edges", "The number of edges (relationships) in the graph.")
+	c.components = desc("components", "The number of weakly connected components of the graph.")
+	c.updates = desc("updates_total", "The number of changes made to the graph.")
+
+	mutations, cancel := inst.Subscribe()
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+		for m := range mutations {
+			// Mutations dropped because the collector didn't keep up with
+			// them still count as updates.
+			atomic.AddUint64(&c.count, 1+uint64(m.Dropped))
+		}
+	}()
+
+	return c
+}
+
+// Close cancels the collector's subscription to the mutations of the graph,
+// after which updates are no longer counted.
+func (c *Collector) Close() {
+	c.cancel()
+	<-c.done
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.nodes
+	ch <- c.edges
+	ch <- c.components
+	ch <- c.updates
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	if c.lock != nil {
+		c.lock.Lock()
+	}
+
+	var (
+		nodes      = c.graph.NodeCount()
+		edges      = c.graph.EdgeCount()
+		components = len(c.graph.Components())
+	)
+
+	if c.lock != nil {
+		c.lock.Unlock()
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.nodes, prometheus.GaugeValue, float64(nodes))
+	ch <- prometheus.MustNewConstMetric(c.edges, prometheus.GaugeValue, float64(edges))
+	ch <- prometheus.MustNewConstMetric(c.components, prometheus.GaugeValue, float64(components))
+	ch <- prometheus.MustNewConstMetric(c.updates, prometheus.CounterValue, float64(atomic.LoadUint64(&c.count)))
+}
@@ -0,0 +1,73 @@
+package promgraph_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/promgraph"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	// a → b    c
+
+	g.AddEdge(a, b)
+
+	collector := promgraph.NewCollector(g, promgraph.WithNamespace("example"))
+	defer collector.Close()
+
+	expected := `
+		# HELP example_nodes The number of nodes in the graph.
+		# TYPE example_nodes gauge
+		example_nodes{graph="test"} 3
+		# HELP example_edges The number of edges (relationships) in the graph.
+		# TYPE example_edges gauge
+		example_edges{graph="test"} 1
+		# HELP example_components The number of weakly connected components of the graph.
+		# TYPE example_components gauge
+		example_components{graph="test"} 2
+	`
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "example_nodes", "example_edges", "example_components")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := testutil.CollectAndCount(collector); count != 4 {
+		t.Fatalf("expected 4 metrics, got %d", count)
+	}
+
+	// a → b → c, with one update per change.
+
+	g.AddEdge(b, c)
+	c.SetAttribute("color", "red")
+
+	expected = `
+		# HELP example_updates_total The number of changes made to the graph.
+		# TYPE example_updates_total counter
+		example_updates_total{graph="test"} 2
+	`
+
+	// Mutations are counted as they are delivered to the collector.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "example_updates_total")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,116 @@
+package graph
+
+// removeNodes removes the given nodes from the graph, along with all of the
+// edges other nodes have with them, like RemoveNode, but in a single pass
+// over the graph's nodes.
+func (inst *Instance) removeNodes(remove NodeSet) {
+	if len(remove) == 0 {
+		return
+	}
+
+	for node := range remove {
+		for _, edge := range node.Edges {
+			if !remove.Contains(edge.Node) {
+				edge.Node.Edges = edge.Node.Edges.ButNotWith(node)
+				edge.Node.touch()
+			}
+		}
+	}
+
+	for node := range remove {
+		node.Edges = nil
+	}
+
+	inst.detachAll(remove)
+}
+
+// Prune removes the nodes of the graph matching the given predicate, along
+// with all of the edges other nodes have with them, and returns the number
+// of nodes removed.
+func (inst *Instance) Prune(pred func(*Node) bool) int {
+	remove := NodeSet{}
+	for _, node := range inst.Nodes {
+		if pred(node) {
+			remove.Add(node)
+		}
+	}
+
+	inst.removeNodes(remove)
+
+	return len(remove)
+}
+
+// RemoveIsolated removes the nodes of the graph without edges to other
+// nodes of the graph, and returns the number of nodes removed. Edges from a
+// node to itself, or to nodes outside of the graph, don't count, and are
+// removed along with the node.
+func (inst *Instance) RemoveIsolated() int {
+	neighbors := undirectedNeighbors(inst.Nodes)
+
+	return inst.Prune(func(n *Node) bool {
+		return len(neighbors[n].Difference(NewNodeSet(n))) == 0
+	})
+}
+
+// PruneLeaves repeatedly removes the leaves of the graph, the nodes with
+// edges to exactly one other node of the graph, ignoring edge directions,
+// and returns the number of nodes removed. All of the leaves found in an
+// iteration are removed at once, then the next iteration removes the nodes
+// that became leaves, until the given number of iterations is done, or no
+// leaves are left if it is less than 1.
+//
+//	a ─ b ─ c ─ d        PruneLeaves(1) removes a
+//	         ╲ ╱         PruneLeaves(0) removes a and b,
+//	          e          leaving the cycle c ─ d ─ e
+//
+// Pruning leaves until none are left reduces a graph to its cycles and the
+// paths between them, and removes trees, like the helpers of a call graph,
+// entirely. Nodes left without any neighbors, like the center of a star,
+// are removed along with the leaves they were connected to. Isolated
+// nodes that weren't connected to leaves are kept, see RemoveIsolated.
+func (inst *Instance) PruneLeaves(iterations int) int {
+	neighbors := undirectedNeighbors(inst.Nodes)
+
+	degree := make(map[*Node]int, len(inst.Nodes))
+	for node, adjacent := range neighbors {
+		degree[node] = len(adjacent)
+		if adjacent.Contains(node) {
+			degree[node]--
+		}
+	}
+
+	remove := NodeSet{}
+
+	for i := 0; iterations < 1 || i < iterations; i++ {
+		var leaves Nodes
+		for _, node := range inst.Nodes {
+			if !remove.Contains(node) && degree[node] == 1 {
+				leaves = append(leaves, node)
+			}
+		}
+
+		if len(leaves) == 0 {
+			break
+		}
+
+		for _, leaf := range leaves {
+			remove.Add(leaf)
+		}
+
+		for _, leaf := range leaves {
+			for neighbor := range neighbors[leaf] {
+				if neighbor == leaf || remove.Contains(neighbor) {
+					continue
+				}
+				degree[neighbor]--
+				if degree[neighbor] == 0 {
+					remove.Add(neighbor)
+				}
+			}
+		}
+	}
+
+	inst.removeNodes(remove)
+
+	return len(remove)
+}
@@ -0,0 +1,85 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+// pruneGraph returns a graph with a tail leading to a cycle, a star, and
+// an isolated node:
+//
+//	a ─ b ─ c ─ d    f ─ g    i
+//	         ╲ ╱     │
+//	          e      h
+func pruneGraph() *graph.Instance {
+	nodes := graph.Nodes{}
+	byName := map[string]*graph.Node{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"} {
+		node := graph.NewNode(name, nil)
+		nodes = append(nodes, node)
+		byName[name] = node
+	}
+
+	for _, pair := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "e"}, {"e", "c"}, {"f", "g"}, {"f", "h"}} {
+		byName[pair[0]].AddEdge(byName[pair[1]])
+	}
+
+	// Self loops don't make a node a leaf, or keep it from being isolated.
+	byName["i"].AddEdge(byName["i"])
+
+	return graph.New("test", graph.WithNodes(nodes))
+}
+
+func TestInstance_PruneLeaves(t *testing.T) {
+	g := pruneGraph()
+
+	if removed := g.PruneLeaves(1); removed != 4 || g.Nodes.String() != "b, c, d, e, i" {
+		t.Fatalf("unexpected pruning: removed %d, left %v", removed, g.Nodes)
+	}
+
+	if removed := g.PruneLeaves(0); removed != 1 || g.Nodes.String() != "c, d, e, i" {
+		t.Fatalf("unexpected pruning: removed %d, left %v", removed, g.Nodes)
+	}
+
+	c, _ := g.Lookup("c")
+	if len(c.Edges) != 2 {
+		t.Fatalf("expected c to only have edges to d and e, got: %v", c.Edges)
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstance_RemoveIsolated(t *testing.T) {
+	g := pruneGraph()
+
+	if removed := g.RemoveIsolated(); removed != 1 || g.Nodes.String() != "a, b, c, d, e, f, g, h" {
+		t.Fatalf("unexpected removal: removed %d, left %v", removed, g.Nodes)
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstance_Prune(t *testing.T) {
+	g := pruneGraph()
+
+	removed := g.Prune(func(n *graph.Node) bool {
+		return n.Name == "c" || n.Name == "f"
+	})
+
+	if removed != 2 || g.Nodes.String() != "a, b, d, e, g, h, i" {
+		t.Fatalf("unexpected pruning: removed %d, left %v", removed, g.Nodes)
+	}
+
+	if len(g.Components()) != 5 {
+		t.Fatalf("expected 5 components, got: %v", g.Components())
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,549 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// QueryResult is the result of a graph query, with a row of nodes for
+// each match, containing the nodes for the returned columns in order.
+type QueryResult struct {
+	Columns []string
+	Rows    []Nodes
+}
+
+// Query runs a small, Cypher-like pattern matching query against the graph.
+//
+//	MATCH (a:Service)-[:depends_on]->(b)<--(c) WHERE a.env = 'prod' AND c.name <> 'x' RETURN a, b
+//
+// The MATCH clause is a comma separated list of patterns, made up of node
+// variables in parentheses, optionally followed by the labels the node must
+// have, like (a:Service:Public), joined by relationships. A relationship can be
+// outward (-->), inward (<--), or in any direction (--), and optionally
+// restrict the edge name (the relationship type) using [:name]. Variables
+// used more than once must match the same node.
+//
+// The optional WHERE clause is a list of comparisons of node attributes
+// against string, number, or boolean literals using =, <>, or !=, joined
+// by AND. Numbers can be negative, like a.offset = -1. The "name" property
+// refers to the node's name.
+//
+// The RETURN clause lists the variables to return for each match.
+//
+// https://neo4j.com/docs/cypher-manual/current/clauses/match/
+func (inst *Instance) Query(q string) (*QueryResult, error) {
+	parsed, err := parseQuery(q)
+	if err != nil {
+		return nil, fmt.Errorf("graph failed to parse query: %w", err)
+	}
+
+	result := &QueryResult{Columns: parsed.returns}
+
+	matchPatterns(inst, parsed.patterns, false, func(bindings map[string]*Node, _ Edges) bool {
+		for _, cond := range parsed.where {
+			if !cond.eval(bindings[cond.variable]) {
+				return true
+			}
+		}
+		row := make(Nodes, len(parsed.returns))
+		for c, variable := range parsed.returns {
+			row[c] = bindings[variable]
+		}
+		result.Rows = append(result.Rows, row)
+		return true
+	})
+
+	return result, nil
+}
+
+// matchPatterns calls the given function with each match of the given
+// patterns in the graph, until it returns false, with the nodes bound to
+// each variable, and the edges matched by the relationships of the
+// patterns, in order. If distinct is true, different variables must match
+// different nodes.
+func matchPatterns(inst *Instance, patterns []queryPattern, distinct bool, fn func(bindings map[string]*Node, edges Edges) bool) {
+	var (
+		bindings = map[string]*Node{}
+		bound    = NodeSet{}
+		edges    = Edges{}
+	)
+
+	// Like Cypher, a relationship can only be used once per match.
+	used := map[*Edge]bool{}
+
+	var match func(p, i int) bool
+	match = func(p, i int) bool {
+		if p == len(patterns) {
+			return fn(bindings, edges)
+		}
+
+		pattern := patterns[p]
+
+		if i == len(pattern.variables) {
+			return match(p+1, 0)
+		}
+
+		variable := pattern.variables[i]
+
+		current, alreadyBound := bindings[variable]
+
+		try := func(candidate *Node) bool {
+			if !candidate.hasLabels(pattern.labels[i]) {
+				return true
+			}
+
+			if alreadyBound {
+				if candidate == current {
+					return match(p, i+1)
+				}
+				return true
+			}
+
+			if distinct && bound.Contains(candidate) {
+				return true
+			}
+
+			bindings[variable] = candidate
+			bound.Add(candidate)
+			more := match(p, i+1)
+			delete(bindings, variable)
+			delete(bound, candidate)
+			return more
+		}
+
+		if i == 0 {
+			for _, candidate := range inst.Nodes {
+				if !try(candidate) {
+					return false
+				}
+			}
+			return true
+		}
+
+		from := bindings[pattern.variables[i-1]]
+
+		for _, edge := range pattern.relationships[i-1].follow(from) {
+			if used[edge] {
+				continue
+			}
+
+			other := edge.reciprocal(from)
+
+			used[edge], used[other] = true, true
+			edges = append(edges, edge)
+			more := try(edge.Node)
+			edges = edges[:len(edges)-1]
+			delete(used, edge)
+			delete(used, other)
+
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+
+	match(0, 0)
+}
+
+// parsedQuery is the parsed form of a query.
+type parsedQuery struct {
+	patterns []queryPattern
+	where    []queryCondition
+	returns  []string
+}
+
+// queryPattern is a chain of node variables joined by relationships.
+type queryPattern struct {
+	variables     []string
+	labels        [][]string
+	relationships []queryRelationship
+}
+
+// queryRelationship is a relationship between two node variables.
+type queryRelationship struct {
+	name      string
+	direction EdgeDirection
+}
+
+// follow returns the edges of the given node matching the relationship.
+func (rel queryRelationship) follow(n *Node) Edges {
+	var edges Edges
+	for _, edge := range n.Edges {
+		if rel.name != "" && edge.Name != rel.name {
+			continue
+		}
+		if rel.direction != Unknown && !edge.Direction.Match(rel.direction) {
+			continue
+		}
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+// queryCondition compares a node's attribute to a literal value.
+type queryCondition struct {
+	variable string
+	property string
+	negate   bool
+	value    any
+}
+
+// eval returns true if the condition holds for the given node.
+func (cond queryCondition) eval(n *Node) bool {
+	var (
+		actual any
+		ok     bool
+	)
+
+	if actual, ok = n.Attributes[cond.property]; !ok && cond.property == "name" {
+		actual, ok = n.Name, true
+	}
+
+	equal := ok && attributeEqual(actual, cond.value)
+
+	return equal != cond.negate
+}
+
+// attributeEqual compares an attribute value to a literal value, comparing
+// all numeric types by their value.
+func attributeEqual(actual, literal any) bool {
+	if lf, ok := literal.(float64); ok {
+		af, ok := toFloat(actual)
+		return ok && af == lf
+	}
+	return actual == literal
+}
+
+// queryParser is a simple recursive descent parser for queries.
+type queryParser struct {
+	tokens []string
+	pos    int
+	anon   int
+}
+
+// parseQuery parses the given query.
+func parseQuery(q string) (*parsedQuery, error) {
+	tokens, err := tokenizeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	parsed := &parsedQuery{}
+
+	if err := p.expectKeyword("MATCH"); err != nil {
+		return nil, err
+	}
+
+	for {
+		pattern, err := p.parsePattern()
+		if err != nil {
+			return nil, err
+		}
+		parsed.patterns = append(parsed.patterns, pattern)
+
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+
+	if p.isKeyword("WHERE") {
+		p.next()
+		for {
+			cond, err := p.parseCondition()
+			if err != nil {
+				return nil, err
+			}
+			parsed.where = append(parsed.where, cond)
+
+			if !p.isKeyword("AND") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if err := p.expectKeyword("RETURN"); err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, pattern := range parsed.patterns {
+		for _, variable := range pattern.variables {
+			known[variable] = true
+		}
+	}
+
+	for {
+		variable := p.next()
+		if !isIdentifier(variable) || !known[variable] {
+			return nil, fmt.Errorf("unknown variable %q in RETURN", variable)
+		}
+		parsed.returns = append(parsed.returns, variable)
+
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+
+	for _, cond := range parsed.where {
+		if !known[cond.variable] {
+			return nil, fmt.Errorf("unknown variable %q in WHERE", cond.variable)
+		}
+	}
+
+	if p.peek() != "" {
+		return nil, fmt.Errorf("unexpected %q after RETURN", p.peek())
+	}
+
+	return parsed, nil
+}
+
+// peek returns the current token without consuming it, or an
+// empty string if there are no more tokens.
+func (p *queryParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *queryParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+// expect consumes the current token, which must be the given token.
+func (p *queryParser) expect(token string) error {
+	if got := p.next(); got != token {
+		return fmt.Errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+// isKeyword checks if the current token is the given keyword, ignoring case.
+func (p *queryParser) isKeyword(keyword string) bool {
+	return strings.EqualFold(p.peek(), keyword)
+}
+
+// expectKeyword consumes the current token, which must be the given keyword.
+func (p *queryParser) expectKeyword(keyword string) error {
+	if !p.isKeyword(keyword) {
+		return fmt.Errorf("expected %s, got %q", keyword, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+// parsePattern parses a pattern like (a)-->(b)<-[:name]-(c).
+func (p *queryParser) parsePattern() (queryPattern, error) {
+	pattern := queryPattern{}
+
+	for {
+		variable, labels, err := p.parseNode()
+		if err != nil {
+			return pattern, err
+		}
+		pattern.variables = append(pattern.variables, variable)
+		pattern.labels = append(pattern.labels, labels)
+
+		if p.peek() != "-" && p.peek() != "<" {
+			return pattern, nil
+		}
+
+		rel, err := p.parseRelationship()
+		if err != nil {
+			return pattern, err
+		}
+		pattern.relationships = append(pattern.relationships, rel)
+	}
+}
+
+// parseNode parses a node variable like (a) or (a:Label), or an anonymous
+// node like () or (:Label).
+func (p *queryParser) parseNode() (string, []string, error) {
+	if err := p.expect("("); err != nil {
+		return "", nil, err
+	}
+
+	var variable string
+
+	if p.peek() == ")" || p.peek() == ":" {
+		p.anon++
+		variable = fmt.Sprintf(" anon%d", p.anon)
+	} else {
+		variable = p.next()
+		if !isIdentifier(variable) {
+			return "", nil, fmt.Errorf("invalid node variable %q", variable)
+		}
+	}
+
+	var labels []string
+
+	for p.peek() == ":" {
+		p.next()
+		label := p.next()
+		if !isIdentifier(label) {
+			return "", nil, fmt.Errorf("invalid node label %q", label)
+		}
+		labels = append(labels, label)
+	}
+
+	return variable, labels, p.expect(")")
+}
+
+// parseRelationship parses a relationship like -->, <--, --, or -[:name]->.
+func (p *queryParser) parseRelationship() (queryRelationship, error) {
+	rel := queryRelationship{}
+
+	inward := p.peek() == "<"
+	if inward {
+		p.next()
+	}
+
+	if err := p.expect("-"); err != nil {
+		return rel, err
+	}
+
+	if p.peek() == "[" {
+		p.next()
+		for p.peek() != "]" {
+			token := p.next()
+			switch {
+			case token == "":
+				return rel, fmt.Errorf("unterminated relationship")
+			case token == ":":
+				rel.name = p.next()
+			}
+		}
+		p.next()
+	}
+
+	if err := p.expect("-"); err != nil {
+		return rel, err
+	}
+
+	outward := p.peek() == ">"
+	if outward {
+		p.next()
+	}
+
+	switch {
+	case inward && outward:
+		return rel, fmt.Errorf("relationship can not be both inward and outward")
+	case inward:
+		rel.direction = In
+	case outward:
+		rel.direction = Out
+	default:
+		rel.direction = Unknown
+	}
+
+	return rel, nil
+}
+
+// parseCondition parses a condition like a.env = 'prod'.
+func (p *queryParser) parseCondition() (queryCondition, error) {
+	cond := queryCondition{variable: p.next()}
+
+	if err := p.expect("."); err != nil {
+		return cond, err
+	}
+
+	cond.property = p.next()
+	if !isIdentifier(cond.property) {
+		return cond, fmt.Errorf("invalid property %q", cond.property)
+	}
+
+	switch op := p.next(); op {
+	case "=":
+	case "<>", "!=":
+		cond.negate = true
+	default:
+		return cond, fmt.Errorf("unsupported operator %q", op)
+	}
+
+	literal := p.next()
+
+	// Negative numbers are tokenized as a minus sign and the number.
+	if literal == "-" && p.peek() != "" && unicode.IsDigit([]rune(p.peek())[0]) {
+		literal += p.next()
+	}
+
+	switch {
+	case strings.HasPrefix(literal, "'") || strings.HasPrefix(literal, `"`):
+		cond.value = literal[1 : len(literal)-1]
+	case strings.EqualFold(literal, "true"), strings.EqualFold(literal, "false"):
+		cond.value = strings.EqualFold(literal, "true")
+	default:
+		f, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			return cond, fmt.Errorf("invalid literal %q", literal)
+		}
+		cond.value = f
+	}
+
+	return cond, nil
+}
+
+// isIdentifier checks if the given token is a valid identifier.
+func isIdentifier(token string) bool {
+	if token == "" {
+		return false
+	}
+	for i, r := range token {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenizeQuery splits the given query into tokens.
+func tokenizeQuery(q string) ([]string, error) {
+	var tokens []string
+
+	runes := []rune(q)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		case r == '<' && i+1 < len(runes) && runes[i+1] == '>',
+			r == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, string(runes[i:i+2]))
+			i += 2
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (runes[end] == '_' || runes[end] == '.' && unicode.IsDigit(r) || unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,92 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Query(t *testing.T) {
+	var (
+		web   = graph.NewNode("web", graph.Attributes{"env": "prod", "replicas": 3})
+		api   = graph.NewNode("api", graph.Attributes{"env": "prod"})
+		db    = graph.NewNode("db", graph.Attributes{"env": "prod"})
+		stage = graph.NewNode("stage", graph.Attributes{"env": "staging", "offset": -1.5})
+	)
+
+	// web → api → db ← stage
+
+	web.AddEdge(api)
+	api.AddEdge(db)
+	stage.AddEdge(db)
+
+	api.Edges.Out()[0].Name = "queries"
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(web, api, db, stage)))
+
+	tests := []struct {
+		Query    string
+		Expected []string
+	}{
+		{
+			Query:    "MATCH (a)-[→]->(b) WHERE a.env = 'prod' RETURN a, b",
+			Expected: []string{"web, api", "api, db"},
+		},
+		{
+			Query:    "match (a)-[:queries]->(b) return b",
+			Expected: []string{"db"},
+		},
+		{
+			Query:    "MATCH (a)-->(b)<--(c) WHERE c.env <> 'prod' RETURN a, c",
+			Expected: []string{"api, stage"},
+		},
+		{
+			Query:    "MATCH (a)--(b) WHERE b.name = 'api' AND a.replicas = 3 RETURN a",
+			Expected: []string{"web"},
+		},
+		{
+			Query:    "MATCH (a)-->(b) WHERE a.offset = -1.5 RETURN a",
+			Expected: []string{"stage"},
+		},
+		{
+			Query:    "MATCH (a)-->(b) WHERE a.offset <> -1 AND b.env = 'prod' RETURN a",
+			Expected: []string{"web", "api", "stage"},
+		},
+		{
+			Query:    "MATCH (a)-->(b), (b)-->(c) RETURN a, b, c",
+			Expected: []string{"web, api, db"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Query, func(t *testing.T) {
+			result, err := g.Query(test.Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Rows) != len(test.Expected) {
+				t.Fatalf("expected %d rows, got %d: %v", len(test.Expected), len(result.Rows), result.Rows)
+			}
+
+			for i, row := range result.Rows {
+				if row.String() != test.Expected[i] {
+					t.Errorf("row %d: expected %q, got %q", i, test.Expected[i], row)
+				}
+			}
+		})
+	}
+
+	for _, invalid := range []string{
+		"RETURN a",
+		"MATCH (a) RETURN b",
+		"MATCH (a)<-->(b) RETURN a",
+		"MATCH (a) WHERE a.env > 1 RETURN a",
+		"MATCH (a) WHERE a.env = - RETURN a",
+		"MATCH (a) WHERE a.env = -'prod' RETURN a",
+	} {
+		if _, err := g.Query(invalid); err == nil {
+			t.Errorf("expected error for query %q", invalid)
+		}
+	}
+}
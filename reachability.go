@@ -0,0 +1,169 @@
+package graph
+
+import "container/heap"
+
+// ReachableFrom returns the nodes that have a path to the node, found by
+// walking every edge that isn't an outward edge, which is the reverse of
+// Reachable(graph.Out). The node itself is not included.
+//
+// If edges point from dependents to their dependencies, these are all of
+// the nodes that directly or transitively depend on the node.
+//
+//	a → b → c ← d    c.ReachableFrom(): {a, b, d}
+//
+// Traversal options, like WithEdgeFilter, restrict the edges followed.
+func (n *Node) ReachableFrom(opts ...func(*VisitOrder)) NodeSet {
+	return n.Reachable(In, opts...)
+}
+
+// Reachable returns all of the nodes reachable from the node by following
+// edges in the given direction, like HasPath: "out" follows every edge that
+// isn't an inward edge, including undirected (None) edges, "in" follows
+// every edge that isn't an outward edge, and any other direction follows
+// every edge. The node itself is not included.
+//
+//	a → b → c ← d    a.Reachable(graph.Out): {b, c}
+//	                 c.Reachable(graph.In):  {a, b, d}
+//
+// Traversal options, like WithEdgeFilter, restrict the edges followed.
+func (n *Node) Reachable(direction EdgeDirection, opts ...func(*VisitOrder)) NodeSet {
+	return n.ReachableWithin(direction, 0, opts...)
+}
+
+// ReachableWithin returns the nodes reachable from the node within the
+// given number of edges, following edges in the given direction like
+// Reachable. A depth less than 1 means there is no limit.
+func (n *Node) ReachableWithin(direction EdgeDirection, depth int, opts ...func(*VisitOrder)) NodeSet {
+	reachable := NodeSet{}
+
+	walk(n, direction, depth, newVisitOrder(opts...).edgeFilter(), func(other *Node, _ int) bool {
+		if other != n {
+			reachable.Add(other)
+		}
+		return true
+	})
+
+	return reachable
+}
+
+// Distances returns the number of edges on the shortest path from the node
+// to every node reachable from it, following every edge that isn't an
+// inward edge like Reachable, including the node itself at distance 0. This answers the
+// same question as calling PathTo for each node, using a single
+// breadth-first search. Traversal options, like WithEdgeFilter, restrict
+// the edges followed.
+//
+//	a → b → c    a.Distances(): a: 0, b: 1, c: 2, d: 1
+//	↓
+//	d
+func (n *Node) Distances(opts ...func(*VisitOrder)) map[*Node]int {
+	distances := map[*Node]int{}
+
+	walk(n, Out, 0, newVisitOrder(opts...).edgeFilter(), func(other *Node, distance int) bool {
+		distances[other] = distance
+		return true
+	})
+
+	return distances
+}
+
+// WeightedDistances returns the total weight of the lightest path from the
+// node to every node reachable from it, following the same edges as
+// Distances, using Edge.Weight for the weight of each edge. Weights are
+// expected to be non-negative.
+//
+// https://en.wikipedia.org/wiki/Dijkstra%27s_algorithm
+func (n *Node) WeightedDistances(opts ...func(*VisitOrder)) map[*Node]float64 {
+	filter := newVisitOrder(opts...).edgeFilter()
+
+	distances := map[*Node]float64{n: 0}
+	done := NodeSet{}
+
+	queue := &refQueue{{node: n}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(refItem)
+
+		node := item.node.(*Node)
+		if done.Contains(node) {
+			continue
+		}
+		done.Add(node)
+
+		for _, edge := range node.Edges {
+			if !edge.follows(Out) || (filter != nil && !filter(edge)) {
+				continue
+			}
+
+			dist := item.dist + edge.Weight()
+			if d, ok := distances[edge.Node]; !ok || dist < d {
+				distances[edge.Node] = dist
+				heap.Push(queue, refItem{node: edge.Node, dist: dist})
+			}
+		}
+	}
+
+	return distances
+}
+
+// walk visits the nodes reachable from the root node in breadth-first order,
+// following the edges that can be followed in the given direction, and are
+// allowed by the given filter, if any, calling the given function with each
+// node and its distance from the root, until the function returns false. A
+// depth less than 1 means there is no limit.
+func walk(root *Node, direction EdgeDirection, depth int, filter EdgeFilter, fn func(*Node, int) bool) {
+	if root == nil {
+		return
+	}
+
+	visited := NewDenseNodeSet(root.graph)
+	visited.Add(root)
+
+	current := Nodes{root}
+
+	for distance := 0; len(current) > 0; distance++ {
+		var next Nodes
+
+		for _, node := range current {
+			node.resolvePlaceholder()
+
+			if !fn(node, distance) {
+				return
+			}
+
+			if depth > 0 && distance == depth {
+				continue
+			}
+
+			for _, edge := range node.Edges {
+				if !edge.follows(direction) || (filter != nil && !filter(edge)) {
+					continue
+				}
+
+				if !visited.Contains(edge.Node) {
+					visited.Add(edge.Node)
+					next = append(next, edge.Node)
+				}
+			}
+		}
+
+		current = next
+	}
+}
+
+// Dependents returns the nodes in the graph that have a path to the given
+// node, answering "what is impacted if this node changes?" for graphs where
+// edges point from dependents to their dependencies. Nodes outside of the
+// graph instance are not included. See Node.ReachableFrom.
+func (inst *Instance) Dependents(n *Node) NodeSet {
+	members := inst.members()
+
+	dependents := NodeSet{}
+	for node := range n.ReachableFrom() {
+		if members.Contains(node) {
+			dependents.Add(node)
+		}
+	}
+
+	return dependents
+}
@@ -0,0 +1,148 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Dependents(t *testing.T) {
+	var (
+		app    = graph.NewNode("app", nil)
+		api    = graph.NewNode("api", nil)
+		db     = graph.NewNode("db", nil)
+		worker = graph.NewNode("worker", nil)
+		other  = graph.NewNode("other", nil)
+		cache  = graph.NewNode("cache", nil)
+	)
+
+	// app → api → db ← worker ← other
+	//        ↓
+	//      cache
+
+	app.AddEdge(api)
+	api.AddEdge(db)
+	api.AddEdge(cache)
+	worker.AddEdge(db)
+	other.AddEdge(worker)
+
+	if reach := db.ReachableFrom(); reach.String() != "api, app, other, worker" {
+		t.Fatalf("unexpected nodes reaching db: %v", reach)
+	}
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(app, api, db, worker, cache)))
+
+	if dependents := g.Dependents(db); dependents.String() != "api, app, worker" {
+		t.Fatalf("unexpected dependents of db: %v", dependents)
+	}
+
+	if dependents := g.Dependents(app); len(dependents) != 0 {
+		t.Fatalf("did not expect app to have dependents: %v", dependents)
+	}
+}
+
+func TestNode_Reachable(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c → d
+	//     ↑
+	//     e
+
+	graph.ConnectNodes(a, b, c, d)
+	e.AddEdge(b)
+
+	tests := []struct {
+		Node      *graph.Node
+		Direction graph.EdgeDirection
+		Depth     int
+		Expected  string
+	}{
+		{a, graph.Out, 0, "b, c, d"},
+		{a, graph.Out, 2, "b, c"},
+		{d, graph.In, 0, "a, b, c, e"},
+		{d, graph.In, 1, "c"},
+		{e, graph.Both, 0, "a, b, c, d"},
+		{e, graph.Both, 2, "a, b, c"},
+		{d, graph.Out, 0, ""},
+	}
+
+	for _, test := range tests {
+		reachable := test.Node.ReachableWithin(test.Direction, test.Depth)
+		if reachable.String() != test.Expected {
+			t.Fatalf("%s reachable %s within %d: expected %q, got %q", test.Node.Name, test.Direction, test.Depth, test.Expected, reachable)
+		}
+	}
+
+	if reachable := a.Reachable(graph.Out); reachable.String() != "b, c, d" {
+		t.Fatalf("unexpected reachable nodes: %v", reachable)
+	}
+
+	// Undirected edges are followed both ways, like HasPath.
+	f, g := graph.NewNode("f", nil), graph.NewNode("g", nil)
+	f.AddEdgeWithDirection(g, graph.None)
+
+	if !f.HasPath(g) || !f.Reachable(graph.Out).Contains(g) || !g.Reachable(graph.In).Contains(f) {
+		t.Fatalf("expected undirected edges to be followed: %v", f.Reachable(graph.Out))
+	}
+
+	if distances := g.Distances(); distances[f] != 1 {
+		t.Fatalf("expected a distance of 1 over an undirected edge, got: %v", distances)
+	}
+
+	if path, _ := f.UniformCostSearch(func(n *graph.Node) bool { return n == g }, nil); path.String() != "f → g" {
+		t.Fatalf("expected a path over an undirected edge, got: %v", path)
+	}
+}
+
+func TestNode_Distances(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a -5→ b -1→ c
+	// └─1→ d -2─↗     e
+
+	a.AddWeightedEdge(b, 5)
+	b.AddWeightedEdge(c, 1)
+	a.AddWeightedEdge(d, 1)
+	d.AddWeightedEdge(c, 2)
+	e.AddEdge(a)
+
+	distances := a.Distances()
+
+	expected := map[*graph.Node]int{a: 0, b: 1, c: 2, d: 1}
+
+	if len(distances) != len(expected) {
+		t.Fatalf("unexpected distances: %v", distances)
+	}
+
+	for node, distance := range expected {
+		if distances[node] != distance {
+			t.Fatalf("%s: expected distance %d, got %d", node.Name, distance, distances[node])
+		}
+	}
+
+	weighted := a.WeightedDistances()
+
+	expectedWeights := map[*graph.Node]float64{a: 0, b: 5, c: 3, d: 1}
+
+	if len(weighted) != len(expectedWeights) {
+		t.Fatalf("unexpected weighted distances: %v", weighted)
+	}
+
+	for node, distance := range expectedWeights {
+		if weighted[node] != distance {
+			t.Fatalf("%s: expected weighted distance %v, got %v", node.Name, distance, weighted[node])
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Match is a match of the pattern of a RewriteRule, with the node of the
+// graph captured by each variable of the pattern.
+type Match map[string]*Node
+
+// RewriteRule is a rule of Instance.Rewrite, replacing the parts of a graph
+// matching a pattern, like inlining pass-through nodes:
+//
+//	graph.RewriteRule{
+//		Name:        "inline proxies",
+//		Pattern:     "(a)-->(p:Proxy)-->(b)",
+//		Replacement: "(a)-->(b)",
+//	}
+//
+//	web → lb → api    ⇒    web → api
+//
+// Pattern and Replacement use the patterns of the MATCH clause of Query: a
+// comma separated list of chains of node variables, with the labels they
+// must have, joined by relationships: outward (-->), inward (<--), or in any
+// direction (--), optionally restricted to an edge name, like -[:calls]->.
+// Each variable of the pattern captures a different node.
+//
+// The replacement describes what the captured nodes become:
+//
+//   - Variables of the pattern that aren't in the replacement are removed
+//     from the graph, along with all of their edges.
+//   - Relationships of the pattern between variables that are kept, but
+//     aren't in the replacement, are removed.
+//   - Relationships of the replacement that aren't in the pattern are
+//     added, as an undirected (None) edge for --.
+//   - Variables that are only in the replacement are added as new nodes,
+//     named after the variable.
+//   - Labels of the replacement are added to the nodes.
+//
+// Anonymous nodes of the pattern, like () or (:Label), are matched but left
+// as they are, along with their relationships.
+type RewriteRule struct {
+	// Name describes the rule, used in errors.
+	Name string
+
+	// Pattern is the pattern of the parts of the graph to replace.
+	Pattern string
+
+	// Replacement is the pattern replacing each match.
+	Replacement string
+
+	// Where decides if a match is replaced, like only inlining nodes that
+	// have no other edges. If nil, every match is replaced.
+	Where func(m Match) bool
+
+	// Apply is called after each match is replaced, with the nodes that
+	// are kept or created, to update their attributes. It can be nil.
+	Apply func(m Match)
+}
+
+// Rewrite applies the given rules to the graph until none of them match
+// (a fixpoint), returning the number of rewrites made. Rules are tried in
+// order, replacing the first match of the first rule that has one, and
+// starting over with the first rule after each rewrite, so earlier rules
+// take precedence:
+//
+//	// Collapse chains of pass-through nodes into single edges.
+//	n, err := g.Rewrite(graph.RewriteRule{
+//		Pattern:     "(a)-->(p)-->(b)",
+//		Replacement: "(a)-->(b)",
+//		Where: func(m graph.Match) bool {
+//			return len(m["p"].Edges) == 2
+//		},
+//	})
+//
+// An error is returned if a rule can't be parsed, or if the rules keep
+// matching after more rewrites than 16 times the number of nodes and edges
+// of the graph, which only rules that don't simplify the graph need.
+//
+// https://en.wikipedia.org/wiki/Graph_rewriting
+func (inst *Instance) Rewrite(rules ...RewriteRule) (int, error) {
+	compiled := make([]*rewriteRule, len(rules))
+	for i, rule := range rules {
+		r, err := compileRewriteRule(rule)
+		if err != nil {
+			return 0, fmt.Errorf("graph failed to parse rewrite rule %q: %w", rule.Name, err)
+		}
+		compiled[i] = r
+	}
+
+	limit := 16 * (len(inst.Nodes) + inst.EdgeCount() + 1)
+
+	var rewrites int
+	for {
+		applied := false
+		for _, rule := range compiled {
+			if rule.applyOnce(inst) {
+				applied = true
+				break
+			}
+		}
+		if !applied {
+			return rewrites, nil
+		}
+
+		rewrites++
+		if rewrites > limit {
+			return rewrites, fmt.Errorf("graph rewrite did not reach a fixpoint after %d rewrites", rewrites)
+		}
+	}
+}
+
+// rewriteRule is a parsed RewriteRule.
+type rewriteRule struct {
+	RewriteRule
+
+	pattern []queryPattern
+
+	// remove are the variables of the pattern that are removed, and create
+	// the variables of the replacement that are added.
+	remove []string
+	create []string
+
+	// labels are the labels of the replacement by variable.
+	labels map[string][]string
+
+	// rels are the relationships of the pattern, in the order they are
+	// matched, keep tells if each of them is kept, and add are the
+	// relationships of the replacement to add.
+	rels []rewriteRelationship
+	keep []bool
+	add  []rewriteRelationship
+}
+
+// rewriteRelationship is a relationship between two variables of a rule.
+type rewriteRelationship struct {
+	from, to string
+	queryRelationship
+}
+
+// key returns the relationship in a form that is equal for relationships
+// described from either side, like (a)-->(b) and (b)<--(a).
+func (rel rewriteRelationship) key() rewriteRelationship {
+	switch {
+	case rel.direction == In:
+		rel.from, rel.to, rel.direction = rel.to, rel.from, Out
+	case rel.direction == Unknown && rel.to < rel.from:
+		rel.from, rel.to = rel.to, rel.from
+	}
+	return rel
+}
+
+// compileRewriteRule parses the pattern and replacement of the given rule.
+func compileRewriteRule(rule RewriteRule) (*rewriteRule, error) {
+	pattern, err := parsePatterns(rule.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("pattern: %w", err)
+	}
+
+	replacement, err := parsePatterns(rule.Replacement)
+	if err != nil && strings.TrimSpace(rule.Replacement) != "" {
+		return nil, fmt.Errorf("replacement: %w", err)
+	}
+
+	r := &rewriteRule{
+		RewriteRule: rule,
+		pattern:     pattern,
+		labels:      map[string][]string{},
+	}
+
+	matched := map[string]bool{}
+	for _, variable := range patternVariables(pattern) {
+		matched[variable] = true
+	}
+
+	replaced := map[string]bool{}
+	for _, p := range replacement {
+		for i, variable := range p.variables {
+			if isAnonymous(variable) {
+				return nil, fmt.Errorf("replacement: nodes must have a variable")
+			}
+			if !replaced[variable] && !matched[variable] {
+				r.create = append(r.create, variable)
+			}
+			replaced[variable] = true
+			r.labels[variable] = append(r.labels[variable], p.labels[i]...)
+		}
+	}
+
+	for _, variable := range patternVariables(pattern) {
+		if !replaced[variable] && !isAnonymous(variable) {
+			r.remove = append(r.remove, variable)
+		}
+	}
+
+	kept := map[rewriteRelationship]int{}
+	for _, rel := range relationshipsOf(replacement) {
+		kept[rel.key()]++
+	}
+
+	r.rels = relationshipsOf(pattern)
+
+	for _, rel := range r.rels {
+		key := rel.key()
+		switch {
+		case isAnonymous(rel.from) || isAnonymous(rel.to):
+			r.keep = append(r.keep, true)
+		case kept[key] > 0:
+			kept[key]--
+			r.keep = append(r.keep, true)
+		default:
+			r.keep = append(r.keep, false)
+		}
+	}
+
+	for _, rel := range relationshipsOf(replacement) {
+		if kept[rel.key()] > 0 {
+			kept[rel.key()]--
+			r.add = append(r.add, rel)
+		}
+	}
+
+	return r, nil
+}
+
+// applyOnce replaces the first match of the rule in the graph, if any,
+// returning true if it did.
+func (r *rewriteRule) applyOnce(inst *Instance) bool {
+	var (
+		match Match
+		edges Edges
+	)
+
+	matchPatterns(inst, r.pattern, true, func(bindings map[string]*Node, matched Edges) bool {
+		m := Match{}
+		for variable, node := range bindings {
+			if !isAnonymous(variable) {
+				m[variable] = node
+			}
+		}
+		if r.Where != nil && !r.Where(m) {
+			return true
+		}
+		match, edges = m, append(Edges{}, matched...)
+		return false
+	})
+
+	if match == nil {
+		return false
+	}
+
+	// Relationships that aren't kept are between named variables.
+	for i, edge := range edges {
+		if !r.keep[i] {
+			match[r.rels[i].from].RemoveEdge(edge)
+		}
+	}
+
+	for _, variable := range r.remove {
+		inst.RemoveNode(match[variable])
+		delete(match, variable)
+	}
+
+	for _, variable := range r.create {
+		node := NewNode(variable, nil)
+		inst.AddNode(node)
+		match[variable] = node
+	}
+
+	for variable, labels := range r.labels {
+		if len(labels) > 0 {
+			match[variable].AddLabel(labels...)
+		}
+	}
+
+	for _, rel := range r.add {
+		addRelationship(match[rel.from], match[rel.to], rel.queryRelationship)
+	}
+
+	if r.Apply != nil {
+		r.Apply(match)
+	}
+
+	return true
+}
+
+// addRelationship adds an edge between the given nodes described by the
+// given relationship, which is an undirected (None) edge if it has no
+// direction.
+func addRelationship(from, to *Node, rel queryRelationship) {
+	direction := rel.direction
+	if direction == Unknown {
+		direction = None
+	}
+
+	from.AddEdgeWithDirection(to, direction)
+
+	if rel.name == "" {
+		return
+	}
+
+	// Self-loops add both edges to the same node.
+	added := Edges{from.Edges[len(from.Edges)-1], to.Edges[len(to.Edges)-1]}
+	if from == to {
+		added[0] = from.Edges[len(from.Edges)-2]
+	}
+	for _, edge := range added {
+		edge.Name = rel.name
+	}
+}
+
+// parsePatterns parses a comma separated list of patterns, like the MATCH
+// clause of a query.
+func parsePatterns(s string) ([]queryPattern, error) {
+	tokens, err := tokenizeQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+
+	var patterns []queryPattern
+	for {
+		pattern, err := p.parsePattern()
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+
+	if p.peek() != "" {
+		return nil, fmt.Errorf("unexpected %q after pattern", p.peek())
+	}
+
+	return patterns, nil
+}
+
+// patternVariables returns the variables of the given patterns, in the
+// order they first appear.
+func patternVariables(patterns []queryPattern) []string {
+	var (
+		variables []string
+		seen      = map[string]bool{}
+	)
+	for _, p := range patterns {
+		for _, variable := range p.variables {
+			if !seen[variable] {
+				seen[variable] = true
+				variables = append(variables, variable)
+			}
+		}
+	}
+	return variables
+}
+
+// relationshipsOf returns the relationships of the given patterns, in the
+// order they are matched.
+func relationshipsOf(patterns []queryPattern) []rewriteRelationship {
+	var rels []rewriteRelationship
+	for _, p := range patterns {
+		for i, rel := range p.relationships {
+			rels = append(rels, rewriteRelationship{
+				from:              p.variables[i],
+				to:                p.variables[i+1],
+				queryRelationship: rel,
+			})
+		}
+	}
+	return rels
+}
+
+// isAnonymous checks if the given variable is that of an anonymous node,
+// like (), which parseNode gives a name that isn't an identifier.
+func isAnonymous(variable string) bool {
+	return strings.HasPrefix(variable, " anon")
+}
@@ -0,0 +1,134 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Rewrite(t *testing.T) {
+	var (
+		web = graph.NewNode("web", nil)
+		lb  = graph.NewNode("lb", nil)
+		api = graph.NewNode("api", nil)
+		a   = graph.NewNode("a", nil)
+		b   = graph.NewNode("b", nil)
+		c   = graph.NewNode("c", nil)
+		d   = graph.NewNode("d", nil)
+		tmp = graph.NewNode("tmp", nil)
+	)
+
+	// web → lb → api    a → b → c → d    tmp
+
+	lb.AddLabel("Proxy")
+	tmp.AddLabel("Temp")
+
+	web.AddEdge(lb)
+	lb.AddEdge(api)
+	a.AddEdge(b)
+	b.AddEdge(c)
+	c.AddEdge(d)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(web, lb, api, a, b, c, d, tmp)))
+
+	n, err := g.Rewrite(
+		graph.RewriteRule{
+			Name:        "inline proxies",
+			Pattern:     "(x)-->(p:Proxy)-->(y)",
+			Replacement: "(x)-->(y)",
+		},
+		graph.RewriteRule{
+			Name:        "remove temporary nodes",
+			Pattern:     "(t:Temp)",
+			Replacement: "",
+		},
+		graph.RewriteRule{
+			Name:        "collapse chains",
+			Pattern:     "(x)-->(p)-->(y)",
+			Replacement: "(x)-->(y)",
+			Where: func(m graph.Match) bool {
+				return len(m["p"].Edges) == 2
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 4 {
+		t.Fatalf("expected 4 rewrites, got %d", n)
+	}
+
+	if got := g.Nodes.String(); got != "web, api, a, d" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+
+	if got := g.Edges().String(); got != "web → api, a → d" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstance_Rewrite_create(t *testing.T) {
+	var (
+		legacy = graph.NewNode("billing", nil)
+		api    = graph.NewNode("api", nil)
+	)
+
+	legacy.AddLabel("Legacy")
+	legacy.AddEdgeTyped(api, "calls")
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(legacy, api)))
+
+	// Route calls from legacy services through a new adapter node.
+	n, err := g.Rewrite(graph.RewriteRule{
+		Pattern:     "(s:Legacy)-[:calls]->(t)",
+		Replacement: "(s)-[:calls]->(adapter:Adapter)-[:calls]->(t)",
+		Where: func(m graph.Match) bool {
+			return !m["t"].HasLabel("Adapter")
+		},
+		Apply: func(m graph.Match) {
+			m["adapter"].Name = m["s"].Name + "-adapter"
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Fatalf("expected 1 rewrite, got %d", n)
+	}
+
+	if got := g.Edges().String(); got != "billing → billing-adapter, billing-adapter → api" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	for _, edge := range g.Edges() {
+		if edge.Name != "calls" {
+			t.Fatalf("expected edges to be named calls, got %q", edge.Name)
+		}
+	}
+
+	if err := graph.CheckInvariants(g); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rules that keep matching don't reach a fixpoint.
+	if _, err := g.Rewrite(graph.RewriteRule{
+		Pattern:     "(s)-[:calls]->(t)",
+		Replacement: "(s)-[:calls]->(hop)-[:calls]->(t)",
+	}); err == nil {
+		t.Fatal("expected an error for rules that don't reach a fixpoint")
+	}
+
+	if _, err := g.Rewrite(graph.RewriteRule{Pattern: "(a)-->"}); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+
+	if _, err := g.Rewrite(graph.RewriteRule{Pattern: "(a)", Replacement: "(a)-->()"}); err == nil {
+		t.Fatal("expected an error for an anonymous replacement node")
+	}
+}
@@ -0,0 +1,135 @@
+package graph
+
+// ComponentStats describes the connected components of a graph, ignoring
+// edge directions, after some of its nodes were removed by
+// SimulateFailures.
+type ComponentStats struct {
+	// Removed is the node removed last, or nil before any node is removed.
+	Removed *Node
+
+	// RemovedCount is the number of nodes removed so far.
+	RemovedCount int
+
+	// Remaining is the number of nodes left in the graph.
+	Remaining int
+
+	// Components is the number of connected components of the nodes left.
+	Components int
+
+	// Largest is the number of nodes in the largest connected component.
+	Largest int
+
+	// LargestFraction is the size of the largest connected component, as a
+	// fraction of the number of nodes in the graph before any was removed,
+	// which is commonly used to measure how fragmented the graph is.
+	LargestFraction float64
+}
+
+// SimulateFailures removes the nodes returned by the given function from
+// the graph, one at a time, and returns the statistics of the connected
+// components of the graph, ignoring edge directions, before any node is
+// removed, and after each removal. The graph itself isn't changed.
+//
+// This is useful to analyze how resilient a network is to random failures,
+// using a random order, or to targeted attacks, like removing the nodes
+// with the most edges first:
+//
+//	stats := g.SimulateFailures(func() []*graph.Node {
+//		order := append([]*graph.Node(nil), g.Nodes...)
+//		rand.Shuffle(len(order), func(i, j int) {
+//			order[i], order[j] = order[j], order[i]
+//		})
+//		return order
+//	})
+//
+//	a ── b ── c    remove b: 2 components, largest 1
+//
+// Nodes that aren't in the graph, and nodes returned more than once, are
+// ignored. To simulate edge failures, the edges can be removed from a Clone
+// of the graph, before checking its Components.
+//
+// https://en.wikipedia.org/wiki/Robustness_of_complex_networks
+func (inst *Instance) SimulateFailures(removeOrder func() []*Node) []ComponentStats {
+	members := inst.members()
+
+	var order Nodes
+	removed := NodeSet{}
+	for _, node := range removeOrder() {
+		if members.Contains(node) && !removed.Contains(node) {
+			removed.Add(node)
+			order = append(order, node)
+		}
+	}
+
+	neighbors := undirectedNeighbors(inst.Nodes)
+
+	// The components are computed in reverse, starting from the nodes that
+	// are never removed, and adding the removed nodes back in the reverse
+	// order, merging components using a disjoint-set, which is much faster
+	// than computing them again after each removal.
+	parent := make(map[*Node]*Node, len(inst.Nodes))
+	size := make(map[*Node]int, len(inst.Nodes))
+
+	find := func(n *Node) *Node {
+		for parent[n] != n {
+			parent[n] = parent[parent[n]]
+			n = parent[n]
+		}
+		return n
+	}
+
+	var components, largest int
+
+	add := func(node *Node) {
+		parent[node] = node
+		size[node] = 1
+		components++
+
+		for next := range neighbors[node] {
+			if _, ok := parent[next]; !ok {
+				continue
+			}
+			a, b := find(node), find(next)
+			if a == b {
+				continue
+			}
+			if size[a] < size[b] {
+				a, b = b, a
+			}
+			parent[b] = a
+			size[a] += size[b]
+			components--
+		}
+
+		if s := size[find(node)]; s > largest {
+			largest = s
+		}
+	}
+
+	for _, node := range inst.Nodes {
+		if !removed.Contains(node) {
+			add(node)
+		}
+	}
+
+	total := len(inst.Nodes)
+
+	stats := make([]ComponentStats, len(order)+1)
+	for i := len(order); i >= 0; i-- {
+		stats[i] = ComponentStats{
+			RemovedCount: i,
+			Remaining:    total - i,
+			Components:   components,
+			Largest:      largest,
+		}
+		if total > 0 {
+			stats[i].LargestFraction = float64(largest) / float64(total)
+		}
+		if i > 0 {
+			stats[i].Removed = order[i-1]
+			add(order[i-1])
+		}
+	}
+
+	return stats
+}
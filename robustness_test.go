@@ -0,0 +1,56 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_SimulateFailures(t *testing.T) {
+	g := graph.New("test")
+
+	// a ── b ── c
+	//      │
+	//      d ── e
+
+	err := g.LoadEdges([][2]string{{"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := g.Lookup("b")
+	d, _ := g.Lookup("d")
+	outside := graph.NewNode("outside", nil)
+
+	stats := g.SimulateFailures(func() []*graph.Node {
+		return []*graph.Node{b, outside, b, d}
+	})
+
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 stats, got %d", len(stats))
+	}
+
+	var got []string
+	for _, s := range stats {
+		name := "-"
+		if s.Removed != nil {
+			name = s.Removed.Name
+		}
+		got = append(got, fmt.Sprintf("%s %d %d %d %d %.1f", name, s.RemovedCount, s.Remaining, s.Components, s.Largest, s.LargestFraction))
+	}
+
+	want := []string{
+		"- 0 5 1 5 1.0",
+		"b 1 4 3 2 0.4",
+		"d 2 3 3 1 0.2",
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got:\n%v\nwant:\n%v", got, want)
+	}
+
+	if len(g.Nodes) != 5 || g.EdgeCount() != 4 {
+		t.Fatal("expected the graph to be unchanged")
+	}
+}
@@ -0,0 +1,246 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RunOptions configures the behavior of Run.
+type RunOptions struct {
+	// ContinueOnError keeps running the nodes that don't depend on a failed
+	// node, instead of stopping at the first failure.
+	ContinueOnError bool
+}
+
+// WithContinueOnError keeps running nodes after a node fails, skipping only
+// the nodes that depend on it, directly or indirectly.
+func WithContinueOnError() func(*RunOptions) {
+	return func(o *RunOptions) {
+		o.ContinueOnError = true
+	}
+}
+
+// NodeError is an error returned by the worker of Run for a node.
+type NodeError struct {
+	Node *Node
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("graph node %q failed: %v", e.Node.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the worker.
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// RunError is returned by Run when nodes failed, or weren't run because
+// the context was canceled.
+type RunError struct {
+	// Errors are the errors of the nodes that failed, in the order they
+	// failed in.
+	Errors []*NodeError
+
+	// Skipped are the nodes that weren't run, in the order of the graph.
+	Skipped Nodes
+
+	// Err is the error of the context, if it was canceled.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RunError) Error() string {
+	var msgs []string
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	if e.Err != nil {
+		msgs = append(msgs, e.Err.Error())
+	}
+	return fmt.Sprintf("graph run failed, skipping %d nodes: %s", len(e.Skipped), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed nodes, and the error of the
+// context, so errors.Is and errors.As can match any of them, which they
+// only do as of Go 1.20, see Is and As.
+func (e *RunError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// Is checks if any of the errors returned by Unwrap matches the target, so
+// errors.Is can match them with versions of Go that don't unwrap multiple
+// errors, like Go 1.19.
+func (e *RunError) Is(target error) bool {
+	for _, err := range e.Unwrap() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors returned by Unwrap that matches the
+// target, like Is.
+func (e *RunError) As(target any) bool {
+	for _, err := range e.Unwrap() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run calls the given worker for each node of the graph, running up to the
+// given number of workers in parallel, and starting each node only after
+// the nodes it depends on finished successfully. Like TopologicalSort,
+// a node depends on the nodes with edges to it, so a → b runs a before b.
+// Nodes that are ready at the same time are started in the order of the
+// graph.
+//
+//	err := graph.Run(ctx, g, func(ctx context.Context, n *graph.Node) error {
+//		return build(ctx, n.Name)
+//	}, runtime.NumCPU())
+//
+// By default, Run fails fast: when a worker returns an error, the context
+// given to running workers is canceled, no more nodes are started, and
+// Run returns after the running workers return. With WithContinueOnError,
+// only the nodes that depend on a failed node are skipped. Canceling the
+// given context also stops starting nodes.
+//
+// If any node failed, or wasn't run, a *RunError is returned. An error is
+// returned without running any node if the graph contains a cycle. The
+// graph must not be changed while it runs.
+func Run(ctx context.Context, inst *Instance, worker func(context.Context, *Node) error, parallelism int, opts ...func(*RunOptions)) error {
+	options := &RunOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	if _, err := inst.TopologicalSort(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	members := inst.members()
+
+	inDegrees := map[*Node]int{}
+	for _, node := range inst.Nodes {
+		for _, next := range node.Edges.successors() {
+			if members.Contains(next) {
+				inDegrees[next]++
+			}
+		}
+	}
+
+	var ready Nodes
+	for _, node := range inst.Nodes {
+		if inDegrees[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	type result struct {
+		node *Node
+		err  error
+	}
+
+	var (
+		results  = make(chan result)
+		running  int
+		stopping bool
+		ran      = NodeSet{}
+		blocked  = NodeSet{}
+		runErr   = &RunError{}
+	)
+
+	// finish releases the nodes that depend on the given node, which are
+	// blocked, and never run, if the node didn't succeed.
+	var finish func(node *Node, ok bool)
+	finish = func(node *Node, ok bool) {
+		for _, next := range node.Edges.successors() {
+			if !members.Contains(next) {
+				continue
+			}
+			if !ok {
+				blocked.Add(next)
+			}
+			inDegrees[next]--
+			if inDegrees[next] > 0 {
+				continue
+			}
+			if blocked.Contains(next) {
+				finish(next, false)
+				continue
+			}
+			ready = append(ready, next)
+		}
+	}
+
+	for (!stopping && len(ready) > 0) || running > 0 {
+		if err := ctx.Err(); err != nil && !stopping {
+			stopping = true
+			runErr.Err = err
+		}
+
+		for !stopping && running < parallelism && len(ready) > 0 {
+			node := ready[0]
+			ready = ready[1:]
+
+			ran.Add(node)
+			running++
+
+			go func() {
+				results <- result{node, worker(ctx, node)}
+			}()
+		}
+
+		var done <-chan struct{}
+		if !stopping {
+			done = ctx.Done()
+		}
+
+		select {
+		case r := <-results:
+			running--
+			if r.err != nil {
+				runErr.Errors = append(runErr.Errors, &NodeError{Node: r.node, Err: r.err})
+				if !options.ContinueOnError {
+					stopping = true
+					cancel()
+				}
+			}
+			finish(r.node, r.err == nil)
+		case <-done:
+			stopping = true
+			runErr.Err = ctx.Err()
+		}
+	}
+
+	for _, node := range inst.Nodes {
+		if !ran.Contains(node) {
+			runErr.Skipped = append(runErr.Skipped, node)
+		}
+	}
+
+	if len(runErr.Errors) == 0 && runErr.Err == nil && len(runErr.Skipped) == 0 {
+		return nil
+	}
+	return runErr
+}
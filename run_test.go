@@ -0,0 +1,179 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+// runGraph returns a graph of tasks:
+//
+//	a → b → d
+//	↓       ↑
+//	c ──────┘
+//	↓
+//	e
+func runGraph() (*graph.Instance, map[string]*graph.Node) {
+	nodes := map[string]*graph.Node{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		nodes[name] = graph.NewNode(name, nil)
+	}
+
+	nodes["a"].AddEdge(nodes["b"])
+	nodes["a"].AddEdge(nodes["c"])
+	nodes["b"].AddEdge(nodes["d"])
+	nodes["c"].AddEdge(nodes["d"])
+	nodes["c"].AddEdge(nodes["e"])
+
+	g := graph.New("tasks", graph.WithNodes(graph.NewNodes(
+		nodes["a"], nodes["b"], nodes["c"], nodes["d"], nodes["e"],
+	)))
+
+	return g, nodes
+}
+
+func TestRun(t *testing.T) {
+	g, _ := runGraph()
+
+	var (
+		mu       sync.Mutex
+		finished = graph.NodeSet{}
+		running  int32
+		peak     int32
+	)
+
+	err := graph.Run(context.Background(), g, func(ctx context.Context, n *graph.Node) error {
+		if r := atomic.AddInt32(&running, 1); r > atomic.LoadInt32(&peak) {
+			atomic.StoreInt32(&peak, r)
+		}
+		defer atomic.AddInt32(&running, -1)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, edge := range n.Edges {
+			if edge.Direction == graph.In && !finished.Contains(edge.Node) {
+				t.Errorf("%s started before its dependency %s finished", n.Name, edge.Node.Name)
+			}
+		}
+		finished.Add(n)
+		return nil
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(finished) != 5 {
+		t.Fatalf("expected every node to run, got: %v", finished)
+	}
+
+	if peak > 2 {
+		t.Fatalf("expected at most 2 workers at once, got %d", peak)
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	failure := errors.New("failure")
+
+	for _, test := range []struct {
+		name    string
+		opts    []func(*graph.RunOptions)
+		ran     []string
+		skipped string
+	}{
+		{"fail fast", nil, []string{"a", "b"}, "c, d, e"},
+		{"continue", []func(*graph.RunOptions){graph.WithContinueOnError()}, []string{"a", "b", "c", "e"}, "d"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g, nodes := runGraph()
+
+			var (
+				mu  sync.Mutex
+				ran = graph.NodeSet{}
+			)
+
+			err := graph.Run(context.Background(), g, func(ctx context.Context, n *graph.Node) error {
+				mu.Lock()
+				ran.Add(n)
+				mu.Unlock()
+
+				if n.Name == "b" {
+					return failure
+				}
+				return nil
+			}, 1, test.opts...)
+
+			if !errors.Is(err, failure) {
+				t.Fatalf("expected the failure, got: %v", err)
+			}
+
+			var runErr *graph.RunError
+			if !errors.As(err, &runErr) {
+				t.Fatalf("expected a run error, got: %T", err)
+			}
+
+			if len(runErr.Errors) != 1 || runErr.Errors[0].Node != nodes["b"] {
+				t.Fatalf("unexpected errors: %v", runErr.Errors)
+			}
+
+			// Is and As match the errors of the nodes without relying
+			// on errors unwrapping multiple errors, which Go 1.19 doesn't.
+			var nodeErr *graph.NodeError
+			if !runErr.Is(failure) || !runErr.As(&nodeErr) || nodeErr.Node != nodes["b"] {
+				t.Fatalf("expected Is and As to match the node error, got: %v", nodeErr)
+			}
+
+			if runErr.Skipped.String() != test.skipped {
+				t.Fatalf("expected %s to be skipped, got: %s", test.skipped, runErr.Skipped)
+			}
+
+			if len(ran) != len(test.ran) {
+				t.Fatalf("expected %v to run, got: %v", test.ran, ran)
+			}
+			for _, name := range test.ran {
+				if !ran.Contains(nodes[name]) {
+					t.Fatalf("expected %s to run, got: %v", name, ran)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_canceled(t *testing.T) {
+	g, _ := runGraph()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := graph.Run(ctx, g, func(ctx context.Context, n *graph.Node) error {
+		cancel()
+		return nil
+	}, 1)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context to be canceled, got: %v", err)
+	}
+
+	var runErr *graph.RunError
+	if !errors.As(err, &runErr) || len(runErr.Skipped) != 4 {
+		t.Fatalf("expected 4 skipped nodes, got: %v", err)
+	}
+}
+
+func TestRun_cycle(t *testing.T) {
+	g, nodes := runGraph()
+
+	nodes["d"].AddEdge(nodes["a"])
+
+	err := graph.Run(context.Background(), g, func(ctx context.Context, n *graph.Node) error {
+		t.Fatalf("unexpected run of %s", n.Name)
+		return nil
+	}, 1)
+
+	if err == nil {
+		t.Fatal("expected an error for a graph with a cycle")
+	}
+}
@@ -0,0 +1,234 @@
+package graph
+
+import "math/rand"
+
+// SampleNodes returns a subgraph of n nodes chosen uniformly at random from
+// the graph, and the edges between them, using the given seed so the same
+// sample can be reproduced. The sampled nodes are copies, in the same order
+// they appear in the graph, so the sample can be changed without affecting
+// the original (see Clone). If n is greater than the number of nodes in the
+// graph, a copy of the whole graph is returned.
+//
+// https://en.wikipedia.org/wiki/Induced_subgraph
+func (inst *Instance) SampleNodes(n int, seed int64) *Instance {
+	if n >= len(inst.Nodes) {
+		return inst.Clone()
+	}
+
+	if n < 0 {
+		n = 0
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	chosen := make([]bool, len(inst.Nodes))
+	for _, i := range rng.Perm(len(inst.Nodes))[:n] {
+		chosen[i] = true
+	}
+
+	nodes := make(Nodes, 0, n)
+	for i, node := range inst.Nodes {
+		if chosen[i] {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return inst.subgraph(nodes)
+}
+
+// SampleRandomWalk returns the subgraph of the nodes visited by a random
+// walk of the given number of steps from the start node, and the edges
+// between them, using the given seed so the same sample can be reproduced.
+//
+// Each step follows a random edge that isn't an inward edge to another node
+// in the graph. When the walk reaches a node without any, it restarts from
+// the start node. Walks keep the neighborhoods of the nodes they visit, so
+// samples tend to be better connected than those from SampleNodes.
+//
+//	a → b → c    SampleRandomWalk(a, 2, seed): {a, b, c} or {a, b, d}
+//	    ↓
+//	    d
+//
+// If the start node isn't in the graph, an empty graph is returned.
+func (inst *Instance) SampleRandomWalk(start *Node, steps int, seed int64) *Instance {
+	members := inst.members()
+	if !members.Contains(start) {
+		return inst.subgraph(nil)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	visited := NewNodeSet(start)
+
+	at := start
+	for i := 0; i < steps; i++ {
+		next := randomStep(at, members, rng)
+		if next == nil {
+			next = start
+		}
+		visited.Add(next)
+		at = next
+	}
+
+	nodes := make(Nodes, 0, len(visited))
+	for _, node := range inst.Nodes {
+		if visited.Contains(node) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return inst.subgraph(nodes)
+}
+
+// RandomWalk returns the path of a random walk of up to the given number of
+// steps from the node, using the given source of randomness. Each step
+// follows a random edge that isn't an inward edge, so undirected (None) and
+// bi-directional (Both) edges can be followed either way. The walk stops
+// early if it reaches a node without any such edges.
+//
+// The path starts with the node, and may visit nodes more than once.
+//
+//	a → b ⇄ c    a.RandomWalk(4, rng): a → b → c → b → c
+//
+// https://en.wikipedia.org/wiki/Random_walk
+func (n *Node) RandomWalk(steps int, rng *rand.Rand) Path {
+	path := Path{n}
+
+	at := n
+	for i := 0; i < steps; i++ {
+		next := randomStep(at, nil, rng)
+		if next == nil {
+			break
+		}
+		path = append(path, next)
+		at = next
+	}
+
+	return path
+}
+
+// GenerateWalks returns biased random walks of up to the given length, in
+// nodes, starting perNode times from each node of the graph, as the names
+// of the nodes they visit, using the given seed so the same walks can be
+// reproduced. Walks are the "sentences" used to learn node embeddings with
+// word2vec-style trainers, like node2vec:
+//
+//	for _, walk := range g.GenerateWalks(10, 80, 1, 0.5, seed) {
+//		fmt.Fprintln(w, strings.Join(walk, " "))
+//	}
+//
+// Like RandomWalk, walks follow edges that aren't inward edges, to nodes in
+// the graph, and stop early at nodes without any. Each step is chosen with
+// a probability proportional to the weight of the edge, see Edge.Weight,
+// biased by the return parameter p and the in-out parameter q, relative to
+// the node the walk just left:
+//
+//   - 1/p to go back to the node the walk came from,
+//   - 1 to go to a node the walk could've reached from it, and
+//   - 1/q to go to other nodes, further away.
+//
+// So a low p keeps walks local, like breadth-first search, and a low q
+// makes them explore outward, like depth-first search. With p and q of 1,
+// which is used for values that aren't positive, walks are uniform, like
+// DeepWalk. Walks are returned in rounds, each starting once from every
+// node, in the order of the graph.
+//
+// https://en.wikipedia.org/wiki/Node2vec
+func (inst *Instance) GenerateWalks(perNode, length int, p, q float64, seed int64) [][]string {
+	if perNode < 1 || length < 1 {
+		return nil
+	}
+
+	if p <= 0 {
+		p = 1
+	}
+	if q <= 0 {
+		q = 1
+	}
+
+	members := inst.members()
+
+	successors := make(map[*Node]Edges, len(inst.Nodes))
+	adjacent := make(map[*Node]NodeSet, len(inst.Nodes))
+	for _, node := range inst.Nodes {
+		adjacent[node] = NodeSet{}
+		for _, edge := range node.Edges {
+			if edge.Direction != In && members.Contains(edge.Node) {
+				successors[node] = append(successors[node], edge)
+				adjacent[node].Add(edge.Node)
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	var (
+		walks   [][]string
+		weights []float64
+	)
+
+	for round := 0; round < perNode; round++ {
+		for _, start := range inst.Nodes {
+			walk := []string{start.Name}
+
+			var previous *Node
+			at := start
+			for len(walk) < length {
+				edges := successors[at]
+				if len(edges) == 0 {
+					break
+				}
+
+				weights = weights[:0]
+				var total float64
+				for _, edge := range edges {
+					w := edge.Weight()
+					switch {
+					case previous == nil:
+					case edge.Node == previous:
+						w /= p
+					case !adjacent[previous].Contains(edge.Node):
+						w /= q
+					}
+					weights = append(weights, w)
+					total += w
+				}
+
+				next := edges[len(edges)-1].Node
+				r := rng.Float64() * total
+				for i, w := range weights {
+					if r < w {
+						next = edges[i].Node
+						break
+					}
+					r -= w
+				}
+
+				walk = append(walk, next.Name)
+				previous, at = at, next
+			}
+
+			walks = append(walks, walk)
+		}
+	}
+
+	return walks
+}
+
+// randomStep returns a random node that can be reached from the given node
+// by following an edge that isn't an inward edge, or nil if there is none.
+// If members is not nil, only nodes in the set are considered.
+func randomStep(node *Node, members nodeSet, rng *rand.Rand) *Node {
+	var candidates Nodes
+	for _, next := range node.Edges.successors() {
+		if members == nil || members.Contains(next) {
+			candidates = append(candidates, next)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return candidates[rng.Intn(len(candidates))]
+}
@@ -0,0 +1,183 @@
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_SampleNodes(t *testing.T) {
+	g := graph.New("test")
+
+	nodes := graph.Nodes{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		nodes = append(nodes, graph.NewNode(name, nil))
+	}
+
+	g.AddNodes(nodes...)
+	graph.ConnectNodes(nodes...)
+
+	sample := g.SampleNodes(3, 1)
+
+	if len(sample.Nodes) != 3 {
+		t.Fatalf("expected 3 sampled nodes, got %d", len(sample.Nodes))
+	}
+
+	if again := g.SampleNodes(3, 1); !graph.Equal(sample, again) {
+		t.Fatal("expected the same seed to produce the same sample")
+	}
+
+	for _, node := range sample.Nodes {
+		if g.Nodes.IndexOf(node) != -1 {
+			t.Fatalf("expected sampled node %s to be a copy", node.Name)
+		}
+		for _, edge := range node.Edges {
+			if sample.Nodes.IndexOf(edge.Node) == -1 {
+				t.Fatalf("sampled node %s has an edge to %s outside of the sample", node.Name, edge.Node.Name)
+			}
+		}
+	}
+
+	if all := g.SampleNodes(10, 1); !graph.Equal(g, all) {
+		t.Fatal("expected sampling more nodes than the graph has to copy it")
+	}
+
+	if g.EdgeCount() != 5 {
+		t.Fatalf("expected the original graph to be unchanged, got %d edges", g.EdgeCount())
+	}
+}
+
+func TestInstance_SampleRandomWalk(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a → b → c
+	//     ↓
+	//     d    e
+
+	graph.ConnectNodes(a, b, c)
+	b.AddEdge(d)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	sample := g.SampleRandomWalk(a, 20, 1)
+
+	if got := graph.NewNodeSet(sample.Nodes...).String(); got != "a, b, c, d" {
+		t.Fatalf("unexpected sampled nodes: %s", got)
+	}
+
+	if sample.EdgeCount() != 3 {
+		t.Fatalf("expected 3 sampled edges, got %d", sample.EdgeCount())
+	}
+
+	if sample := g.SampleRandomWalk(a, 0, 1); len(sample.Nodes) != 1 || sample.Nodes[0].Name != "a" {
+		t.Fatalf("expected a walk without steps to only sample the start node, got %v", sample.Nodes)
+	}
+
+	if sample := g.SampleRandomWalk(graph.NewNode("x", nil), 10, 1); len(sample.Nodes) != 0 {
+		t.Fatalf("expected an empty sample for a node outside of the graph, got %v", sample.Nodes)
+	}
+}
+
+func TestNode_RandomWalk(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a → b ↔ c → d
+
+	a.AddEdge(b)
+	b.AddLink(c)
+	c.AddEdge(d)
+
+	path := a.RandomWalk(100, rand.New(rand.NewSource(1)))
+
+	if path[0] != a {
+		t.Fatalf("expected the walk to start at a, got %v", path)
+	}
+
+	if path[len(path)-1] != d {
+		t.Fatalf("expected the walk to stop at d, got %v", path)
+	}
+
+	for i := 0; i+1 < len(path); i++ {
+		if !path[i].Edges.Contains(path[i+1]) {
+			t.Fatalf("unexpected step from %s to %s", path[i].Name, path[i+1].Name)
+		}
+	}
+
+	if again := a.RandomWalk(100, rand.New(rand.NewSource(1))); !again.Identical(path) {
+		t.Fatalf("expected the same walk from the same source, got %v and %v", path, again)
+	}
+
+	if path := a.RandomWalk(2, rand.New(rand.NewSource(1))); path.String() != "a → b → c" {
+		t.Fatalf("expected a walk of 2 steps, got %v", path)
+	}
+}
+
+func TestInstance_GenerateWalks(t *testing.T) {
+	g := graph.New("test")
+
+	// a ── b ── c ── d
+	//      │
+	//      e → f
+
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.AddNode(graph.NewNode(name, nil))
+	}
+	node := func(name string) *graph.Node {
+		n, _ := g.Lookup(name)
+		return n
+	}
+	for _, pair := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"b", "e"}} {
+		node(pair[0]).AddEdgeWithDirection(node(pair[1]), graph.None)
+	}
+	node("e").AddEdgeWithDirection(node("f"), graph.Out)
+
+	walks := g.GenerateWalks(3, 5, 1, 1, 1)
+	if len(walks) != 3*len(g.Nodes) {
+		t.Fatalf("expected %d walks, got %d", 3*len(g.Nodes), len(walks))
+	}
+
+	for i, walk := range walks {
+		if walk[0] != g.Nodes[i%len(g.Nodes)].Name {
+			t.Fatalf("expected walk %d to start at %s, got %v", i, g.Nodes[i%len(g.Nodes)].Name, walk)
+		}
+		if walk[0] == "f" && len(walk) != 1 {
+			t.Fatalf("expected walks from f to stop, got %v", walk)
+		}
+		if walk[0] != "f" && len(walk) != 5 && walk[len(walk)-1] != "f" {
+			t.Fatalf("expected walk of 5 nodes or ending at f, got %v", walk)
+		}
+		for j := 1; j < len(walk); j++ {
+			if !node(walk[j-1]).HasPath(node(walk[j])) {
+				t.Fatalf("unexpected step %s → %s in %v", walk[j-1], walk[j], walk)
+			}
+		}
+	}
+
+	if again := g.GenerateWalks(3, 5, 1, 1, 1); fmt.Sprint(again) != fmt.Sprint(walks) {
+		t.Fatal("expected the same seed to produce the same walks")
+	}
+
+	// A tiny return parameter makes walks go back and forth.
+	for _, walk := range g.GenerateWalks(5, 5, 1e-9, 1, 1) {
+		if len(walk) >= 3 && walk[2] != walk[0] {
+			t.Fatalf("expected walk to return, got %v", walk)
+		}
+	}
+
+	if walks := g.GenerateWalks(0, 5, 1, 1, 1); walks != nil {
+		t.Fatalf("did not expect walks, got %v", walks)
+	}
+}
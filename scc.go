@@ -0,0 +1,176 @@
+package graph
+
+// MembersAttribute is the name of the attribute used by Condense to
+// record the member nodes (a NodeSet) represented by a condensed node.
+const MembersAttribute = "members"
+
+// StronglyConnectedComponents returns the strongly connected components of
+// the graph, where every node in a component has a path to every other
+// node in the same component, using Tarjan's algorithm.
+//
+// Every edge that isn't an inward edge is followed, so nodes joined by
+// undirected (None) or bi-directional (Both) edges are always in the same
+// component. Edges to nodes outside of the graph instance are ignored.
+//
+// Components are returned in topological order, so no component has an
+// edge to a component returned before it. Results are cached until the
+// graph changes, see Generation, unless an edge filter is given, see
+// WithEdgeFilter, to only consider some edges.
+//
+// https://en.wikipedia.org/wiki/Strongly_connected_component
+// https://en.wikipedia.org/wiki/Tarjan%27s_strongly_connected_components_algorithm
+func (inst *Instance) StronglyConnectedComponents(opts ...func(*VisitOrder)) []NodeSet {
+	defer inst.trace("StronglyConnectedComponents")()
+
+	if filter := newVisitOrder(opts...).edgeFilter(); filter != nil {
+		return inst.stronglyConnectedComponentsWhere(filter)
+	}
+
+	return copyNodeSets(memoize(inst, "scc", inst.stronglyConnectedComponents))
+}
+
+// stronglyConnectedComponents returns the strongly connected components of
+// the graph, in topological order.
+func (inst *Instance) stronglyConnectedComponents() []NodeSet {
+	return inst.stronglyConnectedComponentsWhere(nil)
+}
+
+// stronglyConnectedComponentsWhere returns the strongly connected
+// components of the graph, in topological order, only following the edges
+// allowed by the given filter, if not nil.
+func (inst *Instance) stronglyConnectedComponentsWhere(filter EdgeFilter) []NodeSet {
+	members := inst.members()
+
+	var (
+		index      int
+		indexes    = map[*Node]int{}
+		lowLinks   = map[*Node]int{}
+		onStack    = NodeSet{}
+		stack      = Nodes{}
+		components = []NodeSet{}
+	)
+
+	// frame is an explicit call stack entry, to avoid recursion on deep graphs.
+	type frame struct {
+		node  *Node
+		succ  Nodes
+		child int
+	}
+
+	for _, root := range inst.Nodes {
+		if _, ok := indexes[root]; ok {
+			continue
+		}
+
+		calls := []*frame{}
+
+		push := func(n *Node) {
+			indexes[n] = index
+			lowLinks[n] = index
+			index++
+			stack = append(stack, n)
+			onStack.Add(n)
+			calls = append(calls, &frame{node: n, succ: n.Edges.successorsWhere(filter)})
+		}
+
+		push(root)
+
+		for len(calls) > 0 {
+			f := calls[len(calls)-1]
+
+			if f.child < len(f.succ) {
+				next := f.succ[f.child]
+				f.child++
+
+				if !members.Contains(next) {
+					continue
+				}
+
+				if _, visited := indexes[next]; !visited {
+					push(next)
+				} else if onStack.Contains(next) && indexes[next] < lowLinks[f.node] {
+					lowLinks[f.node] = indexes[next]
+				}
+				continue
+			}
+
+			// All successors have been visited, so return from the "call".
+			calls = calls[:len(calls)-1]
+
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				if lowLinks[f.node] < lowLinks[parent] {
+					lowLinks[parent] = lowLinks[f.node]
+				}
+			}
+
+			if lowLinks[f.node] == indexes[f.node] {
+				component := NodeSet{}
+				for {
+					n := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					delete(onStack, n)
+					component.Add(n)
+					if n == f.node {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+		}
+	}
+
+	// Tarjan's algorithm finds components in reverse topological order.
+	for i, j := 0, len(components)-1; i < j; i, j = i+1, j-1 {
+		components[i], components[j] = components[j], components[i]
+	}
+
+	return components
+}
+
+// Condense returns a new directed acyclic graph where each node represents
+// a strongly connected component of the graph, collapsing all cycles.
+//
+// Each condensed node is named after its members, and the member nodes are
+// recorded as a NodeSet using the MembersAttribute attribute. An edge exists
+// between two condensed nodes if any of their members have an edge.
+//
+// https://en.wikipedia.org/wiki/Strongly_connected_component#Definitions
+func (inst *Instance) Condense() *Instance {
+	defer inst.trace("Condense")()
+
+	components := inst.StronglyConnectedComponents()
+
+	condensed := New(inst.Name)
+
+	byMember := map[*Node]*Node{}
+
+	for _, component := range components {
+		node := NewNode(component.String(), Attributes{MembersAttribute: component})
+		for member := range component {
+			byMember[member] = node
+		}
+		condensed.AddNode(node)
+	}
+
+	linked := map[*Node]NodeSet{}
+
+	for _, member := range inst.Nodes {
+		from := byMember[member]
+
+		if linked[from] == nil {
+			linked[from] = NodeSet{}
+		}
+
+		for _, next := range member.Edges.successors() {
+			to, ok := byMember[next]
+			if !ok || to == from || linked[from].Contains(to) {
+				continue
+			}
+			linked[from].Add(to)
+			from.AddEdge(to)
+		}
+	}
+
+	return condensed
+}
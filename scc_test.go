@@ -0,0 +1,131 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_StronglyConnectedComponents(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	//     a
+	//   ↙   ↖
+	//  b  →  c → d ⇄ e
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	c.AddEdge(a)
+	c.AddEdge(d)
+	d.AddEdge(e)
+	e.AddEdge(d)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e)))
+
+	components := g.StronglyConnectedComponents()
+
+	expected := []string{"a, b, c", "d, e"}
+
+	if len(components) != len(expected) {
+		t.Fatalf("expected %d components, got %d", len(expected), len(components))
+	}
+
+	for i, component := range components {
+		if component.String() != expected[i] {
+			t.Errorf("component %d: expected %q, got %q", i, expected[i], component)
+		}
+	}
+}
+
+func TestInstance_Condense(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// d → a ⇄ b → c
+
+	d.AddEdge(a)
+	a.AddEdge(b)
+	b.AddEdge(a)
+	b.AddEdge(c)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fatalf("expected cyclic graph to fail topological sort")
+	}
+
+	condensed := g.Condense()
+
+	if !condensed.IsAcyclic() {
+		t.Fatalf("expected condensed graph to be acyclic")
+	}
+
+	sorted, err := condensed.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sorted.String() != "d, a, b, c" {
+		t.Fatalf("unexpected topological order: %q", sorted)
+	}
+
+	members, err := graph.GetAttribute[graph.NodeSet](sorted[1].Attributes, graph.MembersAttribute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !members.SameAs(graph.NewNodeSet(a, b)) {
+		t.Fatalf("unexpected members: %v", members)
+	}
+}
+
+func TestInstance_IsConnected(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b ← c
+
+	a.AddEdge(b)
+	c.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	if !g.IsWeaklyConnected() || g.IsStronglyConnected() {
+		t.Fatalf("expected graph to only be weakly connected")
+	}
+
+	// a → b → c
+	// ↑       ↓
+	// └───────┘
+
+	c.RemoveEdgesTo(b)
+	b.AddEdge(c)
+	c.AddEdge(a)
+
+	if !g.IsWeaklyConnected() || !g.IsStronglyConnected() {
+		t.Fatalf("expected graph to be strongly connected")
+	}
+
+	g.AddNode(graph.NewNode("d", nil))
+
+	if g.IsWeaklyConnected() || len(g.WeaklyConnectedComponents()) != 2 {
+		t.Fatalf("did not expect graph with an isolated node to be connected")
+	}
+
+	if graph.New("empty").IsWeaklyConnected() {
+		t.Fatalf("did not expect an empty graph to be connected")
+	}
+}
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+)
+
+// UniformCostSearch returns the lightest path from the node to the closest
+// node matching the given goal predicate, along with its total weight,
+// following the same edges as WeightedDistances.
+//
+// The weight of each edge is given by the weight function, or Edge.Weight
+// if it is nil, and is expected to be non-negative. If the node itself
+// matches, a path of just the node is returned. If no matching node is
+// reachable, a nil path and an infinite weight are returned.
+//
+//	   1      1
+//	a ──→ b ──→ c     a.UniformCostSearch(isService, nil)
+//	│           ↑
+//	└─────5─────┘     a → b → c, 2
+//
+// https://en.wikipedia.org/wiki/Dijkstra%27s_algorithm#Practical_optimizations_and_infinite_graphs
+func (n *Node) UniformCostSearch(goal func(*Node) bool, weight func(*Edge) float64) (Path, float64) {
+	if n == nil || goal == nil {
+		return nil, math.Inf(1)
+	}
+
+	if weight == nil {
+		weight = (*Edge).Weight
+	}
+
+	distances := map[*Node]float64{n: 0}
+	parents := map[*Node]*Node{}
+	done := NodeSet{}
+
+	queue := &refQueue{{node: n}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(refItem)
+
+		node := item.node.(*Node)
+		if done.Contains(node) {
+			continue
+		}
+		done.Add(node)
+
+		if goal(node) {
+			path := Path{node}
+			for at := node; at != n; {
+				at = parents[at]
+				path = append(Path{at}, path...)
+			}
+			return path, item.dist
+		}
+
+		for _, edge := range node.Edges {
+			if !edge.follows(Out) || done.Contains(edge.Node) {
+				continue
+			}
+
+			dist := item.dist + weight(edge)
+			if d, ok := distances[edge.Node]; !ok || dist < d {
+				distances[edge.Node] = dist
+				parents[edge.Node] = node
+				heap.Push(queue, refItem{node: edge.Node, dist: dist})
+			}
+		}
+	}
+
+	return nil, math.Inf(1)
+}
+
+// ShortestPathWhere returns the lightest path from the source node to the
+// destination node that only uses the edges and nodes of the graph allowed
+// by the given predicates, along with its total weight, using Dijkstra's
+// algorithm with Edge.Weight. This allows policy-aware routing without
+// copying the graph to remove what isn't allowed:
+//
+//	path, weight := g.ShortestPathWhere(a, d, func(e *graph.Edge) bool {
+//		return e.Attributes["label"] != "deprecated"
+//	}, nil)
+//
+//	a → b → d    a → c → d, since b → d is deprecated
+//	 ↘     ↗
+//	   c
+//
+// Paths follow edges that aren't inward edges, like Visit, so undirected
+// (None) and bi-directional (Both) edges can be followed either way. A nil
+// predicate allows everything, and the source and destination nodes must
+// be allowed too. Edge weights must not be negative. If there is no such
+// path, a nil path and an infinite weight are returned.
+//
+// The edge predicate takes the place of WithEdgeFilter, so an EdgeFilter
+// can be given as it is.
+//
+// https://en.wikipedia.org/wiki/Constrained_Shortest_Path_First
+func (inst *Instance) ShortestPathWhere(src, dst *Node, allowEdge func(*Edge) bool, allowNode func(*Node) bool) (Path, float64) {
+	members := inst.members()
+
+	allowed := func(n *Node) bool {
+		return members.Contains(n) && (allowNode == nil || allowNode(n))
+	}
+
+	if !allowed(src) || !allowed(dst) {
+		return nil, math.Inf(1)
+	}
+
+	distances := map[*Node]float64{src: 0}
+	parents := map[*Node]*Node{}
+	done := NodeSet{}
+
+	queue := &refQueue{{node: src}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(refItem)
+
+		node := item.node.(*Node)
+		if done.Contains(node) {
+			continue
+		}
+		done.Add(node)
+
+		if node == dst {
+			path := Path{node}
+			for at := node; at != src; {
+				at = parents[at]
+				path = append(Path{at}, path...)
+			}
+			return path, item.dist
+		}
+
+		for _, edge := range node.Edges {
+			if edge.Direction == In || done.Contains(edge.Node) || !allowed(edge.Node) {
+				continue
+			}
+			if allowEdge != nil && !allowEdge(edge) {
+				continue
+			}
+
+			dist := item.dist + edge.Weight()
+			if d, ok := distances[edge.Node]; !ok || dist < d {
+				distances[edge.Node] = dist
+				parents[edge.Node] = node
+				heap.Push(queue, refItem{node: edge.Node, dist: dist})
+			}
+		}
+	}
+
+	return nil, math.Inf(1)
+}
+
+// PathToMatching returns the shortest Path to the closest node matching the
+// given predicate, following every edge that isn't an inward edge, nil if no
+// matching node was found. If the node itself matches, a path of just the
+// node is returned. Traversal options, like WithEdgeFilter, restrict the
+// edges followed.
+//
+//	a → b → c    a.PathToMatching(hasLabel("Database")): a → d
+//	 ↘
+//	   d (Database)
+func (n *Node) PathToMatching(pred func(*Node) bool, opts ...func(*VisitOrder)) Path {
+	if n == nil || pred == nil {
+		return nil
+	}
+
+	filter := newVisitOrder(opts...).edgeFilter()
+
+	if pred(n) {
+		return Path{n}
+	}
+
+	previous := map[*Node]*Node{n: nil}
+
+	queue := Nodes{n}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range node.Edges.successorsWhere(filter) {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+
+			previous[next] = node
+
+			if pred(next) {
+				var path Path
+				for at := next; at != nil; at = previous[at] {
+					path = append(Path{at}, path...)
+				}
+				return path
+			}
+
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// FindFirst returns the first node matching the given predicate in the
+// breadth-first order used by BFS, nil if no node matches.
+func (inst *Instance) FindFirst(pred func(*Node) bool) *Node {
+	if pred == nil {
+		return nil
+	}
+
+	var found *Node
+
+	inst.BFS(func(n *Node) {
+		if found == nil && pred(n) {
+			found = n
+		}
+	})
+
+	return found
+}
@@ -0,0 +1,161 @@
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestNode_UniformCostSearch(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", graph.Attributes{"type": "service"})
+		d = graph.NewNode("d", graph.Attributes{"type": "service"})
+	)
+
+	//     1      1
+	//  a ──→ b ──→ c
+	//  │           ↑
+	//  ├─────5─────┘
+	//  └──3──→ d
+
+	a.AddWeightedEdge(b, 1)
+	b.AddWeightedEdge(c, 1)
+	a.AddWeightedEdge(c, 5)
+	a.AddWeightedEdge(d, 3)
+
+	isService := func(n *graph.Node) bool {
+		return n.Attributes["type"] == "service"
+	}
+
+	path, weight := a.UniformCostSearch(isService, nil)
+	if path.String() != "a → b → c" || weight != 2 {
+		t.Fatalf("unexpected path: %v (%v)", path, weight)
+	}
+
+	// A custom weight function makes every edge cost the same.
+	path, weight = a.UniformCostSearch(isService, func(*graph.Edge) float64 { return 1 })
+	if len(path) != 2 || weight != 1 {
+		t.Fatalf("unexpected path with unit weights: %v (%v)", path, weight)
+	}
+
+	path, weight = c.UniformCostSearch(isService, nil)
+	if path.String() != "c" || weight != 0 {
+		t.Fatalf("expected the start node to match: %v (%v)", path, weight)
+	}
+
+	path, weight = b.UniformCostSearch(func(n *graph.Node) bool { return n == d }, nil)
+	if path != nil || !math.IsInf(weight, 1) {
+		t.Fatalf("did not expect a path: %v (%v)", path, weight)
+	}
+}
+
+func TestNode_PathToMatching(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	c.AddLabel("Database")
+	d.AddLabel("Database")
+
+	//  a → b → c
+	//   ↘
+	//     d
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	a.AddEdge(d)
+
+	isDatabase := func(n *graph.Node) bool {
+		return n.HasLabel("Database")
+	}
+
+	if path := a.PathToMatching(isDatabase); path.String() != "a → d" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	if path := b.PathToMatching(isDatabase); path.String() != "b → c" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	if path := c.PathToMatching(isDatabase); path.String() != "c" {
+		t.Fatalf("expected the start node to match: %v", path)
+	}
+
+	// Inward edges aren't followed.
+	if path := c.PathToMatching(func(n *graph.Node) bool { return n == a }); path != nil {
+		t.Fatalf("did not expect a path: %v", path)
+	}
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	// Breadth-first, so d is found before c.
+	if n := g.FindFirst(isDatabase); n != d {
+		t.Fatalf("expected to find d, got: %v", n.Name)
+	}
+
+	if n := g.FindFirst(func(n *graph.Node) bool { return n.Name == "e" }); n != nil {
+		t.Fatalf("did not expect to find a node, got: %v", n.Name)
+	}
+}
+
+func TestInstance_ShortestPathWhere(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", graph.Attributes{"zone": "eu"})
+		d = graph.NewNode("d", nil)
+	)
+
+	//     1      1
+	//  a ──→ b ──→ d (deprecated)
+	//  │           ↑
+	//  └──2──→ c ──┘ 2
+
+	a.AddWeightedEdge(b, 1)
+	b.AddWeightedEdge(d, 1)
+	a.AddWeightedEdge(c, 2)
+	c.AddWeightedEdge(d, 2)
+
+	b.Edges[1].Attributes["label"] = "deprecated"
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b, c, d}))
+
+	path, weight := g.ShortestPathWhere(a, d, nil, nil)
+	if path.String() != "a → b → d" || weight != 2 {
+		t.Fatalf("unexpected path: %v (%v)", path, weight)
+	}
+
+	notDeprecated := func(e *graph.Edge) bool {
+		return e.Attributes["label"] != "deprecated"
+	}
+
+	path, weight = g.ShortestPathWhere(a, d, notDeprecated, nil)
+	if path.String() != "a → c → d" || weight != 4 {
+		t.Fatalf("unexpected path avoiding deprecated edges: %v (%v)", path, weight)
+	}
+
+	notEU := func(n *graph.Node) bool {
+		return n.Attributes["zone"] != "eu"
+	}
+
+	path, weight = g.ShortestPathWhere(a, d, notDeprecated, notEU)
+	if path != nil || !math.IsInf(weight, 1) {
+		t.Fatalf("did not expect a path: %v (%v)", path, weight)
+	}
+
+	path, _ = g.ShortestPathWhere(a, c, nil, notEU)
+	if path != nil {
+		t.Fatalf("did not expect a path to a node that isn't allowed: %v", path)
+	}
+
+	path, weight = g.ShortestPathWhere(a, a, nil, nil)
+	if path.String() != "a" || weight != 0 {
+		t.Fatalf("unexpected path to itself: %v (%v)", path, weight)
+	}
+}
@@ -0,0 +1,390 @@
+// Package shell provides an interactive shell for graphs, to build and
+// inspect them one command at a time, like when teaching graph theory, or
+// poking at a serialized graph during an incident.
+//
+//	sh := shell.New(g, shell.WithPrompt("graph> "))
+//	err := sh.Run(os.Stdin, os.Stdout)
+//
+// Each line is a command followed by its arguments, separated by spaces:
+//
+//	graph> add-edge a b
+//	graph> add-edge b c
+//	graph> path a c
+//	a → b → c
+//	graph> bridges
+//	a → b
+//	b → c
+//
+// Use the "help" command for the list of commands.
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/picatz/graph"
+)
+
+// ErrQuit is returned by Exec for the "quit" and "exit" commands, which
+// stop Run without an error.
+var ErrQuit = errors.New("graph shell quit")
+
+// command is a shell command.
+type command struct {
+	usage string
+	help  string
+	args  int // the minimum number of arguments
+	run   func(sh *Shell, w io.Writer, args []string) error
+}
+
+// commands are the commands of the shell, by name.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"help":        {"help", "list the commands", 0, (*Shell).help},
+		"nodes":       {"nodes", "list the nodes", 0, (*Shell).nodes},
+		"edges":       {"edges", "list the edges", 0, (*Shell).edges},
+		"add-node":    {"add-node name [key=value ...]", "add a node with attributes", 1, (*Shell).addNode},
+		"add-edge":    {"add-edge from to", "add a directed edge, adding missing nodes", 2, (*Shell).addEdge},
+		"remove-node": {"remove-node name", "remove a node and its edges", 1, (*Shell).removeNode},
+		"remove-edge": {"remove-edge from to", "remove the edges between two nodes", 2, (*Shell).removeEdge},
+		"set":         {"set name key=value ...", "set attributes of a node", 2, (*Shell).set},
+		"show":        {"show name", "show a node's attributes and edges", 1, (*Shell).show},
+		"path":        {"path from to", "print the shortest path between two nodes", 2, (*Shell).path},
+		"bridges":     {"bridges", "list the bridges", 0, (*Shell).bridges},
+		"components":  {"components", "list the connected components", 0, (*Shell).components},
+		"stats":       {"stats", "print statistics about the graph", 0, (*Shell).stats},
+		"render":      {"render [dot|json|yaml]", "print the graph using a format, dot by default", 0, (*Shell).render},
+		"quit":        {"quit", "leave the shell", 0, (*Shell).quit},
+		"exit":        {"exit", "leave the shell", 0, (*Shell).quit},
+	}
+}
+
+// Shell runs commands against a graph.
+type Shell struct {
+	graph  *graph.Instance
+	prompt string
+}
+
+// WithPrompt is a functional option that sets the prompt written before
+// reading each command, which is empty by default, for scripts.
+func WithPrompt(prompt string) func(*Shell) {
+	return func(sh *Shell) {
+		sh.prompt = prompt
+	}
+}
+
+// New returns a new shell for the given graph, or an empty graph if nil.
+func New(g *graph.Instance, opts ...func(*Shell)) *Shell {
+	if g == nil {
+		g = graph.New("shell")
+	}
+
+	sh := &Shell{graph: g}
+
+	for _, opt := range opts {
+		opt(sh)
+	}
+
+	return sh
+}
+
+// Graph returns the graph of the shell.
+func (sh *Shell) Graph() *graph.Instance {
+	return sh.graph
+}
+
+// Run reads commands from the given input, one per line, and executes
+// them, writing their results and errors to the given output, until the
+// input ends or the quit command is executed. Errors of commands don't stop
+// the shell; only errors reading the input or writing the output do.
+func (sh *Shell) Run(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+
+	for {
+		if _, err := io.WriteString(w, sh.prompt); err != nil {
+			return err
+		}
+
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		err := sh.Exec(w, scanner.Text())
+		switch {
+		case errors.Is(err, ErrQuit):
+			return nil
+		case err != nil:
+			if _, err := fmt.Fprintf(w, "error: %v\n", err); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Exec executes a single command line, writing its results to the given
+// output. Empty lines and lines starting with "#" are ignored.
+func (sh *Shell) Exec(w io.Writer, line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+
+	name, args := fields[0], fields[1:]
+
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q, see help", name)
+	}
+
+	if len(args) < cmd.args {
+		return fmt.Errorf("usage: %s", cmd.usage)
+	}
+
+	return cmd.run(sh, w, args)
+}
+
+// lookup returns the node with the given name.
+func (sh *Shell) lookup(name string) (*graph.Node, error) {
+	node, ok := sh.graph.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("node %q not found", name)
+	}
+	return node, nil
+}
+
+// lookupOrAdd returns the node with the given name, adding it if it isn't
+// in the graph yet.
+func (sh *Shell) lookupOrAdd(name string) *graph.Node {
+	node, ok := sh.graph.Lookup(name)
+	if !ok {
+		node = graph.NewNode(name, nil)
+		sh.graph.AddNode(node)
+	}
+	return node
+}
+
+// setAttributes sets the node's attributes from the given key=value
+// arguments.
+func setAttributes(node *graph.Node, args []string) error {
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid attribute %q, expected key=value", arg)
+		}
+		node.SetAttribute(key, parseValue(value))
+	}
+	return nil
+}
+
+// parseValue returns the given attribute value as a boolean or number, if
+// it is one, or as a string.
+func parseValue(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+func (sh *Shell) help(w io.Writer, args []string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := commands[name]
+		if _, err := fmt.Fprintf(w, "%-32s %s\n", cmd.usage, cmd.help); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *Shell) nodes(w io.Writer, args []string) error {
+	for _, node := range sh.graph.Nodes {
+		if _, err := fmt.Fprintln(w, node.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *Shell) edges(w io.Writer, args []string) error {
+	for _, edge := range sh.graph.Edges() {
+		if _, err := fmt.Fprintln(w, edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *Shell) addNode(w io.Writer, args []string) error {
+	if _, ok := sh.graph.Lookup(args[0]); ok {
+		return fmt.Errorf("node %q already exists", args[0])
+	}
+
+	node := graph.NewNode(args[0], nil)
+	if err := setAttributes(node, args[1:]); err != nil {
+		return err
+	}
+
+	sh.graph.AddNode(node)
+	return nil
+}
+
+func (sh *Shell) addEdge(w io.Writer, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s", commands["add-edge"].usage)
+	}
+
+	sh.graph.AddEdge(sh.lookupOrAdd(args[0]), sh.lookupOrAdd(args[1]))
+	return nil
+}
+
+func (sh *Shell) removeNode(w io.Writer, args []string) error {
+	node, err := sh.lookup(args[0])
+	if err != nil {
+		return err
+	}
+
+	sh.graph.RemoveNode(node)
+	return nil
+}
+
+func (sh *Shell) removeEdge(w io.Writer, args []string) error {
+	from, err := sh.lookup(args[0])
+	if err != nil {
+		return err
+	}
+
+	to, err := sh.lookup(args[1])
+	if err != nil {
+		return err
+	}
+
+	from.RemoveEdgesTo(to)
+	return nil
+}
+
+func (sh *Shell) set(w io.Writer, args []string) error {
+	node, err := sh.lookup(args[0])
+	if err != nil {
+		return err
+	}
+
+	return setAttributes(node, args[1:])
+}
+
+func (sh *Shell) show(w io.Writer, args []string) error {
+	node, err := sh.lookup(args[0])
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(node.Attributes))
+	for key := range node.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if _, err := fmt.Fprintln(w, node.Name); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "  %s=%v\n", key, node.Attributes[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range node.Edges {
+		if _, err := fmt.Fprintf(w, "  %s\n", edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *Shell) path(w io.Writer, args []string) error {
+	from, err := sh.lookup(args[0])
+	if err != nil {
+		return err
+	}
+
+	to, err := sh.lookup(args[1])
+	if err != nil {
+		return err
+	}
+
+	path := from.PathToAvoiding(to, nil, nil)
+	if path == nil {
+		return fmt.Errorf("no path from %q to %q", from.Name, to.Name)
+	}
+
+	_, err = fmt.Fprintln(w, path)
+	return err
+}
+
+func (sh *Shell) bridges(w io.Writer, args []string) error {
+	for _, bridge := range sh.graph.Bridges() {
+		if _, err := fmt.Fprintln(w, bridge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *Shell) components(w io.Writer, args []string) error {
+	for _, component := range sh.graph.Components() {
+		var names []string
+		for _, node := range component.Sorted() {
+			names = append(names, node.Name)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(names, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *Shell) stats(w io.Writer, args []string) error {
+	_, err := fmt.Fprintf(w, "nodes: %d\nedges: %d\ncomponents: %d\nacyclic: %v\n",
+		sh.graph.NodeCount(), sh.graph.EdgeCount(), len(sh.graph.Components()), sh.graph.IsAcyclic())
+	return err
+}
+
+func (sh *Shell) render(w io.Writer, args []string) error {
+	format := "dot"
+	if len(args) > 0 {
+		format = args[0]
+	}
+
+	switch format {
+	case "dot":
+		return sh.graph.EncodeDOT(w)
+	case "json":
+		return graph.EncodeJSON(w, sh.graph.Nodes)
+	case "yaml":
+		return graph.EncodeYAML(w, sh.graph)
+	default:
+		return fmt.Errorf("unsupported format %q, expected dot, json, or yaml", format)
+	}
+}
+
+func (sh *Shell) quit(w io.Writer, args []string) error {
+	return ErrQuit
+}
@@ -0,0 +1,103 @@
+package shell_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/shell"
+)
+
+func TestShell(t *testing.T) {
+	sh := shell.New(nil)
+
+	input := strings.Join([]string{
+		"# a → b → c    d",
+		"add-edge a b",
+		"add-edge b c",
+		"add-node d color=red weight=2",
+		"path a c",
+		"path c a",
+		"bridges",
+		"components",
+		"show d",
+		"remove-edge b c",
+		"remove-node d",
+		"stats",
+		"set a",
+		"unknown",
+		"quit",
+		"nodes",
+	}, "\n")
+
+	var out bytes.Buffer
+
+	if err := sh.Run(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"a → b → c",
+		`error: no path from "c" to "a"`,
+		"b → c",
+		"a → b",
+		"a b c",
+		"d",
+		"d",
+		"  color=red",
+		"  weight=2",
+		"nodes: 3",
+		"edges: 1",
+		"components: 2",
+		"acyclic: true",
+		"error: usage: set name key=value ...",
+		`error: unknown command "unknown", see help`,
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	d, ok := sh.Graph().Lookup("d")
+	if ok {
+		t.Fatalf("expected d to be removed, got: %v", d)
+	}
+}
+
+func TestShell_Exec(t *testing.T) {
+	a := graph.NewNode("a", nil)
+
+	sh := shell.New(graph.New("test", graph.WithNodes(graph.NewNodes(a))), shell.WithPrompt("> "))
+
+	var out bytes.Buffer
+
+	if err := sh.Exec(&out, "set a tier=db"); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Attributes["tier"] != "db" {
+		t.Fatalf("expected the attribute to be set, got: %v", a.Attributes)
+	}
+
+	for _, line := range []string{"add-node a", "set a tier", "show b", "render xml"} {
+		if err := sh.Exec(&out, line); err == nil {
+			t.Fatalf("expected an error for %q", line)
+		}
+	}
+
+	if err := sh.Exec(&out, "exit"); err != shell.ErrQuit {
+		t.Fatalf("expected ErrQuit, got: %v", err)
+	}
+
+	out.Reset()
+
+	if err := sh.Run(strings.NewReader("nodes\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "> a\n> " {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
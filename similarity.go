@@ -0,0 +1,198 @@
+package graph
+
+// Similarity returns how similar the given graphs are, from 0, for graphs
+// that have nothing in common, to 1, for graphs with the same nodes and
+// edges, by name, such as two versions of a dependency graph, to detect how
+// much it drifted. It is the Jaccard index of the sets of nodes and edges
+// of the graphs, the number of nodes and edges they share divided by the
+// number of distinct nodes and edges in either graph.
+//
+//	a → b → c    a → b → d    Similarity: 3/7, sharing a, b, and a → b
+//
+// Nodes are compared by name, and edges by the names of their nodes, their
+// direction, and their name, so undirected (None) edges are the same either
+// way, but different from directed edges between the same nodes. Labels and
+// attributes are ignored, and so are edges to nodes outside of the graphs.
+// Two empty graphs are identical, with a similarity of 1.
+//
+// See Fingerprint to compare graphs by structure, regardless of names.
+//
+// https://en.wikipedia.org/wiki/Jaccard_index
+func Similarity(a, b *Instance) float64 {
+	ka, kb := a.similarityKeys(), b.similarityKeys()
+
+	if len(ka) == 0 && len(kb) == 0 {
+		return 1
+	}
+
+	var shared int
+	for key := range ka {
+		if kb[key] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ka)+len(kb)-shared)
+}
+
+// similarityKey identifies a node, with an empty direction, or an edge,
+// with its nodes in a canonical order, across graphs.
+type similarityKey struct {
+	from, to  string
+	direction EdgeDirection
+	name      string
+}
+
+// similarityKeys returns the keys of the graph's nodes and edges.
+func (inst *Instance) similarityKeys() map[similarityKey]bool {
+	keys := make(map[similarityKey]bool, len(inst.Nodes))
+
+	for _, node := range inst.Nodes {
+		keys[similarityKey{from: node.Name}] = true
+	}
+
+	members := inst.members()
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		if !members.Contains(edge.Node) {
+			return
+		}
+
+		key := similarityKey{from: from.Name, to: edge.Node.Name, direction: edge.Direction, name: edge.Name}
+
+		switch edge.Direction {
+		case In:
+			key.from, key.to, key.direction = key.to, key.from, Out
+		case Unknown:
+			key.direction = None
+		}
+
+		if key.direction != Out && key.from > key.to {
+			key.from, key.to = key.to, key.from
+		}
+
+		keys[key] = true
+	})
+
+	return keys
+}
+
+// JaccardSimilarity returns how similar the given nodes are by their
+// neighbors, the number of neighbors they share divided by the number of
+// distinct neighbors of either node, from 0 to 1, ignoring edge directions
+// and edges from a node to itself. This is a simple way to recommend nodes
+// similar to another, like users who follow the same accounts.
+//
+//	a ── c ── b    JaccardSimilarity(a, b): 1/3, sharing c of c, d, and e
+//	│         │
+//	d         e
+//
+// Nodes without any neighbors have a similarity of 0.
+//
+// https://en.wikipedia.org/wiki/Jaccard_index
+func JaccardSimilarity(a, b *Node) float64 {
+	na, nb := neighborSet(a), neighborSet(b)
+
+	var shared int
+	for node := range na {
+		if nb.Contains(node) {
+			shared++
+		}
+	}
+
+	union := len(na) + len(nb) - shared
+	if union == 0 {
+		return 0
+	}
+
+	return float64(shared) / float64(union)
+}
+
+// neighborSet returns the nodes adjacent to the given node, in either
+// direction, other than itself.
+func neighborSet(n *Node) NodeSet {
+	neighbors := NodeSet{}
+	for _, edge := range n.Edges {
+		if edge.Node != n {
+			neighbors.Add(edge.Node)
+		}
+	}
+	return neighbors
+}
+
+// simRankDecay is the decay factor of SimRank, the C of its definition,
+// which makes similarities from distant neighbors count less.
+const simRankDecay = 0.8
+
+// SimRank returns the SimRank similarities of the graph's nodes after the
+// given number of iterations, where the value at row i and column j is the
+// similarity of the i-th and the j-th nodes of the graph, from 0 to 1, with
+// rows and columns in the order of the graph's Nodes, like AdjacencyMatrix.
+//
+// Two nodes are similar if they are referenced by similar nodes: each node
+// is identical to itself, with a similarity of 1, and the similarity of two
+// other nodes is the average similarity of the pairs of nodes with edges to
+// them, times a decay factor of 0.8. Undirected (None) and bi-directional
+// (Both) edges go both ways, and edges to nodes outside of the graph are
+// ignored. Nodes without inward edges aren't similar to other nodes.
+//
+//	a → c    SimRank(1): c and d have a similarity of 0.8,
+//	a → d    since both are referenced by a
+//
+// Each iteration takes a time proportional to the square of the number of
+// edges, and similarities converge quickly, so few iterations, like 5, are
+// usually enough. With less than one iteration, only the similarities of
+// nodes to themselves are returned.
+//
+// https://en.wikipedia.org/wiki/SimRank
+func (inst *Instance) SimRank(iterations int) [][]float64 {
+	index := inst.nodeIndex()
+	n := len(inst.Nodes)
+
+	in := make([][]int, n)
+	for i, node := range inst.Nodes {
+		seen := map[int]bool{}
+		for _, edge := range node.Edges {
+			if edge.Direction == Out {
+				continue
+			}
+			if j, ok := index[edge.Node]; ok && !seen[j] {
+				seen[j] = true
+				in[i] = append(in[i], j)
+			}
+		}
+	}
+
+	sim := squareMatrix(n)
+	for i := range sim {
+		sim[i][i] = 1
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		next := squareMatrix(n)
+
+		for a := 0; a < n; a++ {
+			next[a][a] = 1
+
+			for b := a + 1; b < n; b++ {
+				if len(in[a]) == 0 || len(in[b]) == 0 {
+					continue
+				}
+
+				var total float64
+				for _, i := range in[a] {
+					for _, j := range in[b] {
+						total += sim[i][j]
+					}
+				}
+
+				s := simRankDecay * total / float64(len(in[a])*len(in[b]))
+				next[a][b], next[b][a] = s, s
+			}
+		}
+
+		sim = next
+	}
+
+	return sim
+}
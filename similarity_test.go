@@ -0,0 +1,134 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestSimilarity(t *testing.T) {
+	build := func(last string, direction graph.EdgeDirection) *graph.Instance {
+		var (
+			a = graph.NewNode("a", nil)
+			b = graph.NewNode("b", graph.Attributes{"version": last})
+			c = graph.NewNode(last, nil)
+		)
+
+		a.AddEdge(b)
+		b.AddEdgeWithDirection(c, direction)
+
+		return graph.New("deps", graph.WithNodes(graph.Nodes{a, b, c}))
+	}
+
+	tests := []struct {
+		name     string
+		a, b     *graph.Instance
+		expected float64
+	}{
+		{"same", build("c", graph.Out), build("c", graph.Out), 1},
+		{"drifted", build("c", graph.Out), build("d", graph.Out), 3.0 / 7},
+		{"direction", build("c", graph.Out), build("c", graph.None), 4.0 / 6},
+		{"reversed", build("c", graph.In), build("c", graph.Out), 4.0 / 6},
+		{"empty", graph.New("a"), graph.New("b"), 1},
+		{"disjoint", build("c", graph.Out), graph.New("b", graph.WithNodes(graph.NewNodes(graph.NewNode("x", nil)))), 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := graph.Similarity(test.a, test.b); got != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+
+	// Undirected edges are the same either way.
+	x, y := graph.NewNode("x", nil), graph.NewNode("y", nil)
+	x.AddEdgeWithDirection(y, graph.None)
+
+	v, w := graph.NewNode("x", nil), graph.NewNode("y", nil)
+	w.AddEdgeWithDirection(v, graph.None)
+
+	if got := graph.Similarity(graph.New("a", graph.WithNodes(graph.Nodes{x, y})), graph.New("b", graph.WithNodes(graph.Nodes{v, w}))); got != 1 {
+		t.Fatalf("expected undirected edges to be the same either way, got %v", got)
+	}
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	a.AddEdgeWithDirection(c, graph.None)
+	c.AddEdgeWithDirection(b, graph.None)
+	a.AddEdgeWithDirection(d, graph.None)
+	e.AddEdge(b)
+	a.AddEdge(a)
+
+	if got := graph.JaccardSimilarity(a, b); got != 1.0/3 {
+		t.Fatalf("expected 1/3, got %v", got)
+	}
+
+	if got := graph.JaccardSimilarity(c, d); got != 0.5 {
+		t.Fatalf("expected 0.5, got %v", got)
+	}
+
+	if got := graph.JaccardSimilarity(graph.NewNode("x", nil), graph.NewNode("y", nil)); got != 0 {
+		t.Fatalf("expected 0 for nodes without neighbors, got %v", got)
+	}
+}
+
+func TestInstance_SimRank(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+	)
+
+	// a and b both reference c and d, and e is only referenced by c.
+	a.AddEdge(c)
+	a.AddEdge(d)
+	b.AddEdge(c)
+	b.AddEdge(d)
+	c.AddEdge(e)
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b, c, d, e}))
+
+	sim := g.SimRank(5)
+
+	for i := range g.Nodes {
+		if sim[i][i] != 1 {
+			t.Fatalf("expected %s to be identical to itself, got %v", g.Nodes[i].Name, sim[i][i])
+		}
+		for j := range g.Nodes {
+			if sim[i][j] != sim[j][i] {
+				t.Fatalf("expected similarities to be symmetric")
+			}
+		}
+	}
+
+	// c and d are referenced by a and b, which aren't similar, since
+	// they aren't referenced, so half of the pairs are identical.
+	if got := sim[2][3]; got != 0.4 {
+		t.Fatalf("expected c and d to have a similarity of 0.4, got %v", got)
+	}
+
+	// e is referenced by c, which is similar to d, but d references
+	// nothing, so e isn't similar to d.
+	if got := sim[3][4]; got != 0 {
+		t.Fatalf("expected d and e to not be similar, got %v", got)
+	}
+
+	if got := sim[0][1]; got != 0 {
+		t.Fatalf("expected a and b to not be similar, got %v", got)
+	}
+
+	if got := g.SimRank(0); got[2][3] != 0 || got[2][2] != 1 {
+		t.Fatalf("unexpected similarities without iterations: %v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package graph
+
+// SpanningTree returns a breadth-first spanning tree of the nodes reachable
+// from the given root node, following edges that aren't inward edges. The
+// tree is a new graph with copies of the reachable nodes, where each node
+// has an outward edge to each of its children, keeping the name and
+// attributes of the edge it was reached by, and a single inward edge from
+// its parent, see Node.Parent.
+//
+//	a → b → d         a → b → d
+//	↓ ↗   ↖ ↓    ⇒    ↓       ↓
+//	c       e         c       e
+//
+// Each node is reached using the fewest edges from the root, which is
+// useful to extract hierarchies, like breadcrumbs, from cyclic graphs.
+// Only nodes in the graph are included, and if the root node isn't in
+// the graph, an empty graph is returned.
+//
+// https://en.wikipedia.org/wiki/Spanning_tree
+func (inst *Instance) SpanningTree(root *Node) *Instance {
+	defer inst.trace("SpanningTree")()
+
+	tree := New(inst.Name)
+
+	parents, edges, order := inst.spanningTree(root)
+
+	copies := make(map[*Node]*Node, len(order))
+	for _, node := range order {
+		copies[node] = &Node{
+			Name:       node.Name,
+			Attributes: copyAttributes(node.Attributes),
+			Labels:     append([]string(nil), node.Labels...),
+		}
+		tree.AddNode(copies[node])
+	}
+
+	for _, node := range order {
+		parent, ok := parents[node]
+		if !ok || parent == nil {
+			continue
+		}
+
+		from, to := copies[parent], copies[node]
+		from.AddEdge(to)
+
+		attrs := copyAttributes(edges[node].Attributes)
+		for _, edge := range (Edges{from.Edges[len(from.Edges)-1], to.Edges[len(to.Edges)-1]}) {
+			edge.Name = edges[node].Name
+			edge.Attributes = attrs
+		}
+	}
+
+	return tree
+}
+
+// SpanningTreeParents returns the parent of each node in the breadth-first
+// spanning tree of the nodes reachable from the given root node, like
+// SpanningTree, without copying them. The root node's parent is nil.
+//
+// Following the parents from a node back to the root gives the shortest
+// path to the node, in reverse.
+func (inst *Instance) SpanningTreeParents(root *Node) map[*Node]*Node {
+	parents, _, _ := inst.spanningTree(root)
+	return parents
+}
+
+// spanningTree returns the parent of each node in the breadth-first spanning
+// tree of the given root node, the edges they were reached by, and the nodes
+// in the order they were reached.
+func (inst *Instance) spanningTree(root *Node) (map[*Node]*Node, map[*Node]*Edge, Nodes) {
+	parents := map[*Node]*Node{}
+	edges := map[*Node]*Edge{}
+
+	members := inst.members()
+	if !members.Contains(root) {
+		return parents, edges, nil
+	}
+
+	parents[root] = nil
+	order := Nodes{root}
+
+	for i := 0; i < len(order); i++ {
+		node := order[i]
+		for _, edge := range node.Edges {
+			if edge.Direction == In || !members.Contains(edge.Node) {
+				continue
+			}
+			if _, seen := parents[edge.Node]; seen {
+				continue
+			}
+			parents[edge.Node] = node
+			edges[edge.Node] = edge
+			order = append(order, edge.Node)
+		}
+	}
+
+	return parents, edges, order
+}
+
+// Parent returns the node of the node's first inward edge, which is its
+// parent in trees, like those returned by SpanningTree, or nil if there
+// is none.
+func (n *Node) Parent() *Node {
+	for _, edge := range n.Edges {
+		if edge.Direction == In {
+			return edge.Node
+		}
+	}
+	return nil
+}
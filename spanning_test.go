@@ -0,0 +1,68 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_SpanningTree(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"color": "red"})
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+		e = graph.NewNode("e", nil)
+		f = graph.NewNode("f", nil)
+	)
+
+	// a → b → d
+	// ↓ ↗   ↖ ↓
+	// c       e    f
+
+	a.AddEdge(b)
+	a.AddEdgeTyped(c, "child")
+	c.AddEdge(b)
+	b.AddEdge(d)
+	d.AddEdge(e)
+	e.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d, e, f)))
+
+	tree := g.SpanningTree(a)
+
+	if tree.Nodes.String() != "a, b, c, d, e" {
+		t.Fatalf("unexpected tree nodes: %v", tree.Nodes)
+	}
+
+	if edges := tree.Edges().String(); edges != "a → b, a → c, b → d, d → e" {
+		t.Fatalf("unexpected tree edges: %v", edges)
+	}
+
+	if tree.Nodes[2].Edges[0].Name != "child" || tree.Nodes[0].Attributes["color"] != "red" {
+		t.Fatal("expected tree to keep node attributes and edge names")
+	}
+
+	if !tree.IsAcyclic() {
+		t.Fatal("expected the tree to be acyclic")
+	}
+
+	var breadcrumbs graph.Nodes
+	for n := tree.Nodes[4]; n != nil; n = n.Parent() {
+		breadcrumbs = append(graph.Nodes{n}, breadcrumbs...)
+	}
+
+	if breadcrumbs.String() != "a, b, d, e" {
+		t.Fatalf("unexpected breadcrumbs: %v", breadcrumbs)
+	}
+
+	parents := g.SpanningTreeParents(a)
+
+	if len(parents) != 5 || parents[a] != nil || parents[e] != d || parents[b] != a {
+		t.Fatalf("unexpected parents: %v", parents)
+	}
+
+	if tree := g.SpanningTree(graph.NewNode("x", nil)); len(tree.Nodes) != 0 {
+		t.Fatalf("expected an empty tree for a node outside of the graph, got %v", tree.Nodes)
+	}
+}
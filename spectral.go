@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"math"
+	"sort"
+)
+
+// AdjacencyMatrix returns the weighted adjacency matrix of the graph, where
+// the value at row i and column j is the sum of the weights of the edges
+// from the i-th node of the graph to the j-th node, using Edge.Weight.
+// Rows and columns are in the order of the graph's Nodes. Undirected (None)
+// and bi-directional (Both) edges go both ways, and edges to nodes outside
+// of the graph are ignored.
+//
+//	a → b ── c    [0 1 0]
+//	              [0 0 1]
+//	              [0 1 0]
+//
+// The matrix can be used with numerical libraries, like gonum, by copying
+// its rows into their own matrix types.
+//
+// https://en.wikipedia.org/wiki/Adjacency_matrix
+func (inst *Instance) AdjacencyMatrix() [][]float64 {
+	index := inst.nodeIndex()
+	matrix := squareMatrix(len(inst.Nodes))
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		to, ok := index[edge.Node]
+		if !ok {
+			return
+		}
+		i, w := index[from], edge.Weight()
+
+		switch edge.Direction {
+		case Out:
+			matrix[i][to] += w
+		case In:
+			matrix[to][i] += w
+		default:
+			matrix[i][to] += w
+			if i != to {
+				matrix[to][i] += w
+			}
+		}
+	})
+
+	return matrix
+}
+
+// Laplacian returns the weighted Laplacian matrix of the graph, ignoring
+// edge directions, the degree matrix minus the adjacency matrix, where the
+// degree of a node is the sum of the weights of its edges to other nodes.
+// Rows and columns are in the order of the graph's Nodes, and the matrix is
+// symmetric, so it is suitable for symmetric eigen-decomposition, like with
+// gonum's mat.EigenSym. Edges from a node to itself are ignored.
+//
+//	a → b ── c    [ 1 -1  0]
+//	              [-1  2 -1]
+//	              [ 0 -1  1]
+//
+// Like Bridges, a pair of outward edges in opposite directions, like those
+// added using AddLink or MeshNodes, is a single undirected edge, with the
+// larger of their weights.
+//
+// https://en.wikipedia.org/wiki/Laplacian_matrix
+func (inst *Instance) Laplacian() [][]float64 {
+	index := inst.nodeIndex()
+	matrix := squareMatrix(len(inst.Nodes))
+
+	type undirected struct {
+		i, j int
+		w    float64
+	}
+
+	var (
+		edges   []undirected
+		outward = map[[2]int][]int{}
+	)
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		to, ok := index[edge.Node]
+		i := index[from]
+		if !ok || i == to {
+			return
+		}
+		w := edge.Weight()
+
+		// Outward edges are merged with an outward edge in the opposite
+		// direction, if there is one that isn't merged yet.
+		if edge.Direction == Out {
+			back := [2]int{to, i}
+			if queue := outward[back]; len(queue) > 0 {
+				outward[back] = queue[1:]
+				edges[queue[0]].w = math.Max(edges[queue[0]].w, w)
+				return
+			}
+			key := [2]int{i, to}
+			outward[key] = append(outward[key], len(edges))
+		}
+
+		edges = append(edges, undirected{i, to, w})
+	})
+
+	for _, e := range edges {
+		matrix[e.i][e.j] -= e.w
+		matrix[e.j][e.i] -= e.w
+		matrix[e.i][e.i] += e.w
+		matrix[e.j][e.j] += e.w
+	}
+
+	return matrix
+}
+
+// LaplacianSpectrum returns the eigenvalues of the graph's Laplacian matrix
+// in increasing order. The smallest eigenvalue is always 0, and the number
+// of eigenvalues equal to 0 is the number of connected components of the
+// graph, ignoring edge directions.
+//
+// Eigenvalues are computed using the Jacobi eigenvalue algorithm, which
+// takes O(n³) time for each sweep over the n × n matrix, so it's meant for
+// graphs with up to a few hundred nodes.
+//
+// https://en.wikipedia.org/wiki/Spectral_graph_theory
+func (inst *Instance) LaplacianSpectrum() []float64 {
+	return symmetricEigenvalues(inst.Laplacian())
+}
+
+// AlgebraicConnectivity returns the second smallest eigenvalue of the
+// graph's Laplacian matrix, also known as the Fiedler value, ignoring edge
+// directions, where a pair of outward edges in opposite directions is a
+// single undirected edge, see Laplacian. It is greater than 0 only if the
+// graph is connected, and the larger it is, the harder it is to disconnect
+// the graph by removing nodes or edges, so it can be used to score the
+// robustness of a network.
+//
+//	a ── b ── c           1
+//	a ── b ── c ── a      3
+//
+// Graphs with fewer than two nodes have an algebraic connectivity of 0.
+//
+// https://en.wikipedia.org/wiki/Algebraic_connectivity
+func (inst *Instance) AlgebraicConnectivity() float64 {
+	if len(inst.Nodes) < 2 {
+		return 0
+	}
+
+	spectrum := inst.LaplacianSpectrum()
+
+	// Eigenvalues of disconnected graphs that should be 0 can be off by
+	// rounding errors, relative to the scale of the matrix.
+	scale := math.Max(1, spectrum[len(spectrum)-1])
+	if math.Abs(spectrum[1]) < 1e-9*scale {
+		return 0
+	}
+	return spectrum[1]
+}
+
+// nodeIndex returns the position of each node in the graph's Nodes.
+func (inst *Instance) nodeIndex() map[*Node]int {
+	index := make(map[*Node]int, len(inst.Nodes))
+	for i := len(inst.Nodes) - 1; i >= 0; i-- {
+		index[inst.Nodes[i]] = i
+	}
+	return index
+}
+
+// squareMatrix returns an n × n matrix of zeros.
+func squareMatrix(n int) [][]float64 {
+	values := make([]float64, n*n)
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = values[i*n : (i+1)*n : (i+1)*n]
+	}
+	return matrix
+}
+
+// symmetricEigenvalues returns the eigenvalues of the given symmetric
+// matrix in increasing order, using the cyclic Jacobi eigenvalue algorithm,
+// which repeatedly rotates the matrix to zero its off-diagonal values,
+// leaving the eigenvalues on the diagonal.
+//
+// https://en.wikipedia.org/wiki/Jacobi_eigenvalue_algorithm
+func symmetricEigenvalues(m [][]float64) []float64 {
+	n := len(m)
+
+	a := squareMatrix(n)
+	var norm float64
+	for i := range m {
+		copy(a[i], m[i])
+		for _, v := range m[i] {
+			norm += v * v
+		}
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += a[p][q] * a[p][q]
+			}
+		}
+		if off <= 1e-24*norm {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					kp, kq := a[k][p], a[k][q]
+					a[k][p] = c*kp - s*kq
+					a[k][q] = s*kp + c*kq
+				}
+				for k := 0; k < n; k++ {
+					pk, qk := a[p][k], a[q][k]
+					a[p][k] = c*pk - s*qk
+					a[q][k] = s*pk + c*qk
+				}
+			}
+		}
+	}
+
+	eigenvalues := make([]float64, n)
+	for i := range eigenvalues {
+		eigenvalues[i] = a[i][i]
+	}
+	sort.Float64s(eigenvalues)
+
+	return eigenvalues
+}
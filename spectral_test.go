@@ -0,0 +1,108 @@
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_AdjacencyMatrix(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b ── c
+
+	a.AddEdgeWithDirection(b, graph.Out)
+	b.AddEdgeWithDirection(c, graph.None)
+
+	b.Edges[1].Attributes = graph.Attributes{"weight": 2}
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b, c}))
+
+	if got := fmt.Sprint(g.AdjacencyMatrix()); got != "[[0 1 0] [0 0 2] [0 2 0]]" {
+		t.Fatalf("unexpected adjacency matrix %s", got)
+	}
+
+	if got := fmt.Sprint(g.Laplacian()); got != "[[1 -1 0] [-1 3 -2] [0 -2 2]]" {
+		t.Fatalf("unexpected laplacian %s", got)
+	}
+}
+
+func TestInstance_LaplacianSpectrum(t *testing.T) {
+	tests := []struct {
+		name     string
+		edges    [][2]string
+		spectrum []float64
+	}{
+		{"path", [][2]string{{"a", "b"}, {"b", "c"}}, []float64{0, 1, 3}},
+		{"cycle", [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "a"}}, []float64{0, 2, 2, 4}},
+		{"complete", [][2]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}}, []float64{0, 4, 4, 4}},
+		{"disconnected", [][2]string{{"a", "b"}, {"c", "d"}}, []float64{0, 0, 2, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := graph.New(test.name)
+			if err := g.LoadEdges(test.edges); err != nil {
+				t.Fatal(err)
+			}
+
+			spectrum := g.LaplacianSpectrum()
+			if len(spectrum) != len(test.spectrum) {
+				t.Fatalf("expected %d eigenvalues, got %v", len(test.spectrum), spectrum)
+			}
+			for i := range spectrum {
+				if math.Abs(spectrum[i]-test.spectrum[i]) > 1e-9 {
+					t.Fatalf("expected spectrum %v, got %v", test.spectrum, spectrum)
+				}
+			}
+
+			if got := g.AlgebraicConnectivity(); math.Abs(got-test.spectrum[1]) > 1e-9 {
+				t.Fatalf("expected algebraic connectivity %v, got %v", test.spectrum[1], got)
+			}
+		})
+	}
+
+	t.Run("mesh", func(t *testing.T) {
+		nodes := graph.Nodes{
+			graph.NewNode("a", nil),
+			graph.NewNode("b", nil),
+			graph.NewNode("c", nil),
+			graph.NewNode("d", nil),
+		}
+
+		// Each pair of nodes is linked with two outward edges, which
+		// count as one undirected edge.
+		graph.MeshNodes(nodes...)
+
+		g := graph.New("mesh", graph.WithNodes(nodes))
+
+		if got := fmt.Sprint(g.Laplacian()); got != "[[3 -1 -1 -1] [-1 3 -1 -1] [-1 -1 3 -1] [-1 -1 -1 3]]" {
+			t.Fatalf("unexpected laplacian %s", got)
+		}
+
+		expected := []float64{0, 4, 4, 4}
+		spectrum := g.LaplacianSpectrum()
+		if len(spectrum) != len(expected) {
+			t.Fatalf("expected spectrum %v, got %v", expected, spectrum)
+		}
+		for i := range spectrum {
+			if math.Abs(spectrum[i]-expected[i]) > 1e-9 {
+				t.Fatalf("expected spectrum %v, got %v", expected, spectrum)
+			}
+		}
+
+		if got := g.AlgebraicConnectivity(); math.Abs(got-4) > 1e-9 {
+			t.Fatalf("expected algebraic connectivity 4, got %v", got)
+		}
+	})
+
+	if got := graph.New("empty").AlgebraicConnectivity(); got != 0 {
+		t.Fatalf("expected 0 for an empty graph, got %v", got)
+	}
+}
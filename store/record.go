@@ -0,0 +1,139 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/picatz/graph"
+)
+
+// Recorder records the mutations of a graph to a store as they are made,
+// see Store.Record.
+type Recorder struct {
+	store *Store
+	graph *graph.Instance
+	lock  sync.Locker
+
+	cancel func()
+	done   chan struct{}
+
+	// since is the generation of the graph when it was last snapshotted by
+	// the recorder, before which mutations are already in the store, and
+	// last the generation of the last mutation recorded.
+	since uint64
+	last  uint64
+
+	// err is the first error recording mutations, returned by Close.
+	err error
+}
+
+// WithLock is a functional option that sets the lock held while recording
+// each mutation, which should be the lock held while changing the graph,
+// since the nodes and edges of mutations are read as they are recorded,
+// concurrently with the changes made to the graph.
+func WithLock(lock sync.Locker) func(*Recorder) {
+	return func(r *Recorder) {
+		r.lock = lock
+	}
+}
+
+// Record records the mutations of the given graph to the store as they are
+// made, using the graph's mutation stream, see graph.Instance.Subscribe,
+// until the recorder is closed. The store should hold a snapshot of the
+// graph, or be replayed into it using Load, before recording starts.
+//
+// Mutations are recorded in the background, so recording never slows the
+// changes made to the graph. If the recorder falls behind and mutations
+// are dropped, it takes a new snapshot of the graph, which replaces the
+// log, instead of recording them. Changes that aren't part of the mutation
+// stream, like labels, are only stored by snapshots.
+func (s *Store) Record(inst *graph.Instance, opts ...func(*Recorder)) *Recorder {
+	r := &Recorder{
+		store: s,
+		graph: inst,
+		done:  make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	mutations, cancel := inst.Subscribe()
+	r.cancel = cancel
+
+	go func() {
+		defer close(r.done)
+		for m := range mutations {
+			if err := r.record(m); err != nil && r.err == nil {
+				r.err = err
+			}
+		}
+	}()
+
+	return r
+}
+
+// Close stops recording, after the mutations made before it was called
+// are recorded, returning the first error recording them, if any. If the
+// graph changed since the last mutation that was recorded, because the
+// last mutations were dropped, it takes a new snapshot of the graph.
+func (r *Recorder) Close() error {
+	r.cancel()
+	<-r.done
+
+	if r.lock != nil {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+	}
+
+	if generation := r.graph.Generation(); generation > r.since && generation > r.last {
+		if err := r.store.Snapshot(r.graph); err != nil && r.err == nil {
+			r.err = fmt.Errorf("store failed to resynchronize: %w", err)
+		}
+	}
+
+	return r.err
+}
+
+// record appends the given mutation to the store.
+func (r *Recorder) record(m graph.Mutation) error {
+	if r.lock != nil {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+	}
+
+	if m.Dropped > 0 {
+		r.since = r.graph.Generation()
+		if err := r.store.Snapshot(r.graph); err != nil {
+			return fmt.Errorf("store failed to resynchronize after %d dropped mutations: %w", m.Dropped, err)
+		}
+	}
+
+	if m.Generation <= r.since {
+		return nil
+	}
+	r.last = m.Generation
+
+	switch m.Kind {
+	case graph.NodeAdded:
+		return r.store.AddNode(m.Node.Name, m.Node.Attributes)
+	case graph.NodeRemoved:
+		return r.store.RemoveNode(m.Node.Name)
+	case graph.EdgeAdded, graph.EdgeRemoved:
+		// Edges to nodes of other graphs can't be replayed.
+		if r.graph.ID(m.Edge.Node) < 0 {
+			return nil
+		}
+		if m.Kind == graph.EdgeAdded {
+			return r.store.AddEdge(m.Node.Name, m.Edge)
+		}
+		return r.store.RemoveEdge(m.Node.Name, m.Edge)
+	case graph.AttrChanged:
+		if m.Value == nil {
+			return r.store.DeleteAttribute(m.Node.Name, m.Attribute)
+		}
+		return r.store.SetAttribute(m.Node.Name, m.Attribute, m.Value)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,366 @@
+// Package store persists graph instances to disk, so they survive restarts.
+//
+// A store is a single file of JSON lines, starting with a snapshot of the
+// whole graph, followed by an append-only log of the mutations recorded
+// since the snapshot. Loading the store decodes the snapshot and replays
+// the log. Taking a new snapshot atomically replaces the file, compacting
+// the log.
+//
+// Mutations are recorded from the graph's mutation stream using Record, or
+// one at a time using the methods of the store, like AddNode:
+//
+//	s, err := store.Open("graph.jsonl")
+//	...
+//	g, err := s.Load()
+//	...
+//	rec := s.Record(g, store.WithLock(&mu))
+//	defer rec.Close()
+//
+// Nodes are identified by their names in the log, so node names should be
+// unique within a stored graph.
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/picatz/graph"
+)
+
+// Op is the kind of a record in the store's file.
+type Op string
+
+const (
+	OpSnapshot        Op = "snapshot"
+	OpAddNode         Op = "add_node"
+	OpRemoveNode      Op = "remove_node"
+	OpAddEdge         Op = "add_edge"
+	OpRemoveEdge      Op = "remove_edge"
+	OpRemoveEdges     Op = "remove_edges"
+	OpSetAttribute    Op = "set_attribute"
+	OpDeleteAttribute Op = "delete_attribute"
+)
+
+// record is a single line in the store's file. The name and attributes of
+// edges are stored in Name and Attributes, along with From and To.
+type record struct {
+	Op         Op                  `json:"op"`
+	Name       string              `json:"name,omitempty"`
+	Attributes graph.Attributes    `json:"attributes,omitempty"`
+	Nodes      json.RawMessage     `json:"nodes,omitempty"`
+	From       string              `json:"from,omitempty"`
+	To         string              `json:"to,omitempty"`
+	Direction  graph.EdgeDirection `json:"direction,omitempty"`
+	Key        string              `json:"key,omitempty"`
+	Value      any                 `json:"value,omitempty"`
+}
+
+// Store persists a graph instance to a file.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open opens the store at the given path, creating the file if it
+// doesn't exist yet.
+func Open(path string) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("store failed to open %q: %w", path, err)
+	}
+
+	return &Store{path: path, file: file}, nil
+}
+
+// Close closes the store, syncing any recorded mutations to disk.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("store failed to sync: %w", err)
+	}
+
+	return s.file.Close()
+}
+
+// Sync commits the recorded mutations to stable storage.
+func (s *Store) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Sync()
+}
+
+// append writes the given record to the end of the log.
+func (s *Store) append(rec record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("store failed to encode %s: %w", rec.Op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.file.Write(append(b, '\n'))
+	if err != nil {
+		return fmt.Errorf("store failed to write %s: %w", rec.Op, err)
+	}
+	return nil
+}
+
+// Snapshot atomically replaces the contents of the store with a snapshot
+// of the given graph, discarding the previously recorded mutations.
+func (s *Store) Snapshot(inst *graph.Instance) error {
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeJSON(buf, inst.Nodes)
+	if err != nil {
+		return fmt.Errorf("store failed to encode snapshot: %w", err)
+	}
+
+	b, err := json.Marshal(record{
+		Op:         OpSnapshot,
+		Name:       inst.Name,
+		Attributes: inst.Attributes,
+		Nodes:      bytes.TrimSpace(buf.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("store failed to encode snapshot: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("store failed to create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("store failed to write snapshot: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("store failed to sync snapshot: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("store failed to close snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("store failed to replace %q: %w", s.path, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("store failed to reopen %q: %w", s.path, err)
+	}
+
+	s.file.Close()
+	s.file = file
+
+	return nil
+}
+
+// AddNode records that a node with the given name and attributes was added.
+func (s *Store) AddNode(name string, attrs graph.Attributes) error {
+	return s.append(record{Op: OpAddNode, Name: name, Attributes: attrs})
+}
+
+// RemoveNode records that the named node was removed.
+func (s *Store) RemoveNode(name string) error {
+	return s.append(record{Op: OpRemoveNode, Name: name})
+}
+
+// AddEdge records that the given edge was added to the named node, along
+// with the edge on the other side of the relationship, with its name,
+// direction, and attributes.
+func (s *Store) AddEdge(from string, edge *graph.Edge) error {
+	return s.append(edgeRecord(OpAddEdge, from, edge))
+}
+
+// RemoveEdge records that the given edge was removed from the named node,
+// along with the edge on the other side of the relationship, like
+// Node.RemoveEdge. It is matched by its node, direction, and name.
+func (s *Store) RemoveEdge(from string, edge *graph.Edge) error {
+	return s.append(edgeRecord(OpRemoveEdge, from, edge))
+}
+
+// edgeRecord returns the record of the given operation on the given edge
+// of the named node.
+func edgeRecord(op Op, from string, edge *graph.Edge) record {
+	return record{
+		Op:         op,
+		Name:       edge.Name,
+		Attributes: edge.Attributes,
+		From:       from,
+		To:         edge.Node.Name,
+		Direction:  edge.Direction,
+	}
+}
+
+// RemoveEdges records that all edges between the named nodes were removed.
+func (s *Store) RemoveEdges(from, to string) error {
+	return s.append(record{Op: OpRemoveEdges, From: from, To: to})
+}
+
+// SetAttribute records that an attribute of the named node was set.
+func (s *Store) SetAttribute(node, key string, value any) error {
+	return s.append(record{Op: OpSetAttribute, Name: node, Key: key, Value: value})
+}
+
+// DeleteAttribute records that an attribute of the named node was removed.
+func (s *Store) DeleteAttribute(node, key string) error {
+	return s.append(record{Op: OpDeleteAttribute, Name: node, Key: key})
+}
+
+// Load reads the graph from the store, decoding the latest snapshot and
+// replaying the mutations recorded since. An empty store returns an empty
+// graph.
+func (s *Store) Load() (*graph.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("store failed to open %q: %w", s.path, err)
+	}
+	defer file.Close()
+
+	inst := graph.New("")
+
+	byName := map[string]*graph.Node{}
+
+	lookup := func(name string) (*graph.Node, error) {
+		node, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("store references unknown node %q", name)
+		}
+		return node, nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<30)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		rec := record{}
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("store failed to decode line %d: %w", line, err)
+		}
+
+		err := func() error {
+			switch rec.Op {
+			case OpSnapshot:
+				nodes, err := graph.DecodeJSON(bytes.NewReader(rec.Nodes))
+				if err != nil {
+					return err
+				}
+				inst = graph.New(rec.Name, graph.WithNodes(nodes))
+				if rec.Attributes != nil {
+					inst.Attributes = rec.Attributes
+				}
+				byName = map[string]*graph.Node{}
+				for _, node := range nodes {
+					byName[node.Name] = node
+				}
+			case OpAddNode:
+				node := graph.NewNode(rec.Name, rec.Attributes)
+				byName[rec.Name] = node
+				inst.AddNode(node)
+			case OpRemoveNode:
+				node, err := lookup(rec.Name)
+				if err != nil {
+					return err
+				}
+				delete(byName, rec.Name)
+				inst.RemoveNode(node)
+			case OpAddEdge:
+				from, err := lookup(rec.From)
+				if err != nil {
+					return err
+				}
+				to, err := lookup(rec.To)
+				if err != nil {
+					return err
+				}
+				from.AddEdgeWithDirection(to, rec.Direction)
+
+				// Both edges of the relationship share its name and
+				// attributes, and self-loops add both to the same node.
+				added := graph.Edges{from.Edges[len(from.Edges)-1], to.Edges[len(to.Edges)-1]}
+				if from == to {
+					added[0] = from.Edges[len(from.Edges)-2]
+				}
+				for _, edge := range added {
+					edge.Name, edge.Attributes = rec.Name, rec.Attributes
+				}
+			case OpRemoveEdge:
+				from, err := lookup(rec.From)
+				if err != nil {
+					return err
+				}
+				var removed *graph.Edge
+				for _, edge := range from.Edges {
+					if edge.Node.Name == rec.To && edge.Direction == rec.Direction && edge.Name == rec.Name {
+						removed = edge
+						break
+					}
+				}
+				if removed == nil {
+					return fmt.Errorf("store references unknown edge %s %s %s", rec.From, rec.Direction, rec.To)
+				}
+				from.RemoveEdge(removed)
+			case OpRemoveEdges:
+				from, err := lookup(rec.From)
+				if err != nil {
+					return err
+				}
+				to, err := lookup(rec.To)
+				if err != nil {
+					return err
+				}
+				from.RemoveEdgesTo(to)
+			case OpSetAttribute:
+				node, err := lookup(rec.Name)
+				if err != nil {
+					return err
+				}
+				node.SetAttribute(rec.Key, rec.Value)
+			case OpDeleteAttribute:
+				node, err := lookup(rec.Name)
+				if err != nil {
+					return err
+				}
+				node.DeleteAttribute(rec.Key)
+			default:
+				return fmt.Errorf("unknown operation %q", rec.Op)
+			}
+			return nil
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("store failed to replay line %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("store failed to read %q: %w", s.path, err)
+	}
+
+	return inst, nil
+}
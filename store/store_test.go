@@ -0,0 +1,187 @@
+package store_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/picatz/graph"
+	"github.com/picatz/graph/store"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.jsonl")
+
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	empty, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(empty.Nodes) != 0 {
+		t.Fatalf("expected empty graph, got: %v", empty.Nodes)
+	}
+
+	var (
+		a = graph.NewNode("a", graph.Attributes{"example": true})
+		b = graph.NewNode("b", nil)
+	)
+
+	// a → b
+
+	a.AddEdge(b)
+
+	err = s.Snapshot(graph.New("test", graph.WithNodes(graph.NewNodes(a, b))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range []func() error{
+		func() error { return s.AddNode("c", graph.Attributes{"tier": "db"}) },
+		func() error { return s.AddEdge("b", &graph.Edge{Node: graph.NewNode("c", nil), Direction: graph.Out}) },
+		func() error { return s.SetAttribute("a", "example", false) },
+		func() error { return s.AddNode("d", nil) },
+		func() error { return s.AddEdge("c", &graph.Edge{Node: graph.NewNode("d", nil), Direction: graph.Out}) },
+		func() error { return s.RemoveNode("d") },
+	} {
+		if err := record(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	loaded, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Name != "test" || loaded.Nodes.String() != "a, b, c" {
+		t.Fatalf("unexpected graph: %q %v", loaded.Name, loaded.Nodes)
+	}
+
+	la, lc := loaded.Nodes[0], loaded.Nodes[2]
+
+	if path := la.PathTo(lc); path.String() != "a → b → c" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	if la.Attributes["example"] != false || lc.Attributes["tier"] != "db" {
+		t.Fatalf("unexpected attributes: %v %v", la.Attributes, lc.Attributes)
+	}
+
+	if len(lc.Edges) != 1 {
+		t.Fatalf("expected removed node's edges to be gone: %v", lc.Edges.Nodes())
+	}
+
+	// Compacting the log keeps the same graph.
+	if err := s.Snapshot(loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.Nodes.String() != "a, b, c" || !again.Nodes[0].HasPath(again.Nodes[2]) {
+		t.Fatalf("unexpected graph after compaction: %v", again.Nodes)
+	}
+}
+
+func TestStore_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.jsonl")
+
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	if err := s.Snapshot(g); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+
+	rec := s.Record(g, store.WithLock(&mu))
+
+	mu.Lock()
+
+	// a → b -[calls]→ c    d, then removing a → b and d.
+
+	c := graph.NewNode("c", graph.Attributes{"tier": "db"})
+	d := graph.NewNode("d", nil)
+	g.AddNodes(c, d)
+
+	b.AddEdgeTyped(c, "calls")
+	b.Edges[len(b.Edges)-1].Attributes = graph.Attributes{"port": "5432"}
+	c.AddEdgeWithDirection(d, graph.None)
+	a.SetAttribute("color", "red")
+	a.RemoveEdge(a.Edges[0])
+	g.RemoveNode(d)
+
+	mu.Unlock()
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := graph.Compare(g, loaded); !diff.Empty() {
+		t.Fatalf("expected the replayed graph to match: %+v", diff)
+	}
+
+	edge := loaded.Nodes[1].Edges[0]
+	if edge.Name != "calls" || edge.Attributes["port"] != "5432" {
+		t.Fatalf("expected the edge's name and attributes to be recorded, got: %q %v", edge.Name, edge.Attributes)
+	}
+
+	// A recorder that falls behind takes a new snapshot instead.
+	rec = s.Record(g, store.WithLock(&mu))
+
+	mu.Lock()
+	for i := 0; i < 1000; i++ {
+		g.AddNode(graph.NewNode(fmt.Sprintf("n%d", i), nil))
+	}
+	mu.Unlock()
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err = s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := graph.Compare(g, loaded); !diff.Empty() {
+		t.Fatalf("expected the resynchronized graph to match: %+v", diff)
+	}
+}
@@ -0,0 +1,283 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// MutationKind is the kind of change to a graph described by a Mutation.
+type MutationKind int
+
+const (
+	// NodeAdded is a node added to the graph.
+	NodeAdded MutationKind = iota + 1
+
+	// EdgeAdded is an edge added to a node of the graph.
+	EdgeAdded
+
+	// AttrChanged is an attribute of a node of the graph that was set or
+	// deleted.
+	AttrChanged
+
+	// NodeRemoved is a node removed from the graph, along with all of its
+	// edges.
+	NodeRemoved
+
+	// EdgeRemoved is an edge removed from a node of the graph, along with
+	// the edge on the other side of the relationship.
+	EdgeRemoved
+)
+
+// String returns the name of the mutation kind.
+func (k MutationKind) String() string {
+	switch k {
+	case NodeAdded:
+		return "NodeAdded"
+	case EdgeAdded:
+		return "EdgeAdded"
+	case AttrChanged:
+		return "AttrChanged"
+	case NodeRemoved:
+		return "NodeRemoved"
+	case EdgeRemoved:
+		return "EdgeRemoved"
+	default:
+		return fmt.Sprintf("MutationKind(%d)", int(k))
+	}
+}
+
+// Mutation is a change made to a graph, delivered to its subscribers, see
+// Instance.Subscribe.
+type Mutation struct {
+	// Kind is the kind of change.
+	Kind MutationKind
+
+	// Node is the node that was added or removed, the node the edge was
+	// added to or removed from, or the node whose attribute changed.
+	Node *Node
+
+	// Edge is the edge that was added or removed, for EdgeAdded and
+	// EdgeRemoved mutations. Each relationship is reported once, with the
+	// edge on the side of Node.
+	Edge *Edge
+
+	// Attribute is the name of the attribute that changed, and Value its
+	// new value, which is nil if it was deleted, for AttrChanged mutations.
+	Attribute string
+	Value     any
+
+	// Generation is the generation of the graph after the change, see
+	// Instance.Generation.
+	Generation uint64
+
+	// Dropped is the number of mutations that weren't delivered to the
+	// subscriber before this one, because it didn't keep up with them.
+	Dropped int
+}
+
+// String returns a human-readable description of the mutation.
+//
+//	NodeAdded a, EdgeAdded a → b, AttrChanged a.color = red
+func (m Mutation) String() string {
+	switch m.Kind {
+	case NodeAdded, NodeRemoved:
+		return fmt.Sprintf("%s %s", m.Kind, nodeName(m.Node))
+	case EdgeAdded, EdgeRemoved:
+		return fmt.Sprintf("%s %s", m.Kind, m.Edge)
+	case AttrChanged:
+		return fmt.Sprintf("%s %s.%s = %v", m.Kind, nodeName(m.Node), m.Attribute, m.Value)
+	default:
+		return m.Kind.String()
+	}
+}
+
+// subscriptionBuffer is the number of mutations buffered for a subscriber
+// before mutations are dropped.
+const subscriptionBuffer = 256
+
+// subscription is a subscriber of a graph's mutations.
+type subscription struct {
+	ch      chan Mutation
+	dropped int
+}
+
+// subscribers are the subscriptions of a graph, which can be cancelled from
+// other goroutines than the one changing the graph.
+type subscribers struct {
+	mu   sync.Mutex
+	subs []*subscription
+}
+
+// Subscribe returns a channel that receives the changes made to the graph
+// using its methods, or those of its nodes, like AddNode, AddEdge,
+// SetAttribute, RemoveNode, and RemoveEdge, as they are made, along with a
+// function to cancel the subscription, which closes the channel.
+//
+// Changes are reported as they would be made one at a time, so subscribers
+// can mirror the graph: methods that change many nodes and edges at once,
+// like Prune, MergeNodes, and Reverse, report each node and edge they add
+// or remove, and Checkout reports the removal of every node, followed by
+// the nodes and edges of the version it restores. Edges are reported by
+// reference, so an edge that changed, like a reversed one, is reported as
+// removed and added again.
+//
+//	mutations, cancel := g.Subscribe()
+//	defer cancel()
+//
+//	go func() {
+//		for m := range mutations {
+//			cache.Invalidate(m.Node.Name)
+//		}
+//	}()
+//
+// Changing the graph never waits for subscribers: each subscriber has a
+// buffer of mutations, and mutations that don't fit are dropped, which is
+// reported by the Dropped field of the next mutation delivered, so slow
+// consumers can resynchronize with the graph. Like Generation, changes
+// made directly to fields can't be detected.
+func (inst *Instance) Subscribe() (<-chan Mutation, func()) {
+	sub := &subscription{ch: make(chan Mutation, subscriptionBuffer)}
+
+	inst.subscribers.mu.Lock()
+	inst.subscribers.subs = append(inst.subscribers.subs, sub)
+	inst.subscribers.mu.Unlock()
+
+	var once sync.Once
+
+	cancel := func() {
+		once.Do(func() {
+			inst.subscribers.mu.Lock()
+			defer inst.subscribers.mu.Unlock()
+
+			for i, s := range inst.subscribers.subs {
+				if s == sub {
+					inst.subscribers.subs = append(inst.subscribers.subs[:i:i], inst.subscribers.subs[i+1:]...)
+					break
+				}
+			}
+			close(sub.ch)
+		})
+	}
+
+	return sub.ch, cancel
+}
+
+// publish delivers the given mutation to the graph's subscribers, dropping
+// it for those whose buffer is full.
+func (inst *Instance) publish(m Mutation) {
+	inst.subscribers.mu.Lock()
+	defer inst.subscribers.mu.Unlock()
+
+	if len(inst.subscribers.subs) == 0 {
+		return
+	}
+
+	m.Generation = inst.generation
+
+	for _, sub := range inst.subscribers.subs {
+		delivered := m
+		delivered.Dropped = sub.dropped
+
+		select {
+		case sub.ch <- delivered:
+			sub.dropped = 0
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// publish delivers the given mutation to the subscribers of the graph the
+// node belongs to, if any.
+func (n *Node) publish(m Mutation) {
+	if n != nil && n.graph != nil {
+		n.graph.publish(m)
+	}
+}
+
+// publishEdge delivers the edge that was just added from the node to the
+// given node to the subscribers of their graphs, which is the last edge of
+// each of them, or the one before last of the node for self-loops.
+func (n *Node) publishEdge(e *Node) {
+	edge := n.Edges[len(n.Edges)-1]
+	if n == e {
+		edge = n.Edges[len(n.Edges)-2]
+	}
+
+	n.publish(Mutation{Kind: EdgeAdded, Node: n, Edge: edge})
+
+	// Nodes of other graphs are reported to their own subscribers.
+	if e.graph != n.graph {
+		e.publish(Mutation{Kind: EdgeAdded, Node: e, Edge: e.Edges[len(e.Edges)-1]})
+	}
+}
+
+// publishEdgeRemoved delivers the given edge that was just removed from the
+// node, along with the given edge on the other side of the relationship,
+// if any, to the subscribers of their graphs.
+func (n *Node) publishEdgeRemoved(edge, other *Edge) {
+	n.publish(Mutation{Kind: EdgeRemoved, Node: n, Edge: edge})
+
+	// Nodes of other graphs are reported to their own subscribers.
+	if e := edge.Node; other != nil && e.graph != n.graph {
+		e.publish(Mutation{Kind: EdgeRemoved, Node: e, Edge: other})
+	}
+}
+
+// subscribed checks if the graph has subscribers, to skip the work of
+// reporting changes that are made in bulk when there are none.
+func (inst *Instance) subscribed() bool {
+	inst.subscribers.mu.Lock()
+	defer inst.subscribers.mu.Unlock()
+
+	return len(inst.subscribers.subs) > 0
+}
+
+// publishEdges delivers an EdgeAdded mutation, preceded by an EdgeRemoved
+// mutation if removed is true, for each relationship of the given nodes
+// the given function returns true for, if the graph has subscribers.
+func (inst *Instance) publishEdges(nodes Nodes, removed bool, fn func(edge *Edge) bool) {
+	if !inst.subscribed() {
+		return
+	}
+
+	eachUniqueEdge(nodes, func(from *Node, edge *Edge) {
+		if !fn(edge) {
+			return
+		}
+		if removed {
+			inst.publish(Mutation{Kind: EdgeRemoved, Node: from, Edge: edge})
+		}
+		inst.publish(Mutation{Kind: EdgeAdded, Node: from, Edge: edge})
+	})
+}
+
+// publishAttributeChanges delivers an AttrChanged mutation for each
+// attribute of the node that differs from the given attributes it had
+// before, to the subscribers of its graph.
+func (n *Node) publishAttributeChanges(before Attributes) {
+	if n.graph == nil || !n.graph.subscribed() {
+		return
+	}
+
+	names := make([]string, 0, len(n.Attributes)+len(before))
+	for name := range n.Attributes {
+		names = append(names, name)
+	}
+	for name := range before {
+		if _, ok := n.Attributes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, ok := n.Attributes[name]
+		if old, existed := before[name]; ok && existed && reflect.DeepEqual(old, value) {
+			continue
+		}
+		n.publish(Mutation{Kind: AttrChanged, Node: n, Attribute: name, Value: value})
+	}
+}
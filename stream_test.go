@@ -0,0 +1,152 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Subscribe(t *testing.T) {
+	g := graph.New("test")
+
+	mutations, cancel := g.Subscribe()
+
+	a := graph.NewNode("a", nil)
+	b := graph.NewNode("b", nil)
+
+	g.AddNodes(a, b)
+	a.AddEdge(b)
+	a.SetAttribute("color", "red")
+	a.DeleteAttribute("color")
+
+	expected := []string{
+		"NodeAdded a",
+		"NodeAdded b",
+		"EdgeAdded a → b",
+		"AttrChanged a.color = red",
+		"AttrChanged a.color = <nil>",
+	}
+
+	for _, want := range expected {
+		m := <-mutations
+		if got := m.String(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		if m.Dropped != 0 {
+			t.Fatalf("expected no dropped mutations, got %d", m.Dropped)
+		}
+	}
+
+	cancel()
+	cancel()
+
+	if _, ok := <-mutations; ok {
+		t.Fatal("expected the channel to be closed")
+	}
+
+	// Changes after cancelling aren't delivered, and don't block.
+	a.AddEdge(a)
+}
+
+func TestInstance_Subscribe_backpressure(t *testing.T) {
+	g := graph.New("test")
+
+	mutations, cancel := g.Subscribe()
+	defer cancel()
+
+	// Nobody reads the mutations while the graph changes, so changes
+	// that don't fit in the buffer are dropped instead of blocking.
+	const n = 1000
+	for i := 0; i < n; i++ {
+		g.AddNode(graph.NewNode("n", nil))
+	}
+
+	var received int
+	for len(mutations) > 0 {
+		<-mutations
+		received++
+	}
+
+	if received == 0 || received == n {
+		t.Fatalf("expected some mutations to be dropped, received %d", received)
+	}
+
+	g.AddNode(graph.NewNode("last", nil))
+
+	m := <-mutations
+	if m.Node.Name != "last" || m.Dropped != n-received {
+		t.Fatalf("expected %d dropped mutations reported, got %d", n-received, m.Dropped)
+	}
+
+	if m.Generation != g.Generation() {
+		t.Fatalf("expected generation %d, got %d", g.Generation(), m.Generation)
+	}
+}
+
+func TestInstance_Subscribe_removals(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a → b → c → d
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	c.AddEdge(d)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	v := g.Commit("initial")
+
+	mutations, cancel := g.Subscribe()
+	defer cancel()
+
+	// Mutations are checked after each change, since edges are reported by
+	// reference, and change with the graph.
+	expect := func(expected ...string) {
+		t.Helper()
+		for _, want := range expected {
+			if got := (<-mutations).String(); got != want {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		}
+		if len(mutations) != 0 {
+			t.Fatalf("unexpected mutation: %v", <-mutations)
+		}
+	}
+
+	a.RemoveEdge(a.Edges[0])
+	expect("EdgeRemoved a → b")
+
+	g.RemoveNode(d)
+	expect("NodeRemoved d")
+
+	g.Reverse()
+	expect("EdgeRemoved c → b", "EdgeAdded c → b")
+
+	c.SetAttribute("color", "red")
+	expect("AttrChanged c.color = red")
+
+	if err := g.MergeNodes(a, c, nil); err != nil {
+		t.Fatal(err)
+	}
+	expect("EdgeAdded a → b", "AttrChanged a.color = red", "NodeRemoved c")
+
+	a.RemoveEdgesTo(b)
+	expect("EdgeRemoved a → b")
+
+	g.Prune(func(n *graph.Node) bool { return n == b })
+	expect("NodeRemoved b")
+
+	if err := g.Checkout(v); err != nil {
+		t.Fatal(err)
+	}
+	expect(
+		"NodeRemoved a",
+		"NodeAdded a", "NodeAdded b", "NodeAdded c", "NodeAdded d",
+		"EdgeAdded a → b", "EdgeAdded b → c", "EdgeAdded c → d",
+	)
+}
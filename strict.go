@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNilNode is returned by the error-returning mutation methods, like
+	// TryAddNode, when given a nil node.
+	ErrNilNode = errors.New("graph node is nil")
+
+	// ErrNodeNotFound is returned when a node isn't in the graph.
+	ErrNodeNotFound = errors.New("graph node not found")
+
+	// ErrDuplicateNode is returned when adding a node that is already in
+	// the graph.
+	ErrDuplicateNode = errors.New("graph node already exists")
+
+	// ErrDuplicateEdge is returned when adding an edge that already exists,
+	// with the same nodes, direction, and name.
+	ErrDuplicateEdge = errors.New("graph edge already exists")
+
+	// ErrEdgeNotFound is returned when an edge isn't in the graph.
+	ErrEdgeNotFound = errors.New("graph edge not found")
+)
+
+// TryAddNode adds a node to the graph like AddNode, but returns an error
+// instead of quietly ignoring, or adding, problematic nodes:
+//
+//   - ErrNilNode if the node is nil.
+//   - ErrDuplicateNode if the node is already in the graph.
+//   - ErrDuplicateName if the graph was created using WithUniqueNames and
+//     already has another node with the same name, instead of merging it.
+func (inst *Instance) TryAddNode(node *Node) error {
+	if err := inst.checkNewNodes(node); err != nil {
+		return err
+	}
+
+	inst.AddNode(node)
+	return nil
+}
+
+// TryAddNodes adds the given nodes to the graph like AddNodes, checking
+// them like TryAddNode. If any node can't be added, none are.
+func (inst *Instance) TryAddNodes(nodes ...*Node) error {
+	if err := inst.checkNewNodes(nodes...); err != nil {
+		return err
+	}
+
+	inst.AddNodes(nodes...)
+	return nil
+}
+
+// TryRemoveNode removes a node from the graph like RemoveNode, but returns
+// ErrNilNode or ErrNodeNotFound if the node is nil or not in the graph.
+func (inst *Instance) TryRemoveNode(node *Node) error {
+	if err := inst.checkNodes(node); err != nil {
+		return err
+	}
+
+	inst.RemoveNode(node)
+	return nil
+}
+
+// TryAddEdge adds a directed edge from the source node to the target node
+// like AddEdge, but returns ErrNilNode or ErrNodeNotFound if either node is
+// nil or not in the graph, ErrDuplicateEdge if the edge already exists, and
+// a Violation if it would violate one of the graph's constraints, see
+// EdgeConstraint.
+func (inst *Instance) TryAddEdge(from, to *Node) error {
+	return inst.TryAddEdgeWithDirection(from, to, Out)
+}
+
+// TryAddEdgeTyped adds a directed edge of the given relationship type like
+// AddEdgeTyped, checking it like TryAddEdge.
+func (inst *Instance) TryAddEdgeTyped(from, to *Node, typ string) error {
+	if err := inst.checkNewEdge(from, to, Out, typ); err != nil {
+		return err
+	}
+
+	from.AddEdgeTyped(to, typ)
+	return nil
+}
+
+// TryAddEdgeWithDirection adds an edge with the given direction like
+// Node.AddEdgeWithDirection, checking it like TryAddEdge.
+func (inst *Instance) TryAddEdgeWithDirection(from, to *Node, direction EdgeDirection) error {
+	if err := inst.checkNewEdge(from, to, direction, ""); err != nil {
+		return err
+	}
+
+	from.AddEdgeWithDirection(to, direction)
+	return nil
+}
+
+// TryRemoveEdge removes the given edge of a node in the graph, along with
+// the corresponding edge on the other side of the relationship, like
+// Node.RemoveEdge. ErrNilNode or ErrNodeNotFound is returned if the node
+// is nil or not in the graph, and ErrEdgeNotFound if the edge isn't one
+// of the node's edges.
+func (inst *Instance) TryRemoveEdge(from *Node, edge *Edge) error {
+	if err := inst.checkNodes(from); err != nil {
+		return err
+	}
+
+	for _, e := range from.Edges {
+		if e == edge && edge != nil {
+			from.RemoveEdge(edge)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: from %q", ErrEdgeNotFound, from.Name)
+}
+
+// has checks if the node belongs to the graph.
+func (inst *Instance) has(node *Node) bool {
+	return node.graph == inst || inst.Nodes.Contains(node)
+}
+
+// checkNodes checks that the given nodes are in the graph.
+func (inst *Instance) checkNodes(nodes ...*Node) error {
+	for _, node := range nodes {
+		if node == nil {
+			return ErrNilNode
+		}
+		if !inst.has(node) {
+			return fmt.Errorf("%w: %q", ErrNodeNotFound, node.Name)
+		}
+	}
+	return nil
+}
+
+// checkNewNodes checks that the given nodes can be added to the graph.
+func (inst *Instance) checkNewNodes(nodes ...*Node) error {
+	added := NodeSet{}
+	names := map[string]struct{}{}
+
+	for _, node := range nodes {
+		if node == nil {
+			return ErrNilNode
+		}
+
+		if added.Contains(node) || inst.has(node) {
+			return fmt.Errorf("%w: %q", ErrDuplicateNode, node.Name)
+		}
+		added.Add(node)
+
+		if inst.names == nil {
+			continue
+		}
+
+		_, taken := inst.names[node.Name]
+		if _, batched := names[node.Name]; taken || batched {
+			return fmt.Errorf("%w: %q", ErrDuplicateName, node.Name)
+		}
+		names[node.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkNewEdge checks that the edge can be added between nodes in the graph.
+func (inst *Instance) checkNewEdge(from, to *Node, direction EdgeDirection, name string) error {
+	if err := inst.checkNodes(from, to); err != nil {
+		return err
+	}
+
+	if from.Edges.find(to, direction, name) != nil {
+		return fmt.Errorf("%w: %s %s %s", ErrDuplicateEdge, from.Name, direction, to.Name)
+	}
+
+	return inst.checkEdgeConstraints(from, to, direction)
+}
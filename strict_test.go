@@ -0,0 +1,92 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_TryAdd(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	g := graph.New("test")
+
+	if err := g.TryAddNode(nil); !errors.Is(err, graph.ErrNilNode) {
+		t.Fatalf("expected nil node error, got: %v", err)
+	}
+
+	if err := g.TryAddNodes(a, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.TryAddNode(a); !errors.Is(err, graph.ErrDuplicateNode) {
+		t.Fatalf("expected duplicate node error, got: %v", err)
+	}
+
+	// Nothing is added if any of the nodes can't be.
+	if err := g.TryAddNodes(c, c); !errors.Is(err, graph.ErrDuplicateNode) {
+		t.Fatalf("expected duplicate node error, got: %v", err)
+	}
+
+	if len(g.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got: %s", g.Nodes)
+	}
+
+	if err := g.TryAddEdge(a, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.TryAddEdge(a, b); !errors.Is(err, graph.ErrDuplicateEdge) {
+		t.Fatalf("expected duplicate edge error, got: %v", err)
+	}
+
+	// A different type or direction is a different edge.
+	if err := g.TryAddEdgeTyped(a, b, "calls"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.TryAddEdgeWithDirection(a, b, graph.None); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.TryAddEdge(a, c); !errors.Is(err, graph.ErrNodeNotFound) {
+		t.Fatalf("expected node not found error, got: %v", err)
+	}
+
+	if err := g.TryAddEdge(nil, a); !errors.Is(err, graph.ErrNilNode) {
+		t.Fatalf("expected nil node error, got: %v", err)
+	}
+
+	if len(a.Edges) != 3 || len(c.Edges) != 0 {
+		t.Fatalf("unexpected edges: %s", a.Edges)
+	}
+
+	if err := g.TryRemoveEdge(a, &graph.Edge{Node: b}); !errors.Is(err, graph.ErrEdgeNotFound) {
+		t.Fatalf("expected edge not found error, got: %v", err)
+	}
+
+	if err := g.TryRemoveEdge(a, a.Edges[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a.Edges) != 2 || len(b.Edges) != 2 {
+		t.Fatalf("expected both sides of the edge to be removed, got: %s", a.Edges)
+	}
+
+	if err := g.TryRemoveNode(c); !errors.Is(err, graph.ErrNodeNotFound) {
+		t.Fatalf("expected node not found error, got: %v", err)
+	}
+
+	if err := g.TryRemoveNode(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.TryRemoveNode(b); !errors.Is(err, graph.ErrNodeNotFound) {
+		t.Fatalf("expected node not found error, got: %v", err)
+	}
+}
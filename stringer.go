@@ -0,0 +1,59 @@
+package graph
+
+// StringerConfig is the set of glyphs used to render edge directions, and
+// the paths, edges, and reports that include them, as strings.
+type StringerConfig struct {
+	Unknown string // Glyph for the Unknown direction.
+	None    string // Glyph for the None direction.
+	In      string // Glyph for the In direction.
+	Out     string // Glyph for the Out direction.
+	Both    string // Glyph for the Both direction.
+}
+
+var (
+	// UnicodeStringer renders directions with Unicode arrows, like a → b.
+	UnicodeStringer = StringerConfig{
+		Unknown: "┄",
+		None:    "-",
+		In:      "←",
+		Out:     "→",
+		Both:    "↔",
+	}
+
+	// ASCIIStringer renders directions with ASCII arrows, like a -> b, for
+	// logs and consoles without Unicode support.
+	ASCIIStringer = StringerConfig{
+		Unknown: "~",
+		None:    "-",
+		In:      "<-",
+		Out:     "->",
+		Both:    "<->",
+	}
+)
+
+// Stringer is the configuration used by the String methods of the package,
+// like EdgeDirection.String and Path.String, which is UnicodeStringer by
+// default. It is meant to be set once, before any graph is rendered:
+//
+//	func init() {
+//		graph.Stringer = graph.ASCIIStringer
+//	}
+//
+// Path.Format can be used instead to render a single path differently.
+var Stringer = UnicodeStringer
+
+// glyph returns the glyph of the given direction.
+func (c StringerConfig) glyph(d EdgeDirection) string {
+	switch d {
+	case None:
+		return c.None
+	case In:
+		return c.In
+	case Out:
+		return c.Out
+	case Both:
+		return c.Both
+	default: // Unknown and anything else.
+		return c.Unknown
+	}
+}
@@ -0,0 +1,63 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestPath_Format(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	path := graph.Path{a, b, c}
+
+	if got := path.String(); got != "a → b → c" {
+		t.Fatalf("unexpected path string %q", got)
+	}
+
+	if got := path.Format(" -> "); got != "a -> b -> c" {
+		t.Fatalf("unexpected formatted path %q", got)
+	}
+
+	if got := (graph.Path{}).Format(", "); got != "" {
+		t.Fatalf("unexpected formatted empty path %q", got)
+	}
+}
+
+func TestStringer(t *testing.T) {
+	graph.Stringer = graph.ASCIIStringer
+	t.Cleanup(func() {
+		graph.Stringer = graph.UnicodeStringer
+	})
+
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdgeWithDirection(b, graph.Out)
+	b.AddEdgeWithDirection(c, graph.Both)
+
+	if got := (graph.Path{a, b, c}).String(); got != "a -> b -> c" {
+		t.Fatalf("unexpected path string %q", got)
+	}
+
+	for edge, want := range map[*graph.Edge]string{
+		a.Edges[0]: "a -> b",
+		b.Edges[0]: "b <- a",
+		b.Edges[1]: "b <-> c",
+	} {
+		if got := edge.String(); got != want {
+			t.Fatalf("expected edge %q, got %q", want, got)
+		}
+	}
+
+	if got := graph.Unknown.String(); got != "~" {
+		t.Fatalf("unexpected unknown direction %q", got)
+	}
+}
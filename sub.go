@@ -0,0 +1,169 @@
+package graph
+
+// Sub is a named subgraph of a graph instance, grouping some of its nodes,
+// like the teams or domains that own the services of an architecture graph.
+// Nodes can be members of any number of subgraphs.
+//
+//	┌ payments ─┐   ┌ identity ┐
+//	│ api → db ─┼───┼→ users   │
+//	└───────────┘   └──────────┘
+//
+// Subgraphs are created using Instance.AddSub.
+type Sub struct {
+	// Name is the name of the subgraph, unique within its graph.
+	Name string
+
+	// Attributes is a map of key-value pairs that describe the subgraph.
+	Attributes
+
+	graph   *Instance
+	nodes   Nodes
+	members NodeSet
+}
+
+// AddSub returns the subgraph of the graph with the given name, creating
+// it if it doesn't exist yet.
+func (inst *Instance) AddSub(name string) *Sub {
+	if sub := inst.Sub(name); sub != nil {
+		return sub
+	}
+
+	sub := &Sub{
+		Name:       name,
+		Attributes: Attributes{},
+		graph:      inst,
+		members:    NodeSet{},
+	}
+
+	inst.subs = append(inst.subs, sub)
+
+	return sub
+}
+
+// Sub returns the subgraph of the graph with the given name, or nil if
+// there is none.
+func (inst *Instance) Sub(name string) *Sub {
+	for _, sub := range inst.subs {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Subs returns the subgraphs of the graph, in the order they were created.
+func (inst *Instance) Subs() []*Sub {
+	return append([]*Sub(nil), inst.subs...)
+}
+
+// RemoveSub removes the subgraph with the given name from the graph,
+// leaving its nodes in the graph.
+func (inst *Instance) RemoveSub(name string) {
+	subs := inst.subs[:0]
+	for _, sub := range inst.subs {
+		if sub.Name != name {
+			subs = append(subs, sub)
+		}
+	}
+	inst.subs = subs
+}
+
+// AddNode adds the given nodes to the subgraph, and to its graph if they
+// don't belong to it yet.
+func (sub *Sub) AddNode(nodes ...*Node) {
+	for _, node := range nodes {
+		if node == nil || sub.members.Contains(node) {
+			continue
+		}
+
+		if node.graph != sub.graph {
+			sub.graph.AddNode(node)
+
+			// Graphs with unique names merge the node into the node
+			// with the same name, which is added instead.
+			if node.graph != sub.graph {
+				node, _ = sub.graph.Lookup(node.Name)
+				if node == nil || sub.members.Contains(node) {
+					continue
+				}
+			}
+		}
+
+		sub.members.Add(node)
+		sub.nodes = append(sub.nodes, node)
+	}
+}
+
+// RemoveNode removes the given node from the subgraph, leaving it in the
+// graph.
+func (sub *Sub) RemoveNode(node *Node) {
+	if !sub.members.Contains(node) {
+		return
+	}
+
+	delete(sub.members, node)
+
+	nodes := sub.nodes[:0]
+	for _, n := range sub.nodes {
+		if n != node {
+			nodes = append(nodes, n)
+		}
+	}
+	sub.nodes = nodes
+}
+
+// Nodes returns the nodes of the subgraph, in the order they were added.
+func (sub *Sub) Nodes() Nodes {
+	return append(Nodes{}, sub.nodes...)
+}
+
+// Contains checks if the given node is a member of the subgraph.
+func (sub *Sub) Contains(node *Node) bool {
+	return sub.members.Contains(node)
+}
+
+// Edges returns the unique edges between the nodes of the subgraph,
+// see Instance.Edges.
+func (sub *Sub) Edges() Edges {
+	return sub.graph.EdgesBetween(sub, sub)
+}
+
+// MemberOf returns the subgraphs of the node's graph the node is a member
+// of, in the order they were created.
+func (n *Node) MemberOf() []*Sub {
+	if n.graph == nil {
+		return nil
+	}
+
+	var subs []*Sub
+	for _, sub := range n.graph.subs {
+		if sub.Contains(n) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// EdgesBetween returns the unique edges of the graph between a node of one
+// of the given subgraphs and a node of the other, in either direction,
+// like the dependencies between two teams' services. If both subgraphs are
+// the same, the edges within it are returned. See Instance.Edges.
+//
+//	┌ a ─────┐   ┌ b ───┐
+//	│ x → y ─┼───┼→ z   │    EdgesBetween(a, b): y → z
+//	└────────┘   └──────┘
+func (inst *Instance) EdgesBetween(a, b *Sub) Edges {
+	edges := Edges{}
+
+	if a == nil || b == nil {
+		return edges
+	}
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		if (a.Contains(from) && b.Contains(edge.Node)) || (b.Contains(from) && a.Contains(edge.Node)) {
+			edges = append(edges, edge)
+		}
+	})
+
+	return edges
+}
@@ -0,0 +1,88 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Subs(t *testing.T) {
+	var (
+		api   = graph.NewNode("api", nil)
+		db    = graph.NewNode("db", nil)
+		users = graph.NewNode("users", nil)
+		audit = graph.NewNode("audit", nil)
+	)
+
+	// ┌ payments ─┐   ┌ identity ┐
+	// │ api → db ─┼───┼→ users   │
+	// └─┼─────────┘   └─┼────────┘
+	//   └───→ audit ←───┘
+
+	api.AddEdge(db)
+	db.AddEdge(users)
+	api.AddEdge(audit)
+	users.AddEdge(audit)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(api, db, users)))
+
+	payments := g.AddSub("payments")
+	payments.AddNode(api, db)
+
+	identity := g.AddSub("identity")
+	identity.AddNode(users)
+
+	// Nodes added to a subgraph are added to the graph.
+	g.AddSub("compliance").AddNode(audit, users)
+
+	if g.AddSub("payments") != payments {
+		t.Fatal("expected AddSub to return the existing subgraph")
+	}
+
+	if len(g.Nodes) != 4 {
+		t.Fatalf("expected audit to be added to the graph, got %v", g.Nodes)
+	}
+
+	var names []string
+	for _, sub := range users.MemberOf() {
+		names = append(names, sub.Name)
+	}
+
+	if len(names) != 2 || names[0] != "identity" || names[1] != "compliance" {
+		t.Fatalf("unexpected subgraphs for users: %v", names)
+	}
+
+	if edges := g.EdgesBetween(payments, identity); edges.String() != "db → users" {
+		t.Fatalf("unexpected edges between payments and identity: %v", edges)
+	}
+
+	if edges := g.EdgesBetween(identity, payments); edges.String() != "db → users" {
+		t.Fatalf("unexpected edges between identity and payments: %v", edges)
+	}
+
+	if edges := payments.Edges(); edges.String() != "api → db" {
+		t.Fatalf("unexpected edges within payments: %v", edges)
+	}
+
+	clone := g.Clone()
+
+	if subs := clone.Subs(); len(subs) != 3 || subs[0].Nodes().String() != "api, db" || subs[0].Contains(api) {
+		t.Fatalf("expected the clone to have copies of the subgraphs: %v", subs)
+	}
+
+	g.RemoveNode(db)
+
+	if payments.Nodes().String() != "api" {
+		t.Fatalf("expected removed node to leave its subgraphs, got %v", payments.Nodes())
+	}
+
+	g.RemoveSub("compliance")
+
+	if subs := audit.MemberOf(); len(subs) != 0 {
+		t.Fatalf("expected audit to no longer be a member of any subgraph, got %v", subs)
+	}
+
+	if len(g.Subs()) != 2 {
+		t.Fatalf("expected 2 subgraphs, got %d", len(g.Subs()))
+	}
+}
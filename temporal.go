@@ -0,0 +1,96 @@
+package graph
+
+import "time"
+
+// Attribute names used to store the validity window of temporal edges.
+const (
+	ValidFromAttribute  = "valid_from"
+	ValidUntilAttribute = "valid_until"
+)
+
+// AddEdgeAt adds a directed relationship to a Node which is only valid from
+// the start time until (but not including) the end time. A zero start or end
+// time leaves that side of the window open.
+//
+//	n → e : [start, end)
+//
+// The window is stored using the ValidFromAttribute and ValidUntilAttribute
+// attributes, shared by the edges on both sides of the relationship.
+func (n *Node) AddEdgeAt(e *Node, start, end time.Time) {
+	n.touch()
+	e.touch()
+
+	attrs := Attributes{}
+	if !start.IsZero() {
+		attrs[ValidFromAttribute] = start
+	}
+	if !end.IsZero() {
+		attrs[ValidUntilAttribute] = end
+	}
+
+	n.Edges = append(n.Edges, &Edge{Node: e, Direction: Out, Attributes: attrs, from: n})
+	e.Edges = append(e.Edges, &Edge{Node: n, Direction: In, Attributes: attrs, from: e})
+
+	n.publishEdge(e)
+}
+
+// AddEdgeAt adds an edge to the graph from the source node to the target node
+// which is only valid within the given time window, see Node.AddEdgeAt.
+func (inst *Instance) AddEdgeAt(from, to *Node, start, end time.Time) {
+	if from == nil || to == nil {
+		return
+	}
+
+	from.AddEdgeAt(to, start, end)
+}
+
+// ValidAt checks if the edge is valid at the given time. Edges without a
+// validity window are always valid.
+//
+// The bounds of the window can also be strings in the RFC 3339 format, which
+// is how times are encoded by EncodeJSON and the other encodings, so edges
+// keep their window when decoded, unless time.Time is registered using
+// RegisterAttributeType.
+func (e *Edge) ValidAt(t time.Time) bool {
+	if start, ok := timeAttribute(e.Attributes[ValidFromAttribute]); ok && t.Before(start) {
+		return false
+	}
+	if end, ok := timeAttribute(e.Attributes[ValidUntilAttribute]); ok && !t.Before(end) {
+		return false
+	}
+	return true
+}
+
+// timeAttribute returns the time of the given attribute value, which is
+// either a time.Time or a string in the RFC 3339 format.
+func timeAttribute(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// AsOf restricts the following steps of the traversal to edges that are
+// valid at the given time.
+//
+//	graph.Traverse(start).AsOf(t).Out().Nodes()
+func (t *Traversal) AsOf(at time.Time) *Traversal {
+	return &Traversal{nodes: append(Nodes{}, t.nodes...), asOf: at}
+}
+
+// AsOf is a functional option that makes traversals, like Node.Visit,
+// Node.PathTo, Instance.DFS, and Instance.BFS, and the other algorithms
+// accepting traversal options, only follow edges that are valid at the
+// given time, like Traversal.AsOf.
+//
+//	app.PathTo(db, graph.AsOf(migrated))
+func AsOf(at time.Time) func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.AsOf = at
+	}
+}
@@ -0,0 +1,91 @@
+package graph_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/picatz/graph"
+)
+
+func TestTraversal_AsOf(t *testing.T) {
+	var (
+		app = graph.NewNode("app", nil)
+		v1  = graph.NewNode("db-v1", nil)
+		v2  = graph.NewNode("db-v2", nil)
+		log = graph.NewNode("log", nil)
+
+		migrated = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(app, v1, v2, log)))
+
+	g.AddEdgeAt(app, v1, time.Time{}, migrated)
+	g.AddEdgeAt(app, v2, migrated, time.Time{})
+	g.AddEdge(app, log)
+
+	before := graph.Traverse(app).AsOf(migrated.Add(-time.Hour)).Out().Nodes()
+	if before.String() != "db-v1, log" {
+		t.Fatalf("unexpected dependencies before migration: %v", before)
+	}
+
+	after := graph.Traverse(app).AsOf(migrated).Out().Nodes()
+	if after.String() != "db-v2, log" {
+		t.Fatalf("unexpected dependencies after migration: %v", after)
+	}
+
+	dependents := graph.Traverse(v1).AsOf(migrated).In().Nodes()
+	if len(dependents) != 0 {
+		t.Fatalf("did not expect db-v1 to have dependents after migration: %v", dependents)
+	}
+
+	if all := graph.Traverse(app).Out().Nodes(); len(all) != 3 {
+		t.Fatalf("expected all edges without AsOf, got: %v", all)
+	}
+}
+
+func TestAsOf(t *testing.T) {
+	var (
+		app = graph.NewNode("app", nil)
+		v1  = graph.NewNode("db-v1", nil)
+		v2  = graph.NewNode("db-v2", nil)
+
+		migrated = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(app, v1, v2)))
+
+	g.AddEdgeAt(app, v1, time.Time{}, migrated)
+	g.AddEdgeAt(app, v2, migrated, time.Time{})
+
+	if path := app.PathTo(v1, graph.AsOf(migrated)); path != nil {
+		t.Fatalf("did not expect a path to db-v1 after migration, got %v", path)
+	}
+
+	var visited graph.Nodes
+	app.Visit(func(n *graph.Node) {
+		visited = append(visited, n)
+	}, graph.AsOf(migrated.Add(-time.Hour)))
+	if visited.String() != "app, db-v1" {
+		t.Fatalf("unexpected nodes visited before migration: %v", visited)
+	}
+
+	// Times are strings once the graph is encoded and decoded.
+	var buf bytes.Buffer
+	if err := graph.EncodeJSON(&buf, g.Nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := graph.DecodeJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found graph.Nodes
+	decoded[0].Visit(func(n *graph.Node) {
+		found = append(found, n)
+	}, graph.AsOf(migrated))
+	if found.String() != "app, db-v2" {
+		t.Fatalf("unexpected nodes found after migration in the decoded graph: %v", found)
+	}
+}
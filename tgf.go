@@ -0,0 +1,216 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EncodeTGF encodes the given nodes and their edges using the Trivial Graph
+// Format: a line for each node, with its number and name, a line with "#",
+// and a line for each edge, with the numbers of its nodes and its name, if
+// it has one.
+//
+//	1 a
+//	2 b
+//	3 c
+//	#
+//	1 2
+//	2 3 depends_on
+//
+// TGF only has directed edges, so each undirected (None) and bi-directional
+// (Both) relationship is written once, from its first node, like Edges, and
+// attributes and labels are not encoded. Edges to nodes outside of the given
+// nodes are omitted. Only the Canonical encoding option is used.
+//
+// https://en.wikipedia.org/wiki/Trivial_Graph_Format
+func EncodeTGF(w io.Writer, nodes Nodes, opts ...func(*EncodeOptions)) error {
+	options := newEncodeOptions(opts...)
+
+	if options.Canonical {
+		nodes = canonicalOrder(nodes)
+	}
+
+	index := make(map[*Node]int, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		index[nodes[i]] = i + 1
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for i, node := range nodes {
+		fmt.Fprintf(bw, "%d %s\n", i+1, tgfLabel(node.Name))
+	}
+
+	bw.WriteString("#\n")
+
+	eachUniqueEdge(nodes, func(from *Node, edge *Edge) {
+		to, ok := index[edge.Node]
+		if !ok {
+			return
+		}
+		a, b := index[from], to
+		if edge.Direction == In {
+			a, b = b, a
+		}
+		if edge.Name != "" {
+			fmt.Fprintf(bw, "%d %d %s\n", a, b, tgfLabel(edge.Name))
+		} else {
+			fmt.Fprintf(bw, "%d %d\n", a, b)
+		}
+	})
+
+	return bw.Flush()
+}
+
+// tgfLabel returns the given label on a single line, since TGF labels end
+// at the end of their line.
+func tgfLabel(label string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(label)
+}
+
+// DecodeTGF decodes nodes and their edges encoded using the Trivial Graph
+// Format, like the output of EncodeTGF. Nodes are named after their label,
+// or their identifier if they don't have one, and edges are added as
+// outward edges, named after their label, if any.
+//
+// Blank lines are ignored. Edges referring to nodes that weren't defined
+// add a node named after the identifier, and an error is returned for
+// lines that don't have the identifiers they need.
+//
+// https://en.wikipedia.org/wiki/Trivial_Graph_Format
+func DecodeTGF(r io.Reader) (Nodes, error) {
+	var (
+		nodes Nodes
+		byID  = map[string]*Node{}
+		edges bool
+	)
+
+	node := func(id string) *Node {
+		if n, ok := byID[id]; ok {
+			return n
+		}
+		n := NewNode(id, nil)
+		byID[id] = n
+		nodes = append(nodes, n)
+		return n
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if text == "#" && !edges {
+			edges = true
+			continue
+		}
+
+		id, rest := cutSpace(text)
+
+		if !edges {
+			if n := node(id); rest != "" {
+				n.Name = rest
+			}
+			continue
+		}
+
+		to, label := cutSpace(rest)
+		if to == "" {
+			return nil, fmt.Errorf("graph failed to decode TGF: line %d: edge needs two node identifiers: %q", line, text)
+		}
+
+		if label != "" {
+			node(id).AddEdgeTyped(node(to), label)
+		} else {
+			node(id).AddEdgeWithDirection(node(to), Out)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("graph failed to decode TGF: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// cutSpace returns the text before and after the first space or tab of the
+// given text, without surrounding spaces.
+func cutSpace(text string) (before, after string) {
+	i := strings.IndexAny(text, " \t")
+	if i < 0 {
+		return text, ""
+	}
+	return text[:i], strings.TrimSpace(text[i+1:])
+}
+
+// DecodeEdgeList decodes nodes and their edges from a plain edge list, the
+// format many datasets are distributed in, with an edge on each line, made
+// of the names of its nodes, separated by spaces or tabs, and an optional
+// numeric weight, see Edge.Weight:
+//
+//	# comment
+//	1 2
+//	2 3 0.5
+//
+// Edges are added as outward edges, from the first node to the second, and
+// nodes are returned in the order they first appear. Blank lines, and lines
+// starting with "#" or "%", used for comments by common dataset formats,
+// are ignored. An error is returned for lines with fewer than two, or more
+// than three, fields, or an invalid weight.
+func DecodeEdgeList(r io.Reader) (Nodes, error) {
+	var (
+		nodes  Nodes
+		byName = map[string]*Node{}
+	)
+
+	node := func(name string) *Node {
+		if n, ok := byName[name]; ok {
+			return n
+		}
+		n := NewNode(name, nil)
+		byName[name] = n
+		nodes = append(nodes, n)
+		return n
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == '%' {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("graph failed to decode edge list: line %d: expected two node names and an optional weight: %q", line, text)
+		}
+
+		from, to := node(fields[0]), node(fields[1])
+
+		if len(fields) == 3 {
+			weight, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("graph failed to decode edge list: line %d: invalid weight %q", line, fields[2])
+			}
+			from.AddWeightedEdge(to, weight)
+			continue
+		}
+
+		from.AddEdgeWithDirection(to, Out)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("graph failed to decode edge list: %w", err)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,107 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+const tgf_golden = `1 a
+2 b
+3 c
+4 d
+#
+1 2
+2 3 depends_on
+3 4
+4 3
+`
+
+func TestEncodeTGF(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	a.AddEdgeWithDirection(b, graph.Out)
+	b.AddEdgeTyped(c, "depends_on")
+	c.AddEdgeWithDirection(d, graph.In)
+	c.AddEdgeWithDirection(d, graph.None)
+
+	buf := bytes.NewBuffer(nil)
+
+	err := graph.EncodeTGF(buf, graph.Nodes{a, b, c, d})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != tgf_golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf.String(), tgf_golden)
+	}
+
+	nodes, err := graph.DecodeTGF(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.New("test", graph.WithNodes(nodes))
+
+	if got := g.Edges().String(); got != "a → b, b → c, c → d, d → c" {
+		t.Fatalf("unexpected decoded edges: %s", got)
+	}
+
+	if name := nodes[1].Edges[1].Name; name != "depends_on" {
+		t.Fatalf("expected typed edge, got %q", name)
+	}
+}
+
+func TestDecodeTGF(t *testing.T) {
+	nodes, err := graph.DecodeTGF(strings.NewReader("1 First node\n2\n\n#\n1 2 Edge label\n2 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := nodes.String(); got != "First node, 2, 3" {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+
+	_, err = graph.DecodeTGF(strings.NewReader("1 a\n#\n1\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected an error for line 3, got %v", err)
+	}
+}
+
+func TestDecodeEdgeList(t *testing.T) {
+	input := `# a dataset
+% another comment
+1 2
+2	3 0.5
+
+3 1
+`
+
+	nodes, err := graph.DecodeEdgeList(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.New("test", graph.WithNodes(nodes))
+
+	if got := g.Edges().String(); got != "1 → 2, 2 → 3, 3 → 1" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	if w := nodes[1].Edges[1].Weight(); w != 0.5 {
+		t.Fatalf("expected weight 0.5, got %v", w)
+	}
+
+	for _, input := range []string{"1\n", "1 2 3 4\n", "1 2 heavy\n"} {
+		if _, err := graph.DecodeEdgeList(strings.NewReader(input)); err == nil {
+			t.Fatalf("expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package graph
+
+import "fmt"
+
+// TopologicalSort returns the nodes of the graph ordered such that every
+// node comes before the nodes it has edges to, using Kahn's algorithm.
+//
+// Nodes without dependencies between them keep their relative order in the
+// graph, so the result is stable. An error is returned if the graph contains
+// a cycle, which can be collapsed first using Condense. Results are cached
+// until the graph changes, see Generation.
+//
+// https://en.wikipedia.org/wiki/Topological_sorting
+func (inst *Instance) TopologicalSort() (Nodes, error) {
+	defer inst.trace("TopologicalSort")()
+
+	result := memoize(inst, "topological", func() topologicalResult {
+		sorted, err := inst.topologicalSort()
+		return topologicalResult{sorted, err}
+	})
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	return append(Nodes{}, result.sorted...), nil
+}
+
+// topologicalResult is the cached result of TopologicalSort.
+type topologicalResult struct {
+	sorted Nodes
+	err    error
+}
+
+// topologicalSort returns the nodes of the graph in topological order.
+func (inst *Instance) topologicalSort() (Nodes, error) {
+	members := inst.members()
+
+	inDegrees := map[*Node]int{}
+	for _, node := range inst.Nodes {
+		for _, next := range node.Edges.successors() {
+			if members.Contains(next) {
+				inDegrees[next]++
+			}
+		}
+	}
+
+	queue := Nodes{}
+	for _, node := range inst.Nodes {
+		if inDegrees[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	sorted := make(Nodes, 0, len(inst.Nodes))
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		sorted = append(sorted, node)
+
+		for _, next := range node.Edges.successors() {
+			if !members.Contains(next) {
+				continue
+			}
+			inDegrees[next]--
+			if inDegrees[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if len(sorted) != members.Len() {
+		return nil, fmt.Errorf("graph contains a cycle, cannot be topologically sorted")
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,60 @@
+package graph
+
+// Tracer is notified of each invocation of the graph's algorithms, to
+// instrument them with spans or metrics. It is set using WithTracer.
+//
+// The traced algorithms are Bridges, Components, Condense, HamiltonianPath,
+// PageRank, PersonalizedPageRank, SpanningTree, StronglyConnectedComponents,
+// TopologicalSort, TriangleCount, and TSPApprox. Algorithms that use other
+// traced algorithms, like Condense, report them too.
+//
+// Start is called when an algorithm is invoked, with the name of the
+// algorithm and the number of nodes in the graph, and returns a function
+// that is called when the algorithm returns. Results served from the
+// graph's cache are also reported. Edges aren't counted, since counting
+// them takes time proportional to their number, even for cached results.
+type Tracer interface {
+	Start(algorithm string, nodes int) (end func())
+}
+
+// TracerFunc is a function that implements the Tracer interface.
+type TracerFunc func(algorithm string, nodes int) (end func())
+
+// Start implements the Tracer interface.
+func (f TracerFunc) Start(algorithm string, nodes int) func() {
+	return f(algorithm, nodes)
+}
+
+// WithTracer is a functional option that sets the tracer notified of each
+// invocation of the graph's algorithms. Graphs don't have a tracer by
+// default. Clones of the graph share its tracer.
+//
+//	g := graph.New("deps", graph.WithTracer(graph.TracerFunc(
+//		func(algorithm string, nodes int) func() {
+//			start := time.Now()
+//			return func() {
+//				log.Printf("%s: %d nodes, %v", algorithm, nodes, time.Since(start))
+//			}
+//		},
+//	)))
+func WithTracer(tracer Tracer) func(*Instance) {
+	return func(inst *Instance) {
+		inst.tracer = tracer
+	}
+}
+
+// trace notifies the graph's tracer, if any, of the invocation of the given
+// algorithm, returning the function to call when it returns.
+//
+//	defer inst.trace("Components")()
+func (inst *Instance) trace(algorithm string) func() {
+	if inst.tracer == nil {
+		return func() {}
+	}
+
+	end := inst.tracer.Start(algorithm, len(inst.Nodes))
+	if end == nil {
+		return func() {}
+	}
+	return end
+}
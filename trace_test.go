@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithTracer(t *testing.T) {
+	var (
+		a = NewNode("a", nil)
+		b = NewNode("b", nil)
+		c = NewNode("c", nil)
+	)
+
+	// a → b → c
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+
+	type invocation struct {
+		algorithm string
+		nodes     int
+		ended     bool
+	}
+
+	var invocations []*invocation
+
+	g := New("test", WithNodes(NewNodes(a, b, c)), WithTracer(TracerFunc(
+		func(algorithm string, nodes int) func() {
+			inv := &invocation{algorithm: algorithm, nodes: nodes}
+			invocations = append(invocations, inv)
+			return func() { inv.ended = true }
+		},
+	)))
+
+	g.Components()
+
+	if _, err := g.TopologicalSort(); err != nil {
+		t.Fatal(err)
+	}
+
+	g.Clone().Bridges()
+
+	expected := []*invocation{
+		{"Components", 3, true},
+		{"TopologicalSort", 3, true},
+		{"Bridges", 3, true},
+	}
+
+	if !reflect.DeepEqual(invocations, expected) {
+		for _, inv := range invocations {
+			t.Logf("%+v", *inv)
+		}
+		t.Fatalf("unexpected invocations")
+	}
+
+	// Graphs without a tracer aren't affected.
+	New("untraced", WithNodes(NewNodes(a, b, c))).Components()
+
+	if len(invocations) != len(expected) {
+		t.Fatalf("expected %d invocations, got %d", len(expected), len(invocations))
+	}
+}
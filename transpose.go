@@ -0,0 +1,167 @@
+package graph
+
+import "reflect"
+
+// Clone returns a deep copy of the graph, with copies of its nodes and their
+// edges, so the copy can be changed without affecting the original.
+//
+// Attribute maps are copied, but their values are not. Edges that shared an
+// attribute map, like the two edges of a weighted relationship, share the
+// copied map. Edges to nodes outside of the graph point to the original
+// nodes, indexes, subgraphs, and hyperedges are recreated, and the child
+// graphs of compound nodes are copied, but versions are not copied.
+func (inst *Instance) Clone() *Instance {
+	return inst.subgraph(inst.Nodes)
+}
+
+// subgraph returns a deep copy of the graph, like Clone, that only includes
+// copies of the given nodes of the graph, and the edges between them.
+func (inst *Instance) subgraph(nodes Nodes) *Instance {
+	return inst.subgraphWith(nodes, map[*Node]*Node{})
+}
+
+// subgraphWith returns a copy of the graph like subgraph, recording the
+// copies of nodes in the given map, shared by the copies of the child graphs
+// of compound nodes, so edges can refer to copies of the nodes inside them.
+func (inst *Instance) subgraphWith(nodes Nodes, copies map[*Node]*Node) *Instance {
+	members := inst.members()
+
+	clone := New(inst.Name)
+	clone.Attributes = copyAttributes(inst.Attributes)
+	clone.NodeDefaults = copyAttributes(inst.NodeDefaults)
+	clone.EdgeDefaults = copyAttributes(inst.EdgeDefaults)
+	clone.tracer = inst.tracer
+	clone.resolver = inst.resolver
+
+	for _, node := range nodes {
+		copies[node] = &Node{
+			Name:        node.Name,
+			Attributes:  copyAttributes(node.Attributes),
+			Labels:      append([]string(nil), node.Labels...),
+			placeholder: node.placeholder,
+		}
+	}
+
+	for _, node := range nodes {
+		if node.Child != nil {
+			copies[node].Child = node.Child.subgraphWith(node.Child.Nodes, copies)
+		}
+	}
+
+	copyOf := func(n *Node) *Node {
+		if c, ok := copies[n]; ok {
+			return c
+		}
+		return n
+	}
+
+	// Copy shared attribute maps once, using the identity of the map.
+	attrs := map[uintptr]Attributes{}
+	copyShared := func(a Attributes) Attributes {
+		if a == nil {
+			return nil
+		}
+		ptr := reflect.ValueOf(a).Pointer()
+		if c, ok := attrs[ptr]; ok {
+			return c
+		}
+		attrs[ptr] = copyAttributes(a)
+		return attrs[ptr]
+	}
+
+	for _, node := range nodes {
+		c := copies[node]
+		c.Edges = make(Edges, 0, len(node.Edges))
+		for _, edge := range node.Edges {
+			target, ok := copies[edge.Node]
+			if !ok {
+				if members.Contains(edge.Node) {
+					continue
+				}
+				target = edge.Node
+			}
+			c.Edges = append(c.Edges, &Edge{
+				Name:       edge.Name,
+				Node:       target,
+				Direction:  edge.Direction,
+				Attributes: copyShared(edge.Attributes),
+				from:       c,
+				inner:      copyOf(edge.inner),
+				origin:     copyOf(edge.origin),
+			})
+		}
+	}
+
+	for name := range inst.indexes {
+		clone.CreateIndex(name)
+	}
+
+	if inst.names != nil {
+		clone.names = map[string]*Node{}
+	}
+
+	for _, node := range nodes {
+		clone.AddNode(copies[node])
+	}
+
+	for _, h := range inst.hyperEdges {
+		var members Nodes
+		for _, node := range h.Nodes {
+			if dup, ok := copies[node]; ok {
+				members = append(members, dup)
+			}
+		}
+		if len(members) > 0 {
+			clone.AddHyperEdge(h.Name, members...).Attributes = copyAttributes(h.Attributes)
+		}
+	}
+
+	for _, sub := range inst.subs {
+		c := clone.AddSub(sub.Name)
+		c.Attributes = copyAttributes(sub.Attributes)
+		for _, node := range sub.nodes {
+			if dup, ok := copies[node]; ok {
+				c.AddNode(dup)
+			}
+		}
+	}
+
+	return clone
+}
+
+// Reverse reverses the direction of every edge in the graph in place, so
+// a → b becomes b → a. Undirected (None) and bi-directional (Both) edges
+// are unchanged. Edges to nodes outside of the graph are reversed along
+// with the other side of their relationship.
+//
+//	a → b → c    ⇒    a ← b ← c
+//
+// https://en.wikipedia.org/wiki/Transpose_graph
+func (inst *Instance) Reverse() {
+	inst.Touch()
+
+	members := inst.members()
+
+	for _, node := range inst.Nodes {
+		for _, edge := range node.Edges {
+			if !members.Contains(edge.Node) {
+				if other := edge.reciprocal(node); other != nil {
+					other.Direction = other.Direction.Reverse()
+				}
+			}
+			edge.Direction = edge.Direction.Reverse()
+		}
+	}
+
+	inst.publishEdges(inst.Nodes, true, func(edge *Edge) bool {
+		return edge.Direction == In || edge.Direction == Out
+	})
+}
+
+// Transpose returns a copy of the graph with the direction of every edge
+// reversed, leaving the graph unchanged. See Reverse.
+func (inst *Instance) Transpose() *Instance {
+	transposed := inst.Clone()
+	transposed.Reverse()
+	return transposed
+}
@@ -0,0 +1,55 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Transpose(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"team": "x"})
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	// a → b → c - a
+
+	a.AddWeightedEdge(b, 2)
+	b.AddEdge(c)
+	c.AddEdgeWithDirection(a, graph.None)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+	g.CreateIndex("team")
+
+	transposed := g.Transpose()
+
+	if !a.HasPath(c) || !graph.Equal(g, g.Clone()) {
+		t.Fatalf("expected the original graph to be unchanged")
+	}
+
+	ta, tb, tc := transposed.Nodes[0], transposed.Nodes[1], transposed.Nodes[2]
+
+	if ta == a || tb.Edges.Out().Nodes().String() != "a" || tc.Edges.Out().Nodes().String() != "b" {
+		t.Fatalf("expected a reversed copy of the graph")
+	}
+
+	if !tc.Edges.AdjacentTo(ta) {
+		t.Fatalf("expected the undirected edge to be kept")
+	}
+
+	if w := tb.Edges.Out()[0].Weight(); w != 2 {
+		t.Fatalf("expected weight to be kept, got %v", w)
+	}
+
+	if nodes := transposed.NodesWhere("team", "x"); len(nodes) != 1 || nodes[0] != ta {
+		t.Fatalf("expected indexes to be recreated: %v", nodes)
+	}
+
+	g.Reverse()
+	g.Reverse()
+
+	if b.Edges.Out().Nodes().String() != "c" || c.Edges.Out().Nodes().String() != "" {
+		t.Fatalf("expected reversing twice to restore the graph")
+	}
+}
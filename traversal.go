@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"reflect"
+	"time"
+)
+
+// Traversal is a chainable, Gremlin-style traversal over the nodes of a
+// graph. Each step returns a new traversal, starting from the nodes the
+// previous step ended at.
+//
+//	graph.Traverse(start).Out().Has("type", "service").Out().Dedup().Limit(10).Nodes()
+//
+// https://tinkerpop.apache.org/gremlin.html
+type Traversal struct {
+	nodes Nodes
+
+	// asOf is the time edges must be valid at to be followed, if not zero.
+	asOf time.Time
+}
+
+// Traverse returns a new traversal starting at the given nodes.
+func Traverse(start ...*Node) *Traversal {
+	return &Traversal{nodes: append(Nodes{}, start...)}
+}
+
+// Traverse returns a new traversal starting at all of the nodes in the graph.
+func (inst *Instance) Traverse() *Traversal {
+	return Traverse(inst.Nodes...)
+}
+
+// step returns a new traversal with the nodes produced by the given
+// function for each of the current nodes.
+func (t *Traversal) step(fn func(n *Node) Nodes) *Traversal {
+	next := Nodes{}
+	for _, node := range t.nodes {
+		next = append(next, fn(node)...)
+	}
+	return &Traversal{nodes: next, asOf: t.asOf}
+}
+
+// follow returns a new traversal to the nodes at the other side of the
+// edges matching the given direction, and any of the given types if any.
+func (t *Traversal) follow(direction EdgeDirection, types []string) *Traversal {
+	return t.step(func(n *Node) Nodes {
+		var next Nodes
+		for _, edge := range n.Edges {
+			if !t.asOf.IsZero() && !edge.ValidAt(t.asOf) {
+				continue
+			}
+			if len(types) > 0 && !edge.isType(types) {
+				continue
+			}
+			if direction == Unknown || edge.Direction.Match(direction) {
+				next = append(next, edge.Node)
+			}
+		}
+		return next
+	})
+}
+
+// Out moves to the nodes at the other side of outward (Out or Both) edges,
+// optionally only following edges with any of the given relationship types.
+func (t *Traversal) Out(types ...string) *Traversal {
+	return t.follow(Out, types)
+}
+
+// In moves to the nodes at the other side of inward (In or Both) edges,
+// optionally only following edges with any of the given relationship types.
+func (t *Traversal) In(types ...string) *Traversal {
+	return t.follow(In, types)
+}
+
+// Both moves to the nodes at the other side of all edges, optionally only
+// following edges with any of the given relationship types.
+func (t *Traversal) Both(types ...string) *Traversal {
+	return t.follow(Unknown, types)
+}
+
+// Where keeps only the nodes matching the given predicate.
+func (t *Traversal) Where(pred func(*Node) bool) *Traversal {
+	return t.step(func(n *Node) Nodes {
+		if pred(n) {
+			return Nodes{n}
+		}
+		return nil
+	})
+}
+
+// Has keeps only the nodes with the named attribute equal to the given value,
+// compared using reflect.DeepEqual, so values that can't be compared using
+// ==, like slices, can be matched too.
+func (t *Traversal) Has(name string, value any) *Traversal {
+	return t.Where(func(n *Node) bool {
+		v, ok := n.Attributes[name]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// HasName keeps only the nodes with the given name.
+func (t *Traversal) HasName(name string) *Traversal {
+	return t.Where(func(n *Node) bool {
+		return n.Name == name
+	})
+}
+
+// Dedup removes repeated nodes, keeping the first occurrence.
+func (t *Traversal) Dedup() *Traversal {
+	seen := NodeSet{}
+	return t.Where(func(n *Node) bool {
+		if seen.Contains(n) {
+			return false
+		}
+		seen.Add(n)
+		return true
+	})
+}
+
+// Limit keeps at most the first n nodes.
+func (t *Traversal) Limit(n int) *Traversal {
+	if n < 0 {
+		n = 0
+	}
+	if n > len(t.nodes) {
+		n = len(t.nodes)
+	}
+	return &Traversal{nodes: append(Nodes{}, t.nodes[:n]...), asOf: t.asOf}
+}
+
+// Nodes returns the nodes the traversal ended at.
+func (t *Traversal) Nodes() Nodes {
+	return append(Nodes{}, t.nodes...)
+}
+
+// Count returns the number of nodes the traversal ended at.
+func (t *Traversal) Count() int {
+	return len(t.nodes)
+}
@@ -0,0 +1,100 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestTraverse(t *testing.T) {
+	var (
+		gateway = graph.NewNode("gateway", graph.Attributes{"type": "service"})
+		users   = graph.NewNode("users", graph.Attributes{"type": "service"})
+		orders  = graph.NewNode("orders", graph.Attributes{"type": "service"})
+		cache   = graph.NewNode("cache", graph.Attributes{"type": "cache"})
+		db      = graph.NewNode("db", graph.Attributes{"type": "db"})
+	)
+
+	//             users
+	//           ↗       ↘
+	// gateway → cache     db
+	//           ↘       ↗
+	//             orders
+
+	gateway.AddEdge(users)
+	gateway.AddEdge(cache)
+	gateway.AddEdge(orders)
+	users.AddEdge(db)
+	orders.AddEdge(db)
+
+	services := graph.Traverse(gateway).Out().Has("type", "service").Nodes()
+	if services.String() != "users, orders" {
+		t.Fatalf("unexpected services: %v", services)
+	}
+
+	databases := graph.Traverse(gateway).Out().Has("type", "service").Out().Dedup().Nodes()
+	if databases.String() != "db" {
+		t.Fatalf("unexpected databases: %v", databases)
+	}
+
+	// Values that can't be compared using ==, like slices, don't panic.
+	users.SetAttribute("tags", []string{"public"})
+	orders.SetAttribute("tags", []string{"internal"})
+	if public := graph.Traverse(gateway).Out().Has("tags", []string{"public"}).Nodes(); public.String() != "users" {
+		t.Fatalf("unexpected public services: %v", public)
+	}
+
+	if n := graph.Traverse(gateway).Out().Out().Count(); n != 2 {
+		t.Fatalf("expected 2 nodes without dedup, got %d", n)
+	}
+
+	dependents := graph.Traverse(db).In().In().Dedup().Nodes()
+	if dependents.String() != "gateway" {
+		t.Fatalf("unexpected dependents: %v", dependents)
+	}
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(gateway, users, orders, cache, db)))
+
+	first := g.Traverse().Where(func(n *graph.Node) bool {
+		return len(n.Edges.In()) > 0
+	}).Limit(2).Nodes()
+
+	if first.String() != "users, orders" {
+		t.Fatalf("unexpected limited nodes: %v", first)
+	}
+
+	if n := g.Traverse().HasName("cache").Both().Count(); n != 1 {
+		t.Fatalf("expected cache to have 1 neighbor, got %d", n)
+	}
+}
+
+func TestTraverse_types(t *testing.T) {
+	var (
+		main   = graph.NewNode("main", nil)
+		http   = graph.NewNode("http", nil)
+		handle = graph.NewNode("handle", nil)
+		team   = graph.NewNode("team", nil)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(main, http, handle, team)))
+
+	g.AddEdgeTyped(main, http, "imports")
+	g.AddEdgeTyped(main, handle, "calls")
+	g.AddEdgeTyped(team, main, "owns")
+
+	if imports := graph.Traverse(main).Out("imports").Nodes(); imports.String() != "http" {
+		t.Fatalf("unexpected imports: %v", imports)
+	}
+
+	if deps := graph.Traverse(main).Out("imports", "calls").Nodes(); deps.String() != "http, handle" {
+		t.Fatalf("unexpected dependencies: %v", deps)
+	}
+
+	if owners := graph.Traverse(main).In("owns").Nodes(); owners.String() != "team" {
+		t.Fatalf("unexpected owners: %v", owners)
+	}
+
+	if n := len(main.Edges.OfType("calls", "owns")); n != 2 {
+		t.Fatalf("expected 2 calls or owns edges, got %d", n)
+	}
+}
@@ -0,0 +1,120 @@
+package graph
+
+import "math"
+
+// TSPApprox returns an approximate solution to the travelling salesman
+// problem, a tour that starts and ends at the given node visiting every
+// other node in the graph once, along with the total weight of the tour.
+//
+// The tour is built using the nearest neighbor heuristic and then improved
+// using 2-opt moves. Edge weights come from Edge.Weight, and the graph
+// should be complete, like graphs built using MeshNodes. If no tour can be
+// found, a nil path and an infinite weight are returned.
+//
+// https://en.wikipedia.org/wiki/Travelling_salesman_problem
+// https://en.wikipedia.org/wiki/2-opt
+func (inst *Instance) TSPApprox(start *Node) (Path, float64) {
+	defer inst.trace("TSPApprox")()
+
+	n := len(inst.Nodes)
+
+	ids := make(map[*Node]int, n)
+	for i, node := range inst.Nodes {
+		ids[node] = i
+	}
+
+	first, ok := ids[start]
+	if !ok {
+		return nil, math.Inf(1)
+	}
+
+	if n == 1 {
+		return Path{start}, 0
+	}
+
+	// Build a matrix of the lightest edge weights between nodes.
+	weights := make([][]float64, n)
+	for i, node := range inst.Nodes {
+		weights[i] = make([]float64, n)
+		for j := range weights[i] {
+			weights[i][j] = math.Inf(1)
+		}
+		for _, edge := range node.Edges {
+			if edge.Direction == In {
+				continue
+			}
+			if j, ok := ids[edge.Node]; ok && edge.Weight() < weights[i][j] {
+				weights[i][j] = edge.Weight()
+			}
+		}
+	}
+
+	cost := func(tour []int) float64 {
+		var total float64
+		for i := 0; i+1 < len(tour); i++ {
+			total += weights[tour[i]][tour[i+1]]
+		}
+		return total
+	}
+
+	// Nearest neighbor construction.
+	visited := make([]bool, n)
+	visited[first] = true
+
+	tour := []int{first}
+
+	for len(tour) < n {
+		at := tour[len(tour)-1]
+
+		next := -1
+		for j := 0; j < n; j++ {
+			if !visited[j] && (next == -1 || weights[at][j] < weights[at][next]) {
+				next = j
+			}
+		}
+
+		if math.IsInf(weights[at][next], 1) {
+			return nil, math.Inf(1)
+		}
+
+		visited[next] = true
+		tour = append(tour, next)
+	}
+
+	tour = append(tour, first)
+
+	best := cost(tour)
+	if math.IsInf(best, 1) {
+		return nil, best
+	}
+
+	// 2-opt improvement, reversing segments of the tour while it helps.
+	for improved := true; improved; {
+		improved = false
+		for i := 1; i < len(tour)-2; i++ {
+			for j := i + 1; j < len(tour)-1; j++ {
+				reverse(tour[i : j+1])
+				if c := cost(tour); c < best {
+					best = c
+					improved = true
+				} else {
+					reverse(tour[i : j+1])
+				}
+			}
+		}
+	}
+
+	path := make(Path, len(tour))
+	for i, id := range tour {
+		path[i] = inst.Nodes[id]
+	}
+
+	return path, best
+}
+
+// reverse reverses the given slice of IDs in place.
+func reverse(ids []int) {
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+}
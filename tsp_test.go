@@ -0,0 +1,59 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_TSPApprox(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a ─1─ b
+	// │ ╲ ╱ │
+	// 1  ╳  1   (diagonals weigh 5)
+	// │ ╱ ╲ │
+	// d ─1─ c
+
+	link := func(x, y *graph.Node, weight float64) {
+		x.AddWeightedEdge(y, weight)
+		y.AddWeightedEdge(x, weight)
+	}
+
+	link(a, b, 1)
+	link(b, c, 1)
+	link(c, d, 1)
+	link(d, a, 1)
+	link(a, c, 5)
+	link(b, d, 5)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c, d)))
+
+	tour, cost := g.TSPApprox(a)
+
+	if cost != 4 {
+		t.Fatalf("expected tour cost of 4, got %v (%v)", cost, tour)
+	}
+
+	if len(tour) != 5 || tour[0] != a || tour[4] != a {
+		t.Fatalf("expected tour to start and end at a: %v", tour)
+	}
+
+	for _, node := range g.Nodes {
+		if !tour.ContainsNode(node) {
+			t.Fatalf("expected tour to contain %s: %v", node.Name, tour)
+		}
+	}
+
+	e := graph.NewNode("e", nil)
+	g.AddNode(e)
+
+	if tour, _ := g.TSPApprox(a); tour != nil {
+		t.Fatalf("did not expect a tour with an unreachable node: %v", tour)
+	}
+}
@@ -0,0 +1,326 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// VersionID identifies a committed version of a graph instance.
+type VersionID int
+
+// Version describes a committed version of a graph instance.
+type Version struct {
+	// ID is the identifier of the version, starting at 1.
+	ID VersionID
+
+	// Parent is the version that was checked out when this version was
+	// committed, or 0 for the first version.
+	Parent VersionID
+
+	// Message describes the changes made in the version.
+	Message string
+
+	// Time is when the version was committed.
+	Time time.Time
+}
+
+// snapshot is the frozen state of a graph instance for a version.
+type snapshot struct {
+	Version
+
+	name  string
+	attrs Attributes
+	nodes []*nodeSnapshot
+}
+
+// nodeSnapshot is the frozen state of a node, which is shared between
+// versions when the node didn't change.
+type nodeSnapshot struct {
+	node   *Node
+	name   string
+	labels []string
+	attrs  Attributes
+	edges  []edgeSnapshot
+}
+
+// edgeSnapshot is the frozen state of one of a node's edges.
+type edgeSnapshot struct {
+	node      *Node
+	name      string
+	direction EdgeDirection
+	attrs     Attributes
+}
+
+// Commit records the current state of the graph as a new version with the
+// given message, which can be restored later using Checkout.
+//
+// Snapshots use structural sharing, so nodes that haven't changed since the
+// version that is currently checked out aren't copied again. Attribute maps
+// are copied, along with values that are slices or maps, but other values,
+// like pointers, are shared with the version.
+func (inst *Instance) Commit(msg string) VersionID {
+	var previous map[*Node]*nodeSnapshot
+	if inst.head > 0 {
+		parent := inst.versions[inst.head-1]
+		previous = make(map[*Node]*nodeSnapshot, len(parent.nodes))
+		for _, ns := range parent.nodes {
+			previous[ns.node] = ns
+		}
+	}
+
+	snap := &snapshot{
+		Version: Version{
+			ID:      VersionID(len(inst.versions) + 1),
+			Parent:  inst.head,
+			Message: msg,
+			Time:    time.Now(),
+		},
+		name:  inst.Name,
+		attrs: copyAttributes(inst.Attributes),
+		nodes: make([]*nodeSnapshot, 0, len(inst.Nodes)),
+	}
+
+	for _, node := range inst.Nodes {
+		if prev, ok := previous[node]; ok && prev.matches(node) {
+			snap.nodes = append(snap.nodes, prev)
+			continue
+		}
+
+		snap.nodes = append(snap.nodes, snapshotNode(node))
+	}
+
+	inst.versions = append(inst.versions, snap)
+	inst.head = snap.ID
+
+	return snap.ID
+}
+
+// Checkout restores the graph to the state of the given version. Nodes are
+// restored in place, so existing references to them remain valid, while
+// nodes added after the version are removed from the graph.
+//
+// New versions committed after a checkout use it as their parent, which
+// allows the history to branch.
+func (inst *Instance) Checkout(id VersionID) error {
+	if id < 1 || int(id) > len(inst.versions) {
+		return fmt.Errorf("graph version %d does not exist", id)
+	}
+
+	snap := inst.versions[id-1]
+
+	inst.Touch()
+
+	// Subscribers see every node removed, and those of the version added
+	// back, with their edges.
+	for _, node := range inst.Nodes {
+		inst.publish(Mutation{Kind: NodeRemoved, Node: node})
+	}
+
+	kept := make(map[*Node]bool, len(snap.nodes))
+	for _, ns := range snap.nodes {
+		kept[ns.node] = true
+	}
+
+	for _, node := range inst.Nodes {
+		inst.unindexNode(node)
+		if !kept[node] {
+			inst.releaseID(node)
+			node.Edges = nil
+			if node.graph == inst {
+				node.graph = nil
+			}
+		}
+	}
+
+	inst.Name = snap.name
+	inst.Attributes = copyAttributes(snap.attrs)
+	inst.Nodes = make(Nodes, 0, len(snap.nodes))
+
+	for _, ns := range snap.nodes {
+		node := ns.node
+		node.Name = ns.name
+		node.Labels = append([]string(nil), ns.labels...)
+		node.Attributes = cloneAttributes(ns.attrs)
+		node.Edges = make(Edges, len(ns.edges))
+		for i, es := range ns.edges {
+			node.Edges[i] = &Edge{
+				Name:       es.name,
+				Node:       es.node,
+				Direction:  es.direction,
+				Attributes: cloneAttributes(es.attrs),
+				from:       node,
+			}
+		}
+
+		inst.Nodes = append(inst.Nodes, node)
+		inst.adopt(node)
+	}
+
+	inst.publishEdges(inst.Nodes, false, func(*Edge) bool { return true })
+
+	inst.head = id
+
+	return nil
+}
+
+// Head returns the version that was last committed or checked out, or 0
+// if there are no versions.
+func (inst *Instance) Head() VersionID {
+	return inst.head
+}
+
+// History returns the versions leading up to the current head, from the
+// newest to the oldest, following each version's parent.
+func (inst *Instance) History() []Version {
+	var history []Version
+	for id := inst.head; id > 0; id = inst.versions[id-1].Parent {
+		history = append(history, inst.versions[id-1].Version)
+	}
+	return history
+}
+
+// snapshotNode returns the frozen state of the given node.
+func snapshotNode(node *Node) *nodeSnapshot {
+	ns := &nodeSnapshot{
+		node:   node,
+		name:   node.Name,
+		labels: append([]string(nil), node.Labels...),
+		attrs:  cloneAttributes(node.Attributes),
+		edges:  make([]edgeSnapshot, len(node.Edges)),
+	}
+	for i, edge := range node.Edges {
+		ns.edges[i] = edgeSnapshot{
+			node:      edge.Node,
+			name:      edge.Name,
+			direction: edge.Direction,
+			attrs:     cloneAttributes(edge.Attributes),
+		}
+	}
+	return ns
+}
+
+// matches checks if the node still has the state recorded in the snapshot.
+func (ns *nodeSnapshot) matches(node *Node) bool {
+	if node.Name != ns.name || len(node.Edges) != len(ns.edges) || !attributesEqual(node.Attributes, ns.attrs) {
+		return false
+	}
+
+	if len(node.Labels) != len(ns.labels) {
+		return false
+	}
+	for i, label := range node.Labels {
+		if label != ns.labels[i] {
+			return false
+		}
+	}
+
+	for i, edge := range node.Edges {
+		es := ns.edges[i]
+		if edge.Node != es.node || edge.Name != es.name || edge.Direction != es.direction || !attributesEqual(edge.Attributes, es.attrs) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copyAttributes returns a shallow copy of the given attributes.
+func copyAttributes(attrs Attributes) Attributes {
+	if attrs == nil {
+		return nil
+	}
+
+	copied := make(Attributes, len(attrs))
+	for k, v := range attrs {
+		copied[k] = v
+	}
+	return copied
+}
+
+// cloneAttributes returns a copy of the given attributes, which also copies
+// values that are slices or maps, recursively, so changing them in place
+// doesn't change the copy.
+func cloneAttributes(attrs Attributes) Attributes {
+	if attrs == nil {
+		return nil
+	}
+
+	cloned := make(Attributes, len(attrs))
+	for k, v := range attrs {
+		cloned[k] = cloneValue(v)
+	}
+	return cloned
+}
+
+// cloneValue returns a copy of the given value that shares no slices or
+// maps with it, or the value itself if it has none.
+func cloneValue(v any) any {
+	if v == nil {
+		return nil
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return cloneReflect(rv).Interface()
+	default:
+		return v
+	}
+}
+
+// cloneReflect returns a copy of the given value that shares no slices or
+// maps with it.
+func cloneReflect(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cloned := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cloned.Index(i).Set(cloneReflect(v.Index(i)))
+		}
+		return cloned
+	case reflect.Array:
+		cloned := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cloned.Index(i).Set(cloneReflect(v.Index(i)))
+		}
+		return cloned
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cloned := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cloned.SetMapIndex(iter.Key(), cloneReflect(iter.Value()))
+		}
+		return cloned
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cloned := reflect.New(v.Type()).Elem()
+		cloned.Set(cloneReflect(v.Elem()))
+		return cloned
+	default:
+		return v
+	}
+}
+
+// attributesEqual checks if the given attributes have the same keys and
+// deeply equal values.
+func attributesEqual(a, b Attributes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		other, ok := b[k]
+		if !ok || !reflect.DeepEqual(v, other) {
+			return false
+		}
+	}
+	return true
+}
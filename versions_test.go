@@ -0,0 +1,63 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_Commit(t *testing.T) {
+	var (
+		a = graph.NewNode("a", graph.Attributes{"version": 1})
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdge(b)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b)))
+
+	v1 := g.Commit("initial")
+
+	g.AddNode(c)
+	b.AddEdge(c)
+	a.Attributes["version"] = 2
+
+	v2 := g.Commit("add c")
+
+	if history := g.History(); len(history) != 2 || history[0].Message != "add c" || history[1].Parent != 0 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	if err := g.Checkout(v1); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Nodes.String() != "a, b" || a.Attributes["version"] != 1 || len(b.Edges) != 1 {
+		t.Fatalf("unexpected graph after checking out v1: %v, %v", g.Nodes, a.Attributes)
+	}
+
+	if a.HasPath(c) {
+		t.Fatalf("did not expect a path to c after checking out v1")
+	}
+
+	a.Attributes["version"] = 3
+
+	v3 := g.Commit("branch from v1")
+
+	if history := g.History(); len(history) != 2 || history[0].ID != v3 || history[1].ID != v1 {
+		t.Fatalf("unexpected history after branching: %+v", history)
+	}
+
+	if err := g.Checkout(v2); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Nodes.String() != "a, b, c" || a.Attributes["version"] != 2 || !a.HasPath(c) {
+		t.Fatalf("unexpected graph after checking out v2: %v, %v", g.Nodes, a.Attributes)
+	}
+
+	if err := g.Checkout(42); err == nil {
+		t.Fatalf("expected an error checking out a missing version")
+	}
+}
@@ -0,0 +1,206 @@
+package graph
+
+import "sync"
+
+// GraphView is a read-only view of the nodes and edges of a graph allowed by
+// filters, see Instance.View, which reads the graph's own nodes and edges
+// without copying them, so only a subset of a large graph can be analyzed
+// without doubling the memory used, as filtering it into a copy would.
+//
+// Views implement the Graph interface, so algorithms written against it,
+// like DepthFirst, BreadthFirst, ShortestPath, and BridgesOf, accept them,
+// and have their own methods for common analyses, like Components.
+//
+// Views are lazy: filters are evaluated when the view is read, so changes
+// to the graph are seen by the view.
+type GraphView struct {
+	inst       *Instance
+	nodeFilter func(*Node) bool
+	edgeFilter EdgeFilter
+
+	// members are the nodes of the graph, for nodes that don't have an ID,
+	// found once for the generation and number of nodes of the graph in
+	// shape.
+	mu      sync.Mutex
+	members NodeSet
+	shape   [2]uint64
+}
+
+var _ Graph = (*GraphView)(nil)
+
+// View returns a view of the graph's nodes that the given node filter
+// returns true for, and the edges between them that the given edge filter
+// returns true for. Either filter can be nil to allow every node or edge.
+//
+//	a → b → c    g.View(notB, nil): a, c, without any edges
+//
+// Edges are given to the edge filter from the side of the node they are
+// followed from, like WithEdgeFilter.
+func (inst *Instance) View(nodeFilter func(*Node) bool, edgeFilter EdgeFilter) *GraphView {
+	return &GraphView{
+		inst:       inst,
+		nodeFilter: nodeFilter,
+		edgeFilter: edgeFilter,
+	}
+}
+
+// Contains checks if the node is in the view: a node of the graph allowed by
+// the node filter.
+func (v *GraphView) Contains(n *Node) bool {
+	if n == nil || (v.nodeFilter != nil && !v.nodeFilter(n)) {
+		return false
+	}
+	if v.inst.ID(n) >= 0 {
+		return true
+	}
+	// Nodes that were added to another graph since don't have an ID.
+	return v.memberNodes().Contains(n)
+}
+
+// memberNodes returns the set of the nodes of the graph, which is only
+// built again if the graph changed since it was last built.
+func (v *GraphView) memberNodes() NodeSet {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	shape := [2]uint64{v.inst.generation, uint64(len(v.inst.Nodes))}
+	if v.members == nil || v.shape != shape {
+		v.members, v.shape = NewNodeSet(v.inst.Nodes...), shape
+	}
+	return v.members
+}
+
+// allows checks if the edge from the given node is in the view, which is
+// the case if the edge filter allows it, and its node is in the view.
+func (v *GraphView) allows(edge *Edge) bool {
+	return (v.edgeFilter == nil || v.edgeFilter(edge)) && v.Contains(edge.Node)
+}
+
+// Nodes returns the nodes in the view, in the order of the graph. The nodes
+// aren't copies, so they have all of their edges, including those outside
+// of the view.
+func (v *GraphView) Nodes() Nodes {
+	var nodes Nodes
+	for _, node := range v.inst.Nodes {
+		if v.nodeFilter == nil || v.nodeFilter(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Edges returns the unique edges in the view, like Instance.Edges.
+func (v *GraphView) Edges() Edges {
+	edges := Edges{}
+	eachUniqueEdge(v.Nodes(), func(from *Node, edge *Edge) {
+		if v.allows(edge) {
+			edges = append(edges, edge)
+		}
+	})
+	return edges
+}
+
+// NodeCount returns the number of nodes in the view.
+func (v *GraphView) NodeCount() int {
+	if v.nodeFilter == nil {
+		return len(v.inst.Nodes)
+	}
+
+	var count int
+	for _, node := range v.inst.Nodes {
+		if v.nodeFilter(node) {
+			count++
+		}
+	}
+	return count
+}
+
+// EachNode calls the given function for each node in the view, in the order
+// of the graph, until it returns false.
+func (v *GraphView) EachNode(fn func(NodeRef) bool) {
+	for _, node := range v.inst.Nodes {
+		if v.nodeFilter != nil && !v.nodeFilter(node) {
+			continue
+		}
+		if !fn(node) {
+			return
+		}
+	}
+}
+
+// FindNode returns the first node in the view with the given name.
+func (v *GraphView) FindNode(key string) (NodeRef, bool) {
+	if node, ok := v.inst.Lookup(key); ok && v.Contains(node) {
+		return node, true
+	}
+
+	// Another node with the same name may be in the view.
+	for _, node := range v.inst.Nodes {
+		if node.Name == key && v.Contains(node) {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// OutEdges returns the edges in the view starting from the given node.
+func (v *GraphView) OutEdges(n NodeRef) []EdgeRef {
+	if node, ok := n.(*Node); !ok || !v.Contains(node) {
+		return nil
+	}
+	return outEdges(n, v.allows)
+}
+
+// InEdges returns the edges in the view pointing to the given node.
+func (v *GraphView) InEdges(n NodeRef) []EdgeRef {
+	if node, ok := n.(*Node); !ok || !v.Contains(node) {
+		return nil
+	}
+	return inEdges(n, v.allows)
+}
+
+// Components returns the weakly connected components of the view, like
+// Instance.Components, in the order their first node appears in the graph.
+func (v *GraphView) Components() []NodeSet {
+	return componentsOf(v.Nodes(), v.inst, v.allows)
+}
+
+// Bridges returns the bridges of the view, like Instance.Bridges.
+func (v *GraphView) Bridges() Edges {
+	refs := BridgesOf(v)
+
+	bridges := make(Edges, 0, len(refs))
+	for _, ref := range refs {
+		if edge, ok := ref.(*Edge); ok {
+			bridges = append(bridges, edge)
+		}
+	}
+	return bridges
+}
+
+// Visit walks the nodes in the view reachable from the given node, following
+// the edges in the view like Node.Visit, using the given traversal options.
+// Nodes outside of the view aren't visited.
+func (v *GraphView) Visit(start *Node, fn func(*Node), opts ...func(*VisitOrder)) {
+	if !v.Contains(start) {
+		return
+	}
+
+	order := newVisitOrder(opts...)
+
+	filter := order.Filter
+	order.Filter = func(edge *Edge) bool {
+		return v.allows(edge) && (filter == nil || filter(edge))
+	}
+
+	visit(start, order, fn)
+}
+
+// PathTo returns a path from the start node to the end node using the edges
+// in the view, like Node.PathTo, or nil if there is none.
+func (v *GraphView) PathTo(start, end *Node) Path {
+	if !v.Contains(start) || !v.Contains(end) {
+		return nil
+	}
+	return start.PathTo(end, WithEdgeFilter(v.allows))
+}
@@ -0,0 +1,115 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestInstance_View(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", graph.Attributes{"test": true})
+		c = graph.NewNode("c", nil)
+		d = graph.NewNode("d", nil)
+	)
+
+	// a → b → c → d, and a → c is a dev dependency.
+	a.AddEdgeTyped(b, "runtime")
+	b.AddEdgeTyped(c, "runtime")
+	c.AddEdgeTyped(d, "runtime")
+	a.AddEdgeTyped(c, "dev")
+
+	g := graph.New("deps", graph.WithNodes(graph.Nodes{a, b, c, d}))
+
+	view := g.View(func(n *graph.Node) bool {
+		return n.Attributes["test"] != true
+	}, func(e *graph.Edge) bool {
+		return e.Name == "runtime"
+	})
+
+	if got := view.Nodes().String(); got != "a, c, d" || view.NodeCount() != 3 {
+		t.Fatalf("unexpected nodes: %s", got)
+	}
+
+	if view.Contains(b) || !view.Contains(a) || view.Contains(graph.NewNode("a", nil)) {
+		t.Fatalf("unexpected membership")
+	}
+
+	if got := view.Edges().String(); got != "c → d" {
+		t.Fatalf("unexpected edges: %s", got)
+	}
+
+	if components := view.Components(); len(components) != 2 || components[0].String() != "a" || components[1].String() != "c, d" {
+		t.Fatalf("unexpected components: %v", components)
+	}
+
+	if got := view.Bridges().String(); got != "c → d" {
+		t.Fatalf("unexpected bridges: %s", got)
+	}
+
+	if view.PathTo(a, d) != nil {
+		t.Fatalf("expected no path from a to d")
+	}
+
+	if _, ok := view.FindNode("b"); ok {
+		t.Fatalf("expected b to not be found")
+	}
+
+	// Views are read lazily, so they see changes to the graph.
+	b.Attributes["test"] = false
+
+	if path := view.PathTo(a, d); path.String() != "a → b → c → d" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	var visited graph.Nodes
+	view.Visit(b, func(n *graph.Node) {
+		visited = append(visited, n)
+	})
+
+	if got := visited.String(); got != "b, c, d" {
+		t.Fatalf("unexpected visited nodes: %s", got)
+	}
+
+	// Views can be used by algorithms written against the Graph interface.
+	start, _ := view.FindNode("a")
+	end, _ := view.FindNode("d")
+
+	path, cost := graph.ShortestPath(view, start, end)
+	if len(path) != 4 || cost != 3 {
+		t.Fatalf("unexpected shortest path %v with cost %v", path, cost)
+	}
+
+	if all := g.View(nil, nil); all.NodeCount() != 4 || len(all.Edges()) != 4 {
+		t.Fatalf("expected a view without filters to have the whole graph")
+	}
+}
+
+func TestGraphView_Contains(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b}))
+	v := g.View(nil, nil)
+
+	if !v.Contains(a) || v.Contains(c) {
+		t.Fatal("expected the view to contain the nodes of the graph only")
+	}
+
+	// Nodes added to another graph since are still in the view.
+	graph.New("other", graph.WithNodes(graph.Nodes{b}))
+
+	if !v.Contains(b) {
+		t.Fatal("expected b to still be in the view")
+	}
+
+	g.RemoveNode(b)
+
+	if v.Contains(b) {
+		t.Fatal("expected b to be removed from the view")
+	}
+}
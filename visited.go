@@ -0,0 +1,178 @@
+package graph
+
+import (
+	"math"
+	"math/bits"
+)
+
+// VisitedTracker records the nodes visited by a traversal, so each node is
+// only visited once. NodeSet is the simplest tracker, but uses a map entry
+// for each node, which dominates the memory used to traverse very large
+// graphs, so trackers using less memory can be selected with traversal
+// options, like WithBitsetVisited and WithBloomVisited.
+type VisitedTracker interface {
+	// Contains checks if the node was visited.
+	Contains(*Node) bool
+
+	// Add records that the node was visited.
+	Add(*Node)
+}
+
+// WithVisitedTracker makes traversals, like Node.Visit, Instance.DFS, and
+// Instance.BFS, record visited nodes using a tracker returned by the given
+// function for each traversal, given the graph being traversed, which is
+// nil when traversing nodes that weren't added to a graph.
+//
+//	g.DFS(fn, graph.WithVisitedTracker(func(*graph.Instance) graph.VisitedTracker {
+//		return graph.NodeSet{}
+//	}))
+func WithVisitedTracker(newTracker func(inst *Instance) VisitedTracker) func(*VisitOrder) {
+	return func(o *VisitOrder) {
+		o.Visited = newTracker
+	}
+}
+
+// WithBitsetVisited makes traversals record visited nodes using a bitset
+// indexed by node ID, see NewBitsetTracker, which is the default.
+func WithBitsetVisited() func(*VisitOrder) {
+	return WithVisitedTracker(func(inst *Instance) VisitedTracker {
+		return NewBitsetTracker(inst)
+	})
+}
+
+// WithBloomVisited makes traversals record visited nodes using a bloom
+// filter sized for the given number of nodes and false positive rate, see
+// NewBloomTracker. Nodes wrongly reported as visited are skipped, so this
+// trades exact results for less memory when only part of a giant graph is
+// expected to be traversed.
+func WithBloomVisited(expected int, falsePositiveRate float64) func(*VisitOrder) {
+	return WithVisitedTracker(func(inst *Instance) VisitedTracker {
+		tracker := NewBloomTracker(expected, falsePositiveRate).(*bloomTracker)
+		tracker.graph = inst
+		return tracker
+	})
+}
+
+// tracker returns a new tracker of visited nodes for a traversal of the
+// given graph, using the configured tracker, or a bitset by default.
+func (o *VisitOrder) tracker(inst *Instance) VisitedTracker {
+	if o == nil || o.Visited == nil {
+		return NewBitsetTracker(inst)
+	}
+	return o.Visited(inst)
+}
+
+// NewBitsetTracker returns a tracker of visited nodes using a bit for each
+// node ID of the given graph, see DenseNodeSet, so it takes 1 bit per node
+// instead of a map entry. The graph can be nil to only use a map.
+func NewBitsetTracker(inst *Instance) VisitedTracker {
+	return NewDenseNodeSet(inst)
+}
+
+// bloomTracker is a VisitedTracker using a bloom filter of the IDs of the
+// nodes of a graph, and a map for nodes of other graphs, or that aren't in
+// a graph.
+type bloomTracker struct {
+	graph  *Instance
+	bits   []uint64
+	hashes int
+	others NodeSet
+}
+
+// NewBloomTracker returns a tracker of visited nodes using a bloom filter
+// sized to hold the given number of nodes with the given false positive
+// rate, like 0.01, taking about 10 bits per expected node for a rate of 1%.
+// Nodes are never wrongly reported as unvisited, but other nodes may be
+// wrongly reported as visited, more often once more nodes than expected
+// were added. Nodes are identified by their ID in the graph of the first
+// node that is tracked, while nodes of other graphs, reached by traversals
+// that leave the graph, and nodes that weren't added to a graph, are
+// tracked exactly using a map.
+//
+// https://en.wikipedia.org/wiki/Bloom_filter
+func NewBloomTracker(expected int, falsePositiveRate float64) VisitedTracker {
+	if expected < 1 {
+		expected = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+
+	m := math.Ceil(-float64(expected) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := int(math.Round(m / float64(expected) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &bloomTracker{
+		bits:   make([]uint64, (int(m)+63)/64),
+		hashes: k,
+	}
+}
+
+// Contains checks if the node was probably visited.
+func (b *bloomTracker) Contains(n *Node) bool {
+	id := b.id(n)
+	if id < 0 {
+		return b.others.Contains(n)
+	}
+
+	size := uint64(len(b.bits) * 64)
+	h1, h2 := bloomHashes(uint64(id))
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % size
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Add records that the node was visited.
+func (b *bloomTracker) Add(n *Node) {
+	id := b.id(n)
+	if id < 0 {
+		if b.others == nil {
+			b.others = NodeSet{}
+		}
+		b.others.Add(n)
+		return
+	}
+
+	size := uint64(len(b.bits) * 64)
+	h1, h2 := bloomHashes(uint64(id))
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % size
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// id returns the ID of the node in the tracker's graph, which is the graph
+// of the first node that is tracked, or -1 if it isn't in that graph.
+func (b *bloomTracker) id(n *Node) int {
+	if n == nil || n.graph == nil {
+		return -1
+	}
+	if b.graph == nil {
+		b.graph = n.graph
+	}
+	if n.graph != b.graph {
+		return -1
+	}
+	return b.graph.ID(n)
+}
+
+// bloomHashes returns two independent hashes of the given value, which are
+// combined to compute the bits of a bloom filter, using the SplitMix64
+// finalizer.
+func bloomHashes(v uint64) (uint64, uint64) {
+	mix := func(z uint64) uint64 {
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+	h1 := mix(v + 0x9e3779b97f4a7c15)
+	h2 := mix(bits.RotateLeft64(h1, 32) ^ v)
+	// An odd step visits different bits for each hash.
+	return h1, h2 | 1
+}
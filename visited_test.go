@@ -0,0 +1,126 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+func TestVisitedTracker(t *testing.T) {
+	g := graph.New("test")
+	for i := 0; i < 200; i++ {
+		g.AddNode(graph.NewNode(fmt.Sprintf("n%d", i), nil))
+	}
+
+	outside := graph.NewNode("outside", nil)
+
+	trackers := map[string]graph.VisitedTracker{
+		"node set": graph.NodeSet{},
+		"bitset":   graph.NewBitsetTracker(g),
+		"bloom":    graph.NewBloomTracker(len(g.Nodes), 0.01),
+	}
+
+	for name, tracker := range trackers {
+		t.Run(name, func(t *testing.T) {
+			for _, node := range g.Nodes[:100] {
+				tracker.Add(node)
+			}
+			tracker.Add(outside)
+
+			for _, node := range g.Nodes[:100] {
+				if !tracker.Contains(node) {
+					t.Fatalf("expected %s to be visited", node.Name)
+				}
+			}
+
+			if !tracker.Contains(outside) {
+				t.Fatalf("expected node outside of the graph to be visited")
+			}
+
+			var wrong int
+			for _, node := range g.Nodes[100:] {
+				if tracker.Contains(node) {
+					wrong++
+				}
+			}
+
+			if wrong > 10 {
+				t.Fatalf("expected few nodes to be wrongly reported as visited, got %d", wrong)
+			}
+		})
+	}
+}
+
+func TestNewBloomTracker_graphs(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+	)
+
+	// Both nodes have the same ID in their own graph.
+	graph.New("first", graph.WithNodes(graph.Nodes{a}))
+	graph.New("second", graph.WithNodes(graph.Nodes{b}))
+
+	a.AddEdge(b)
+
+	tracker := graph.NewBloomTracker(1, 0.01)
+	tracker.Add(a)
+
+	if tracker.Contains(b) {
+		t.Fatal("expected a node of another graph with the same ID not to be visited")
+	}
+
+	var visited graph.Nodes
+	a.Visit(func(n *graph.Node) {
+		visited = append(visited, n)
+	}, graph.FollowExternalEdges(), graph.WithBloomVisited(1, 0.01))
+
+	if visited.String() != "a, b" {
+		t.Fatalf("expected the traversal to leave the graph, got %v", visited)
+	}
+}
+
+func TestWithVisitedTracker(t *testing.T) {
+	var (
+		a = graph.NewNode("a", nil)
+		b = graph.NewNode("b", nil)
+		c = graph.NewNode("c", nil)
+	)
+
+	a.AddEdge(b)
+	b.AddEdge(c)
+	c.AddEdge(a)
+
+	g := graph.New("test", graph.WithNodes(graph.Nodes{a, b, c}))
+
+	var created int
+	tracker := graph.WithVisitedTracker(func(inst *graph.Instance) graph.VisitedTracker {
+		if inst != g {
+			t.Fatalf("expected the traversed graph, got %v", inst)
+		}
+		created++
+		return graph.NodeSet{}
+	})
+
+	for _, traverse := range []func(func(*graph.Node)){
+		func(fn func(*graph.Node)) { a.Visit(fn, tracker) },
+		func(fn func(*graph.Node)) { g.DFS(fn, tracker) },
+		func(fn func(*graph.Node)) { g.BFS(fn, tracker) },
+		func(fn func(*graph.Node)) { g.BFS(fn, graph.WithBitsetVisited()) },
+		func(fn func(*graph.Node)) { g.DFS(fn, graph.WithBloomVisited(10, 0.01)) },
+	} {
+		var visited graph.Nodes
+		traverse(func(n *graph.Node) {
+			visited = append(visited, n)
+		})
+
+		if got := visited.String(); got != "a, b, c" {
+			t.Fatalf("unexpected visited nodes: %s", got)
+		}
+	}
+
+	if created != 3 {
+		t.Fatalf("expected a tracker for each traversal, got %d", created)
+	}
+}
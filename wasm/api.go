@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/picatz/graph"
+)
+
+// api holds the graphs created from JavaScript, by handle, and implements
+// the exported operations using plain Go values, so they can be tested
+// without a JavaScript runtime.
+type api struct {
+	graphs map[int]*graph.Instance
+	next   int
+}
+
+// newAPI returns a new api without any graphs.
+func newAPI() *api {
+	return &api{graphs: map[int]*graph.Instance{}}
+}
+
+// add adds the graph, returning its handle.
+func (a *api) add(g *graph.Instance) int {
+	a.next++
+	a.graphs[a.next] = g
+	return a.next
+}
+
+// graph returns the graph with the given handle.
+func (a *api) graph(id int) (*graph.Instance, error) {
+	g, ok := a.graphs[id]
+	if !ok {
+		return nil, fmt.Errorf("graph %d not found", id)
+	}
+	return g, nil
+}
+
+// node returns the node with the given name in the graph with the given
+// handle.
+func (a *api) node(id int, name string) (*graph.Instance, *graph.Node, error) {
+	g, err := a.graph(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node, ok := g.Lookup(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q not found", name)
+	}
+	return g, node, nil
+}
+
+// newGraph creates an empty graph with the given name.
+func (a *api) newGraph(name string) int {
+	return a.add(graph.New(name))
+}
+
+// free forgets the graph with the given handle, so it can be garbage
+// collected.
+func (a *api) free(id int) {
+	delete(a.graphs, id)
+}
+
+// decode creates a graph from the given text, using the given format:
+// "json", "dot", or "yaml".
+func (a *api) decode(format, text string) (int, error) {
+	var (
+		nodes graph.Nodes
+		err   error
+	)
+
+	switch format {
+	case "json":
+		nodes, err = graph.DecodeJSON(strings.NewReader(text))
+	case "dot":
+		nodes, err = graph.DecodeDOT(strings.NewReader(text))
+	case "yaml":
+		g, err := graph.DecodeYAML(strings.NewReader(text))
+		if err != nil {
+			return 0, err
+		}
+		return a.add(g), nil
+	default:
+		return 0, fmt.Errorf("unsupported format %q", format)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return a.add(graph.New("", graph.WithNodes(nodes))), nil
+}
+
+// encode returns the graph with the given handle encoded using the given
+// format: "json", "dot", or "yaml".
+func (a *api) encode(id int, format string) (string, error) {
+	g, err := a.graph(id)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	switch format {
+	case "json":
+		err = graph.EncodeJSON(&buf, g.Nodes)
+	case "dot":
+		err = g.EncodeDOT(&buf)
+	case "yaml":
+		err = graph.EncodeYAML(&buf, g)
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+
+	return buf.String(), err
+}
+
+// addNode adds a node with the given name and attributes to the graph with
+// the given handle, unless it already has a node with the name.
+func (a *api) addNode(id int, name string, attrs map[string]any) error {
+	g, err := a.graph(id)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := g.Lookup(name); ok {
+		return fmt.Errorf("node %q already exists", name)
+	}
+
+	g.AddNode(graph.NewNode(name, attrs))
+	return nil
+}
+
+// addEdge adds a directed edge between the nodes with the given names to
+// the graph with the given handle, adding nodes that don't exist yet.
+func (a *api) addEdge(id int, from, to string) error {
+	g, err := a.graph(id)
+	if err != nil {
+		return err
+	}
+
+	return g.LoadEdges([][2]string{{from, to}})
+}
+
+// traverse returns the names of the nodes reachable from the given node of
+// the graph with the given handle, in the order visited using the given
+// order: "dfs" (depth-first) or "bfs" (breadth-first).
+func (a *api) traverse(id int, start, order string) ([]string, error) {
+	g, node, err := a.node(id, start)
+	if err != nil {
+		return nil, err
+	}
+
+	var search func(graph.Graph, graph.NodeRef, func(graph.NodeRef) bool)
+
+	switch order {
+	case "dfs":
+		search = graph.DepthFirst
+	case "bfs":
+		search = graph.BreadthFirst
+	default:
+		return nil, fmt.Errorf("unsupported order %q, expected dfs or bfs", order)
+	}
+
+	names := []string{}
+	search(g, node, func(n graph.NodeRef) bool {
+		names = append(names, n.Key())
+		return true
+	})
+	return names, nil
+}
+
+// shortestPath returns the names of the nodes on the lightest path between
+// the given nodes of the graph with the given handle, and its weight.
+func (a *api) shortestPath(id int, from, to string) ([]string, float64, error) {
+	g, start, err := a.node(id, from)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_, end, err := a.node(id, to)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	path, weight := graph.ShortestPath(g, start, end)
+	if path == nil {
+		return nil, 0, fmt.Errorf("no path from %q to %q", from, to)
+	}
+
+	names := make([]string, len(path))
+	for i, n := range path {
+		names[i] = n.Key()
+	}
+	return names, weight, nil
+}
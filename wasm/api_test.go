@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPI(t *testing.T) {
+	a := newAPI()
+
+	// a → b → c
+
+	g, err := a.decode("dot", "digraph { a -> b -> c }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.addEdge(g, "a", "d"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.addNode(g, "e", map[string]any{"color": "red"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.addNode(g, "e", nil); err == nil {
+		t.Fatal("expected an error adding a duplicate node")
+	}
+
+	for order, expected := range map[string][]string{
+		"dfs": {"a", "b", "c", "d"},
+		"bfs": {"a", "b", "d", "c"},
+	} {
+		names, err := a.traverse(g, "a", order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("%s: expected %v, got %v", order, expected, names)
+		}
+	}
+
+	path, weight, err := a.shortestPath(g, "a", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []string{"a", "b", "c"}) || weight != 2 {
+		t.Fatalf("unexpected path: %v, %v", path, weight)
+	}
+
+	if _, _, err := a.shortestPath(g, "c", "a"); err == nil {
+		t.Fatal("expected no path from c to a")
+	}
+
+	text, err := a.encode(g, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err := a.decode("json", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dot, err := a.encode(copied, "dot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot, `"e" ["color"="red"]`) {
+		t.Fatalf("expected the copied graph to have the node's attributes:\n%s", dot)
+	}
+
+	a.free(g)
+
+	if _, err := a.encode(g, "dot"); err == nil {
+		t.Fatal("expected an error using a freed graph")
+	}
+
+	if _, err := a.decode("xml", ""); err == nil {
+		t.Fatal("expected an error decoding an unsupported format")
+	}
+
+	if _, err := a.traverse(copied, "a", "random"); err == nil {
+		t.Fatal("expected an error using an unsupported order")
+	}
+}
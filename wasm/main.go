@@ -0,0 +1,145 @@
+//go:build js && wasm
+
+// Command wasm exports the core operations of the graph package to
+// JavaScript, so browser tools can use the same graph logic as Go services.
+//
+//	$ GOOS=js GOARCH=wasm go build -o graph.wasm ./wasm
+//
+// Loading the module, using the wasm_exec.js support file of the Go
+// distribution, defines a global "graph" object. Graphs are referred to by
+// the integer handles returned by graph.new and graph.decode, and should be
+// released using graph.free:
+//
+//	const g = graph.decode("dot", "digraph { a -> b -> c }")
+//	graph.addEdge(g, "a", "c")
+//	graph.traverse(g, "a", "bfs")     // ["a", "b", "c"]
+//	graph.shortestPath(g, "a", "c")   // { path: ["a", "c"], weight: 1 }
+//	graph.encode(g, "json")
+//	graph.free(g)
+//
+// Functions return an Error, rather than throwing it, when they fail:
+//
+//	graph.new(name)                      handle
+//	graph.decode(format, text)           handle, format is json, dot, or yaml
+//	graph.encode(handle, format)         string
+//	graph.addNode(handle, name, attrs?)  null
+//	graph.addEdge(handle, from, to)      null
+//	graph.traverse(handle, start, order) array of names, order is dfs or bfs
+//	graph.shortestPath(handle, from, to) { path, weight }
+//	graph.free(handle)                   null
+package main
+
+import (
+	"fmt"
+	"syscall/js"
+)
+
+func main() {
+	a := newAPI()
+
+	exports := map[string]func(args []js.Value) (any, error){
+		"new": func(args []js.Value) (any, error) {
+			return a.newGraph(arg(args, 0).String()), nil
+		},
+		"decode": func(args []js.Value) (any, error) {
+			return a.decode(arg(args, 0).String(), arg(args, 1).String())
+		},
+		"encode": func(args []js.Value) (any, error) {
+			return a.encode(arg(args, 0).Int(), arg(args, 1).String())
+		},
+		"addNode": func(args []js.Value) (any, error) {
+			var attrs map[string]any
+			if v := arg(args, 2); v.Type() == js.TypeObject {
+				attrs = map[string]any{}
+				keys := js.Global().Get("Object").Call("keys", v)
+				for i := 0; i < keys.Length(); i++ {
+					key := keys.Index(i).String()
+					attrs[key] = toGo(v.Get(key))
+				}
+			}
+			return nil, a.addNode(arg(args, 0).Int(), arg(args, 1).String(), attrs)
+		},
+		"addEdge": func(args []js.Value) (any, error) {
+			return nil, a.addEdge(arg(args, 0).Int(), arg(args, 1).String(), arg(args, 2).String())
+		},
+		"traverse": func(args []js.Value) (any, error) {
+			names, err := a.traverse(arg(args, 0).Int(), arg(args, 1).String(), arg(args, 2).String())
+			return toJS(names), err
+		},
+		"shortestPath": func(args []js.Value) (any, error) {
+			path, weight, err := a.shortestPath(arg(args, 0).Int(), arg(args, 1).String(), arg(args, 2).String())
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"path": toJS(path), "weight": weight}, nil
+		},
+		"free": func(args []js.Value) (any, error) {
+			a.free(arg(args, 0).Int())
+			return nil, nil
+		},
+	}
+
+	obj := js.Global().Get("Object").New()
+	for name, fn := range exports {
+		obj.Set(name, export(fn))
+	}
+	js.Global().Set("graph", obj)
+
+	// Keep the exported functions available.
+	select {}
+}
+
+// export wraps the given function as a JavaScript function, which returns
+// an Error if the function fails, including if it panics on arguments of
+// the wrong type.
+func export(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) (result any) {
+		defer func() {
+			if r := recover(); r != nil {
+				result = jsError(fmt.Errorf("%v", r))
+			}
+		}()
+
+		v, err := fn(args)
+		if err != nil {
+			return jsError(err)
+		}
+		return v
+	})
+}
+
+// arg returns the argument at the given index, or undefined if missing.
+func arg(args []js.Value, i int) js.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return js.Undefined()
+}
+
+// jsError returns a JavaScript Error with the message of the given error.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+// toJS converts the given names to a value that can be passed to
+// JavaScript as an array.
+func toJS(names []string) []any {
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return values
+}
+
+// toGo converts a JavaScript attribute value to a Go value: a boolean,
+// number, or string.
+func toGo(v js.Value) any {
+	switch v.Type() {
+	case js.TypeBoolean:
+		return v.Bool()
+	case js.TypeNumber:
+		return v.Float()
+	default:
+		return v.String()
+	}
+}
@@ -0,0 +1,320 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlGraph is the human-authorable YAML representation of a graph.
+//
+//	name: topology
+//	attributes:
+//	  env: prod
+//	nodes:
+//	  - name: a
+//	    labels: [Service]
+//	    attributes:
+//	      color: red
+//	  - b
+//	edges:
+//	  - a -> b
+//	  - edge: b -- c
+//	    name: link
+//	    attributes:
+//	      weight: 2
+//	  - from: c -> d
+//	    to: e
+//	    direction: unknown
+type yamlGraph struct {
+	Name       string     `yaml:"name,omitempty"`
+	Attributes Attributes `yaml:"attributes,omitempty"`
+	Nodes      []yamlNode `yaml:"nodes,omitempty"`
+	Edges      []yamlEdge `yaml:"edges,omitempty"`
+}
+
+// yamlNode is a node, which can be written as just its name.
+type yamlNode struct {
+	Name       string     `yaml:"name"`
+	Labels     []string   `yaml:"labels,flow,omitempty"`
+	Attributes Attributes `yaml:"attributes,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (n *yamlNode) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		n.Name = value.Value
+		return nil
+	}
+
+	type plain yamlNode
+	return value.Decode((*plain)(n))
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (n yamlNode) MarshalYAML() (any, error) {
+	if len(n.Attributes) == 0 && len(n.Labels) == 0 {
+		return n.Name, nil
+	}
+
+	type plain yamlNode
+	return plain(n), nil
+}
+
+// yamlEdge is an edge, which can be written as just its "from -> to" string,
+// or with its nodes and direction as separate fields, for edges that can't
+// be written as a string.
+type yamlEdge struct {
+	Edge       string     `yaml:"edge,omitempty"`
+	From       string     `yaml:"from,omitempty"`
+	To         string     `yaml:"to,omitempty"`
+	Direction  string     `yaml:"direction,omitempty"`
+	Name       string     `yaml:"name,omitempty"`
+	Attributes Attributes `yaml:"attributes,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (e *yamlEdge) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		e.Edge = value.Value
+		return nil
+	}
+
+	type plain yamlEdge
+	return value.Decode((*plain)(e))
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (e yamlEdge) MarshalYAML() (any, error) {
+	if e.Edge != "" && e.Name == "" && len(e.Attributes) == 0 {
+		return e.Edge, nil
+	}
+
+	type plain yamlEdge
+	return plain(e), nil
+}
+
+// yamlOperators maps the edge operators used in YAML edge strings to edge
+// directions, ordered so that longer operators are matched first.
+var yamlOperators = []struct {
+	Operator  string
+	Direction EdgeDirection
+}{
+	{"<->", Both},
+	{"->", Out},
+	{"<-", In},
+	{"--", None},
+}
+
+// yamlDirections maps the direction names of YAML edges written with
+// separate fields to edge directions.
+var yamlDirections = map[string]EdgeDirection{
+	"unknown": Unknown,
+	"none":    None,
+	"in":      In,
+	"out":     Out,
+	"both":    Both,
+}
+
+// yamlDirectionName returns the name of the given direction used by YAML
+// edges written with separate fields, which is its number for directions
+// without a name.
+func yamlDirectionName(direction EdgeDirection) string {
+	for name, d := range yamlDirections {
+		if d == direction {
+			return name
+		}
+	}
+	return strconv.Itoa(int(direction))
+}
+
+// parseYAMLDirection parses the direction of a YAML edge written with
+// separate fields, which is Out if it is empty.
+func parseYAMLDirection(s string) (EdgeDirection, error) {
+	if s == "" {
+		return Out, nil
+	}
+	if d, ok := yamlDirections[strings.ToLower(s)]; ok {
+		return d, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return EdgeDirection(n), nil
+	}
+	return Unknown, fmt.Errorf("graph invalid YAML edge direction %q", s)
+}
+
+// yamlOperator returns the operator of the given direction used in YAML
+// edge strings, if it has one.
+func yamlOperator(direction EdgeDirection) (string, bool) {
+	for _, op := range yamlOperators {
+		if op.Direction == direction {
+			return op.Operator, true
+		}
+	}
+	return "", false
+}
+
+// parseYAMLEdge parses an edge string, like "a -> b", into its parts.
+func parseYAMLEdge(s string) (from string, direction EdgeDirection, to string, err error) {
+	for _, op := range yamlOperators {
+		parts := strings.SplitN(s, " "+op.Operator+" ", 2)
+		if len(parts) == 2 {
+			from, to = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if from == "" || to == "" {
+				break
+			}
+			return from, op.Direction, to, nil
+		}
+	}
+	return "", Unknown, "", fmt.Errorf("graph invalid YAML edge %q, expected \"from -> to\"", s)
+}
+
+// DecodeYAML decodes a graph from its human-authorable YAML representation.
+//
+// Nodes can be written as just their name, or with attributes. Edges are
+// written as "from -> to" strings, using "->" or "<-" for directed edges,
+// "<->" for bi-directional (Both) edges, and "--" for undirected (None)
+// edges. Edges can also be written with separate from, to, and direction
+// fields, which is needed for node names containing operators, like
+// "a -> b", and for other directions, like Unknown:
+//
+//   - from: a -> b
+//     to: c
+//     direction: unknown
+//
+// The direction is one of unknown, none, in, out, or both, and is out if
+// it is omitted. Nodes referenced by edges are added to the graph if they
+// were not listed.
+func DecodeYAML(r io.Reader) (*Instance, error) {
+	yg := &yamlGraph{}
+
+	err := yaml.NewDecoder(r).Decode(yg)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("graph failed to decode YAML: %w", err)
+	}
+
+	inst := New(yg.Name)
+	if yg.Attributes != nil {
+		inst.Attributes = yg.Attributes
+	}
+
+	byName := map[string]*Node{}
+
+	node := func(name string) *Node {
+		n, ok := byName[name]
+		if !ok {
+			n = NewNode(name, nil)
+			byName[name] = n
+			inst.AddNode(n)
+		}
+		return n
+	}
+
+	for _, yn := range yg.Nodes {
+		if _, ok := byName[yn.Name]; ok {
+			return nil, fmt.Errorf("graph failed to decode YAML: duplicate node %q", yn.Name)
+		}
+		n := node(yn.Name)
+		n.Attributes = yn.Attributes
+		n.Labels = yn.Labels
+	}
+
+	for _, ye := range yg.Edges {
+		fromName, direction, toName, err := ye.parse()
+		if err != nil {
+			return nil, fmt.Errorf("graph failed to decode YAML: %w", err)
+		}
+
+		from, to := node(fromName), node(toName)
+
+		added := len(from.Edges)
+
+		from.AddEdgeWithDirection(to, direction)
+
+		edges := append(Edges{}, from.Edges[added:]...)
+		if from != to {
+			edges = append(edges, to.Edges[len(to.Edges)-1])
+		}
+
+		// Name both sides of the relationship, which share the attributes.
+		for _, edge := range edges {
+			edge.Name = ye.Name
+			edge.Attributes = ye.Attributes
+		}
+	}
+
+	return inst, nil
+}
+
+// parse returns the nodes and direction of the edge, written either as a
+// string or with separate fields.
+func (e yamlEdge) parse() (from string, direction EdgeDirection, to string, err error) {
+	if e.From == "" && e.To == "" && e.Direction == "" {
+		return parseYAMLEdge(e.Edge)
+	}
+
+	if e.Edge != "" {
+		return "", Unknown, "", fmt.Errorf("graph invalid YAML edge %q, expected either an edge string or from and to fields", e.Edge)
+	}
+	if e.From == "" || e.To == "" {
+		return "", Unknown, "", fmt.Errorf("graph invalid YAML edge from %q to %q, expected both nodes", e.From, e.To)
+	}
+
+	direction, err = parseYAMLDirection(e.Direction)
+	if err != nil {
+		return "", Unknown, "", err
+	}
+	return e.From, direction, e.To, nil
+}
+
+// EncodeYAML encodes the graph using its human-authorable YAML representation,
+// which can be decoded using DecodeYAML.
+//
+// Each relationship is written once, using the edges returned by Edges.
+// Edges are written as strings, unless the names of their nodes can't be
+// parsed back from them or their direction has no operator, like Unknown,
+// in which case they are written with separate fields.
+func EncodeYAML(w io.Writer, inst *Instance) error {
+	yg := yamlGraph{
+		Name:       inst.Name,
+		Attributes: inst.Attributes,
+	}
+
+	for _, node := range inst.Nodes {
+		yg.Nodes = append(yg.Nodes, yamlNode{Name: node.Name, Labels: node.Labels, Attributes: node.Attributes})
+	}
+
+	eachUniqueEdge(inst.Nodes, func(from *Node, edge *Edge) {
+		ye := yamlEdge{
+			Name:       edge.Name,
+			Attributes: edge.Attributes,
+		}
+
+		// Names containing operators, like "a -> b", can't be parsed back
+		// from the string.
+		if op, ok := yamlOperator(edge.Direction); ok {
+			s := fmt.Sprintf("%s %s %s", from.Name, op, edge.Node.Name)
+			if f, d, t, err := parseYAMLEdge(s); err == nil && f == from.Name && d == edge.Direction && t == edge.Node.Name {
+				ye.Edge = s
+			}
+		}
+		if ye.Edge == "" {
+			ye.From, ye.To, ye.Direction = from.Name, edge.Node.Name, yamlDirectionName(edge.Direction)
+		}
+
+		yg.Edges = append(yg.Edges, ye)
+	})
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	err := enc.Encode(yg)
+	if err != nil {
+		return fmt.Errorf("graph failed to encode YAML: %w", err)
+	}
+
+	return enc.Close()
+}
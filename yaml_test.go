@@ -0,0 +1,133 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/picatz/graph"
+)
+
+const topology_yaml = `name: topology
+attributes:
+  env: prod
+nodes:
+  - name: lb
+    attributes:
+      tier: edge
+  - api
+  - db
+edges:
+  - lb -> api
+  - edge: api -> db
+    name: queries
+    attributes:
+      weight: 2
+  - db -- replica
+`
+
+func TestDecodeYAML(t *testing.T) {
+	g, err := graph.DecodeYAML(strings.NewReader(topology_yaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Name != "topology" || g.Attributes["env"] != "prod" {
+		t.Fatalf("unexpected graph name or attributes: %q %v", g.Name, g.Attributes)
+	}
+
+	if g.Nodes.String() != "lb, api, db, replica" {
+		t.Fatalf("unexpected nodes: %v", g.Nodes)
+	}
+
+	lb, api, db, replica := g.Nodes[0], g.Nodes[1], g.Nodes[2], g.Nodes[3]
+
+	if lb.Attributes["tier"] != "edge" {
+		t.Fatalf("unexpected node attributes: %v", lb.Attributes)
+	}
+
+	if path := lb.PathTo(db); path.String() != "lb → api → db" {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	queries := api.Edges.Out()[0]
+	if queries.Name != "queries" || queries.Weight() != 2 {
+		t.Fatalf("unexpected edge name or weight: %q %v", queries.Name, queries.Weight())
+	}
+
+	if db.Edges[1].Node != replica || db.Edges[1].Direction != graph.None {
+		t.Fatalf("expected undirected edge from db to replica")
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	err = graph.EncodeYAML(buf, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nodes only referenced by edges are listed after encoding.
+	golden := strings.Replace(topology_yaml, "  - db\n", "  - db\n  - replica\n", 1)
+
+	if buf.String() != golden {
+		t.Fatalf("got:\n%s\ngolden:\n%s\n", buf, golden)
+	}
+
+	again, err := graph.DecodeYAML(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.Nodes.String() != g.Nodes.String() || len(again.Nodes[2].Edges) != len(db.Edges) {
+		t.Fatalf("expected YAML encoding to round-trip")
+	}
+}
+
+func TestDecodeYAML_invalidEdge(t *testing.T) {
+	_, err := graph.DecodeYAML(strings.NewReader("edges:\n  - a => b\n"))
+	if err == nil {
+		t.Fatalf("expected error for invalid edge")
+	}
+}
+
+func TestEncodeYAML_fields(t *testing.T) {
+	var (
+		a = graph.NewNode("a -> b", nil)
+		b = graph.NewNode("c", nil)
+		c = graph.NewNode(" d ", nil)
+	)
+
+	a.AddEdge(b)
+	b.AddEdgeWithDirection(c, graph.Unknown)
+	c.AddEdgeWithDirection(a, graph.None)
+
+	g := graph.New("test", graph.WithNodes(graph.NewNodes(a, b, c)))
+
+	buf := bytes.NewBuffer(nil)
+	if err := graph.EncodeYAML(buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "direction: unknown") {
+		t.Fatalf("expected the unknown direction to be written, got:\n%s", buf)
+	}
+
+	decoded, err := graph.DecodeYAML(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !graph.Equal(g, decoded) {
+		t.Fatalf("expected YAML encoding to round-trip, got nodes %v, edges %v", decoded.Nodes, decoded.Edges())
+	}
+
+	for _, input := range []string{
+		"edges:\n  - from: a\n",
+		"edges:\n  - from: a\n    to: b\n    direction: sideways\n",
+		"edges:\n  - edge: a -> b\n    from: a\n    to: b\n",
+	} {
+		if _, err := graph.DecodeYAML(strings.NewReader(input)); err == nil {
+			t.Fatalf("expected error for invalid edge:\n%s", input)
+		}
+	}
+}